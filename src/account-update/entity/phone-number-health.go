@@ -0,0 +1,53 @@
+package account_update_entity
+
+import (
+	"time"
+
+	common_model "github.com/Astervia/wacraft-core/src/common/model"
+	phone_config_entity "github.com/Astervia/wacraft-core/src/phone-config/entity"
+	"github.com/google/uuid"
+)
+
+// Restriction limits what the WABA can do until it expires.
+type Restriction struct {
+	// RestrictionType is e.g. RESTRICTED_BIZ_INITIATED_MESSAGING.
+	RestrictionType string `json:"restriction_type"`
+	Expiration      string `json:"expiration,omitempty"`
+}
+
+// PhoneNumberHealth is the quality, messaging limits and restrictions of a
+// phone config, as last reported by Meta. Fields Meta has not reported yet are
+// empty.
+type PhoneNumberHealth struct {
+	PhoneConfigID      uuid.UUID                        `json:"phone_config_id" gorm:"type:uuid;not null;uniqueIndex"`
+	PhoneConfig        *phone_config_entity.PhoneConfig `json:"-" gorm:"foreignKey:PhoneConfigID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	WorkspaceID        *uuid.UUID                       `json:"workspace_id,omitempty" gorm:"type:uuid;index"`
+	DisplayPhoneNumber string                           `json:"display_phone_number,omitempty" gorm:"type:varchar(50)"`
+	// QualityEvent is the last quality event, e.g. FLAGGED when the quality
+	// rating dropped, UNFLAGGED, UPGRADE or DOWNGRADE.
+	QualityEvent string `json:"quality_event,omitempty" gorm:"type:varchar(50)"`
+	// MessagingLimitTier is the current messaging limit, e.g. TIER_1K.
+	MessagingLimitTier         string `json:"messaging_limit_tier,omitempty" gorm:"type:varchar(50)"`
+	MaxDailyConversations      *int   `json:"max_daily_conversations,omitempty"`
+	MaxPhoneNumbersPerBusiness *int   `json:"max_phone_numbers_per_business,omitempty"`
+	MaxPhoneNumbersPerWaba     *int   `json:"max_phone_numbers_per_waba,omitempty"`
+	// AccountEvent is the last account update event, e.g. ACCOUNT_RESTRICTION.
+	AccountEvent  string        `json:"account_event,omitempty" gorm:"type:varchar(100)"`
+	BanState      string        `json:"ban_state,omitempty" gorm:"type:varchar(50)"`
+	BanDate       string        `json:"ban_date,omitempty" gorm:"type:varchar(50)"`
+	Restrictions  []Restriction `json:"restrictions" gorm:"type:jsonb;serializer:json"`
+	ViolationType string        `json:"violation_type,omitempty" gorm:"type:varchar(100)"`
+
+	// When Meta last reported the quality, the account and the capabilities.
+	// Reports older than the stored one are ignored.
+	QualityReportedAt    *time.Time `json:"quality_reported_at,omitempty"`
+	AccountReportedAt    *time.Time `json:"account_reported_at,omitempty"`
+	CapabilityReportedAt *time.Time `json:"capability_reported_at,omitempty"`
+
+	common_model.Audit
+}
+
+// TableName keeps the table name singular, as health is uncountable.
+func (PhoneNumberHealth) TableName() string {
+	return "phone_number_health"
+}
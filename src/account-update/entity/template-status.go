@@ -0,0 +1,30 @@
+package account_update_entity
+
+import (
+	"time"
+
+	common_model "github.com/Astervia/wacraft-core/src/common/model"
+	phone_config_entity "github.com/Astervia/wacraft-core/src/phone-config/entity"
+	"github.com/google/uuid"
+)
+
+// TemplateStatus is the review status of a message template of the WABA of a
+// phone config, as last reported by Meta.
+type TemplateStatus struct {
+	PhoneConfigID uuid.UUID                        `json:"phone_config_id" gorm:"type:uuid;not null;uniqueIndex:idx_template_statuses_template"`
+	PhoneConfig   *phone_config_entity.PhoneConfig `json:"-" gorm:"foreignKey:PhoneConfigID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	WorkspaceID   *uuid.UUID                       `json:"workspace_id,omitempty" gorm:"type:uuid;index"`
+	TemplateID    string                           `json:"template_id" gorm:"type:varchar(255);not null;uniqueIndex:idx_template_statuses_template"`
+	Name          string                           `json:"name" gorm:"type:varchar(512);index"`
+	Language      string                           `json:"language" gorm:"type:varchar(20)"`
+	// Status is the last template event, e.g. APPROVED, REJECTED, PAUSED or DISABLED.
+	Status string `json:"status" gorm:"type:varchar(50);not null;index"`
+	// Reason explains rejections, e.g. INCORRECT_CATEGORY. NONE when there is none.
+	Reason      string `json:"reason,omitempty" gorm:"type:varchar(255)"`
+	Title       string `json:"title,omitempty" gorm:"type:text"`
+	Description string `json:"description,omitempty" gorm:"type:text"`
+	// ReportedAt is when Meta reported the status. Older reports are ignored.
+	ReportedAt *time.Time `json:"reported_at,omitempty"`
+
+	common_model.Audit
+}
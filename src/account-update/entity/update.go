@@ -0,0 +1,30 @@
+package account_update_entity
+
+import (
+	common_model "github.com/Astervia/wacraft-core/src/common/model"
+	phone_config_entity "github.com/Astervia/wacraft-core/src/phone-config/entity"
+	wh_model "github.com/Rfluid/whatsapp-cloud-api/src/webhook"
+	"github.com/google/uuid"
+)
+
+// AccountUpdate is a notification Meta sent about the WABA of a phone config,
+// its phone numbers or its message templates, e.g. a template approval or a
+// messaging limit downgrade. Every notification is kept, and the current
+// state it changes is stored in TemplateStatus and PhoneNumberHealth.
+type AccountUpdate struct {
+	PhoneConfigID uuid.UUID                        `json:"phone_config_id" gorm:"type:uuid;not null;index;uniqueIndex:idx_account_updates_change"`
+	PhoneConfig   *phone_config_entity.PhoneConfig `json:"-" gorm:"foreignKey:PhoneConfigID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	WorkspaceID   *uuid.UUID                       `json:"workspace_id,omitempty" gorm:"type:uuid;index"`
+	WabaID        string                           `json:"waba_id" gorm:"type:varchar(255);not null"`
+	Field         wh_model.Field                   `json:"field" gorm:"type:varchar(50);not null;index"`
+	// Event is the event of the notification, e.g. APPROVED, DOWNGRADE or
+	// ACCOUNT_RESTRICTION, or the alert type of account alerts.
+	Event string `json:"event,omitempty" gorm:"type:varchar(100)"`
+	// Value is the value of the change as sent by Meta.
+	Value map[string]any `json:"value" gorm:"type:jsonb;serializer:json"`
+	// ChangeKey identifies the change across deliveries of its webhook, so
+	// redeliveries are recorded once.
+	ChangeKey *string `json:"-" gorm:"type:varchar(100);uniqueIndex:idx_account_updates_change"`
+
+	common_model.Audit
+}
@@ -0,0 +1,121 @@
+package account_update_handler
+
+import (
+	common_model "github.com/Astervia/wacraft-core/src/common/model"
+	account_update_model "github.com/Astervia/wacraft-server/src/account-update/model"
+	account_update_service "github.com/Astervia/wacraft-server/src/account-update/service"
+	"github.com/Astervia/wacraft-server/src/validators"
+	workspace_middleware "github.com/Astervia/wacraft-server/src/workspace/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetUpdates returns the account notifications Meta sent for the phone configs of the workspace.
+//
+//	@Summary		Get account updates
+//	@Description	Lists the template status, phone number quality, account, alert and capability notifications Meta sent for the phone configs of the workspace, newest first. Filter by phone config, webhook field and creation time.
+//	@Tags			Account Update
+//	@Produce		json
+//	@Param			query	query		account_update_model.UpdateQuery		true	"Filters and pagination"
+//	@Success		200		{array}		account_update_entity.AccountUpdate	"Account updates"
+//	@Failure		400		{object}	common_model.DescriptiveError			"Invalid query parameters"
+//	@Failure		500		{object}	common_model.DescriptiveError			"Failed to get account updates"
+//	@Router			/account-update [get]
+//	@Security		ApiKeyAuth
+//	@Security		WorkspaceAuth
+func GetUpdates(c *fiber.Ctx) error {
+	query := new(account_update_model.UpdateQuery)
+	if err := c.QueryParser(query); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewParseJsonError(err).Send(),
+		)
+	}
+
+	if err := validators.Validator().Struct(query); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewValidationError(err).Send(),
+		)
+	}
+
+	workspace := workspace_middleware.GetWorkspace(c)
+
+	updates, err := account_update_service.GetUpdates(*query, workspace.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			common_model.NewApiError("unable to get account updates", err, "account_update_service").Send(),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(updates)
+}
+
+// GetTemplateStatuses returns the last review status of the message templates of the workspace.
+//
+//	@Summary		Get template statuses
+//	@Description	Lists the last status Meta reported for each message template of the phone configs of the workspace, with the rejection reason, last updated first. Filter by phone config, template name and status.
+//	@Tags			Account Update
+//	@Produce		json
+//	@Param			query	query		account_update_model.TemplateStatusQuery	true	"Filters and pagination"
+//	@Success		200		{array}		account_update_entity.TemplateStatus		"Template statuses"
+//	@Failure		400		{object}	common_model.DescriptiveError				"Invalid query parameters"
+//	@Failure		500		{object}	common_model.DescriptiveError				"Failed to get template statuses"
+//	@Router			/account-update/template-status [get]
+//	@Security		ApiKeyAuth
+//	@Security		WorkspaceAuth
+func GetTemplateStatuses(c *fiber.Ctx) error {
+	query := new(account_update_model.TemplateStatusQuery)
+	if err := c.QueryParser(query); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewParseJsonError(err).Send(),
+		)
+	}
+
+	if err := validators.Validator().Struct(query); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewValidationError(err).Send(),
+		)
+	}
+
+	workspace := workspace_middleware.GetWorkspace(c)
+
+	statuses, err := account_update_service.GetTemplateStatuses(*query, workspace.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			common_model.NewApiError("unable to get template statuses", err, "account_update_service").Send(),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(statuses)
+}
+
+// GetHealth returns the quality, messaging limits and restrictions of the phone configs of the workspace.
+//
+//	@Summary		Get phone number health
+//	@Description	Returns the last quality event, messaging limit tier, conversation and phone number limits, ban state and restrictions Meta reported for each phone config of the workspace. Phone configs Meta reported nothing for are not listed.
+//	@Tags			Account Update
+//	@Produce		json
+//	@Param			query	query		account_update_model.HealthQuery			true	"Filters"
+//	@Success		200		{array}		account_update_entity.PhoneNumberHealth	"Phone number health"
+//	@Failure		400		{object}	common_model.DescriptiveError				"Invalid query parameters"
+//	@Failure		500		{object}	common_model.DescriptiveError				"Failed to get phone number health"
+//	@Router			/account-update/phone-number-health [get]
+//	@Security		ApiKeyAuth
+//	@Security		WorkspaceAuth
+func GetHealth(c *fiber.Ctx) error {
+	query := new(account_update_model.HealthQuery)
+	if err := c.QueryParser(query); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewParseJsonError(err).Send(),
+		)
+	}
+
+	workspace := workspace_middleware.GetWorkspace(c)
+
+	health, err := account_update_service.GetHealth(*query, workspace.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			common_model.NewApiError("unable to get phone number health", err, "account_update_service").Send(),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(health)
+}
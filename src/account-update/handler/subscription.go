@@ -0,0 +1,90 @@
+package account_update_handler
+
+import (
+	"sync"
+
+	_ "github.com/Astervia/wacraft-core/src/common/model"
+	user_entity "github.com/Astervia/wacraft-core/src/user/entity"
+	websocket_model "github.com/Astervia/wacraft-core/src/websocket/model"
+	workspace_entity "github.com/Astervia/wacraft-core/src/workspace/entity"
+	account_update_entity "github.com/Astervia/wacraft-server/src/account-update/entity"
+	webhook_event_model "github.com/Astervia/wacraft-server/src/webhook-event/model"
+	webhook_service "github.com/Astervia/wacraft-server/src/webhook/service"
+	websocket_workspace_manager "github.com/Astervia/wacraft-server/src/websocket/workspace-manager"
+	"github.com/gofiber/contrib/websocket"
+)
+
+var (
+	accountUpdateClientPool       = websocket_model.CreateClientPool()
+	AccountUpdateWorkspaceManager = websocket_workspace_manager.CreateWorkspaceChannelManager[account_update_entity.AccountUpdate]()
+)
+
+// PropagateAccountUpdate broadcasts an account update to the workspace and
+// sends it to the webhooks of its event.
+func PropagateAccountUpdate(update account_update_entity.AccountUpdate) {
+	if update.WorkspaceID != nil {
+		go AccountUpdateWorkspaceManager.BroadcastToWorkspace(*update.WorkspaceID, update)
+	}
+	if event, ok := webhook_event_model.AccountUpdateEvent(update.Field); ok {
+		go webhook_service.SendEvent(event, update.WorkspaceID, update)
+	}
+}
+
+// AccountUpdateSubscription upgrades the connection to WebSocket and streams account updates.
+//
+//	@Summary		Subscribe to account updates
+//	@Description	Establishes a WebSocket connection and streams the template status, phone number quality, account, alert and capability notifications Meta sends for the phone configs of a specific workspace.
+//	@Tags			Account Update Websocket
+//	@Accept			json
+//	@Produce		json
+//	@Param			workspace_id	query		string							false	"Workspace ID (alternative to header)"
+//	@Success		101				{string}	string							"WebSocket connection established"
+//	@Failure		400				{object}	common_model.DescriptiveError	"Invalid connection request"
+//	@Failure		500				{object}	common_model.DescriptiveError	"Internal server error"
+//	@Security		ApiKeyAuth
+//	@Security		WorkspaceAuth
+//	@Router			/websocket/account-update/event [get]
+func AccountUpdateSubscription(ctx *websocket.Conn) {
+	defer ctx.Close()
+
+	// Registering user and workspace
+	user := ctx.Locals("user").(*user_entity.User)                     // This must be paired with the UserMiddleware. Otherwise will panic.
+	workspace := ctx.Locals("workspace").(*workspace_entity.Workspace) // This must be paired with the WebSocketWorkspaceMiddleware. Otherwise will panic.
+
+	clientID := accountUpdateClientPool.CreateID(user.ID)
+	client := websocket_model.Client[websocket_model.ClientID]{
+		Connection: ctx,
+		Data:       *clientID,
+	}
+	AccountUpdateWorkspaceManager.AppendClient(workspace.ID, client, clientID.String())
+
+	// Configuring disconnection
+	defer func() {
+		var deleteWg sync.WaitGroup
+
+		deleteWg.Go(func() {
+			accountUpdateClientPool.DeleteID(*clientID)
+		})
+
+		deleteWg.Go(func() {
+			AccountUpdateWorkspaceManager.RemoveClient(workspace.ID, client.Data.String())
+		})
+
+		deleteWg.Wait()
+	}()
+
+	for {
+		// Read message from WebSocket
+		msgType, data, err := ctx.ReadMessage()
+		if err != nil {
+			break // connection closed or other error
+		}
+
+		// Only handle text frames; ignore others
+		if msgType == websocket.TextMessage && string(data) == string(websocket_model.Ping) {
+			if writeErr := ctx.WriteMessage(websocket.TextMessage, []byte(websocket_model.Pong)); writeErr != nil {
+				break // stop if the write fails
+			}
+		}
+	}
+}
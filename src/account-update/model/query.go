@@ -0,0 +1,30 @@
+package account_update_model
+
+import (
+	database_model "github.com/Astervia/wacraft-core/src/database/model"
+	wh_model "github.com/Rfluid/whatsapp-cloud-api/src/webhook"
+	"github.com/google/uuid"
+)
+
+// UpdateQuery filters the account updates listing.
+type UpdateQuery struct {
+	PhoneConfigID *uuid.UUID     `json:"phone_config_id,omitempty" query:"phone_config_id"`
+	Field         wh_model.Field `json:"field,omitempty" query:"field" validate:"omitempty,oneof=message_template_status_update phone_number_quality_update account_update account_alerts business_capability_update"`
+
+	database_model.Paginate
+	database_model.DateWhere
+}
+
+// TemplateStatusQuery filters the template statuses listing.
+type TemplateStatusQuery struct {
+	PhoneConfigID *uuid.UUID `json:"phone_config_id,omitempty" query:"phone_config_id"`
+	Name          string     `json:"name,omitempty" query:"name"`
+	Status        string     `json:"status,omitempty" query:"status"`
+
+	database_model.Paginate
+}
+
+// HealthQuery filters the phone number health listing.
+type HealthQuery struct {
+	PhoneConfigID *uuid.UUID `json:"phone_config_id,omitempty" query:"phone_config_id"`
+}
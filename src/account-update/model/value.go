@@ -0,0 +1,87 @@
+package account_update_model
+
+import (
+	"encoding/json"
+	"strings"
+
+	account_update_entity "github.com/Astervia/wacraft-server/src/account-update/entity"
+)
+
+// Values of the account level webhook fields. The WhatsApp webhook model only
+// parses the messages field, so these are read from the raw change value.
+//
+// https://developers.facebook.com/docs/graph-api/webhooks/reference/whatsapp-business-account/
+
+// TemplateStatusValue is the value of message_template_status_update.
+type TemplateStatusValue struct {
+	Event                   string      `json:"event"`
+	MessageTemplateID       json.Number `json:"message_template_id"`
+	MessageTemplateName     string      `json:"message_template_name"`
+	MessageTemplateLanguage string      `json:"message_template_language"`
+	Reason                  string      `json:"reason,omitempty"`
+	OtherInfo               *struct {
+		Title       string `json:"title"`
+		Description string `json:"description"`
+	} `json:"other_info,omitempty"`
+}
+
+// PhoneNumberQualityValue is the value of phone_number_quality_update.
+type PhoneNumberQualityValue struct {
+	DisplayPhoneNumber string `json:"display_phone_number"`
+	Event              string `json:"event"`
+	CurrentLimit       string `json:"current_limit,omitempty"`
+	OldLimit           string `json:"old_limit,omitempty"`
+}
+
+// AccountUpdateValue is the value of account_update.
+type AccountUpdateValue struct {
+	PhoneNumber string `json:"phone_number,omitempty"`
+	Event       string `json:"event"`
+	BanInfo     *struct {
+		WabaBanState StringList `json:"waba_ban_state"`
+		WabaBanDate  string     `json:"waba_ban_date"`
+	} `json:"ban_info,omitempty"`
+	RestrictionInfo []account_update_entity.Restriction `json:"restriction_info,omitempty"`
+	ViolationInfo   *struct {
+		ViolationType string `json:"violation_type"`
+	} `json:"violation_info,omitempty"`
+}
+
+// AccountAlertValue is the value of account_alerts.
+type AccountAlertValue struct {
+	EntityType       string `json:"entity_type"`
+	EntityID         string `json:"entity_id"`
+	AlertSeverity    string `json:"alert_severity"`
+	AlertStatus      string `json:"alert_status"`
+	AlertType        string `json:"alert_type"`
+	AlertDescription string `json:"alert_description"`
+}
+
+// BusinessCapabilityValue is the value of business_capability_update.
+type BusinessCapabilityValue struct {
+	MaxDailyConversationPerPhone *int `json:"max_daily_conversation_per_phone,omitempty"`
+	MaxPhoneNumbersPerBusiness   *int `json:"max_phone_numbers_per_business,omitempty"`
+	MaxPhoneNumbersPerWaba       *int `json:"max_phone_numbers_per_waba,omitempty"`
+}
+
+// StringList is a list Meta sends either as an array or as a single string.
+type StringList []string
+
+func (l *StringList) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*l = StringList{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*l = list
+	return nil
+}
+
+// String joins the list with commas.
+func (l StringList) String() string {
+	return strings.Join(l, ",")
+}
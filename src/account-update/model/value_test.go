@@ -0,0 +1,33 @@
+package account_update_model
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestStringList(t *testing.T) {
+	cases := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"single string", `"DISABLE"`, "DISABLE"},
+		{"array", `["SCHEDULE_FOR_DISABLE", "DISABLE"]`, "SCHEDULE_FOR_DISABLE,DISABLE"},
+		{"empty array", `[]`, ""},
+	}
+	for _, c := range cases {
+		var list StringList
+		if err := json.Unmarshal([]byte(c.body), &list); err != nil {
+			t.Errorf("%s: unexpected error: %v", c.name, err)
+			continue
+		}
+		if got := list.String(); got != c.want {
+			t.Errorf("%s: expected %q, got %q", c.name, c.want, got)
+		}
+	}
+
+	var list StringList
+	if err := json.Unmarshal([]byte(`{"state": "DISABLE"}`), &list); err == nil {
+		t.Errorf("expected an error for an object, got %v", list)
+	}
+}
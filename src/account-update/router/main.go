@@ -0,0 +1,36 @@
+package account_update_router
+
+import (
+	workspace_model "github.com/Astervia/wacraft-core/src/workspace/model"
+	account_update_handler "github.com/Astervia/wacraft-server/src/account-update/handler"
+	auth_middleware "github.com/Astervia/wacraft-server/src/auth/middleware"
+	billing_middleware "github.com/Astervia/wacraft-server/src/billing/middleware"
+	workspace_middleware "github.com/Astervia/wacraft-server/src/workspace/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+func Route(app *fiber.App) {
+	group := app.Group("/account-update")
+
+	group.Get("/",
+		auth_middleware.UserMiddleware,
+		auth_middleware.EmailVerifiedMiddleware,
+		workspace_middleware.WorkspaceMiddleware,
+		workspace_middleware.RequirePolicy(workspace_model.PolicyPhoneConfigRead),
+		billing_middleware.ThroughputMiddleware,
+		account_update_handler.GetUpdates)
+	group.Get("/template-status",
+		auth_middleware.UserMiddleware,
+		auth_middleware.EmailVerifiedMiddleware,
+		workspace_middleware.WorkspaceMiddleware,
+		workspace_middleware.RequirePolicy(workspace_model.PolicyPhoneConfigRead),
+		billing_middleware.ThroughputMiddleware,
+		account_update_handler.GetTemplateStatuses)
+	group.Get("/phone-number-health",
+		auth_middleware.UserMiddleware,
+		auth_middleware.EmailVerifiedMiddleware,
+		workspace_middleware.WorkspaceMiddleware,
+		workspace_middleware.RequirePolicy(workspace_model.PolicyPhoneConfigRead),
+		billing_middleware.ThroughputMiddleware,
+		account_update_handler.GetHealth)
+}
@@ -0,0 +1,70 @@
+package account_update_service
+
+import (
+	account_update_entity "github.com/Astervia/wacraft-server/src/account-update/entity"
+	account_update_model "github.com/Astervia/wacraft-server/src/account-update/model"
+	"github.com/Astervia/wacraft-server/src/database"
+	database_cursor "github.com/Astervia/wacraft-server/src/database/cursor"
+	"github.com/google/uuid"
+)
+
+// GetUpdates lists the account updates of the workspace, newest first.
+func GetUpdates(query account_update_model.UpdateQuery, workspaceID uuid.UUID) ([]account_update_entity.AccountUpdate, error) {
+	db := database.DB.Model(&account_update_entity.AccountUpdate{}).
+		Where("account_updates.workspace_id = ?", workspaceID)
+
+	if query.PhoneConfigID != nil {
+		db = db.Where("account_updates.phone_config_id = ?", *query.PhoneConfigID)
+	}
+	if query.Field != "" {
+		db = db.Where("account_updates.field = ?", query.Field)
+	}
+	query.DateWhere.Where(&db, `"account_updates"`)
+
+	updates := []account_update_entity.AccountUpdate{}
+	err := db.
+		Order("account_updates.created_at DESC, account_updates.id DESC").
+		Offset(query.Offset).
+		Limit(database_cursor.Limit(query.Limit)).
+		Find(&updates).Error
+	return updates, err
+}
+
+// GetTemplateStatuses lists the template statuses of the workspace, last
+// updated first.
+func GetTemplateStatuses(query account_update_model.TemplateStatusQuery, workspaceID uuid.UUID) ([]account_update_entity.TemplateStatus, error) {
+	db := database.DB.Model(&account_update_entity.TemplateStatus{}).
+		Where("template_statuses.workspace_id = ?", workspaceID)
+
+	if query.PhoneConfigID != nil {
+		db = db.Where("template_statuses.phone_config_id = ?", *query.PhoneConfigID)
+	}
+	if query.Name != "" {
+		db = db.Where("template_statuses.name = ?", query.Name)
+	}
+	if query.Status != "" {
+		db = db.Where("template_statuses.status = ?", query.Status)
+	}
+
+	statuses := []account_update_entity.TemplateStatus{}
+	err := db.
+		Order("template_statuses.updated_at DESC, template_statuses.id DESC").
+		Offset(query.Offset).
+		Limit(database_cursor.Limit(query.Limit)).
+		Find(&statuses).Error
+	return statuses, err
+}
+
+// GetHealth lists the phone number health of the phone configs of the workspace.
+func GetHealth(query account_update_model.HealthQuery, workspaceID uuid.UUID) ([]account_update_entity.PhoneNumberHealth, error) {
+	db := database.DB.Model(&account_update_entity.PhoneNumberHealth{}).
+		Where("phone_number_health.workspace_id = ?", workspaceID)
+
+	if query.PhoneConfigID != nil {
+		db = db.Where("phone_number_health.phone_config_id = ?", *query.PhoneConfigID)
+	}
+
+	health := []account_update_entity.PhoneNumberHealth{}
+	err := db.Order("phone_number_health.created_at ASC").Find(&health).Error
+	return health, err
+}
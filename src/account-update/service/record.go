@@ -0,0 +1,224 @@
+package account_update_service
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	phone_config_entity "github.com/Astervia/wacraft-core/src/phone-config/entity"
+	account_update_entity "github.com/Astervia/wacraft-server/src/account-update/entity"
+	account_update_model "github.com/Astervia/wacraft-server/src/account-update/model"
+	"github.com/Astervia/wacraft-server/src/database"
+	wh_model "github.com/Rfluid/whatsapp-cloud-api/src/webhook"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Fields are the account level webhook fields recorded as account updates.
+var Fields = []wh_model.Field{
+	wh_model.MessageTemplateStatusUpdate,
+	wh_model.PhoneNumberQualityUpdate,
+	wh_model.AccountUpdate,
+	wh_model.AccountAlerts,
+	wh_model.BusinessCapabilityUpdate,
+}
+
+// Change is a change of an account level field read from a webhook.
+type Change struct {
+	Field wh_model.Field
+	// Value is the raw value of the change.
+	Value json.RawMessage
+	// Key identifies the change across deliveries of the webhook, e.g. the
+	// digest of the webhook body and the index of the change in it.
+	Key string
+	// ReportedAt is when Meta reported the change, the time of its entry.
+	ReportedAt time.Time
+}
+
+// Record stores the account level changes of a webhook received for the
+// phone config in one transaction, and applies them to the template statuses
+// and the phone number health. Changes whose key was already recorded, e.g.
+// because Meta delivered the webhook again, are skipped. Returns the recorded
+// updates.
+func Record(phoneConfig phone_config_entity.PhoneConfig, changes []Change) ([]account_update_entity.AccountUpdate, error) {
+	updates := make([]account_update_entity.AccountUpdate, 0, len(changes))
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		for _, change := range changes {
+			key := change.Key
+			update := account_update_entity.AccountUpdate{
+				PhoneConfigID: phoneConfig.ID,
+				WorkspaceID:   phoneConfig.WorkspaceID,
+				WabaID:        phoneConfig.WabaID,
+				Field:         change.Field,
+				ChangeKey:     &key,
+			}
+			if err := json.Unmarshal(change.Value, &update.Value); err != nil {
+				return err
+			}
+
+			result := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "phone_config_id"}, {Name: "change_key"}},
+				DoNothing: true,
+			}).Create(&update)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				continue // Already recorded
+			}
+
+			event, err := apply(phoneConfig, change, tx)
+			if err != nil {
+				return err
+			}
+			if event != "" {
+				update.Event = event
+				if err := tx.Model(&update).UpdateColumn("event", event).Error; err != nil {
+					return err
+				}
+			}
+			updates = append(updates, update)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updates, nil
+}
+
+// apply updates the state the change describes and returns its event. State
+// reported after the change is kept, so late deliveries never roll it back.
+func apply(phoneConfig phone_config_entity.PhoneConfig, change Change, tx *gorm.DB) (string, error) {
+	value := change.Value
+	reportedAt := change.ReportedAt
+	switch change.Field {
+	case wh_model.MessageTemplateStatusUpdate:
+		var v account_update_model.TemplateStatusValue
+		if err := json.Unmarshal(value, &v); err != nil {
+			return "", err
+		}
+		return v.Event, applyTemplateStatus(phoneConfig, v, reportedAt, tx)
+
+	case wh_model.PhoneNumberQualityUpdate:
+		var v account_update_model.PhoneNumberQualityValue
+		if err := json.Unmarshal(value, &v); err != nil {
+			return "", err
+		}
+		health := account_update_entity.PhoneNumberHealth{
+			DisplayPhoneNumber: v.DisplayPhoneNumber,
+			QualityEvent:       v.Event,
+			MessagingLimitTier: v.CurrentLimit,
+			QualityReportedAt:  &reportedAt,
+		}
+		columns := []string{"display_phone_number", "quality_event"}
+		if v.CurrentLimit != "" {
+			columns = append(columns, "messaging_limit_tier")
+		}
+		return v.Event, updateHealth(phoneConfig, health, columns, "quality_reported_at", tx)
+
+	case wh_model.AccountUpdate:
+		var v account_update_model.AccountUpdateValue
+		if err := json.Unmarshal(value, &v); err != nil {
+			return "", err
+		}
+		health := account_update_entity.PhoneNumberHealth{AccountEvent: v.Event, AccountReportedAt: &reportedAt}
+		columns := []string{"account_event"}
+		if v.BanInfo != nil {
+			health.BanState = v.BanInfo.WabaBanState.String()
+			health.BanDate = v.BanInfo.WabaBanDate
+			columns = append(columns, "ban_state", "ban_date")
+		}
+		if v.RestrictionInfo != nil {
+			health.Restrictions = v.RestrictionInfo
+			columns = append(columns, "restrictions")
+		}
+		if v.ViolationInfo != nil {
+			health.ViolationType = v.ViolationInfo.ViolationType
+			columns = append(columns, "violation_type")
+		}
+		return v.Event, updateHealth(phoneConfig, health, columns, "account_reported_at", tx)
+
+	case wh_model.AccountAlerts:
+		var v account_update_model.AccountAlertValue
+		if err := json.Unmarshal(value, &v); err != nil {
+			return "", err
+		}
+		return v.AlertType, nil
+
+	case wh_model.BusinessCapabilityUpdate:
+		var v account_update_model.BusinessCapabilityValue
+		if err := json.Unmarshal(value, &v); err != nil {
+			return "", err
+		}
+		health := account_update_entity.PhoneNumberHealth{
+			MaxDailyConversations:      v.MaxDailyConversationPerPhone,
+			MaxPhoneNumbersPerBusiness: v.MaxPhoneNumbersPerBusiness,
+			MaxPhoneNumbersPerWaba:     v.MaxPhoneNumbersPerWaba,
+			CapabilityReportedAt:       &reportedAt,
+		}
+		var columns []string
+		if v.MaxDailyConversationPerPhone != nil {
+			columns = append(columns, "max_daily_conversations")
+		}
+		if v.MaxPhoneNumbersPerBusiness != nil {
+			columns = append(columns, "max_phone_numbers_per_business")
+		}
+		if v.MaxPhoneNumbersPerWaba != nil {
+			columns = append(columns, "max_phone_numbers_per_waba")
+		}
+		return "", updateHealth(phoneConfig, health, columns, "capability_reported_at", tx)
+	}
+	return "", nil
+}
+
+// applyTemplateStatus stores the status of the template unless a later one
+// is stored.
+func applyTemplateStatus(phoneConfig phone_config_entity.PhoneConfig, v account_update_model.TemplateStatusValue, reportedAt time.Time, tx *gorm.DB) error {
+	status := account_update_entity.TemplateStatus{
+		PhoneConfigID: phoneConfig.ID,
+		WorkspaceID:   phoneConfig.WorkspaceID,
+		TemplateID:    v.MessageTemplateID.String(),
+		Name:          v.MessageTemplateName,
+		Language:      v.MessageTemplateLanguage,
+		Status:        v.Event,
+		Reason:        v.Reason,
+		ReportedAt:    &reportedAt,
+	}
+	if v.OtherInfo != nil {
+		status.Title = v.OtherInfo.Title
+		status.Description = v.OtherInfo.Description
+	}
+
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "phone_config_id"}, {Name: "template_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "language", "status", "reason", "title", "description", "reported_at", "updated_at"}),
+		Where:     reportedLater("template_statuses", "reported_at"),
+	}).Create(&status).Error
+}
+
+// updateHealth sets the columns of the phone number health of the phone
+// config, creating it on the first change. Other columns keep their value.
+// reportedAtColumn is when the field of the columns was last reported; the
+// columns are only set when the change is not older.
+func updateHealth(phoneConfig phone_config_entity.PhoneConfig, health account_update_entity.PhoneNumberHealth, columns []string, reportedAtColumn string, tx *gorm.DB) error {
+	health.PhoneConfigID = phoneConfig.ID
+	health.WorkspaceID = phoneConfig.WorkspaceID
+	if health.Restrictions == nil {
+		health.Restrictions = []account_update_entity.Restriction{}
+	}
+
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "phone_config_id"}},
+		DoUpdates: clause.AssignmentColumns(append(columns, reportedAtColumn, "updated_at")),
+		Where:     reportedLater("phone_number_health", reportedAtColumn),
+	}).Create(&health).Error
+}
+
+// reportedLater conditions an upsert to changes reported after the stored
+// state, or at the same time.
+func reportedLater(table string, column string) clause.Where {
+	return clause.Where{Exprs: []clause.Expression{clause.Expr{
+		SQL: fmt.Sprintf("%[1]s.%[2]s IS NULL OR %[1]s.%[2]s <= EXCLUDED.%[2]s", table, column),
+	}}}
+}
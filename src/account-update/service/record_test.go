@@ -0,0 +1,173 @@
+package account_update_service
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	phone_config_entity "github.com/Astervia/wacraft-core/src/phone-config/entity"
+	account_update_entity "github.com/Astervia/wacraft-server/src/account-update/entity"
+	"github.com/Astervia/wacraft-server/src/database"
+	database_fixture "github.com/Astervia/wacraft-server/src/database/fixture"
+	wh_model "github.com/Rfluid/whatsapp-cloud-api/src/webhook"
+	"github.com/google/uuid"
+)
+
+func TestMain(m *testing.M) {
+	database.DB.AutoMigrate(
+		&account_update_entity.AccountUpdate{},
+		&account_update_entity.TemplateStatus{},
+		&account_update_entity.PhoneNumberHealth{},
+	)
+	// Tests use random phone config IDs.
+	database_fixture.DropForeignKeys("account_updates", "fk_account_updates_phone_config")
+	database_fixture.DropForeignKeys("template_statuses", "fk_template_statuses_phone_config")
+	database_fixture.DropForeignKeys("phone_number_health", "fk_phone_number_health_phone_config")
+	os.Exit(m.Run())
+}
+
+func newTestPhoneConfig(t *testing.T) phone_config_entity.PhoneConfig {
+	t.Helper()
+	workspaceID := uuid.New()
+	phoneConfig := phone_config_entity.PhoneConfig{WabaID: "waba-" + uuid.NewString()}
+	phoneConfig.ID = uuid.New()
+	phoneConfig.WorkspaceID = &workspaceID
+	t.Cleanup(func() {
+		database.DB.Exec("DELETE FROM account_updates WHERE phone_config_id = ?", phoneConfig.ID)
+		database.DB.Exec("DELETE FROM template_statuses WHERE phone_config_id = ?", phoneConfig.ID)
+		database.DB.Exec("DELETE FROM phone_number_health WHERE phone_config_id = ?", phoneConfig.ID)
+	})
+	return phoneConfig
+}
+
+func change(field wh_model.Field, value string, reportedAt time.Time) Change {
+	return Change{Field: field, Value: json.RawMessage(value), Key: uuid.NewString(), ReportedAt: reportedAt}
+}
+
+func record(t *testing.T, phoneConfig phone_config_entity.PhoneConfig, changes ...Change) []account_update_entity.AccountUpdate {
+	t.Helper()
+	updates, err := Record(phoneConfig, changes)
+	if err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	return updates
+}
+
+func getHealth(t *testing.T, phoneConfig phone_config_entity.PhoneConfig) account_update_entity.PhoneNumberHealth {
+	t.Helper()
+	var health account_update_entity.PhoneNumberHealth
+	if err := database.DB.Where("phone_config_id = ?", phoneConfig.ID).Take(&health).Error; err != nil {
+		t.Fatalf("get health: %v", err)
+	}
+	return health
+}
+
+func TestRecord_SkipsRecordedChanges(t *testing.T) {
+	phoneConfig := newTestPhoneConfig(t)
+	now := time.Now()
+	approved := change(wh_model.MessageTemplateStatusUpdate, `{"event": "APPROVED", "message_template_id": 1}`, now)
+	alert := change(wh_model.AccountAlerts, `{"alert_type": "OBA_APPROVED"}`, now)
+
+	updates := record(t, phoneConfig, approved, alert)
+	if len(updates) != 2 || updates[0].Event != "APPROVED" || updates[1].Event != "OBA_APPROVED" {
+		t.Fatalf("expected both updates with their events, got %+v", updates)
+	}
+
+	// Meta delivers the webhook again: its changes are already recorded.
+	if updates := record(t, phoneConfig, approved, alert); len(updates) != 0 {
+		t.Errorf("expected no updates on redelivery, got %d", len(updates))
+	}
+	var count int64
+	database.DB.Model(&account_update_entity.AccountUpdate{}).Where("phone_config_id = ?", phoneConfig.ID).Count(&count)
+	if count != 2 {
+		t.Errorf("expected 2 stored updates, got %d", count)
+	}
+}
+
+func TestRecord_InvalidChangeRecordsNothing(t *testing.T) {
+	phoneConfig := newTestPhoneConfig(t)
+	now := time.Now()
+	valid := change(wh_model.AccountAlerts, `{"alert_type": "OBA_APPROVED"}`, now)
+	invalid := change(wh_model.PhoneNumberQualityUpdate, `{"event": 1}`, now)
+
+	if _, err := Record(phoneConfig, []Change{valid, invalid}); err == nil {
+		t.Fatal("expected an error for an invalid change")
+	}
+	var count int64
+	database.DB.Model(&account_update_entity.AccountUpdate{}).Where("phone_config_id = ?", phoneConfig.ID).Count(&count)
+	if count != 0 {
+		t.Errorf("expected the webhook to be recorded in one transaction, got %d updates", count)
+	}
+}
+
+func TestApply_TemplateStatusKeepsTheLatestReport(t *testing.T) {
+	phoneConfig := newTestPhoneConfig(t)
+	now := time.Now()
+	record(t, phoneConfig,
+		change(wh_model.MessageTemplateStatusUpdate, `{"event": "APPROVED", "message_template_id": 1, "message_template_name": "welcome"}`, now),
+		// A late delivery of an earlier report must not roll the status back.
+		change(wh_model.MessageTemplateStatusUpdate, `{"event": "PENDING", "message_template_id": 1, "message_template_name": "welcome"}`, now.Add(-time.Minute)),
+	)
+
+	var status account_update_entity.TemplateStatus
+	if err := database.DB.Where("phone_config_id = ? AND template_id = ?", phoneConfig.ID, "1").Take(&status).Error; err != nil {
+		t.Fatalf("get template status: %v", err)
+	}
+	if status.Status != "APPROVED" || status.Name != "welcome" {
+		t.Errorf("expected the template to stay APPROVED, got %s", status.Status)
+	}
+
+	record(t, phoneConfig, change(wh_model.MessageTemplateStatusUpdate, `{"event": "REJECTED", "message_template_id": 1, "reason": "INCORRECT_CATEGORY"}`, now.Add(time.Minute)))
+	database.DB.Where("phone_config_id = ? AND template_id = ?", phoneConfig.ID, "1").Take(&status)
+	if status.Status != "REJECTED" || status.Reason != "INCORRECT_CATEGORY" {
+		t.Errorf("expected the later REJECTED status, got %s %s", status.Status, status.Reason)
+	}
+}
+
+func TestApply_HealthFieldsKeepTheirLatestReport(t *testing.T) {
+	phoneConfig := newTestPhoneConfig(t)
+	now := time.Now()
+	record(t, phoneConfig,
+		change(wh_model.PhoneNumberQualityUpdate, `{"display_phone_number": "15550000000", "event": "UPGRADE", "current_limit": "TIER_10K"}`, now),
+		change(wh_model.BusinessCapabilityUpdate, `{"max_daily_conversation_per_phone": 1000}`, now),
+	)
+
+	// An older quality report is ignored, while an account report of the same
+	// time is applied: each field is guarded by its own report time.
+	record(t, phoneConfig,
+		change(wh_model.PhoneNumberQualityUpdate, `{"event": "DOWNGRADE", "current_limit": "TIER_1K"}`, now.Add(-time.Minute)),
+		change(wh_model.AccountUpdate, `{"event": "ACCOUNT_RESTRICTION", "restriction_info": [{"restriction_type": "RESTRICTED_BIZ_INITIATED_MESSAGING"}]}`, now.Add(-time.Minute)),
+	)
+
+	health := getHealth(t, phoneConfig)
+	if health.QualityEvent != "UPGRADE" || health.MessagingLimitTier != "TIER_10K" {
+		t.Errorf("expected the later quality report, got %s %s", health.QualityEvent, health.MessagingLimitTier)
+	}
+	if health.DisplayPhoneNumber != "15550000000" {
+		t.Errorf("expected the display phone number to be kept, got %q", health.DisplayPhoneNumber)
+	}
+	if health.MaxDailyConversations == nil || *health.MaxDailyConversations != 1000 {
+		t.Errorf("expected the capabilities to be kept, got %v", health.MaxDailyConversations)
+	}
+	if health.AccountEvent != "ACCOUNT_RESTRICTION" || len(health.Restrictions) != 1 {
+		t.Errorf("expected the account restriction, got %s %+v", health.AccountEvent, health.Restrictions)
+	}
+}
+
+func TestApply_AccountBanInfo(t *testing.T) {
+	phoneConfig := newTestPhoneConfig(t)
+	updates := record(t, phoneConfig, change(
+		wh_model.AccountUpdate,
+		`{"event": "DISABLED_UPDATE", "ban_info": {"waba_ban_state": ["SCHEDULE_FOR_DISABLE", "DISABLE"], "waba_ban_date": "2026-10-20"}}`,
+		time.Now(),
+	))
+	if len(updates) != 1 || updates[0].Event != "DISABLED_UPDATE" {
+		t.Fatalf("expected the DISABLED_UPDATE update, got %+v", updates)
+	}
+
+	health := getHealth(t, phoneConfig)
+	if health.BanState != "SCHEDULE_FOR_DISABLE,DISABLE" || health.BanDate != "2026-10-20" {
+		t.Errorf("expected the ban info, got %q %q", health.BanState, health.BanDate)
+	}
+}
@@ -0,0 +1,17 @@
+package account_update_websocket
+
+import (
+	account_update_handler "github.com/Astervia/wacraft-server/src/account-update/handler"
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+)
+
+func Route(app fiber.Router) {
+	group := app.Group("/account-update")
+
+	// This route must handle the registering, broadcasting, and unregistering of the connections.
+	group.Get(
+		"/event",
+		websocket.New(account_update_handler.AccountUpdateSubscription),
+	)
+}
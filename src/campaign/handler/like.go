@@ -16,6 +16,7 @@ import (
 //
 //	@Summary		Search campaigns with regex-like operator
 //	@Description	Applies a ILIKE filter on the specified key field and returns paginated results.
+//	@Description	Deprecated: use POST /campaign/search with an ilike condition.
 //	@Tags			Campaign
 //	@Accept			json
 //	@Produce		json
@@ -26,6 +27,7 @@ import (
 //	@Failure		500			{object}	common_model.DescriptiveError		"Internal server error"
 //	@Security		ApiKeyAuth
 //	@Security		WorkspaceAuth
+//	@Deprecated
 //	@Router			/campaign/content/{keyName}/like/{likeText} [get]
 func ContentKeyLike(c *fiber.Ctx) error {
 	workspace := workspace_middleware.GetWorkspace(c)
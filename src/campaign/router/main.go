@@ -53,6 +53,13 @@ func mainRoutes(group fiber.Router) {
 		workspace_middleware.RequirePolicy(workspace_model.PolicyCampaignRead),
 		billing_middleware.ThroughputMiddleware,
 		campaign_handler.ContentKeyLike)
+	group.Post("/search",
+		auth_middleware.UserMiddleware,
+		auth_middleware.EmailVerifiedMiddleware,
+		workspace_middleware.WorkspaceMiddleware,
+		workspace_middleware.RequirePolicy(workspace_model.PolicyCampaignRead),
+		billing_middleware.ThroughputMiddleware,
+		campaign_handler.SearchByFilter)
 	group.Post("/schedule",
 		auth_middleware.UserMiddleware,
 		auth_middleware.EmailVerifiedMiddleware,
@@ -0,0 +1,31 @@
+package campaign_service
+
+import (
+	campaign_entity "github.com/Astervia/wacraft-core/src/campaign/entity"
+	"github.com/Astervia/wacraft-server/src/database"
+	database_filter "github.com/Astervia/wacraft-server/src/database/filter"
+	"gorm.io/gorm"
+)
+
+// FilterSchema whitelists the fields usable in campaign filters.
+var FilterSchema = database_filter.Schema{
+	Table: "campaigns",
+	Fields: map[string]database_filter.Field{
+		"id":                   {Column: "campaigns.id", Type: database_filter.UUID},
+		"created_at":           {Column: "campaigns.created_at", Type: database_filter.Time},
+		"updated_at":           {Column: "campaigns.updated_at", Type: database_filter.Time},
+		"name":                 {Column: "campaigns.name", Type: database_filter.String},
+		"status":               {Column: "campaigns.status", Type: database_filter.String},
+		"scheduled_at":         {Column: "campaigns.scheduled_at", Type: database_filter.Time},
+		"messaging_product_id": {Column: "campaigns.messaging_product_id", Type: database_filter.UUID},
+	},
+}
+
+// SearchByFilter returns the campaigns matching the search.
+func SearchByFilter(search database_filter.Search, db *gorm.DB) ([]campaign_entity.Campaign, error) {
+	if db == nil {
+		db = database.DB
+	}
+
+	return database_filter.Find[campaign_entity.Campaign](search, FilterSchema, db.Model(&campaign_entity.Campaign{}))
+}
@@ -17,10 +17,12 @@ import (
 	webhook_entity "github.com/Astervia/wacraft-core/src/webhook/entity"
 	webhook_model "github.com/Astervia/wacraft-core/src/webhook/model"
 	"github.com/Astervia/wacraft-server/src/database"
+	message_thread_service "github.com/Astervia/wacraft-server/src/message-thread/service"
 	message_handler "github.com/Astervia/wacraft-server/src/message/handler"
 	message_service "github.com/Astervia/wacraft-server/src/message/service"
 	messaging_product_service "github.com/Astervia/wacraft-server/src/messaging-product/service"
 	phone_config_service "github.com/Astervia/wacraft-server/src/phone-config/service"
+	webhook_event_model "github.com/Astervia/wacraft-server/src/webhook-event/model"
 	webhook_service "github.com/Astervia/wacraft-server/src/webhook/service"
 
 	bootstrap_module "github.com/Rfluid/whatsapp-cloud-api/src/bootstrap"
@@ -28,11 +30,14 @@ import (
 	"gorm.io/gorm"
 )
 
+// SendWhatsAppCampaign sends the unsent messages of a campaign. The
+// campaign.started event is sent once the campaign is loaded, then
+// campaign.completed or campaign.failed when it returns.
 func SendWhatsAppCampaign(
 	campaignID uuid.UUID,
 	campaignChannel campaign_model.CampaignChannel,
 	callback func(*campaign_model.CampaignResults),
-) (campaign_model.CampaignResults, error) {
+) (results campaign_model.CampaignResults, err error) {
 	if campaignChannel.IsSending() {
 		return campaign_model.CampaignResults{}, errors.New("campaign is already sending")
 	}
@@ -56,6 +61,17 @@ func SendWhatsAppCampaign(
 		return campaign_model.CampaignResults{}, err
 	}
 
+	go webhook_service.SendEvent(webhook_event_model.CampaignStarted, campaign.WorkspaceID, webhook_event_model.CampaignData{Campaign: campaign})
+	defer func() {
+		data := webhook_event_model.CampaignData{Campaign: campaign, Results: &results}
+		event := webhook_event_model.CampaignCompleted
+		if err != nil {
+			event = webhook_event_model.CampaignFailed
+			data.Error = err.Error()
+		}
+		go webhook_service.SendEvent(event, campaign.WorkspaceID, data)
+	}()
+
 	if campaign.MessagingProductID == nil {
 		return campaign_model.CampaignResults{}, errors.New("empty messaging product ID for campaign")
 	}
@@ -81,14 +97,15 @@ func SendWhatsAppCampaign(
 
 	// Create broadcast callback for workspace-scoped WebSocket broadcasting
 	broadcastCallback := func(msg message_entity.Message) {
+		payload := message_thread_service.Threaded(msg)
 		if campaign.WorkspaceID != nil {
-			go message_handler.NewMessageWorkspaceManager.BroadcastToWorkspace(*campaign.WorkspaceID, msg)
+			go message_handler.NewMessageWorkspaceManager.BroadcastToWorkspace(*campaign.WorkspaceID, payload)
 		}
 		go webhook_service.SendAllByQuery(
 			webhook_entity.Webhook{
 				Event: webhook_model.SendWhatsAppMessage,
 			},
-			msg,
+			payload,
 		)
 	}
 
@@ -211,11 +228,6 @@ func SendWhatsAppCampaignMessage(
 	if err != nil {
 		return err
 	}
-	// Propagating results
-	if broadcastCallback != nil {
-		broadcastCallback(msg)
-	}
-
 	campaignMessageUpdateData := campaign_entity.CampaignMessage{
 		MessageID: msg.ID,
 	}
@@ -230,6 +242,12 @@ func SendWhatsAppCampaignMessage(
 		return err
 	}
 
+	// Propagating results once the campaign message is linked to the sent
+	// message, so webhooks ordered by campaign can resolve its campaign.
+	if broadcastCallback != nil {
+		broadcastCallback(msg)
+	}
+
 	defer func() {
 		(*offset) = (*offset) - 1
 	}()
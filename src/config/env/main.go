@@ -18,6 +18,8 @@ func init() {
 	loadFirewallEnv()
 	loadBillingEnv()
 	loadRedisEnv()
+	loadWebhookEnv()
+	loadWebhookInEnv()
 }
 
 func loadEnv() {
@@ -0,0 +1,131 @@
+package env
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pterm/pterm"
+)
+
+var (
+	// WebhookDeliveryPollInterval is the longest the delivery worker sleeps
+	// without a wake-up. New deliveries wake it immediately, so this only
+	// bounds how late missed notifications and skipped deliveries are seen.
+	WebhookDeliveryPollInterval = 5 * time.Second
+	// WebhookDeliveryPoolSize is the max number of concurrent delivery attempts.
+	WebhookDeliveryPoolSize = 10
+	// WebhookDeliveryBatchSize is the max number of deliveries fetched per pass.
+	WebhookDeliveryBatchSize = 50
+	// WebhookDeliveryLease is how long a claimed delivery belongs to the
+	// worker that claimed it. Deliveries of a worker that crashed are claimed
+	// again once the lease expires, so it must exceed the time a whole batch
+	// takes to be attempted.
+	WebhookDeliveryLease = 5 * time.Minute
+	// WebhookSecretRotationGrace is how long the replaced signing secret keeps
+	// signing deliveries after a rotation that sets no grace period.
+	WebhookSecretRotationGrace = 24 * time.Hour
+	// WebhookCircuitRecoveryTimeout is how long the circuit breaker of
+	// wacraft-core keeps a tripped circuit open before letting a probe
+	// through. Core does not expose it, so it is read from the environment
+	// to report when the next probe is due and must match the core build.
+	WebhookCircuitRecoveryTimeout = 30 * time.Second
+
+	// WebhookAllowedSchemes are the URL schemes webhooks may use.
+	WebhookAllowedSchemes = []string{"http", "https"}
+	// WebhookAllowedPorts are the ports webhooks may use. Empty allows any port.
+	WebhookAllowedPorts []int
+	// WebhookBlockedCIDRs are blocked on top of the loopback, private,
+	// link-local and reserved ranges that are always blocked. An invalid
+	// range blocks every destination.
+	WebhookBlockedCIDRs []string
+	// WebhookAllowedDestinations are CIDRs, IP addresses or hostnames exempt
+	// from the blocked ranges, e.g. internal services operators trust.
+	// Hostnames starting with a dot match their subdomains.
+	WebhookAllowedDestinations []string
+	// WebhookMaxRedirects is how many redirects a delivery follows.
+	WebhookMaxRedirects = 3
+)
+
+func loadWebhookEnv() {
+	if val := os.Getenv("WEBHOOK_DELIVERY_POLL_INTERVAL"); val != "" {
+		if d, err := time.ParseDuration(val); err == nil && d > 0 {
+			WebhookDeliveryPollInterval = d
+		}
+	}
+
+	if val, err := strconv.Atoi(os.Getenv("WEBHOOK_DELIVERY_POOL_SIZE")); err == nil && val > 0 {
+		WebhookDeliveryPoolSize = val
+	}
+
+	if val, err := strconv.Atoi(os.Getenv("WEBHOOK_DELIVERY_BATCH_SIZE")); err == nil && val > 0 {
+		WebhookDeliveryBatchSize = val
+	}
+
+	if val := os.Getenv("WEBHOOK_DELIVERY_LEASE"); val != "" {
+		if d, err := time.ParseDuration(val); err == nil && d > 0 {
+			WebhookDeliveryLease = d
+		}
+	}
+
+	if val := os.Getenv("WEBHOOK_SECRET_ROTATION_GRACE"); val != "" {
+		if d, err := time.ParseDuration(val); err == nil && d >= 0 {
+			WebhookSecretRotationGrace = d
+		}
+	}
+
+	if val := os.Getenv("WEBHOOK_CIRCUIT_RECOVERY_TIMEOUT"); val != "" {
+		if d, err := time.ParseDuration(val); err == nil && d > 0 {
+			WebhookCircuitRecoveryTimeout = d
+		}
+	}
+
+	if val := parseWebhookList(os.Getenv("WEBHOOK_ALLOWED_SCHEMES")); len(val) > 0 {
+		WebhookAllowedSchemes = val
+	}
+
+	for _, port := range parseWebhookList(os.Getenv("WEBHOOK_ALLOWED_PORTS")) {
+		if n, err := strconv.Atoi(port); err == nil && n > 0 && n <= 65535 {
+			WebhookAllowedPorts = append(WebhookAllowedPorts, n)
+		} else {
+			pterm.DefaultLogger.Warn(fmt.Sprintf("Ignoring invalid webhook port %q", port))
+		}
+	}
+
+	// Invalid ranges are kept so the destination policy refuses every
+	// destination instead of silently leaving a range open.
+	WebhookBlockedCIDRs = parseWebhookList(os.Getenv("WEBHOOK_BLOCKED_CIDRS"))
+
+	for _, destination := range parseWebhookList(os.Getenv("WEBHOOK_ALLOWED_DESTINATIONS")) {
+		WebhookAllowedDestinations = append(WebhookAllowedDestinations, strings.ToLower(destination))
+	}
+
+	if val, err := strconv.Atoi(os.Getenv("WEBHOOK_MAX_REDIRECTS")); err == nil && val >= 0 {
+		WebhookMaxRedirects = val
+	}
+
+	if len(WebhookAllowedDestinations) > 0 {
+		pterm.DefaultLogger.Info(fmt.Sprintf("Webhook destination allowlist active: %d entries", len(WebhookAllowedDestinations)))
+	}
+
+	pterm.DefaultLogger.Info(
+		fmt.Sprintf(
+			"Webhook environment done with delivery poll interval %s, pool size %d, batch size %d, lease %s, secret rotation grace %s and circuit recovery timeout %s",
+			WebhookDeliveryPollInterval, WebhookDeliveryPoolSize, WebhookDeliveryBatchSize, WebhookDeliveryLease, WebhookSecretRotationGrace, WebhookCircuitRecoveryTimeout,
+		),
+	)
+}
+
+// parseWebhookList splits a comma separated WEBHOOK_* variable, dropping
+// blank entries.
+func parseWebhookList(val string) []string {
+	var result []string
+	for _, entry := range strings.Split(val, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
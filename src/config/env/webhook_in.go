@@ -0,0 +1,52 @@
+package env
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/pterm/pterm"
+)
+
+var (
+	// WebhookInAsync acknowledges webhooks received at /webhook-in/:waba_id
+	// once they are stored and processes them in the background.
+	WebhookInAsync = true
+	// WebhookInPoolSize is the max number of WABAs whose webhooks are
+	// processed concurrently. Webhooks of a WABA are processed one at a time.
+	WebhookInPoolSize = 10
+	// WebhookInQueueCapacity is the max number of webhooks waiting to be
+	// processed. Webhooks received while the queue is full are stored but
+	// answered with 503, so Meta sends them again.
+	WebhookInQueueCapacity = 10000
+	// InboundReceiptRetention is how long the receipts of inbound messages and
+	// statuses are kept to skip redeliveries. Meta retries a webhook for up to
+	// 7 days, so older receipts are pruned.
+	InboundReceiptRetention = 7 * 24 * time.Hour
+)
+
+func loadWebhookInEnv() {
+	WebhookInAsync = os.Getenv("WEBHOOK_IN_ASYNC") != "false"
+
+	if val, err := strconv.Atoi(os.Getenv("WEBHOOK_IN_POOL_SIZE")); err == nil && val > 0 {
+		WebhookInPoolSize = val
+	}
+
+	if val, err := strconv.Atoi(os.Getenv("WEBHOOK_IN_QUEUE_CAPACITY")); err == nil && val > 0 {
+		WebhookInQueueCapacity = val
+	}
+
+	if val := os.Getenv("INBOUND_RECEIPT_RETENTION"); val != "" {
+		if d, err := time.ParseDuration(val); err == nil && d > 0 {
+			InboundReceiptRetention = d
+		}
+	}
+
+	pterm.DefaultLogger.Info(
+		fmt.Sprintf(
+			"Webhook-in environment done with async %t, pool size %d and queue capacity %d and receipt retention %s",
+			WebhookInAsync, WebhookInPoolSize, WebhookInQueueCapacity, InboundReceiptRetention,
+		),
+	)
+}
@@ -17,6 +17,17 @@ var (
 	MetaAppSecret            string
 	MetaVerifyToken          string
 	MessageStatusSyncTimeout = 20 * time.Second
+
+	// MessageOutboxPollInterval is how often the outbox worker looks for
+	// asynchronously accepted messages that are due to be sent.
+	MessageOutboxPollInterval = 2 * time.Second
+	// MessageOutboxMaxAttempts is how many Graph API calls are made for an
+	// outbox entry before it is marked as failed.
+	MessageOutboxMaxAttempts = 5
+
+	// ConversationSnoozePollInterval is how often snoozed conversations whose
+	// snoozed_until has passed are moved back to open.
+	ConversationSnoozePollInterval = 30 * time.Second
 )
 
 func loadWhatsAppEnv() {
@@ -33,6 +44,22 @@ func loadWhatsAppEnv() {
 		MessageStatusSyncTimeout = time.Duration(timeoutSecToInt) * time.Second
 	}
 
+	if val := os.Getenv("MESSAGE_OUTBOX_POLL_INTERVAL"); val != "" {
+		if d, err := time.ParseDuration(val); err == nil && d > 0 {
+			MessageOutboxPollInterval = d
+		}
+	}
+
+	if val, err := strconv.Atoi(os.Getenv("MESSAGE_OUTBOX_MAX_ATTEMPTS")); err == nil && val > 0 {
+		MessageOutboxMaxAttempts = val
+	}
+
+	if val := os.Getenv("CONVERSATION_SNOOZE_POLL_INTERVAL"); val != "" {
+		if d, err := time.ParseDuration(val); err == nil && d > 0 {
+			ConversationSnoozePollInterval = d
+		}
+	}
+
 	pterm.DefaultLogger.Info(
 		fmt.Sprintf(
 			"WhatsApp environment done with waba id %s and message<=>status timeout %s seconds",
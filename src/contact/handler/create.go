@@ -7,6 +7,8 @@ import (
 	"github.com/Astervia/wacraft-core/src/repository"
 	"github.com/Astervia/wacraft-server/src/database"
 	"github.com/Astervia/wacraft-server/src/validators"
+	webhook_event_model "github.com/Astervia/wacraft-server/src/webhook-event/model"
+	webhook_service "github.com/Astervia/wacraft-server/src/webhook/service"
 	workspace_middleware "github.com/Astervia/wacraft-server/src/workspace/middleware"
 	"github.com/gofiber/fiber/v2"
 )
@@ -55,6 +57,8 @@ func CreateContact(c *fiber.Ctx) error {
 		)
 	}
 
+	go webhook_service.SendEvent(webhook_event_model.ContactCreated, &workspace.ID, contact)
+
 	return c.Status(fiber.StatusCreated).JSON(contact)
 }
 
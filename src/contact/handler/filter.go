@@ -0,0 +1,60 @@
+package contact_handler
+
+import (
+	"errors"
+
+	common_model "github.com/Astervia/wacraft-core/src/common/model"
+	contact_service "github.com/Astervia/wacraft-server/src/contact/service"
+	"github.com/Astervia/wacraft-server/src/database"
+	database_filter "github.com/Astervia/wacraft-server/src/database/filter"
+	"github.com/Astervia/wacraft-server/src/validators"
+	workspace_middleware "github.com/Astervia/wacraft-server/src/workspace/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+// SearchByFilter returns contacts matching a JSON filter.
+//
+//	@Summary		Search contacts with a filter
+//	@Description	Filters contacts with nested and/or/not groups of conditions. Operators: eq, neq, gt, gte, lt, lte, in, exists and ilike.
+//	@Description	Fields: id, created_at, updated_at, name, email and photo_path.
+//	@Tags			Contact
+//	@Accept			json
+//	@Produce		json
+//	@Param			search	body		database_filter.Search			true	"Filter, order and pagination"
+//	@Success		200		{array}		contact_entity.Contact	"Matching contacts"
+//	@Failure		400		{object}	common_model.DescriptiveError	"Invalid filter"
+//	@Failure		500		{object}	common_model.DescriptiveError	"Failed to query contacts"
+//	@Security		ApiKeyAuth
+//	@Security		WorkspaceAuth
+//	@Router			/contact/search [post]
+func SearchByFilter(c *fiber.Ctx) error {
+	search := new(database_filter.Search)
+	if err := c.BodyParser(search); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewParseJsonError(err).Send(),
+		)
+	}
+
+	if err := validators.Validator().Struct(search); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewValidationError(err).Send(),
+		)
+	}
+
+	workspace := workspace_middleware.GetWorkspace(c)
+	db := database.DB.Where("contacts.workspace_id = ?", workspace.ID)
+
+	contacts, err := contact_service.SearchByFilter(*search, db)
+	if err != nil {
+		if errors.Is(err, database_filter.ErrInvalidFilter) {
+			return c.Status(fiber.StatusBadRequest).JSON(
+				common_model.NewApiError("invalid filter", err, "database_filter").Send(),
+			)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			common_model.NewApiError("unable to get contacts", err, "database_filter").Send(),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(contacts)
+}
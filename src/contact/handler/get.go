@@ -6,6 +6,7 @@ import (
 	contact_model "github.com/Astervia/wacraft-core/src/contact/model"
 	"github.com/Astervia/wacraft-core/src/repository"
 	"github.com/Astervia/wacraft-server/src/database"
+	database_cursor "github.com/Astervia/wacraft-server/src/database/cursor"
 	"github.com/Astervia/wacraft-server/src/validators"
 	workspace_middleware "github.com/Astervia/wacraft-server/src/workspace/middleware"
 	"github.com/gofiber/fiber/v2"
@@ -15,10 +16,12 @@ import (
 //
 //	@Summary		Get contacts paginated
 //	@Description	Returns a paginated list of contacts using optional query parameters for filtering and sorting.
+//	@Description	Set pagination=cursor to receive a page with next_cursor and prev_cursor instead of a plain array, newest first; pass either cursor back to move between pages. Offset and order are ignored in cursor mode.
 //	@Tags			Contact
 //	@Accept			json
 //	@Produce		json
 //	@Param			paginate	query		contact_model.QueryPaginated	true	"Query parameters"
+//	@Param			cursor		query		database_cursor.Query			false	"Cursor pagination"
 //	@Success		200			{array}		contact_entity.Contact			"List of contacts"
 //	@Failure		400			{object}	common_model.DescriptiveError	"Invalid query parameters"
 //	@Failure		500			{object}	common_model.DescriptiveError	"Internal server error"
@@ -41,13 +44,50 @@ func Get(c *fiber.Ctx) error {
 		)
 	}
 
+	cursorQuery := new(database_cursor.Query)
+	if err := c.QueryParser(cursorQuery); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewParseJsonError(err).Send(),
+		)
+	}
+
+	entity := contact_entity.Contact{
+		Audit:       common_model.Audit{ID: query.ID},
+		Name:        query.Name,
+		Email:       query.Email,
+		WorkspaceID: &workspace.ID,
+	}
+
+	if cursorQuery.Enabled() {
+		cursor, err := cursorQuery.Parse()
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(
+				common_model.NewApiError("unable to parse cursor", err, "database_cursor").Send(),
+			)
+		}
+
+		page, err := database_cursor.GetPage(
+			entity,
+			&query.DateWhere,
+			"contacts",
+			cursor,
+			database_cursor.Limit(query.Limit),
+			func(contact contact_entity.Contact) database_cursor.Cursor {
+				return database_cursor.AuditKey(contact.Audit)
+			},
+			database.DB,
+		)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(
+				common_model.NewApiError("unable to get contacts", err, "database_cursor").Send(),
+			)
+		}
+
+		return c.Status(fiber.StatusOK).JSON(page)
+	}
+
 	contacts, err := repository.GetPaginated(
-		contact_entity.Contact{
-			Audit:       common_model.Audit{ID: query.ID},
-			Name:        query.Name,
-			Email:       query.Email,
-			WorkspaceID: &workspace.ID,
-		},
+		entity,
 		&query.Paginate,
 		&query.DateOrder,
 		&query.DateWhere,
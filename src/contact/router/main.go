@@ -23,6 +23,13 @@ func mainRoutes(group fiber.Router) {
 		workspace_middleware.RequirePolicy(workspace_model.PolicyContactRead),
 		billing_middleware.ThroughputMiddleware,
 		contact_handler.Get)
+	group.Post("/search",
+		auth_middleware.UserMiddleware,
+		auth_middleware.EmailVerifiedMiddleware,
+		workspace_middleware.WorkspaceMiddleware,
+		workspace_middleware.RequirePolicy(workspace_model.PolicyContactRead),
+		billing_middleware.ThroughputMiddleware,
+		contact_handler.SearchByFilter)
 	group.Post("/",
 		auth_middleware.UserMiddleware,
 		auth_middleware.EmailVerifiedMiddleware,
@@ -0,0 +1,30 @@
+package contact_service
+
+import (
+	contact_entity "github.com/Astervia/wacraft-core/src/contact/entity"
+	"github.com/Astervia/wacraft-server/src/database"
+	database_filter "github.com/Astervia/wacraft-server/src/database/filter"
+	"gorm.io/gorm"
+)
+
+// FilterSchema whitelists the fields usable in contact filters.
+var FilterSchema = database_filter.Schema{
+	Table: "contacts",
+	Fields: map[string]database_filter.Field{
+		"id":         {Column: "contacts.id", Type: database_filter.UUID},
+		"created_at": {Column: "contacts.created_at", Type: database_filter.Time},
+		"updated_at": {Column: "contacts.updated_at", Type: database_filter.Time},
+		"name":       {Column: "contacts.name", Type: database_filter.String},
+		"email":      {Column: "contacts.email", Type: database_filter.String},
+		"photo_path": {Column: "contacts.photo_path", Type: database_filter.String},
+	},
+}
+
+// SearchByFilter returns the contacts matching the search.
+func SearchByFilter(search database_filter.Search, db *gorm.DB) ([]contact_entity.Contact, error) {
+	if db == nil {
+		db = database.DB
+	}
+
+	return database_filter.Find[contact_entity.Contact](search, FilterSchema, db.Model(&contact_entity.Contact{}))
+}
@@ -0,0 +1,81 @@
+package conversation_entity
+
+import (
+	"time"
+
+	common_model "github.com/Astervia/wacraft-core/src/common/model"
+	message_entity "github.com/Astervia/wacraft-core/src/message/entity"
+	messaging_product_entity "github.com/Astervia/wacraft-core/src/messaging-product/entity"
+	workspace_entity "github.com/Astervia/wacraft-core/src/workspace/entity"
+	"github.com/google/uuid"
+)
+
+// ConversationState is the inbox state of a conversation.
+type ConversationState string
+
+const (
+	// ConversationOpen conversations wait for an answer from the team.
+	ConversationOpen ConversationState = "open"
+	// ConversationPending conversations wait for an answer from the contact.
+	ConversationPending ConversationState = "pending"
+	// ConversationSnoozed conversations are hidden until SnoozedUntil or the next inbound message.
+	ConversationSnoozed ConversationState = "snoozed"
+	// ConversationClosed conversations are resolved. An inbound message reopens them.
+	ConversationClosed ConversationState = "closed"
+)
+
+// IsValid reports whether the state is one of the known conversation states.
+func (s ConversationState) IsValid() bool {
+	switch s {
+	case ConversationOpen, ConversationPending, ConversationSnoozed, ConversationClosed:
+		return true
+	}
+	return false
+}
+
+// Conversation is the shared inbox entry of a messaging product contact.
+// There is at most one conversation per messaging product contact.
+type Conversation struct {
+	MessagingProductContactID uuid.UUID                                         `json:"messaging_product_contact_id" gorm:"type:uuid;not null;uniqueIndex"`
+	MessagingProductContact   *messaging_product_entity.MessagingProductContact `json:"messaging_product_contact,omitempty" gorm:"foreignKey:MessagingProductContactID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	WorkspaceID               uuid.UUID                                         `json:"workspace_id" gorm:"type:uuid;not null;index"`
+	Workspace                 *workspace_entity.Workspace                       `json:"workspace,omitempty" gorm:"foreignKey:WorkspaceID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+
+	// AssigneeID is the workspace member handling the conversation.
+	// Removing the member from the workspace unassigns the conversation.
+	AssigneeID *uuid.UUID                        `json:"assignee_id,omitempty" gorm:"type:uuid;index"`
+	Assignee   *workspace_entity.WorkspaceMember `json:"assignee,omitempty" gorm:"foreignKey:AssigneeID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
+
+	State        ConversationState `json:"state" gorm:"type:varchar(20);not null;default:'open';index"`
+	SnoozedUntil *time.Time        `json:"snoozed_until,omitempty"`
+	ClosedAt     *time.Time        `json:"closed_at,omitempty"`
+
+	// The last message and counters are kept up to date as messages and statuses are
+	// written so conversation listings do not need to scan the messages table.
+	// LastMessageAt is the creation time of the conversation until it has messages.
+	LastMessageID  *uuid.UUID              `json:"last_message_id,omitempty" gorm:"type:uuid;index"`
+	LastMessage    *message_entity.Message `json:"last_message,omitempty" gorm:"foreignKey:LastMessageID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
+	LastMessageAt  *time.Time              `json:"last_message_at,omitempty"`
+	LastInboundAt  *time.Time              `json:"last_inbound_at,omitempty"`
+	LastOutboundAt *time.Time              `json:"last_outbound_at,omitempty"`
+
+	// LastStatus is the most advanced status (sent, delivered, read or failed) of the last message.
+	LastStatus   string     `json:"last_status,omitempty" gorm:"type:varchar(20)"`
+	LastStatusAt *time.Time `json:"last_status_at,omitempty"`
+
+	MessageCount  int64 `json:"message_count" gorm:"not null;default:0"`
+	InboundCount  int64 `json:"inbound_count" gorm:"not null;default:0"`
+	OutboundCount int64 `json:"outbound_count" gorm:"not null;default:0"`
+
+	// UnreadCount is the number of inbound messages received after the contact's last_read_at.
+	// It is computed when the conversation is read and never stored.
+	UnreadCount int64 `json:"unread_count" gorm:"->;-:migration"`
+
+	// ServiceWindowExpiresAt is when the contact's customer service window closes and
+	// ServiceWindowOpen whether free-form messages are still accepted. Both are computed
+	// from the contact's last inbound message when the conversation is read.
+	ServiceWindowExpiresAt *time.Time `json:"service_window_expires_at,omitempty" gorm:"->;-:migration"`
+	ServiceWindowOpen      bool       `json:"service_window_open" gorm:"->;-:migration"`
+
+	common_model.Audit
+}
@@ -0,0 +1,135 @@
+package conversation_handler
+
+import (
+	"errors"
+
+	common_model "github.com/Astervia/wacraft-core/src/common/model"
+	conversation_model "github.com/Astervia/wacraft-server/src/conversation/model"
+	conversation_service "github.com/Astervia/wacraft-server/src/conversation/service"
+	"github.com/Astervia/wacraft-server/src/validators"
+	workspace_middleware "github.com/Astervia/wacraft-server/src/workspace/middleware"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// AssignConversation assigns a conversation to a workspace member.
+//
+//	@Summary		Assign conversation
+//	@Description	Assigns the conversation to a workspace member, replacing the current assignee if any.
+//	@Tags			Conversation
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		string								true	"Conversation ID"
+//	@Param			assignee	body		conversation_model.AssignConversation	true	"Workspace member to assign"
+//	@Success		200			{object}	conversation_entity.Conversation	"Assigned conversation"
+//	@Failure		400			{object}	common_model.DescriptiveError		"Invalid body or member not in the workspace"
+//	@Failure		404			{object}	common_model.DescriptiveError		"Conversation not found"
+//	@Failure		500			{object}	common_model.DescriptiveError		"Failed to assign the conversation"
+//	@Security		ApiKeyAuth
+//	@Security		WorkspaceAuth
+//	@Router			/conversation/{id}/assign [patch]
+func AssignConversation(c *fiber.Ctx) error {
+	return assign(c, false)
+}
+
+// TransferConversation transfers an assigned conversation to another workspace member.
+//
+//	@Summary		Transfer conversation
+//	@Description	Moves an assigned conversation from its current assignee to another workspace member.
+//	@Tags			Conversation
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		string								true	"Conversation ID"
+//	@Param			assignee	body		conversation_model.AssignConversation	true	"Workspace member receiving the conversation"
+//	@Success		200			{object}	conversation_entity.Conversation	"Transferred conversation"
+//	@Failure		400			{object}	common_model.DescriptiveError		"Invalid body or member not in the workspace"
+//	@Failure		404			{object}	common_model.DescriptiveError		"Conversation not found"
+//	@Failure		409			{object}	common_model.DescriptiveError		"Conversation not assigned or already assigned to the member"
+//	@Failure		500			{object}	common_model.DescriptiveError		"Failed to transfer the conversation"
+//	@Security		ApiKeyAuth
+//	@Security		WorkspaceAuth
+//	@Router			/conversation/{id}/transfer [patch]
+func TransferConversation(c *fiber.Ctx) error {
+	return assign(c, true)
+}
+
+// UnassignConversation removes the assignee of a conversation.
+//
+//	@Summary		Unassign conversation
+//	@Description	Removes the assignee of the conversation, putting it back in the shared inbox.
+//	@Tags			Conversation
+//	@Produce		json
+//	@Param			id	path		string								true	"Conversation ID"
+//	@Success		200	{object}	conversation_entity.Conversation	"Unassigned conversation"
+//	@Failure		400	{object}	common_model.DescriptiveError		"Invalid conversation ID"
+//	@Failure		404	{object}	common_model.DescriptiveError		"Conversation not found"
+//	@Failure		500	{object}	common_model.DescriptiveError		"Failed to unassign the conversation"
+//	@Security		ApiKeyAuth
+//	@Security		WorkspaceAuth
+//	@Router			/conversation/{id}/assign [delete]
+func UnassignConversation(c *fiber.Ctx) error {
+	conversation, ok, err := findConversation(c)
+	if !ok {
+		return err
+	}
+
+	event, err := conversation_service.Assign(conversation, nil, false)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			common_model.NewApiError("unable to unassign conversation", err, "conversation_service").Send(),
+		)
+	}
+
+	go ConversationEventWorkspaceManager.BroadcastToWorkspace(event.Conversation.WorkspaceID, event)
+
+	return c.Status(fiber.StatusOK).JSON(event.Conversation)
+}
+
+func assign(c *fiber.Ctx, transfer bool) error {
+	conversation, ok, err := findConversation(c)
+	if !ok {
+		return err
+	}
+
+	var body conversation_model.AssignConversation
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewParseJsonError(err).Send(),
+		)
+	}
+
+	if err := validators.Validator().Struct(&body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewValidationError(err).Send(),
+		)
+	}
+
+	workspace := workspace_middleware.GetWorkspace(c)
+	err = conversation_service.EnsureAssigneeInWorkspace(body.AssigneeID, workspace.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewApiError("assignee is not a member of this workspace", err, "conversation_service").Send(),
+		)
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			common_model.NewApiError("unable to validate assignee", err, "conversation_service").Send(),
+		)
+	}
+
+	event, err := conversation_service.Assign(conversation, &body.AssigneeID, transfer)
+	if errors.Is(err, conversation_service.ErrConversationNotAssigned) || errors.Is(err, conversation_service.ErrSameAssignee) {
+		return c.Status(fiber.StatusConflict).JSON(
+			common_model.NewApiError(err.Error(), err, "conversation_service").Send(),
+		)
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			common_model.NewApiError("unable to assign conversation", err, "conversation_service").Send(),
+		)
+	}
+
+	go ConversationEventWorkspaceManager.BroadcastToWorkspace(workspace.ID, event)
+
+	return c.Status(fiber.StatusOK).JSON(event.Conversation)
+}
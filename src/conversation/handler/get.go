@@ -0,0 +1,196 @@
+package conversation_handler
+
+import (
+	"errors"
+
+	common_model "github.com/Astervia/wacraft-core/src/common/model"
+	conversation_entity "github.com/Astervia/wacraft-server/src/conversation/entity"
+	conversation_model "github.com/Astervia/wacraft-server/src/conversation/model"
+	conversation_service "github.com/Astervia/wacraft-server/src/conversation/service"
+	database_cursor "github.com/Astervia/wacraft-server/src/database/cursor"
+	"github.com/Astervia/wacraft-server/src/validators"
+	workspace_middleware "github.com/Astervia/wacraft-server/src/workspace/middleware"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GetConversations returns the conversation inbox of the workspace.
+//
+//	@Summary		Get conversations
+//	@Description	Retrieves a paginated list of conversations with their state, assignee, last message and unread count. Filter by state, assignee or messaging product contact.
+//	@Description	Set pagination=cursor to receive a page with next_cursor and prev_cursor instead of a plain array, most recent message first; pass either cursor back to move between pages. Offset and order are ignored in cursor mode.
+//	@Tags			Conversation
+//	@Accept			json
+//	@Produce		json
+//	@Param			conversation	query		conversation_model.QueryPaginated		true	"Pagination and filter parameters"
+//	@Param			cursor			query		database_cursor.Query					false	"Cursor pagination"
+//	@Success		200				{array}		conversation_entity.Conversation		"Conversations"
+//	@Failure		400				{object}	common_model.DescriptiveError			"Invalid query"
+//	@Failure		500				{object}	common_model.DescriptiveError			"Failed to retrieve conversations"
+//	@Security		ApiKeyAuth
+//	@Security		WorkspaceAuth
+//	@Router			/conversation [get]
+func GetConversations(c *fiber.Ctx) error {
+	query := new(conversation_model.QueryPaginated)
+	if err := c.QueryParser(query); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewParseJsonError(err).Send(),
+		)
+	}
+
+	if err := validators.Validator().Struct(query); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewValidationError(err).Send(),
+		)
+	}
+	if query.State != "" && !query.State.IsValid() {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewApiError("invalid conversation state", conversation_service.ErrInvalidState, "handler").Send(),
+		)
+	}
+
+	workspace := workspace_middleware.GetWorkspace(c)
+
+	cursorQuery := new(database_cursor.Query)
+	if err := c.QueryParser(cursorQuery); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewParseJsonError(err).Send(),
+		)
+	}
+
+	if cursorQuery.Enabled() {
+		cursor, err := cursorQuery.Parse()
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(
+				common_model.NewApiError("unable to parse cursor", err, "database_cursor").Send(),
+			)
+		}
+
+		page, err := conversation_service.GetConversationsByCursor(
+			*query,
+			workspace.ID,
+			cursor,
+			database_cursor.Limit(query.Limit),
+			&query.DateWhere,
+		)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(
+				common_model.NewApiError("unable to get conversations", err, "conversation_service").Send(),
+			)
+		}
+
+		return c.Status(fiber.StatusOK).JSON(page)
+	}
+
+	conversations, err := conversation_service.GetConversations(
+		*query,
+		workspace.ID,
+		&query.Paginate,
+		&query.DateOrder,
+		&query.DateWhere,
+	)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			common_model.NewApiError("unable to get conversations", err, "conversation_service").Send(),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(conversations)
+}
+
+// GetConversation returns a single conversation of the workspace.
+//
+//	@Summary		Get conversation
+//	@Description	Retrieves a conversation with its state, assignee and unread count.
+//	@Tags			Conversation
+//	@Produce		json
+//	@Param			id	path		string								true	"Conversation ID"
+//	@Success		200	{object}	conversation_entity.Conversation	"Conversation"
+//	@Failure		400	{object}	common_model.DescriptiveError		"Invalid conversation ID"
+//	@Failure		404	{object}	common_model.DescriptiveError		"Conversation not found"
+//	@Failure		500	{object}	common_model.DescriptiveError		"Failed to retrieve the conversation"
+//	@Security		ApiKeyAuth
+//	@Security		WorkspaceAuth
+//	@Router			/conversation/{id} [get]
+func GetConversation(c *fiber.Ctx) error {
+	conversation, ok, err := findConversation(c)
+	if !ok {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(conversation)
+}
+
+// GetContactConversation returns the conversation of a messaging product contact, creating it when missing.
+//
+//	@Summary		Get conversation of a contact
+//	@Description	Retrieves the conversation of the specified messaging product contact. An open conversation is created when the contact has none yet.
+//	@Tags			Conversation
+//	@Produce		json
+//	@Param			messagingProductContactID	path		string								true	"Messaging product contact ID"
+//	@Success		200							{object}	conversation_entity.Conversation	"Conversation"
+//	@Failure		400							{object}	common_model.DescriptiveError		"Invalid contact ID"
+//	@Failure		404							{object}	common_model.DescriptiveError		"Contact not found in the workspace"
+//	@Failure		500							{object}	common_model.DescriptiveError		"Failed to retrieve the conversation"
+//	@Security		ApiKeyAuth
+//	@Security		WorkspaceAuth
+//	@Router			/conversation/messaging-product-contact/{messagingProductContactID} [get]
+func GetContactConversation(c *fiber.Ctx) error {
+	mpcID, err := uuid.Parse(c.Params("messagingProductContactID"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewApiError("unable to parse messaging product contact id string to UUID", err, "github.com/google/uuid").Send(),
+		)
+	}
+
+	workspace := workspace_middleware.GetWorkspace(c)
+
+	conversation, created, err := conversation_service.GetOrCreateConversation(mpcID, workspace.ID)
+	if errors.Is(err, conversation_service.ErrContactNotInWorkspace) {
+		return c.Status(fiber.StatusNotFound).JSON(
+			common_model.NewApiError("messaging product contact not found", err, "conversation_service").Send(),
+		)
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			common_model.NewApiError("unable to get conversation", err, "conversation_service").Send(),
+		)
+	}
+
+	if created {
+		go ConversationEventWorkspaceManager.BroadcastToWorkspace(workspace.ID, conversation_model.ConversationEvent{
+			Event:        conversation_model.ConversationCreated,
+			Conversation: conversation,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(conversation)
+}
+
+// findConversation loads the conversation referenced by the :id path parameter.
+// When ok is false the error response has already been written and the handler must return err.
+func findConversation(c *fiber.Ctx) (conversation conversation_entity.Conversation, ok bool, err error) {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return conversation, false, c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewApiError("unable to parse conversation id string to UUID", err, "github.com/google/uuid").Send(),
+		)
+	}
+
+	workspace := workspace_middleware.GetWorkspace(c)
+
+	conversation, err = conversation_service.GetConversation(id, workspace.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return conversation, false, c.Status(fiber.StatusNotFound).JSON(
+			common_model.NewApiError("conversation not found", err, "conversation_service").Send(),
+		)
+	}
+	if err != nil {
+		return conversation, false, c.Status(fiber.StatusInternalServerError).JSON(
+			common_model.NewApiError("unable to get conversation", err, "conversation_service").Send(),
+		)
+	}
+
+	return conversation, true, nil
+}
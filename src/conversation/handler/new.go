@@ -0,0 +1,93 @@
+package conversation_handler
+
+import (
+	"sync"
+
+	_ "github.com/Astervia/wacraft-core/src/common/model"
+	user_entity "github.com/Astervia/wacraft-core/src/user/entity"
+	websocket_model "github.com/Astervia/wacraft-core/src/websocket/model"
+	workspace_entity "github.com/Astervia/wacraft-core/src/workspace/entity"
+	conversation_model "github.com/Astervia/wacraft-server/src/conversation/model"
+	conversation_service "github.com/Astervia/wacraft-server/src/conversation/service"
+	websocket_workspace_manager "github.com/Astervia/wacraft-server/src/websocket/workspace-manager"
+	"github.com/gofiber/contrib/websocket"
+	"github.com/google/uuid"
+	"github.com/pterm/pterm"
+)
+
+var (
+	conversationClientPool            = websocket_model.CreateClientPool()
+	ConversationEventWorkspaceManager = websocket_workspace_manager.CreateWorkspaceChannelManager[conversation_model.ConversationEvent]()
+)
+
+// BroadcastConversationEvent reloads the conversation (so the unread count is current)
+// and broadcasts the event to the workspace.
+func BroadcastConversationEvent(workspaceID uuid.UUID, event conversation_model.ConversationEvent) {
+	conversation, err := conversation_service.GetConversation(event.Conversation.ID, workspaceID)
+	if err != nil {
+		pterm.DefaultLogger.Error("Unable to load conversation " + event.Conversation.ID.String() + " for broadcast: " + err.Error())
+		return
+	}
+	event.Conversation = conversation
+
+	ConversationEventWorkspaceManager.BroadcastToWorkspace(workspaceID, event)
+}
+
+// ConversationEventSubscription upgrades the connection to WebSocket and streams conversation changes.
+//
+//	@Summary		Subscribe to conversation changes
+//	@Description	Establishes a WebSocket connection and streams conversation inbox events (created, assigned, transferred, unassigned, closed, reopened and state changes) for a specific workspace.
+//	@Tags			Conversation Websocket
+//	@Accept			json
+//	@Produce		json
+//	@Param			workspace_id	query		string							false	"Workspace ID (alternative to header)"
+//	@Success		101				{string}	string							"WebSocket connection established"
+//	@Failure		400				{object}	common_model.DescriptiveError	"Invalid connection request"
+//	@Failure		500				{object}	common_model.DescriptiveError	"Internal server error"
+//	@Security		ApiKeyAuth
+//	@Security		WorkspaceAuth
+//	@Router			/websocket/conversation/event [get]
+func ConversationEventSubscription(ctx *websocket.Conn) {
+	defer ctx.Close()
+
+	// Registering user and workspace
+	user := ctx.Locals("user").(*user_entity.User)                     // This must be paired with the UserMiddleware. Otherwise will panic.
+	workspace := ctx.Locals("workspace").(*workspace_entity.Workspace) // This must be paired with the WebSocketWorkspaceMiddleware. Otherwise will panic.
+
+	clientID := conversationClientPool.CreateID(user.ID)
+	client := websocket_model.Client[websocket_model.ClientID]{
+		Connection: ctx,
+		Data:       *clientID,
+	}
+	ConversationEventWorkspaceManager.AppendClient(workspace.ID, client, clientID.String())
+
+	// Configuring disconnection
+	defer func() {
+		var deleteWg sync.WaitGroup
+
+		deleteWg.Go(func() {
+			conversationClientPool.DeleteID(*clientID)
+		})
+
+		deleteWg.Go(func() {
+			ConversationEventWorkspaceManager.RemoveClient(workspace.ID, client.Data.String())
+		})
+
+		deleteWg.Wait()
+	}()
+
+	for {
+		// Read message from WebSocket
+		msgType, data, err := ctx.ReadMessage()
+		if err != nil {
+			break // connection closed or other error
+		}
+
+		// Only handle text frames; ignore others
+		if msgType == websocket.TextMessage && string(data) == string(websocket_model.Ping) {
+			if writeErr := ctx.WriteMessage(websocket.TextMessage, []byte(websocket_model.Pong)); writeErr != nil {
+				break // stop if the write fails
+			}
+		}
+	}
+}
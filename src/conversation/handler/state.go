@@ -0,0 +1,102 @@
+package conversation_handler
+
+import (
+	common_model "github.com/Astervia/wacraft-core/src/common/model"
+	conversation_entity "github.com/Astervia/wacraft-server/src/conversation/entity"
+	conversation_model "github.com/Astervia/wacraft-server/src/conversation/model"
+	conversation_service "github.com/Astervia/wacraft-server/src/conversation/service"
+	"github.com/Astervia/wacraft-server/src/validators"
+	"github.com/gofiber/fiber/v2"
+)
+
+// CloseConversation marks a conversation as closed.
+//
+//	@Summary		Close conversation
+//	@Description	Closes the conversation. The next inbound message from the contact reopens it.
+//	@Tags			Conversation
+//	@Produce		json
+//	@Param			id	path		string								true	"Conversation ID"
+//	@Success		200	{object}	conversation_entity.Conversation	"Closed conversation"
+//	@Failure		400	{object}	common_model.DescriptiveError		"Invalid conversation ID"
+//	@Failure		404	{object}	common_model.DescriptiveError		"Conversation not found"
+//	@Failure		500	{object}	common_model.DescriptiveError		"Failed to close the conversation"
+//	@Security		ApiKeyAuth
+//	@Security		WorkspaceAuth
+//	@Router			/conversation/{id}/close [patch]
+func CloseConversation(c *fiber.Ctx) error {
+	return setState(c, conversation_model.UpdateConversationState{State: conversation_entity.ConversationClosed})
+}
+
+// ReopenConversation moves a conversation back to open.
+//
+//	@Summary		Reopen conversation
+//	@Description	Moves the conversation back to the open state.
+//	@Tags			Conversation
+//	@Produce		json
+//	@Param			id	path		string								true	"Conversation ID"
+//	@Success		200	{object}	conversation_entity.Conversation	"Reopened conversation"
+//	@Failure		400	{object}	common_model.DescriptiveError		"Invalid conversation ID"
+//	@Failure		404	{object}	common_model.DescriptiveError		"Conversation not found"
+//	@Failure		500	{object}	common_model.DescriptiveError		"Failed to reopen the conversation"
+//	@Security		ApiKeyAuth
+//	@Security		WorkspaceAuth
+//	@Router			/conversation/{id}/reopen [patch]
+func ReopenConversation(c *fiber.Ctx) error {
+	return setState(c, conversation_model.UpdateConversationState{State: conversation_entity.ConversationOpen})
+}
+
+// UpdateConversationState moves a conversation to any inbox state.
+//
+//	@Summary		Update conversation state
+//	@Description	Moves the conversation to open, pending, snoozed or closed. Snoozing requires snoozed_until.
+//	@Tags			Conversation
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string										true	"Conversation ID"
+//	@Param			state	body		conversation_model.UpdateConversationState	true	"New state"
+//	@Success		200		{object}	conversation_entity.Conversation			"Updated conversation"
+//	@Failure		400		{object}	common_model.DescriptiveError				"Invalid body or state"
+//	@Failure		404		{object}	common_model.DescriptiveError				"Conversation not found"
+//	@Failure		500		{object}	common_model.DescriptiveError				"Failed to update the conversation"
+//	@Security		ApiKeyAuth
+//	@Security		WorkspaceAuth
+//	@Router			/conversation/{id}/state [patch]
+func UpdateConversationState(c *fiber.Ctx) error {
+	var body conversation_model.UpdateConversationState
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewParseJsonError(err).Send(),
+		)
+	}
+
+	if err := validators.Validator().Struct(&body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewValidationError(err).Send(),
+		)
+	}
+	if !body.State.IsValid() {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewApiError("invalid conversation state", conversation_service.ErrInvalidState, "handler").Send(),
+		)
+	}
+
+	return setState(c, body)
+}
+
+func setState(c *fiber.Ctx, body conversation_model.UpdateConversationState) error {
+	conversation, ok, err := findConversation(c)
+	if !ok {
+		return err
+	}
+
+	event, err := conversation_service.SetState(conversation, body.State, body.SnoozedUntil)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			common_model.NewApiError("unable to update conversation state", err, "conversation_service").Send(),
+		)
+	}
+
+	go ConversationEventWorkspaceManager.BroadcastToWorkspace(event.Conversation.WorkspaceID, event)
+
+	return c.Status(fiber.StatusOK).JSON(event.Conversation)
+}
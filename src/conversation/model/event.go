@@ -0,0 +1,24 @@
+package conversation_model
+
+import (
+	conversation_entity "github.com/Astervia/wacraft-server/src/conversation/entity"
+)
+
+// ConversationEventType identifies what changed in a conversation.
+type ConversationEventType string
+
+const (
+	ConversationCreated     ConversationEventType = "created"
+	ConversationAssigned    ConversationEventType = "assigned"
+	ConversationTransferred ConversationEventType = "transferred"
+	ConversationUnassigned  ConversationEventType = "unassigned"
+	ConversationClosed      ConversationEventType = "closed"
+	ConversationReopened    ConversationEventType = "reopened"
+	ConversationStateChange ConversationEventType = "state_changed"
+)
+
+// ConversationEvent is broadcast to the workspace whenever a conversation changes.
+type ConversationEvent struct {
+	Event        ConversationEventType            `json:"event"`
+	Conversation conversation_entity.Conversation `json:"conversation"`
+}
@@ -0,0 +1,19 @@
+package conversation_model
+
+import (
+	database_model "github.com/Astervia/wacraft-core/src/database/model"
+	conversation_entity "github.com/Astervia/wacraft-server/src/conversation/entity"
+	"github.com/google/uuid"
+)
+
+// QueryPaginated filters the conversation inbox.
+type QueryPaginated struct {
+	State                     conversation_entity.ConversationState `json:"state,omitempty" query:"state"`
+	AssigneeID                *uuid.UUID                            `json:"assignee_id,omitempty" query:"assignee_id"`
+	Unassigned                bool                                  `json:"unassigned,omitempty" query:"unassigned"` // Only conversations without assignee.
+	MessagingProductContactID *uuid.UUID                            `json:"messaging_product_contact_id,omitempty" query:"messaging_product_contact_id"`
+
+	database_model.Paginate
+	database_model.DateOrder
+	database_model.DateWhere
+}
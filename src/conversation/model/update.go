@@ -0,0 +1,19 @@
+package conversation_model
+
+import (
+	"time"
+
+	conversation_entity "github.com/Astervia/wacraft-server/src/conversation/entity"
+	"github.com/google/uuid"
+)
+
+// AssignConversation selects the workspace member that handles a conversation.
+type AssignConversation struct {
+	AssigneeID uuid.UUID `json:"assignee_id" validate:"required"` // Workspace member ID.
+}
+
+// UpdateConversationState moves a conversation to another inbox state.
+type UpdateConversationState struct {
+	State        conversation_entity.ConversationState `json:"state" validate:"required"`
+	SnoozedUntil *time.Time                            `json:"snoozed_until,omitempty" validate:"required_if=State snoozed"`
+}
@@ -0,0 +1,90 @@
+package conversation_router
+
+import (
+	workspace_model "github.com/Astervia/wacraft-core/src/workspace/model"
+	auth_middleware "github.com/Astervia/wacraft-server/src/auth/middleware"
+	billing_middleware "github.com/Astervia/wacraft-server/src/billing/middleware"
+	conversation_handler "github.com/Astervia/wacraft-server/src/conversation/handler"
+	workspace_middleware "github.com/Astervia/wacraft-server/src/workspace/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+func Route(app *fiber.App) {
+	group := app.Group("/conversation")
+
+	mainRoutes(group)
+}
+
+func mainRoutes(group fiber.Router) {
+	group.Get("",
+		auth_middleware.UserMiddleware,
+		auth_middleware.EmailVerifiedMiddleware,
+		workspace_middleware.WorkspaceMiddleware,
+		workspace_middleware.RequirePolicy(workspace_model.PolicyMessageRead),
+		billing_middleware.ThroughputMiddleware,
+		conversation_handler.GetConversations)
+
+	group.Get("/messaging-product-contact/:messagingProductContactID",
+		auth_middleware.UserMiddleware,
+		auth_middleware.EmailVerifiedMiddleware,
+		workspace_middleware.WorkspaceMiddleware,
+		workspace_middleware.RequirePolicy(workspace_model.PolicyMessageRead),
+		billing_middleware.ThroughputMiddleware,
+		conversation_handler.GetContactConversation)
+
+	group.Get("/:id",
+		auth_middleware.UserMiddleware,
+		auth_middleware.EmailVerifiedMiddleware,
+		workspace_middleware.WorkspaceMiddleware,
+		workspace_middleware.RequirePolicy(workspace_model.PolicyMessageRead),
+		billing_middleware.ThroughputMiddleware,
+		conversation_handler.GetConversation)
+
+	group.Patch("/:id/assign",
+		auth_middleware.UserMiddleware,
+		auth_middleware.EmailVerifiedMiddleware,
+		workspace_middleware.WorkspaceMiddleware,
+		workspace_middleware.RequirePolicy(workspace_model.PolicyMessageSend),
+		billing_middleware.ThroughputMiddleware,
+		conversation_handler.AssignConversation)
+
+	group.Delete("/:id/assign",
+		auth_middleware.UserMiddleware,
+		auth_middleware.EmailVerifiedMiddleware,
+		workspace_middleware.WorkspaceMiddleware,
+		workspace_middleware.RequirePolicy(workspace_model.PolicyMessageSend),
+		billing_middleware.ThroughputMiddleware,
+		conversation_handler.UnassignConversation)
+
+	group.Patch("/:id/transfer",
+		auth_middleware.UserMiddleware,
+		auth_middleware.EmailVerifiedMiddleware,
+		workspace_middleware.WorkspaceMiddleware,
+		workspace_middleware.RequirePolicy(workspace_model.PolicyMessageSend),
+		billing_middleware.ThroughputMiddleware,
+		conversation_handler.TransferConversation)
+
+	group.Patch("/:id/close",
+		auth_middleware.UserMiddleware,
+		auth_middleware.EmailVerifiedMiddleware,
+		workspace_middleware.WorkspaceMiddleware,
+		workspace_middleware.RequirePolicy(workspace_model.PolicyMessageSend),
+		billing_middleware.ThroughputMiddleware,
+		conversation_handler.CloseConversation)
+
+	group.Patch("/:id/reopen",
+		auth_middleware.UserMiddleware,
+		auth_middleware.EmailVerifiedMiddleware,
+		workspace_middleware.WorkspaceMiddleware,
+		workspace_middleware.RequirePolicy(workspace_model.PolicyMessageSend),
+		billing_middleware.ThroughputMiddleware,
+		conversation_handler.ReopenConversation)
+
+	group.Patch("/:id/state",
+		auth_middleware.UserMiddleware,
+		auth_middleware.EmailVerifiedMiddleware,
+		workspace_middleware.WorkspaceMiddleware,
+		workspace_middleware.RequirePolicy(workspace_model.PolicyMessageSend),
+		billing_middleware.ThroughputMiddleware,
+		conversation_handler.UpdateConversationState)
+}
@@ -0,0 +1,369 @@
+package conversation_service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	database_model "github.com/Astervia/wacraft-core/src/database/model"
+	messaging_product_entity "github.com/Astervia/wacraft-core/src/messaging-product/entity"
+	"github.com/Astervia/wacraft-core/src/repository"
+	workspace_entity "github.com/Astervia/wacraft-core/src/workspace/entity"
+	conversation_entity "github.com/Astervia/wacraft-server/src/conversation/entity"
+	conversation_model "github.com/Astervia/wacraft-server/src/conversation/model"
+	"github.com/Astervia/wacraft-server/src/database"
+	database_cursor "github.com/Astervia/wacraft-server/src/database/cursor"
+	messaging_product_service "github.com/Astervia/wacraft-server/src/messaging-product/service"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrContactNotInWorkspace is returned when the messaging product contact does not belong to the workspace.
+	ErrContactNotInWorkspace = errors.New("messaging product contact does not belong to the workspace")
+	// ErrConversationNotAssigned is returned when transferring a conversation that has no assignee.
+	ErrConversationNotAssigned = errors.New("conversation is not assigned; assign it instead of transferring it")
+	// ErrSameAssignee is returned when transferring a conversation to its current assignee.
+	ErrSameAssignee = errors.New("conversation is already assigned to this member")
+	// ErrInvalidState is returned for unknown conversation states.
+	ErrInvalidState = errors.New("invalid conversation state")
+)
+
+// conversationSelect loads the conversation along with the inbound messages received
+// after the contact was last read and the contact's customer service window.
+var conversationSelect = fmt.Sprintf(`conversations.*, (
+	SELECT COUNT(*)
+	  FROM messages
+	  JOIN messaging_product_contacts mpc ON mpc.id = messages.from_id
+	 WHERE messages.from_id = conversations.messaging_product_contact_id
+	   AND messages.deleted_at IS NULL
+	   AND (mpc.last_read_at IS NULL OR messages.created_at > mpc.last_read_at)
+) AS unread_count, (
+	SELECT mpc.last_inbound_at + interval '%[1]d seconds'
+	  FROM messaging_product_contacts mpc
+	 WHERE mpc.id = conversations.messaging_product_contact_id
+) AS service_window_expires_at, COALESCE((
+	SELECT mpc.last_inbound_at + interval '%[1]d seconds' > NOW()
+	  FROM messaging_product_contacts mpc
+	 WHERE mpc.id = conversations.messaging_product_contact_id
+), false) AS service_window_open`, int(messaging_product_service.ServiceWindowDuration.Seconds()))
+
+// GetConversations returns the paginated conversations of a workspace.
+func GetConversations(
+	query conversation_model.QueryPaginated,
+	workspaceID uuid.UUID,
+	pagination database_model.Paginable,
+	order database_model.Orderable,
+	whereable database_model.Whereable,
+) ([]conversation_entity.Conversation, error) {
+	return repository.GetPaginated(
+		conversation_entity.Conversation{}, pagination, order, whereable, "conversations",
+		conversationsQuery(query, workspaceID),
+	)
+}
+
+// GetConversationsByCursor is the keyset variant of GetConversations. Conversations are
+// ordered from the most recent message to the oldest.
+func GetConversationsByCursor(
+	query conversation_model.QueryPaginated,
+	workspaceID uuid.UUID,
+	cursor *database_cursor.Cursor,
+	limit int,
+	whereable database_model.Whereable,
+) (database_cursor.Page[conversation_entity.Conversation], error) {
+	var conversations []conversation_entity.Conversation
+
+	db := conversationsQuery(query, workspaceID)
+	if whereable != nil {
+		whereable.Where(&db, `"conversations"`)
+	}
+	db = database_cursor.Apply(
+		db,
+		cursor,
+		"conversations.last_message_at",
+		"conversations.messaging_product_contact_id",
+		limit,
+	)
+
+	if err := db.Find(&conversations).Error; err != nil {
+		return database_cursor.Page[conversation_entity.Conversation]{}, err
+	}
+
+	return database_cursor.NewPage(conversations, cursor, limit, conversationCursor), nil
+}
+
+// conversationCursor returns the keyset position of a conversation. It matches the
+// position of its last message in the latest messages listing.
+func conversationCursor(conversation conversation_entity.Conversation) database_cursor.Cursor {
+	c := database_cursor.Cursor{ID: conversation.MessagingProductContactID}
+	if conversation.LastMessageAt != nil {
+		c.Time = *conversation.LastMessageAt
+	}
+	return c
+}
+
+func conversationsQuery(query conversation_model.QueryPaginated, workspaceID uuid.UUID) *gorm.DB {
+	db := database.DB.
+		Model(&conversation_entity.Conversation{}).
+		Select(conversationSelect).
+		Where("conversations.workspace_id = ?", workspaceID).
+		Preload("MessagingProductContact.Contact").
+		Preload("LastMessage").
+		Preload("Assignee")
+
+	if query.State != "" {
+		db = db.Where("conversations.state = ?", query.State)
+	}
+	if query.AssigneeID != nil {
+		db = db.Where("conversations.assignee_id = ?", *query.AssigneeID)
+	}
+	if query.Unassigned {
+		db = db.Where("conversations.assignee_id IS NULL")
+	}
+	if query.MessagingProductContactID != nil {
+		db = db.Where("conversations.messaging_product_contact_id = ?", *query.MessagingProductContactID)
+	}
+	return db
+}
+
+// GetConversation returns a conversation of the workspace with its unread count.
+func GetConversation(id uuid.UUID, workspaceID uuid.UUID) (conversation_entity.Conversation, error) {
+	var conversation conversation_entity.Conversation
+	err := database.DB.
+		Model(&conversation).
+		Select(conversationSelect).
+		Where("conversations.id = ? AND conversations.workspace_id = ?", id, workspaceID).
+		Preload("MessagingProductContact.Contact").
+		Preload("LastMessage").
+		Preload("Assignee").
+		First(&conversation).Error
+	return conversation, err
+}
+
+// GetOrCreateConversation returns the conversation of a messaging product contact,
+// creating an open one when the contact has none yet.
+// The boolean is true when the conversation was created.
+func GetOrCreateConversation(mpcID uuid.UUID, workspaceID uuid.UUID) (conversation_entity.Conversation, bool, error) {
+	var mpc messaging_product_entity.MessagingProductContact
+	err := database.DB.Model(&mpc).
+		Joins("JOIN messaging_products ON messaging_product_contacts.messaging_product_id = messaging_products.id AND messaging_products.workspace_id = ?", workspaceID).
+		Where("messaging_product_contacts.id = ?", mpcID).
+		First(&mpc).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return conversation_entity.Conversation{}, false, ErrContactNotInWorkspace
+	}
+	if err != nil {
+		return conversation_entity.Conversation{}, false, err
+	}
+
+	now := time.Now()
+	conversation := conversation_entity.Conversation{
+		MessagingProductContactID: mpcID,
+		WorkspaceID:               workspaceID,
+		State:                     conversation_entity.ConversationOpen,
+		LastMessageAt:             &now,
+	}
+	result := database.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "messaging_product_contact_id"}},
+		DoNothing: true,
+	}).Create(&conversation)
+	if result.Error != nil {
+		return conversation, false, result.Error
+	}
+
+	if err := database.DB.Where("messaging_product_contact_id = ?", mpcID).First(&conversation).Error; err != nil {
+		return conversation, false, err
+	}
+
+	conversation, err = GetConversation(conversation.ID, workspaceID)
+	return conversation, result.RowsAffected > 0, err
+}
+
+// RegisterInboundMessage moves the conversation of the contact to open for an inbound
+// message. The conversation is created when missing; the message itself is recorded on
+// it by RecordMessageInConversation. Returns the event to broadcast, or nil when the
+// conversation was already open.
+func RegisterInboundMessage(
+	mpcID uuid.UUID,
+	workspaceID uuid.UUID,
+	at time.Time,
+	db *gorm.DB,
+) (*conversation_model.ConversationEvent, error) {
+	if db == nil {
+		db = database.DB
+	}
+
+	conversation := conversation_entity.Conversation{
+		MessagingProductContactID: mpcID,
+		WorkspaceID:               workspaceID,
+		State:                     conversation_entity.ConversationOpen,
+		LastMessageAt:             &at,
+	}
+	result := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "messaging_product_contact_id"}},
+		DoNothing: true,
+	}).Create(&conversation)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected > 0 {
+		return &conversation_model.ConversationEvent{
+			Event:        conversation_model.ConversationCreated,
+			Conversation: conversation,
+		}, nil
+	}
+
+	if err := db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("messaging_product_contact_id = ?", mpcID).
+		First(&conversation).Error; err != nil {
+		return nil, err
+	}
+
+	if !reopensOnInbound(conversation.State) {
+		return nil, nil
+	}
+	if err := db.Model(&conversation_entity.Conversation{}).
+		Where("id = ?", conversation.ID).
+		Updates(map[string]any{
+			"state":         conversation_entity.ConversationOpen,
+			"closed_at":     nil,
+			"snoozed_until": nil,
+		}).Error; err != nil {
+		return nil, err
+	}
+
+	event := stateChangeEvent(conversation.State, conversation_entity.ConversationOpen)
+	conversation.State = conversation_entity.ConversationOpen
+	conversation.ClosedAt = nil
+	conversation.SnoozedUntil = nil
+	return &conversation_model.ConversationEvent{
+		Event:        event,
+		Conversation: conversation,
+	}, nil
+}
+
+// WakeSnoozedConversations moves up to limit snoozed conversations whose snoozed_until
+// has passed back to open. Rows locked by another instance are skipped.
+func WakeSnoozedConversations(now time.Time, limit int, db *gorm.DB) ([]conversation_model.ConversationEvent, error) {
+	if db == nil {
+		db = database.DB
+	}
+
+	due := db.Model(&conversation_entity.Conversation{}).
+		Select("id").
+		Where("state = ? AND snoozed_until <= ?", conversation_entity.ConversationSnoozed, now).
+		Order("snoozed_until").
+		Limit(limit).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+
+	var conversations []conversation_entity.Conversation
+	if err := db.Model(&conversations).
+		Clauses(clause.Returning{}).
+		Where("id IN (?)", due).
+		Updates(map[string]any{
+			"state":         conversation_entity.ConversationOpen,
+			"snoozed_until": nil,
+		}).Error; err != nil {
+		return nil, err
+	}
+
+	events := make([]conversation_model.ConversationEvent, len(conversations))
+	for i, conversation := range conversations {
+		events[i] = conversation_model.ConversationEvent{
+			Event:        stateChangeEvent(conversation_entity.ConversationSnoozed, conversation_entity.ConversationOpen),
+			Conversation: conversation,
+		}
+	}
+	return events, nil
+}
+
+// reopensOnInbound reports whether an inbound message moves a conversation back to open.
+// Closed and snoozed conversations need the team again, and pending ones got the answer they waited for.
+func reopensOnInbound(state conversation_entity.ConversationState) bool {
+	return state != conversation_entity.ConversationOpen
+}
+
+// stateChangeEvent returns the event broadcast when a conversation moves between states.
+func stateChangeEvent(from conversation_entity.ConversationState, to conversation_entity.ConversationState) conversation_model.ConversationEventType {
+	switch {
+	case to == conversation_entity.ConversationClosed:
+		return conversation_model.ConversationClosed
+	case from == conversation_entity.ConversationClosed,
+		from == conversation_entity.ConversationSnoozed && to == conversation_entity.ConversationOpen:
+		return conversation_model.ConversationReopened
+	}
+	return conversation_model.ConversationStateChange
+}
+
+// Assign sets the assignee of a conversation. A nil assignee unassigns it.
+// Transfers are rejected unless the conversation already has another assignee.
+func Assign(
+	conversation conversation_entity.Conversation,
+	assigneeID *uuid.UUID,
+	transfer bool,
+) (conversation_model.ConversationEvent, error) {
+	event := conversation_model.ConversationEvent{Event: conversation_model.ConversationAssigned}
+	switch {
+	case assigneeID == nil:
+		event.Event = conversation_model.ConversationUnassigned
+	case transfer && conversation.AssigneeID == nil:
+		return event, ErrConversationNotAssigned
+	case transfer && *conversation.AssigneeID == *assigneeID:
+		return event, ErrSameAssignee
+	case transfer:
+		event.Event = conversation_model.ConversationTransferred
+	}
+
+	if err := database.DB.Model(&conversation_entity.Conversation{}).
+		Where("id = ?", conversation.ID).
+		Update("assignee_id", assigneeID).Error; err != nil {
+		return event, err
+	}
+
+	updated, err := GetConversation(conversation.ID, conversation.WorkspaceID)
+	event.Conversation = updated
+	return event, err
+}
+
+// SetState moves a conversation to another state. SnoozedUntil is only kept for snoozed conversations.
+func SetState(
+	conversation conversation_entity.Conversation,
+	state conversation_entity.ConversationState,
+	snoozedUntil *time.Time,
+) (conversation_model.ConversationEvent, error) {
+	event := conversation_model.ConversationEvent{Event: stateChangeEvent(conversation.State, state)}
+	if !state.IsValid() {
+		return event, ErrInvalidState
+	}
+
+	updates := map[string]any{
+		"state":         state,
+		"snoozed_until": nil,
+		"closed_at":     nil,
+	}
+	switch state {
+	case conversation_entity.ConversationClosed:
+		updates["closed_at"] = time.Now()
+	case conversation_entity.ConversationSnoozed:
+		updates["snoozed_until"] = snoozedUntil
+	}
+
+	if err := database.DB.Model(&conversation_entity.Conversation{}).
+		Where("id = ?", conversation.ID).
+		Updates(updates).Error; err != nil {
+		return event, err
+	}
+
+	updated, err := GetConversation(conversation.ID, conversation.WorkspaceID)
+	event.Conversation = updated
+	return event, err
+}
+
+// EnsureAssigneeInWorkspace returns gorm.ErrRecordNotFound when the member does not belong to the workspace.
+func EnsureAssigneeInWorkspace(memberID uuid.UUID, workspaceID uuid.UUID) error {
+	var member workspace_entity.WorkspaceMember
+	return database.DB.
+		Where("id = ? AND workspace_id = ?", memberID, workspaceID).
+		First(&member).Error
+}
@@ -0,0 +1,44 @@
+package conversation_service
+
+import (
+	"testing"
+
+	conversation_entity "github.com/Astervia/wacraft-server/src/conversation/entity"
+	conversation_model "github.com/Astervia/wacraft-server/src/conversation/model"
+)
+
+func TestReopensOnInbound(t *testing.T) {
+	cases := map[conversation_entity.ConversationState]bool{
+		conversation_entity.ConversationOpen:    false,
+		conversation_entity.ConversationPending: true,
+		conversation_entity.ConversationSnoozed: true,
+		conversation_entity.ConversationClosed:  true,
+	}
+	for state, want := range cases {
+		if got := reopensOnInbound(state); got != want {
+			t.Errorf("%s: expected reopen=%v, got %v", state, want, got)
+		}
+	}
+}
+
+func TestStateChangeEvent(t *testing.T) {
+	cases := []struct {
+		from conversation_entity.ConversationState
+		to   conversation_entity.ConversationState
+		want conversation_model.ConversationEventType
+	}{
+		{conversation_entity.ConversationOpen, conversation_entity.ConversationClosed, conversation_model.ConversationClosed},
+		{conversation_entity.ConversationSnoozed, conversation_entity.ConversationClosed, conversation_model.ConversationClosed},
+		{conversation_entity.ConversationClosed, conversation_entity.ConversationOpen, conversation_model.ConversationReopened},
+		{conversation_entity.ConversationClosed, conversation_entity.ConversationPending, conversation_model.ConversationReopened},
+		{conversation_entity.ConversationSnoozed, conversation_entity.ConversationOpen, conversation_model.ConversationReopened},
+		{conversation_entity.ConversationPending, conversation_entity.ConversationOpen, conversation_model.ConversationStateChange},
+		{conversation_entity.ConversationOpen, conversation_entity.ConversationSnoozed, conversation_model.ConversationStateChange},
+		{conversation_entity.ConversationOpen, conversation_entity.ConversationPending, conversation_model.ConversationStateChange},
+	}
+	for _, c := range cases {
+		if got := stateChangeEvent(c.from, c.to); got != c.want {
+			t.Errorf("%s -> %s: expected %s, got %s", c.from, c.to, c.want, got)
+		}
+	}
+}
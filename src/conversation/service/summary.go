@@ -0,0 +1,112 @@
+package conversation_service
+
+import (
+	"fmt"
+	"time"
+
+	message_entity "github.com/Astervia/wacraft-core/src/message/entity"
+	conversation_entity "github.com/Astervia/wacraft-server/src/conversation/entity"
+	"github.com/Astervia/wacraft-server/src/database"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// statusRankExpr orders statuses so late webhooks never move the conversation backwards.
+// Unknown statuses rank 0 and are always overwritten.
+func statusRankExpr(column string) string {
+	return fmt.Sprintf(`COALESCE(array_position(ARRAY['sent', 'delivered', 'read', 'failed']::varchar[], %s), 0)`, column)
+}
+
+// recordMessageInConversation creates the conversation of the contact when it has none
+// and moves its last message and counters forward. New conversations start open when
+// the contact wrote and pending when the team did.
+const recordMessageInConversation = `
+INSERT INTO conversations (
+	id, messaging_product_contact_id, workspace_id, state,
+	last_message_id, last_message_at, last_inbound_at, last_outbound_at,
+	message_count, inbound_count, outbound_count, created_at, updated_at
+)
+SELECT gen_random_uuid(), @mpc_id::uuid, mp.workspace_id, @state,
+	@message_id::uuid, @at::timestamptz, @inbound_at::timestamptz, @outbound_at::timestamptz,
+	1, @inbound::bigint, @outbound::bigint, NOW(), NOW()
+  FROM messaging_products mp
+ WHERE mp.id = @mp_id AND mp.workspace_id IS NOT NULL
+ON CONFLICT (messaging_product_contact_id) DO UPDATE SET
+	last_message_id  = CASE WHEN conversations.last_message_id IS NULL
+	                          OR EXCLUDED.last_message_at >= conversations.last_message_at
+	                        THEN EXCLUDED.last_message_id ELSE conversations.last_message_id END,
+	last_status      = CASE WHEN conversations.last_message_id IS NULL
+	                          OR EXCLUDED.last_message_at >= conversations.last_message_at
+	                        THEN NULL ELSE conversations.last_status END,
+	last_status_at   = CASE WHEN conversations.last_message_id IS NULL
+	                          OR EXCLUDED.last_message_at >= conversations.last_message_at
+	                        THEN NULL ELSE conversations.last_status_at END,
+	last_message_at  = CASE WHEN conversations.last_message_id IS NULL
+	                        THEN EXCLUDED.last_message_at
+	                        ELSE GREATEST(conversations.last_message_at, EXCLUDED.last_message_at) END,
+	last_inbound_at  = GREATEST(conversations.last_inbound_at, EXCLUDED.last_inbound_at),
+	last_outbound_at = GREATEST(conversations.last_outbound_at, EXCLUDED.last_outbound_at),
+	message_count    = conversations.message_count + 1,
+	inbound_count    = conversations.inbound_count + EXCLUDED.inbound_count,
+	outbound_count   = conversations.outbound_count + EXCLUDED.outbound_count,
+	updated_at       = NOW()`
+
+// RecordMessageInConversation updates the conversation of the contact that sent or
+// received the message. It must run after the message was created so its ID and
+// creation time are known. Messages of messaging products without workspace are ignored.
+func RecordMessageInConversation(message message_entity.Message, db *gorm.DB) error {
+	if db == nil {
+		db = database.DB
+	}
+
+	mpcID := message.FromID
+	inbound := 1
+	state := conversation_entity.ConversationOpen
+	if mpcID == nil {
+		mpcID = message.ToID
+		inbound = 0
+		state = conversation_entity.ConversationPending
+	}
+	if mpcID == nil {
+		return nil
+	}
+
+	at := message.CreatedAt
+	if at.IsZero() {
+		at = time.Now()
+	}
+	var inboundAt, outboundAt *time.Time
+	if inbound == 1 {
+		inboundAt = &at
+	} else {
+		outboundAt = &at
+	}
+
+	return db.Exec(recordMessageInConversation, map[string]any{
+		"mpc_id":      *mpcID,
+		"mp_id":       message.MessagingProductID,
+		"state":       string(state),
+		"message_id":  message.ID,
+		"at":          at,
+		"inbound_at":  inboundAt,
+		"outbound_at": outboundAt,
+		"inbound":     inbound,
+		"outbound":    1 - inbound,
+	}).Error
+}
+
+// RecordStatusInConversation stores the status of a message on the conversation whose
+// last message it is. Statuses that are less advanced than the stored one are ignored.
+func RecordStatusInConversation(messageID uuid.UUID, status string, at time.Time, db *gorm.DB) error {
+	if db == nil {
+		db = database.DB
+	}
+
+	return db.Exec(
+		`UPDATE conversations
+		    SET last_status = ?, last_status_at = ?, updated_at = NOW()
+		  WHERE last_message_id = ?
+		    AND `+statusRankExpr("last_status")+` <= `+statusRankExpr("?::varchar"),
+		status, at, messageID, status,
+	).Error
+}
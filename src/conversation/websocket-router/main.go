@@ -0,0 +1,17 @@
+package conversation_websocket
+
+import (
+	conversation_handler "github.com/Astervia/wacraft-server/src/conversation/handler"
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+)
+
+func Route(app fiber.Router) {
+	group := app.Group("/conversation")
+
+	// This route must handle the registering, broadcasting, and unregistering of the connections.
+	group.Get(
+		"/event",
+		websocket.New(conversation_handler.ConversationEventSubscription),
+	)
+}
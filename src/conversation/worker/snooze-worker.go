@@ -0,0 +1,86 @@
+package conversation_worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Astervia/wacraft-server/src/config/env"
+	conversation_handler "github.com/Astervia/wacraft-server/src/conversation/handler"
+	conversation_service "github.com/Astervia/wacraft-server/src/conversation/service"
+	"github.com/pterm/pterm"
+)
+
+// SnoozeBatchSize is the max number of snoozed conversations woken per poll.
+const SnoozeBatchSize = 100
+
+// SnoozeWorker moves snoozed conversations back to open once their snoozed_until passes.
+type SnoozeWorker struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewSnoozeWorker creates a new snooze worker.
+func NewSnoozeWorker() *SnoozeWorker {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &SnoozeWorker{
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Start wakes the conversations that became due while the server was down and
+// begins the polling loop.
+func (w *SnoozeWorker) Start() {
+	w.wakeDueConversations()
+
+	w.wg.Add(1)
+	go w.run()
+	pterm.DefaultLogger.Info("Conversation snooze worker started")
+}
+
+// Stop gracefully stops the snooze worker.
+func (w *SnoozeWorker) Stop() {
+	pterm.DefaultLogger.Info("Stopping conversation snooze worker...")
+	w.cancel()
+	w.wg.Wait()
+	pterm.DefaultLogger.Info("Conversation snooze worker stopped")
+}
+
+// run is the main polling loop.
+func (w *SnoozeWorker) run() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(env.ConversationSnoozePollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.ctx.Done():
+			return
+		case <-ticker.C:
+			w.wakeDueConversations()
+		}
+	}
+}
+
+// wakeDueConversations reopens due conversations batch by batch and broadcasts each change.
+// Claiming is atomic, so several instances can run the worker at once.
+func (w *SnoozeWorker) wakeDueConversations() {
+	for w.ctx.Err() == nil {
+		events, err := conversation_service.WakeSnoozedConversations(time.Now(), SnoozeBatchSize, nil)
+		if err != nil {
+			pterm.DefaultLogger.Error("Conversation snooze: failed to wake snoozed conversations: " + err.Error())
+			return
+		}
+
+		for _, event := range events {
+			go conversation_handler.BroadcastConversationEvent(event.Conversation.WorkspaceID, event)
+		}
+
+		if len(events) < SnoozeBatchSize {
+			return
+		}
+	}
+}
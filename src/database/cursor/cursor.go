@@ -0,0 +1,153 @@
+// Package database_cursor implements opaque keyset cursors over a (timestamp, id) key.
+//
+// Listings in cursor mode are always ordered from newest to oldest. The next
+// cursor walks towards older rows and the previous cursor back towards newer ones.
+package database_cursor
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DefaultLimit is the page size used when the request does not set one.
+const DefaultLimit = 20
+
+// ErrInvalidCursor is returned when a cursor cannot be decoded.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor is the position of a row in a keyset listing.
+type Cursor struct {
+	Time     time.Time `json:"t"`
+	ID       uuid.UUID `json:"id"`
+	Backward bool      `json:"b,omitempty"` // Walk towards newer rows (previous page).
+}
+
+// Encode returns the opaque representation of the cursor.
+func (c Cursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// Decode parses a cursor produced by Encode.
+func Decode(s string) (Cursor, error) {
+	var c Cursor
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+	if err := json.Unmarshal(b, &c); err != nil {
+		return c, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+	return c, nil
+}
+
+// Query is embedded in listing queries to opt in to cursor pagination.
+type Query struct {
+	Pagination string `json:"pagination,omitempty" query:"pagination"` // Set to "cursor" to receive a Page instead of a plain array.
+	Cursor     string `json:"cursor,omitempty" query:"cursor"`         // next_cursor or prev_cursor of a previous Page.
+}
+
+// Enabled reports whether the request asked for cursor pagination.
+func (q Query) Enabled() bool {
+	return q.Pagination == "cursor" || q.Cursor != ""
+}
+
+// Parse decodes the cursor of the query. It returns nil for the first page.
+func (q Query) Parse() (*Cursor, error) {
+	if q.Cursor == "" {
+		return nil, nil
+	}
+	c, err := Decode(q.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Page is the response of a listing in cursor mode.
+type Page[T any] struct {
+	Data       []T     `json:"data"`
+	NextCursor *string `json:"next_cursor"` // nil when there are no older rows.
+	PrevCursor *string `json:"prev_cursor"` // nil when there are no newer rows.
+}
+
+// Limit returns a usable page size.
+func Limit(limit int) int {
+	if limit <= 0 {
+		return DefaultLimit
+	}
+	return limit
+}
+
+// Apply adds the keyset condition, order and limit to db. One extra row is
+// fetched so NewPage can tell whether another page exists.
+func Apply(db *gorm.DB, cursor *Cursor, timeColumn string, idColumn string, limit int) *gorm.DB {
+	order := "DESC"
+	if cursor != nil {
+		op := "<"
+		if cursor.Backward {
+			op = ">"
+			order = "ASC"
+		}
+		db = db.Where(
+			fmt.Sprintf("(%s, %s) %s (?, ?)", timeColumn, idColumn, op),
+			cursor.Time, cursor.ID,
+		)
+	}
+
+	return db.
+		Order(fmt.Sprintf("%s %s", timeColumn, order)).
+		Order(fmt.Sprintf("%s %s", idColumn, order)).
+		Limit(limit + 1)
+}
+
+// NewPage builds the page from rows fetched with Apply. key returns the cursor position of a row.
+func NewPage[T any](rows []T, cursor *Cursor, limit int, key func(T) Cursor) Page[T] {
+	hasMore := len(rows) > limit
+	if hasMore {
+		rows = rows[:limit]
+	}
+
+	backward := cursor != nil && cursor.Backward
+	if backward {
+		slices.Reverse(rows)
+	}
+
+	page := Page[T]{Data: rows}
+	if len(rows) == 0 {
+		if cursor != nil {
+			// Let the client walk back from an empty page.
+			reverse := *cursor
+			reverse.Backward = !cursor.Backward
+			encoded := reverse.Encode()
+			if backward {
+				page.NextCursor = &encoded
+			} else {
+				page.PrevCursor = &encoded
+			}
+		}
+		return page
+	}
+
+	if hasMore || backward {
+		next := key(rows[len(rows)-1])
+		next.Backward = false
+		encoded := next.Encode()
+		page.NextCursor = &encoded
+	}
+	if (backward && hasMore) || (!backward && cursor != nil) {
+		prev := key(rows[0])
+		prev.Backward = true
+		encoded := prev.Encode()
+		page.PrevCursor = &encoded
+	}
+
+	return page
+}
@@ -0,0 +1,103 @@
+package database_cursor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type row struct {
+	at time.Time
+	id uuid.UUID
+}
+
+func key(r row) Cursor {
+	return Cursor{Time: r.at, ID: r.id}
+}
+
+func rows(n int) []row {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	out := make([]row, n)
+	for i := range out {
+		out[i] = row{at: base.Add(-time.Duration(i) * time.Minute), id: uuid.New()}
+	}
+	return out
+}
+
+func TestCursor_EncodeDecode(t *testing.T) {
+	c := Cursor{Time: time.Date(2026, 1, 1, 12, 30, 0, 123456000, time.UTC), ID: uuid.New(), Backward: true}
+
+	decoded, err := Decode(c.Encode())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !decoded.Time.Equal(c.Time) || decoded.ID != c.ID || decoded.Backward != c.Backward {
+		t.Errorf("expected %+v, got %+v", c, decoded)
+	}
+}
+
+func TestDecode_Invalid(t *testing.T) {
+	for _, s := range []string{"not base64!", "bm90IGpzb24"} {
+		if _, err := Decode(s); err == nil {
+			t.Errorf("expected error decoding %q", s)
+		}
+	}
+}
+
+func TestNewPage_FirstPage(t *testing.T) {
+	page := NewPage(rows(3), nil, 2, key)
+
+	if len(page.Data) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(page.Data))
+	}
+	if page.NextCursor == nil {
+		t.Error("expected next cursor when more rows exist")
+	}
+	if page.PrevCursor != nil {
+		t.Error("expected no previous cursor on the first page")
+	}
+}
+
+func TestNewPage_LastPage(t *testing.T) {
+	data := rows(2)
+	cursor := &Cursor{Time: time.Now(), ID: uuid.New()}
+
+	page := NewPage(data, cursor, 2, key)
+
+	if page.NextCursor != nil {
+		t.Error("expected no next cursor on the last page")
+	}
+	if page.PrevCursor == nil {
+		t.Fatal("expected previous cursor when walking forward")
+	}
+	prev, _ := Decode(*page.PrevCursor)
+	if !prev.Backward || prev.ID != data[0].id {
+		t.Errorf("expected backward cursor at the first row, got %+v", prev)
+	}
+}
+
+func TestNewPage_Backward(t *testing.T) {
+	// Rows fetched backward come oldest first.
+	data := rows(3)
+	asc := []row{data[2], data[1], data[0]}
+	cursor := &Cursor{Time: time.Now(), ID: uuid.New(), Backward: true}
+
+	page := NewPage(asc, cursor, 2, key)
+
+	if len(page.Data) != 2 || page.Data[0].id != data[1].id || page.Data[1].id != data[2].id {
+		t.Fatalf("expected rows reversed to newest first, got %+v", page.Data)
+	}
+	if page.PrevCursor == nil || page.NextCursor == nil {
+		t.Error("expected both cursors when walking backward with more rows")
+	}
+}
+
+func TestQuery_Enabled(t *testing.T) {
+	if (Query{}).Enabled() {
+		t.Error("expected offset mode by default")
+	}
+	if !(Query{Pagination: "cursor"}).Enabled() || !(Query{Cursor: "x"}).Enabled() {
+		t.Error("expected cursor mode")
+	}
+}
@@ -0,0 +1,41 @@
+package database_cursor
+
+import (
+	"fmt"
+
+	common_model "github.com/Astervia/wacraft-core/src/common/model"
+	database_model "github.com/Astervia/wacraft-core/src/database/model"
+	"gorm.io/gorm"
+)
+
+// AuditKey returns the keyset position of an entity from its audit fields.
+func AuditKey(audit common_model.Audit) Cursor {
+	return Cursor{Time: audit.CreatedAt, ID: audit.ID}
+}
+
+// GetPage is the keyset counterpart of repository.GetPaginated. Rows matching the
+// non-zero fields of entity and the whereable are ordered by (created_at, id) of
+// table, newest first.
+func GetPage[T any](
+	entity T,
+	whereable database_model.Whereable,
+	table string,
+	cursor *Cursor,
+	limit int,
+	key func(T) Cursor,
+	db *gorm.DB,
+) (Page[T], error) {
+	var rows []T
+
+	db = db.Model(&entity).Where(&entity)
+	if whereable != nil {
+		whereable.Where(&db, fmt.Sprintf("%q", table))
+	}
+	db = Apply(db, cursor, table+".created_at", table+".id", limit)
+
+	if err := db.Find(&rows).Error; err != nil {
+		return Page[T]{}, err
+	}
+
+	return NewPage(rows, cursor, limit, key), nil
+}
@@ -0,0 +1,365 @@
+// Package database_filter compiles the JSON filter DSL accepted by the
+// POST .../search endpoints into parameterized GORM clauses.
+//
+// A filter is a tree of groups and conditions:
+//
+//	{"and": [
+//	  {"field": "created_at", "op": "gte", "value": "2026-01-01T00:00:00Z"},
+//	  {"or": [
+//	    {"field": "sender_data.text.body", "op": "ilike", "value": "%refund%"},
+//	    {"not": {"field": "receiver_data.type", "op": "in", "value": ["reaction", "sticker"]}}
+//	  ]}
+//	]}
+//
+// Only fields declared in the resource Schema can be used. Column names and
+// JSONB roots come from the schema; every value and JSON path is bound as a
+// parameter.
+package database_filter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// MaxDepth is how deep groups may be nested.
+	MaxDepth = 8
+	// MaxConditions is the max number of conditions in a single filter.
+	MaxConditions = 100
+	// MaxInValues is the max number of values accepted by the in operator.
+	MaxInValues = 500
+	// MaxPathDepth is the max number of segments of a JSONB path.
+	MaxPathDepth = 8
+)
+
+// ErrInvalidFilter is wrapped by every error caused by the filter content.
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// Operator compares a field with the condition value.
+type Operator string
+
+const (
+	Eq     Operator = "eq"
+	Neq    Operator = "neq"
+	Gt     Operator = "gt"
+	Gte    Operator = "gte"
+	Lt     Operator = "lt"
+	Lte    Operator = "lte"
+	In     Operator = "in"
+	Exists Operator = "exists" // Value is a boolean; omitted means true.
+	ILike  Operator = "ilike"  // Accent-insensitive; the value is a LIKE pattern.
+)
+
+// FieldType tells how condition values are parsed for a field.
+type FieldType int
+
+const (
+	String FieldType = iota
+	Number
+	Bool
+	Time
+	UUID
+)
+
+// Field is a filterable column.
+type Field struct {
+	Column string // Qualified column, e.g. "messages.created_at".
+	Type   FieldType
+}
+
+// Schema whitelists what a resource can be filtered and ordered by.
+type Schema struct {
+	Table  string            // Used for the default order.
+	Fields map[string]Field  // Filterable columns by field name.
+	JSON   map[string]string // JSONB columns by root name; any path below them can be filtered.
+}
+
+// Filter is a node of the filter tree. Exactly one of And, Or, Not or Field must be set.
+type Filter struct {
+	And []Filter `json:"and,omitempty"`
+	Or  []Filter `json:"or,omitempty"`
+	Not *Filter  `json:"not,omitempty"`
+
+	Field string          `json:"field,omitempty"` // Column name or JSONB path such as "sender_data.text.body".
+	Op    Operator        `json:"op,omitempty"`
+	Value json.RawMessage `json:"value,omitempty" swaggertype:"object"`
+}
+
+var pathSegment = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// Compile turns the filter into a parameterized expression for db.Where.
+func (f Filter) Compile(schema Schema) (clause.Expr, error) {
+	c := compiler{schema: schema}
+	sql, err := c.node(f, 0)
+	if err != nil {
+		return clause.Expr{}, err
+	}
+	return clause.Expr{SQL: sql, Vars: c.vars}, nil
+}
+
+type compiler struct {
+	schema     Schema
+	vars       []any
+	conditions int
+}
+
+func invalid(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrInvalidFilter, fmt.Sprintf(format, args...))
+}
+
+func (c *compiler) node(f Filter, depth int) (string, error) {
+	if depth > MaxDepth {
+		return "", invalid("groups nested deeper than %d levels", MaxDepth)
+	}
+
+	set := 0
+	for _, isSet := range []bool{f.And != nil, f.Or != nil, f.Not != nil, f.Field != ""} {
+		if isSet {
+			set++
+		}
+	}
+	if set != 1 {
+		return "", invalid("each node must have exactly one of and, or, not or field")
+	}
+
+	switch {
+	case f.And != nil:
+		return c.group(f.And, " AND ", depth)
+	case f.Or != nil:
+		return c.group(f.Or, " OR ", depth)
+	case f.Not != nil:
+		sql, err := c.node(*f.Not, depth+1)
+		if err != nil {
+			return "", err
+		}
+		return "NOT (" + sql + ")", nil
+	default:
+		c.conditions++
+		if c.conditions > MaxConditions {
+			return "", invalid("more than %d conditions", MaxConditions)
+		}
+		return c.condition(f)
+	}
+}
+
+func (c *compiler) group(nodes []Filter, sep string, depth int) (string, error) {
+	if len(nodes) == 0 {
+		return "", invalid("empty group")
+	}
+
+	parts := make([]string, 0, len(nodes))
+	for _, n := range nodes {
+		sql, err := c.node(n, depth+1)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, "("+sql+")")
+	}
+	return strings.Join(parts, sep), nil
+}
+
+// resolve returns the SQL expression of a field and whether it is a JSONB path.
+func (c *compiler) resolve(name string) (string, FieldType, bool, error) {
+	if field, ok := c.schema.Fields[name]; ok {
+		return field.Column, field.Type, false, nil
+	}
+
+	root, path, found := strings.Cut(name, ".")
+	column, ok := c.schema.JSON[root]
+	if !ok || !found {
+		return "", 0, false, invalid("unknown field %q", name)
+	}
+
+	segments := strings.Split(path, ".")
+	if len(segments) > MaxPathDepth {
+		return "", 0, false, invalid("path %q is deeper than %d segments", name, MaxPathDepth)
+	}
+	for _, s := range segments {
+		if !pathSegment.MatchString(s) {
+			return "", 0, false, invalid("invalid path segment %q in %q", s, name)
+		}
+	}
+
+	c.vars = append(c.vars, "{"+strings.Join(segments, ",")+"}")
+	return column, String, true, nil
+}
+
+func (c *compiler) condition(f Filter) (string, error) {
+	column, fieldType, isPath, err := c.resolve(f.Field)
+	if err != nil {
+		return "", err
+	}
+
+	// JSONB paths are compared as text, or as numbers by range operators;
+	// exists checks the raw JSON value.
+	expr := column
+	if isPath {
+		expr = "(" + column + " #>> ?::text[])"
+		if f.Op == Exists {
+			expr = "(" + column + " #> ?::text[])"
+		}
+	}
+
+	switch f.Op {
+	case Eq, Neq, Gt, Gte, Lt, Lte:
+		if f.Op != Eq && f.Op != Neq && fieldType == Bool {
+			return "", invalid("%s is not supported on %q", f.Op, f.Field)
+		}
+		if isPath && f.Op != Eq && f.Op != Neq {
+			return c.numericPathRange(f, column)
+		}
+		value, err := parseValue(f.Value, fieldType, isPath)
+		if err != nil {
+			return "", invalid("%q: %v", f.Field, err)
+		}
+		c.vars = append(c.vars, value)
+		return expr + " " + comparison[f.Op] + " ?", nil
+
+	case In:
+		var raws []json.RawMessage
+		if err := json.Unmarshal(f.Value, &raws); err != nil || len(raws) == 0 {
+			return "", invalid("%q: in expects a non-empty array", f.Field)
+		}
+		if len(raws) > MaxInValues {
+			return "", invalid("%q: in accepts at most %d values", f.Field, MaxInValues)
+		}
+		values := make([]any, len(raws))
+		for i, raw := range raws {
+			if values[i], err = parseValue(raw, fieldType, isPath); err != nil {
+				return "", invalid("%q: %v", f.Field, err)
+			}
+		}
+		c.vars = append(c.vars, values)
+		return expr + " IN ?", nil
+
+	case Exists:
+		exists := true
+		if len(f.Value) > 0 {
+			if err := json.Unmarshal(f.Value, &exists); err != nil {
+				return "", invalid("%q: exists expects a boolean", f.Field)
+			}
+		}
+		if isPath {
+			// JSON null counts as missing.
+			if exists {
+				return "COALESCE(" + expr + " <> 'null'::jsonb, false)", nil
+			}
+			return "COALESCE(" + expr + " = 'null'::jsonb, true)", nil
+		}
+		if exists {
+			return expr + " IS NOT NULL", nil
+		}
+		return expr + " IS NULL", nil
+
+	case ILike:
+		if fieldType != String {
+			return "", invalid("ilike is only supported on text fields, not %q", f.Field)
+		}
+		var pattern string
+		if err := json.Unmarshal(f.Value, &pattern); err != nil {
+			return "", invalid("%q: ilike expects a string", f.Field)
+		}
+		c.vars = append(c.vars, pattern)
+		return "immutable_unaccent(COALESCE(" + expr + ", '')) ILIKE immutable_unaccent(?)", nil
+
+	default:
+		return "", invalid("unknown operator %q", f.Op)
+	}
+}
+
+// numericPattern matches the text of JSON numbers, and of the numeric strings
+// Meta sends, such as timestamps.
+const numericPattern = `^-?[0-9]+(\.[0-9]+)?([eE][-+]?[0-9]+)?$`
+
+// numericPathRange compares a JSONB path with a number. Comparing the text
+// would order "10" before "9", so the path is cast to numeric; values that are
+// not numeric never match.
+func (c *compiler) numericPathRange(f Filter, column string) (string, error) {
+	var value float64
+	if err := json.Unmarshal(f.Value, &value); err != nil {
+		return "", invalid("%q: %s on a JSON path expects a number", f.Field, f.Op)
+	}
+
+	// The path bound by resolve is used twice.
+	path := c.vars[len(c.vars)-1]
+	c.vars = append(c.vars, numericPattern, path, value)
+	text := "(" + column + " #>> ?::text[])"
+	return "(CASE WHEN " + text + " ~ ? THEN " + text + "::numeric END) " + comparison[f.Op] + " ?", nil
+}
+
+var comparison = map[Operator]string{
+	Eq:  "=",
+	Neq: "IS DISTINCT FROM",
+	Gt:  ">",
+	Gte: ">=",
+	Lt:  "<",
+	Lte: "<=",
+}
+
+// parseValue decodes a condition value according to the field type. Values
+// compared for equality with JSONB paths may be strings, numbers or booleans
+// and are compared with their text form.
+func parseValue(raw json.RawMessage, fieldType FieldType, isPath bool) (any, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, errors.New("value is required, use exists to match missing values")
+	}
+
+	if isPath {
+		var value any
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return nil, err
+		}
+		switch v := value.(type) {
+		case string:
+			return v, nil
+		case float64, bool:
+			return strings.TrimSpace(string(raw)), nil
+		default:
+			return nil, errors.New("expected a string, number or boolean")
+		}
+	}
+
+	switch fieldType {
+	case Number:
+		var v float64
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, errors.New("expected a number")
+		}
+		return v, nil
+	case Bool:
+		var v bool
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, errors.New("expected a boolean")
+		}
+		return v, nil
+	case Time:
+		var v time.Time
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, errors.New("expected an RFC 3339 timestamp")
+		}
+		return v, nil
+	case UUID:
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return nil, errors.New("expected a UUID string")
+		}
+		v, err := uuid.Parse(s)
+		if err != nil {
+			return nil, errors.New("expected a UUID string")
+		}
+		return v, nil
+	default:
+		var v string
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, errors.New("expected a string")
+		}
+		return v, nil
+	}
+}
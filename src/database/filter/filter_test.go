@@ -0,0 +1,189 @@
+package database_filter
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var testSchema = Schema{
+	Table: "messages",
+	Fields: map[string]Field{
+		"id":         {Column: "messages.id", Type: UUID},
+		"created_at": {Column: "messages.created_at", Type: Time},
+		"name":       {Column: "messages.name", Type: String},
+		"count":      {Column: "messages.count", Type: Number},
+		"active":     {Column: "messages.active", Type: Bool},
+	},
+	JSON: map[string]string{
+		"sender_data": "messages.sender_data",
+	},
+}
+
+func compile(t *testing.T, body string) (string, []any, error) {
+	t.Helper()
+	var f Filter
+	if err := json.Unmarshal([]byte(body), &f); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	expr, err := f.Compile(testSchema)
+	return expr.SQL, expr.Vars, err
+}
+
+func TestCompile_NestedGroups(t *testing.T) {
+	sql, vars, err := compile(t, `{"and": [
+		{"field": "created_at", "op": "gte", "value": "2026-01-01T00:00:00Z"},
+		{"or": [
+			{"field": "sender_data.text.body", "op": "ilike", "value": "%refund%"},
+			{"not": {"field": "name", "op": "in", "value": ["a", "b"]}}
+		]}
+	]}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantSQL := "(messages.created_at >= ?) AND (" +
+		"(immutable_unaccent(COALESCE((messages.sender_data #>> ?::text[]), '')) ILIKE immutable_unaccent(?)) OR " +
+		"(NOT (messages.name IN ?)))"
+	if sql != wantSQL {
+		t.Errorf("expected SQL\n%s\ngot\n%s", wantSQL, sql)
+	}
+
+	wantVars := []any{
+		time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		"{text,body}",
+		"%refund%",
+		[]any{"a", "b"},
+	}
+	if !reflect.DeepEqual(vars, wantVars) {
+		t.Errorf("expected vars %#v, got %#v", wantVars, vars)
+	}
+}
+
+func TestCompile_TypedValues(t *testing.T) {
+	id := uuid.New()
+	cases := []struct {
+		body string
+		sql  string
+		val  any
+	}{
+		{`{"field": "id", "op": "eq", "value": "` + id.String() + `"}`, "messages.id = ?", id},
+		{`{"field": "count", "op": "lt", "value": 3}`, "messages.count < ?", float64(3)},
+		{`{"field": "active", "op": "neq", "value": true}`, "messages.active IS DISTINCT FROM ?", true},
+		{`{"field": "sender_data.type", "op": "eq", "value": "text"}`, "(messages.sender_data #>> ?::text[]) = ?", "text"},
+		{`{"field": "sender_data.interactive.action.buttons.0.reply.id", "op": "eq", "value": 12}`, "(messages.sender_data #>> ?::text[]) = ?", "12"},
+	}
+	for _, tc := range cases {
+		sql, vars, err := compile(t, tc.body)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.body, err)
+			continue
+		}
+		if sql != tc.sql {
+			t.Errorf("%s: expected %q, got %q", tc.body, tc.sql, sql)
+		}
+		if got := vars[len(vars)-1]; !reflect.DeepEqual(got, tc.val) {
+			t.Errorf("%s: expected value %#v, got %#v", tc.body, tc.val, got)
+		}
+	}
+}
+
+func TestCompile_RangeOnJSONPathComparesNumbers(t *testing.T) {
+	sql, vars, err := compile(t, `{"field": "sender_data.x", "op": "gt", "value": 9}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantSQL := "(CASE WHEN (messages.sender_data #>> ?::text[]) ~ ? THEN (messages.sender_data #>> ?::text[])::numeric END) > ?"
+	if sql != wantSQL {
+		t.Errorf("expected SQL\n%s\ngot\n%s", wantSQL, sql)
+	}
+	wantVars := []any{"{x}", numericPattern, "{x}", float64(9)}
+	if !reflect.DeepEqual(vars, wantVars) {
+		t.Errorf("expected vars %#v, got %#v", wantVars, vars)
+	}
+
+	// Ranges on text would compare lexically, so they are rejected.
+	for _, body := range []string{
+		`{"field": "sender_data.timestamp", "op": "lte", "value": "2026-01-01T00:00:00Z"}`,
+		`{"field": "sender_data.x", "op": "gte", "value": true}`,
+	} {
+		if _, _, err := compile(t, body); !errors.Is(err, ErrInvalidFilter) {
+			t.Errorf("%s: expected ErrInvalidFilter, got %v", body, err)
+		}
+	}
+}
+
+func TestCompile_Exists(t *testing.T) {
+	cases := map[string]string{
+		`{"field": "name", "op": "exists"}`:                                   "messages.name IS NOT NULL",
+		`{"field": "name", "op": "exists", "value": false}`:                   "messages.name IS NULL",
+		`{"field": "sender_data.context.id", "op": "exists"}`:                 "COALESCE((messages.sender_data #> ?::text[]) <> 'null'::jsonb, false)",
+		`{"field": "sender_data.context.id", "op": "exists", "value": false}`: "COALESCE((messages.sender_data #> ?::text[]) = 'null'::jsonb, true)",
+	}
+	for body, want := range cases {
+		sql, _, err := compile(t, body)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", body, err)
+			continue
+		}
+		if sql != want {
+			t.Errorf("%s: expected %q, got %q", body, want, sql)
+		}
+	}
+}
+
+func TestCompile_Rejects(t *testing.T) {
+	cases := []string{
+		`{}`,
+		`{"and": []}`,
+		`{"field": "name", "op": "eq", "value": "a", "and": [{"field": "name", "op": "exists"}]}`,
+		`{"field": "password", "op": "eq", "value": "a"}`,
+		`{"field": "sender_data", "op": "eq", "value": "a"}`,
+		`{"field": "sender_data.text'); DROP TABLE messages; --", "op": "eq", "value": "a"}`,
+		`{"field": "name", "op": "regex", "value": "a"}`,
+		`{"field": "name", "op": "eq"}`,
+		`{"field": "name", "op": "eq", "value": null}`,
+		`{"field": "count", "op": "eq", "value": "three"}`,
+		`{"field": "count", "op": "ilike", "value": "%3%"}`,
+		`{"field": "active", "op": "gt", "value": true}`,
+		`{"field": "id", "op": "eq", "value": "not-a-uuid"}`,
+		`{"field": "created_at", "op": "gt", "value": "yesterday"}`,
+		`{"field": "name", "op": "in", "value": []}`,
+		`{"field": "name", "op": "in", "value": "a"}`,
+		`{"field": "sender_data.text", "op": "eq", "value": {"body": "a"}}`,
+	}
+	for _, body := range cases {
+		if _, _, err := compile(t, body); !errors.Is(err, ErrInvalidFilter) {
+			t.Errorf("%s: expected ErrInvalidFilter, got %v", body, err)
+		}
+	}
+}
+
+func TestCompile_Limits(t *testing.T) {
+	deep := `{"field": "name", "op": "exists"}`
+	for range MaxDepth + 1 {
+		deep = `{"not": ` + deep + `}`
+	}
+	if _, _, err := compile(t, deep); !errors.Is(err, ErrInvalidFilter) {
+		t.Errorf("expected nesting limit error, got %v", err)
+	}
+
+	conditions := make([]Filter, MaxConditions+1)
+	for i := range conditions {
+		conditions[i] = Filter{Field: "name", Op: Exists}
+	}
+	if _, err := (Filter{Or: conditions}).Compile(testSchema); !errors.Is(err, ErrInvalidFilter) {
+		t.Errorf("expected condition limit error, got %v", err)
+	}
+}
+
+func TestSearch_LimitCapped(t *testing.T) {
+	if _, err := (Search{Limit: MaxLimit + 1}).Apply(nil, testSchema); !errors.Is(err, ErrInvalidFilter) {
+		t.Errorf("expected limit error, got %v", err)
+	}
+}
@@ -0,0 +1,77 @@
+package database_filter
+
+import (
+	database_cursor "github.com/Astervia/wacraft-server/src/database/cursor"
+	"gorm.io/gorm"
+)
+
+// MaxOrder is the max number of order fields of a search.
+const MaxOrder = 5
+
+// MaxLimit is the max number of rows a search returns.
+const MaxLimit = 100
+
+// Order sorts the results by a schema field.
+type Order struct {
+	Field string `json:"field" validate:"required"`
+	Desc  bool   `json:"desc,omitempty"`
+}
+
+// Search is the body of the POST .../search endpoints.
+type Search struct {
+	Where   *Filter `json:"where,omitempty"`
+	OrderBy []Order `json:"order_by,omitempty" validate:"omitempty,max=5,dive"`
+	Limit   int     `json:"limit,omitempty" validate:"min=0,max=100"`
+	Offset  int     `json:"offset,omitempty" validate:"min=0"`
+}
+
+// Apply adds the filter, order and pagination of the search to db. Results are
+// ordered newest first when the search sets no order.
+func (s Search) Apply(db *gorm.DB, schema Schema) (*gorm.DB, error) {
+	if s.Limit > MaxLimit {
+		return nil, invalid("at most %d results per page", MaxLimit)
+	}
+
+	if s.Where != nil {
+		expr, err := s.Where.Compile(schema)
+		if err != nil {
+			return nil, err
+		}
+		db = db.Where(expr)
+	}
+
+	if len(s.OrderBy) > MaxOrder {
+		return nil, invalid("at most %d order fields", MaxOrder)
+	}
+	for _, o := range s.OrderBy {
+		field, ok := schema.Fields[o.Field]
+		if !ok {
+			return nil, invalid("unknown order field %q", o.Field)
+		}
+		direction := " ASC"
+		if o.Desc {
+			direction = " DESC"
+		}
+		db = db.Order(field.Column + direction)
+	}
+	if len(s.OrderBy) == 0 {
+		db = db.Order(schema.Table + ".created_at DESC")
+	}
+	// Keeps offset pagination stable when the order has ties.
+	db = db.Order(schema.Table + ".id DESC")
+
+	return db.Offset(s.Offset).Limit(database_cursor.Limit(s.Limit)), nil
+}
+
+// Find runs the search on db, which must already hold the model and any
+// scoping joins, e.g. the workspace.
+func Find[T any](s Search, schema Schema, db *gorm.DB) ([]T, error) {
+	db, err := s.Apply(db, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []T{}
+	err = db.Find(&rows).Error
+	return rows, err
+}
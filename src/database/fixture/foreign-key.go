@@ -0,0 +1,17 @@
+// Package database_fixture prepares the database of the tests that store rows
+// referencing random parent IDs instead of creating the parent rows.
+package database_fixture
+
+import (
+	"github.com/Astervia/wacraft-server/src/database"
+	"gorm.io/gorm/clause"
+)
+
+// DropForeignKeys drops the named foreign keys of the table, like the campaign
+// handler tests do. Only the given constraints are dropped, and they are not
+// restored, so it must only run against a test database.
+func DropForeignKeys(table string, constraints ...string) {
+	for _, constraint := range constraints {
+		database.DB.Exec("ALTER TABLE ? DROP CONSTRAINT IF EXISTS ?", clause.Table{Name: table}, clause.Column{Name: constraint})
+	}
+}
@@ -16,9 +16,18 @@ import (
 	user_entity "github.com/Astervia/wacraft-core/src/user/entity"
 	webhook_entity "github.com/Astervia/wacraft-core/src/webhook/entity"
 	workspace_entity "github.com/Astervia/wacraft-core/src/workspace/entity"
+	account_update_entity "github.com/Astervia/wacraft-server/src/account-update/entity"
+	conversation_entity "github.com/Astervia/wacraft-server/src/conversation/entity"
 	"github.com/Astervia/wacraft-server/src/database"
 	_ "github.com/Astervia/wacraft-server/src/database/migrations"
 	_ "github.com/Astervia/wacraft-server/src/database/migrations-before"
+	inbound_receipt_entity "github.com/Astervia/wacraft-server/src/inbound-receipt/entity"
+	message_outbox_entity "github.com/Astervia/wacraft-server/src/message-outbox/entity"
+	message_reaction_entity "github.com/Astervia/wacraft-server/src/message-reaction/entity"
+	message_thread_entity "github.com/Astervia/wacraft-server/src/message-thread/entity"
+	webhook_in_event_entity "github.com/Astervia/wacraft-server/src/webhook-in-event/entity"
+	webhook_setting_entity "github.com/Astervia/wacraft-server/src/webhook-setting/entity"
+	workspace_setting_entity "github.com/Astervia/wacraft-server/src/workspace-setting/entity"
 	"github.com/pressly/goose/v3"
 	"github.com/pterm/pterm"
 )
@@ -45,10 +54,16 @@ func automaticMigrations() {
 		&workspace_entity.WorkspaceMemberPolicy{},
 		&workspace_entity.WorkspaceInvitation{},
 		&phone_config_entity.PhoneConfig{},
+		&workspace_setting_entity.WorkspaceSetting{},
 		&contact_entity.Contact{},
 		&messaging_product_entity.MessagingProduct{},
 		&messaging_product_entity.MessagingProductContact{},
 		&message_entity.Message{},
+		&message_outbox_entity.MessageOutbox{},
+		&message_thread_entity.MessageReply{},
+		&message_reaction_entity.MessageReaction{},
+		&inbound_receipt_entity.InboundReceipt{},
+		&conversation_entity.Conversation{},
 		// PREMIUM STARTS
 		&campaign_entity.Campaign{},
 		&campaign_entity.CampaignMessage{},
@@ -57,6 +72,11 @@ func automaticMigrations() {
 		&webhook_entity.Webhook{},
 		&webhook_entity.WebhookLog{},
 		&webhook_entity.WebhookDelivery{},
+		&webhook_setting_entity.WebhookSetting{},
+		&webhook_in_event_entity.WebhookInEvent{},
+		&account_update_entity.AccountUpdate{},
+		&account_update_entity.TemplateStatus{},
+		&account_update_entity.PhoneNumberHealth{},
 		&status_entity.Status{},
 		// Billing
 		&billing_entity.Plan{},
@@ -0,0 +1,65 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Astervia/wacraft-server/src/database"
+	"github.com/pressly/goose/v3"
+	"github.com/pterm/pterm"
+)
+
+func init() {
+	goose.AddMigrationContext(upMessageOutboxIndexes, downMessageOutboxIndexes)
+}
+
+func upMessageOutboxIndexes(ctx context.Context, tx *sql.Tx) error {
+	db := database.DB
+
+	stmts := []string{
+		// Partial index for the outbox worker poll (only indexes pending rows)
+		`CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_message_outbox_pending
+		   ON message_outbox(next_attempt_at) WHERE status = 'pending';`,
+
+		// Index for restart recovery of entries stuck in processing
+		`CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_message_outbox_processing
+		   ON message_outbox(updated_at) WHERE status = 'processing';`,
+
+		// Index for the entries whose response is stored again
+		`CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_message_outbox_unconfirmed
+		   ON message_outbox(updated_at) WHERE status = 'unconfirmed';`,
+	}
+
+	for _, s := range stmts {
+		if err := db.Exec(s).Error; err != nil {
+			pterm.DefaultLogger.Error(fmt.Sprintf("migration upMessageOutboxIndexes failed on: %s\nerr: %v", s, err))
+			return err
+		}
+		pterm.DefaultLogger.Info("Executed: " + s)
+	}
+
+	pterm.DefaultLogger.Info("message_outbox_indexes: all indexes created.")
+	return nil
+}
+
+func downMessageOutboxIndexes(ctx context.Context, tx *sql.Tx) error {
+	db := database.DB
+
+	stmts := []string{
+		`DROP INDEX CONCURRENTLY IF EXISTS idx_message_outbox_unconfirmed;`,
+		`DROP INDEX CONCURRENTLY IF EXISTS idx_message_outbox_processing;`,
+		`DROP INDEX CONCURRENTLY IF EXISTS idx_message_outbox_pending;`,
+	}
+
+	for _, s := range stmts {
+		if err := db.Exec(s).Error; err != nil {
+			pterm.DefaultLogger.Error(fmt.Sprintf("migration downMessageOutboxIndexes failed on: %s\nerr: %v", s, err))
+			return err
+		}
+		pterm.DefaultLogger.Info("Executed: " + s)
+	}
+
+	pterm.DefaultLogger.Info("message_outbox_indexes: all indexes dropped.")
+	return nil
+}
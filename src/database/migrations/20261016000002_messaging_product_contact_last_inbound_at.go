@@ -0,0 +1,66 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Astervia/wacraft-server/src/database"
+	"github.com/pressly/goose/v3"
+	"github.com/pterm/pterm"
+)
+
+func init() {
+	goose.AddMigrationContext(upMessagingProductContactLastInboundAt, downMessagingProductContactLastInboundAt)
+}
+
+func upMessagingProductContactLastInboundAt(ctx context.Context, tx *sql.Tx) error {
+	db := database.DB
+
+	stmts := []string{
+		// Last time the contact wrote to the number; opens the 24-hour customer service window
+		`ALTER TABLE messaging_product_contacts ADD COLUMN IF NOT EXISTS last_inbound_at TIMESTAMPTZ;`,
+
+		// Backfill from the messages already received
+		`UPDATE messaging_product_contacts mpc
+		    SET last_inbound_at = inbound.last_inbound_at
+		   FROM (
+		         SELECT from_id, MAX(created_at) AS last_inbound_at
+		           FROM messages
+		          WHERE from_id IS NOT NULL
+		          GROUP BY from_id
+		        ) inbound
+		  WHERE mpc.id = inbound.from_id
+		    AND mpc.last_inbound_at IS NULL;`,
+	}
+
+	for _, s := range stmts {
+		if err := db.Exec(s).Error; err != nil {
+			pterm.DefaultLogger.Error(fmt.Sprintf("migration upMessagingProductContactLastInboundAt failed on: %s\nerr: %v", s, err))
+			return err
+		}
+		pterm.DefaultLogger.Info("Executed: " + s)
+	}
+
+	pterm.DefaultLogger.Info("messaging_product_contact_last_inbound_at: column created and backfilled.")
+	return nil
+}
+
+func downMessagingProductContactLastInboundAt(ctx context.Context, tx *sql.Tx) error {
+	db := database.DB
+
+	stmts := []string{
+		`ALTER TABLE messaging_product_contacts DROP COLUMN IF EXISTS last_inbound_at;`,
+	}
+
+	for _, s := range stmts {
+		if err := db.Exec(s).Error; err != nil {
+			pterm.DefaultLogger.Error(fmt.Sprintf("migration downMessagingProductContactLastInboundAt failed on: %s\nerr: %v", s, err))
+			return err
+		}
+		pterm.DefaultLogger.Info("Executed: " + s)
+	}
+
+	pterm.DefaultLogger.Info("messaging_product_contact_last_inbound_at: column dropped.")
+	return nil
+}
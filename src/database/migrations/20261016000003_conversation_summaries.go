@@ -0,0 +1,120 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Astervia/wacraft-server/src/database"
+	"github.com/pressly/goose/v3"
+	"github.com/pterm/pterm"
+)
+
+func init() {
+	goose.AddMigrationContext(upConversationSummaries, downConversationSummaries)
+}
+
+func upConversationSummaries(ctx context.Context, tx *sql.Tx) error {
+	db := database.DB
+
+	stmts := []string{
+		// Backfill the last message and counters of each messaging product contact from the
+		// existing messages. Contacts without a conversation get a closed one, so the history
+		// is listed without flooding the inbox. Reaction messages are left out: they are
+		// moved to message_reactions.
+		`WITH last_messages AS (
+		     SELECT DISTINCT ON (COALESCE(from_id, to_id))
+		            COALESCE(from_id, to_id) AS mpc_id, id, created_at, messaging_product_id
+		       FROM messages
+		      WHERE deleted_at IS NULL AND COALESCE(from_id, to_id) IS NOT NULL
+		        AND COALESCE(receiver_data #>> '{reaction,message_id}', sender_data #>> '{reaction,message_id}', '') = ''
+		      ORDER BY COALESCE(from_id, to_id), created_at DESC, id DESC
+		 ), counters AS (
+		     SELECT COALESCE(from_id, to_id) AS mpc_id,
+		            MAX(created_at) FILTER (WHERE from_id IS NOT NULL) AS last_inbound_at,
+		            MAX(created_at) FILTER (WHERE from_id IS NULL) AS last_outbound_at,
+		            COUNT(*) AS message_count,
+		            COUNT(*) FILTER (WHERE from_id IS NOT NULL) AS inbound_count,
+		            COUNT(*) FILTER (WHERE from_id IS NULL) AS outbound_count
+		       FROM messages
+		      WHERE deleted_at IS NULL AND COALESCE(from_id, to_id) IS NOT NULL
+		        AND COALESCE(receiver_data #>> '{reaction,message_id}', sender_data #>> '{reaction,message_id}', '') = ''
+		      GROUP BY COALESCE(from_id, to_id)
+		 )
+		 INSERT INTO conversations (
+		     id, messaging_product_contact_id, workspace_id, state, closed_at,
+		     last_message_id, last_message_at, last_inbound_at, last_outbound_at,
+		     message_count, inbound_count, outbound_count, created_at, updated_at
+		 )
+		 SELECT gen_random_uuid(), lm.mpc_id, mp.workspace_id, 'closed', lm.created_at,
+		        lm.id, lm.created_at, c.last_inbound_at, c.last_outbound_at,
+		        c.message_count, c.inbound_count, c.outbound_count, NOW(), NOW()
+		   FROM last_messages lm
+		   JOIN counters c ON c.mpc_id = lm.mpc_id
+		   JOIN messaging_products mp ON mp.id = lm.messaging_product_id AND mp.workspace_id IS NOT NULL
+		 ON CONFLICT (messaging_product_contact_id) DO UPDATE SET
+		     last_message_id  = EXCLUDED.last_message_id,
+		     last_message_at  = EXCLUDED.last_message_at,
+		     last_inbound_at  = EXCLUDED.last_inbound_at,
+		     last_outbound_at = EXCLUDED.last_outbound_at,
+		     message_count    = EXCLUDED.message_count,
+		     inbound_count    = EXCLUDED.inbound_count,
+		     outbound_count   = EXCLUDED.outbound_count;`,
+
+		// Conversations without messages are ordered by their creation time
+		`UPDATE conversations SET last_message_at = created_at WHERE last_message_at IS NULL;`,
+
+		// Backfill the most advanced status of each last message
+		`UPDATE conversations cv
+		    SET last_status = st.status, last_status_at = st.created_at
+		   FROM (
+		         SELECT DISTINCT ON (message_id)
+		                message_id, product_data->>'status' AS status, created_at
+		           FROM statuses
+		          WHERE message_id IN (SELECT last_message_id FROM conversations)
+		          ORDER BY message_id,
+		                   COALESCE(array_position(ARRAY['sent', 'delivered', 'read', 'failed'], product_data->>'status'), 0) DESC,
+		                   created_at DESC
+		        ) st
+		  WHERE cv.last_message_id = st.message_id
+		    AND cv.last_status IS NULL;`,
+
+		// Keyset index for the conversation lists (most recent conversations first)
+		`CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_conversations_workspace_last_message
+		   ON conversations(workspace_id, last_message_at DESC, messaging_product_contact_id DESC);`,
+	}
+
+	for _, s := range stmts {
+		if err := db.Exec(s).Error; err != nil {
+			pterm.DefaultLogger.Error(fmt.Sprintf("migration upConversationSummaries failed on: %s\nerr: %v", s, err))
+			return err
+		}
+		pterm.DefaultLogger.Info("Executed: " + s)
+	}
+
+	pterm.DefaultLogger.Info("conversations: last messages backfilled and index created.")
+	return nil
+}
+
+func downConversationSummaries(ctx context.Context, tx *sql.Tx) error {
+	db := database.DB
+
+	stmts := []string{
+		`DROP INDEX CONCURRENTLY IF EXISTS idx_conversations_workspace_last_message;`,
+		`UPDATE conversations
+		    SET last_message_id = NULL, last_inbound_at = NULL, last_outbound_at = NULL,
+		        last_status = NULL, last_status_at = NULL,
+		        message_count = 0, inbound_count = 0, outbound_count = 0;`,
+	}
+
+	for _, s := range stmts {
+		if err := db.Exec(s).Error; err != nil {
+			pterm.DefaultLogger.Error(fmt.Sprintf("migration downConversationSummaries failed on: %s\nerr: %v", s, err))
+			return err
+		}
+		pterm.DefaultLogger.Info("Executed: " + s)
+	}
+
+	pterm.DefaultLogger.Info("conversations: index dropped and last messages cleared.")
+	return nil
+}
@@ -0,0 +1,111 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Astervia/wacraft-server/src/database"
+	"github.com/pressly/goose/v3"
+	"github.com/pterm/pterm"
+)
+
+func init() {
+	goose.AddMigrationContext(upMessageSearchVector, downMessageSearchVector)
+}
+
+func upMessageSearchVector(ctx context.Context, tx *sql.Tx) error {
+	db := database.DB
+
+	stmts := []string{
+		`CREATE EXTENSION IF NOT EXISTS unaccent;`,
+
+		// 1) Language-neutral configuration that strips accents before indexing, so
+		//    "cafe" matches "café". Conversations are multilingual, hence no stemming.
+		`DO $$
+		 BEGIN
+		   IF NOT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = 'wacraft_unaccent') THEN
+		     CREATE TEXT SEARCH CONFIGURATION wacraft_unaccent (COPY = simple);
+		     ALTER TEXT SEARCH CONFIGURATION wacraft_unaccent
+		       ALTER MAPPING FOR hword, hword_part, word WITH unaccent, simple;
+		   END IF;
+		 END
+		 $$;`,
+
+		// 2) Human readable text of a sent or received message: text bodies, media
+		//    captions, button and list replies, interactive texts and template parameters.
+		`CREATE OR REPLACE FUNCTION message_search_document(data jsonb)
+			RETURNS text
+			LANGUAGE sql
+			IMMUTABLE
+			PARALLEL SAFE
+		AS $$
+			SELECT concat_ws(' ',
+				data #>> '{text,body}',
+				data #>> '{image,caption}',
+				data #>> '{video,caption}',
+				data #>> '{document,caption}',
+				data #>> '{document,filename}',
+				data #>> '{button,text}',
+				data #>> '{interactive,header,text}',
+				data #>> '{interactive,body,text}',
+				data #>> '{interactive,footer,text}',
+				data #>> '{interactive,button_reply,title}',
+				data #>> '{interactive,list_reply,title}',
+				data #>> '{interactive,list_reply,description}',
+				(SELECT string_agg(v #>> '{}', ' ')
+				   FROM jsonb_path_query(data, '$.template.components[*].parameters[*].text') AS v),
+				(SELECT string_agg(v #>> '{}', ' ')
+				   FROM jsonb_path_query(data, '$.interactive.action.buttons[*].reply.title') AS v),
+				(SELECT string_agg(v #>> '{}', ' ')
+				   FROM jsonb_path_query(data, '$.interactive.action.sections[*].rows[*].title') AS v),
+				(SELECT string_agg(v #>> '{}', ' ')
+				   FROM jsonb_path_query(data, '$.interactive.action.sections[*].rows[*].description') AS v)
+			)
+		$$;`,
+
+		// 3) Generated column. Expression MUST match searchDocumentExpr in message/service/search.go.
+		//    Adding a stored column rewrites the table once.
+		`ALTER TABLE messages ADD COLUMN IF NOT EXISTS search_vector tsvector
+		   GENERATED ALWAYS AS (
+		     to_tsvector('wacraft_unaccent'::regconfig,
+		       concat_ws(' ', message_search_document(sender_data), message_search_document(receiver_data)))
+		   ) STORED;`,
+
+		`CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_messages_search_vector
+		   ON messages USING GIN (search_vector);`,
+	}
+
+	for _, s := range stmts {
+		if err := db.Exec(s).Error; err != nil {
+			pterm.DefaultLogger.Error(fmt.Sprintf("migration upMessageSearchVector failed on: %s\nerr: %v", s, err))
+			return err
+		}
+		pterm.DefaultLogger.Info("Executed: " + s)
+	}
+
+	pterm.DefaultLogger.Info("message_search_vector: column and index ensured.")
+	return nil
+}
+
+func downMessageSearchVector(ctx context.Context, tx *sql.Tx) error {
+	db := database.DB
+
+	stmts := []string{
+		`DROP INDEX CONCURRENTLY IF EXISTS idx_messages_search_vector;`,
+		`ALTER TABLE messages DROP COLUMN IF EXISTS search_vector;`,
+		`DROP FUNCTION IF EXISTS message_search_document(jsonb);`,
+		`DROP TEXT SEARCH CONFIGURATION IF EXISTS wacraft_unaccent;`,
+	}
+
+	for _, s := range stmts {
+		if err := db.Exec(s).Error; err != nil {
+			pterm.DefaultLogger.Error(fmt.Sprintf("migration downMessageSearchVector failed on: %s\nerr: %v", s, err))
+			return err
+		}
+		pterm.DefaultLogger.Info("Executed: " + s)
+	}
+
+	pterm.DefaultLogger.Info("message_search_vector: column and index dropped.")
+	return nil
+}
@@ -0,0 +1,76 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Astervia/wacraft-server/src/database"
+	"github.com/pressly/goose/v3"
+	"github.com/pterm/pterm"
+)
+
+func init() {
+	goose.AddMigrationContext(upMessageReplies, downMessageReplies)
+}
+
+func upMessageReplies(ctx context.Context, tx *sql.Tx) error {
+	db := database.DB
+
+	stmts := []string{
+		// Inbound wamids; outbound ones are matched through idx_messages_product_gin
+		`CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_messages_receiver_wam_id
+		   ON messages ((receiver_data->>'id'));`,
+
+		// Backfill the reply links of the messages already stored: inbound replies
+		// carry context.id and outbound replies context.message_id.
+		`INSERT INTO message_replies (id, message_id, messaging_product_id, replied_to_wam_id, created_at, updated_at)
+		 SELECT gen_random_uuid(), m.id, m.messaging_product_id,
+		        COALESCE(m.receiver_data #>> '{context,id}', m.sender_data #>> '{context,message_id}'),
+		        NOW(), NOW()
+		   FROM messages m
+		  WHERE m.deleted_at IS NULL
+		    AND COALESCE(m.receiver_data #>> '{context,id}', m.sender_data #>> '{context,message_id}', '') <> ''
+		 ON CONFLICT (message_id) DO NOTHING;`,
+
+		// Resolve the referenced messages by wamid
+		`UPDATE message_replies r
+		    SET replied_to_id = t.id
+		   FROM messages t
+		  WHERE r.replied_to_id IS NULL
+		    AND t.messaging_product_id = r.messaging_product_id
+		    AND (t.receiver_data->>'id' = r.replied_to_wam_id
+		         OR t.product_data @> jsonb_build_object('messages', jsonb_build_array(jsonb_build_object('id', r.replied_to_wam_id))));`,
+	}
+
+	for _, s := range stmts {
+		if err := db.Exec(s).Error; err != nil {
+			pterm.DefaultLogger.Error(fmt.Sprintf("migration upMessageReplies failed on: %s\nerr: %v", s, err))
+			return err
+		}
+		pterm.DefaultLogger.Info("Executed: " + s)
+	}
+
+	pterm.DefaultLogger.Info("message_replies: wamid index created and reply links backfilled.")
+	return nil
+}
+
+func downMessageReplies(ctx context.Context, tx *sql.Tx) error {
+	db := database.DB
+
+	stmts := []string{
+		`DELETE FROM message_replies;`,
+		`DROP INDEX CONCURRENTLY IF EXISTS idx_messages_receiver_wam_id;`,
+	}
+
+	for _, s := range stmts {
+		if err := db.Exec(s).Error; err != nil {
+			pterm.DefaultLogger.Error(fmt.Sprintf("migration downMessageReplies failed on: %s\nerr: %v", s, err))
+			return err
+		}
+		pterm.DefaultLogger.Info("Executed: " + s)
+	}
+
+	pterm.DefaultLogger.Info("message_replies: reply links removed and wamid index dropped.")
+	return nil
+}
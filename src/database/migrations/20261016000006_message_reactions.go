@@ -0,0 +1,91 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Astervia/wacraft-server/src/database"
+	"github.com/pressly/goose/v3"
+	"github.com/pterm/pterm"
+)
+
+func init() {
+	goose.AddMigrationContext(upMessageReactions, downMessageReactions)
+}
+
+func upMessageReactions(ctx context.Context, tx *sql.Tx) error {
+	db := database.DB
+
+	stmts := []string{
+		// Backfill the current reaction of each reactor from the reaction messages
+		// already stored. Inbound reactions belong to the sending contact and
+		// outbound ones to the messaging product; the latest one wins and an empty
+		// emoji means the reaction was cleared.
+		`INSERT INTO message_reactions (id, messaging_product_id, target_wam_id, reactor_id, outbound, emoji, reacted_at, created_at, updated_at)
+		 SELECT gen_random_uuid(), r.messaging_product_id, r.target_wam_id, r.reactor_id, r.outbound, r.emoji, r.reacted_at, NOW(), NOW()
+		   FROM (
+		         SELECT DISTINCT ON (m.messaging_product_id, target_wam_id, reactor_id)
+		                m.messaging_product_id,
+		                COALESCE(m.receiver_data #>> '{reaction,message_id}', m.sender_data #>> '{reaction,message_id}') AS target_wam_id,
+		                COALESCE(m.from_id, m.messaging_product_id) AS reactor_id,
+		                m.receiver_data IS NULL AS outbound,
+		                COALESCE(m.receiver_data #>> '{reaction,emoji}', m.sender_data #>> '{reaction,emoji}', '') AS emoji,
+		                m.created_at AS reacted_at
+		           FROM messages m
+		          WHERE m.deleted_at IS NULL
+		            AND COALESCE(m.receiver_data #>> '{reaction,message_id}', m.sender_data #>> '{reaction,message_id}', '') <> ''
+		          ORDER BY m.messaging_product_id, target_wam_id, reactor_id, m.created_at DESC
+		        ) r
+		 ON CONFLICT (messaging_product_id, target_wam_id, reactor_id) DO NOTHING;`,
+
+		// Resolve the reacted messages by wamid
+		`UPDATE message_reactions r
+		    SET message_id = t.id
+		   FROM messages t
+		  WHERE r.message_id IS NULL
+		    AND t.messaging_product_id = r.messaging_product_id
+		    AND (t.receiver_data->>'id' = r.target_wam_id
+		         OR t.product_data @> jsonb_build_object('messages', jsonb_build_array(jsonb_build_object('id', r.target_wam_id))));`,
+
+		// Reactions live on the reacted messages now, drop the reaction messages
+		`UPDATE messages
+		    SET deleted_at = NOW()
+		  WHERE deleted_at IS NULL
+		    AND COALESCE(receiver_data #>> '{reaction,message_id}', sender_data #>> '{reaction,message_id}', '') <> '';`,
+	}
+
+	for _, s := range stmts {
+		if err := db.Exec(s).Error; err != nil {
+			pterm.DefaultLogger.Error(fmt.Sprintf("migration upMessageReactions failed on: %s\nerr: %v", s, err))
+			return err
+		}
+		pterm.DefaultLogger.Info("Executed: " + s)
+	}
+
+	pterm.DefaultLogger.Info("message_reactions: reaction sets backfilled and reaction messages removed.")
+	return nil
+}
+
+func downMessageReactions(ctx context.Context, tx *sql.Tx) error {
+	db := database.DB
+
+	stmts := []string{
+		`DELETE FROM message_reactions;`,
+		`UPDATE messages
+		    SET deleted_at = NULL
+		  WHERE deleted_at IS NOT NULL
+		    AND COALESCE(receiver_data #>> '{reaction,message_id}', sender_data #>> '{reaction,message_id}', '') <> '';`,
+	}
+
+	for _, s := range stmts {
+		if err := db.Exec(s).Error; err != nil {
+			pterm.DefaultLogger.Error(fmt.Sprintf("migration downMessageReactions failed on: %s\nerr: %v", s, err))
+			return err
+		}
+		pterm.DefaultLogger.Info("Executed: " + s)
+	}
+
+	pterm.DefaultLogger.Info("message_reactions: reaction sets removed and reaction messages restored.")
+	return nil
+}
@@ -0,0 +1,59 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Astervia/wacraft-server/src/database"
+	"github.com/pressly/goose/v3"
+	"github.com/pterm/pterm"
+)
+
+func init() {
+	goose.AddMigrationContext(upWebhookDeliveryLeases, downWebhookDeliveryLeases)
+}
+
+func upWebhookDeliveryLeases(ctx context.Context, tx *sql.Tx) error {
+	db := database.DB
+
+	stmts := []string{
+		// The worker instance that claimed the delivery and until when. Claims
+		// with an expired lease are taken over by any worker.
+		`ALTER TABLE webhook_deliveries
+		   ADD COLUMN IF NOT EXISTS lease_owner TEXT,
+		   ADD COLUMN IF NOT EXISTS lease_expires_at TIMESTAMPTZ;`,
+	}
+
+	for _, s := range stmts {
+		if err := db.Exec(s).Error; err != nil {
+			pterm.DefaultLogger.Error(fmt.Sprintf("migration upWebhookDeliveryLeases failed on: %s\nerr: %v", s, err))
+			return err
+		}
+		pterm.DefaultLogger.Info("Executed: " + s)
+	}
+
+	pterm.DefaultLogger.Info("webhook_deliveries: lease columns ensured.")
+	return nil
+}
+
+func downWebhookDeliveryLeases(ctx context.Context, tx *sql.Tx) error {
+	db := database.DB
+
+	stmts := []string{
+		`ALTER TABLE webhook_deliveries
+		   DROP COLUMN IF EXISTS lease_expires_at,
+		   DROP COLUMN IF EXISTS lease_owner;`,
+	}
+
+	for _, s := range stmts {
+		if err := db.Exec(s).Error; err != nil {
+			pterm.DefaultLogger.Error(fmt.Sprintf("migration downWebhookDeliveryLeases failed on: %s\nerr: %v", s, err))
+			return err
+		}
+		pterm.DefaultLogger.Info("Executed: " + s)
+	}
+
+	pterm.DefaultLogger.Info("webhook_deliveries: lease columns dropped.")
+	return nil
+}
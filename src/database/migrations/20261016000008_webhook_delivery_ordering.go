@@ -0,0 +1,61 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Astervia/wacraft-server/src/database"
+	"github.com/pressly/goose/v3"
+	"github.com/pterm/pterm"
+)
+
+func init() {
+	goose.AddMigrationContext(upWebhookDeliveryOrdering, downWebhookDeliveryOrdering)
+}
+
+func upWebhookDeliveryOrdering(ctx context.Context, tx *sql.Tx) error {
+	db := database.DB
+
+	stmts := []string{
+		// Deliveries of a webhook sharing an ordering key are sent one at a time,
+		// in queue order. NULL for webhooks without ordering.
+		`ALTER TABLE webhook_deliveries ADD COLUMN IF NOT EXISTS ordering_key TEXT;`,
+
+		// Finds whether an earlier delivery of the same key is still queued
+		`CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_deliveries_ordering
+		   ON webhook_deliveries(webhook_id, ordering_key, created_at)
+		   WHERE ordering_key IS NOT NULL AND status IN ('pending', 'attempted');`,
+	}
+
+	for _, s := range stmts {
+		if err := db.Exec(s).Error; err != nil {
+			pterm.DefaultLogger.Error(fmt.Sprintf("migration upWebhookDeliveryOrdering failed on: %s\nerr: %v", s, err))
+			return err
+		}
+		pterm.DefaultLogger.Info("Executed: " + s)
+	}
+
+	pterm.DefaultLogger.Info("webhook_deliveries: ordering key ensured.")
+	return nil
+}
+
+func downWebhookDeliveryOrdering(ctx context.Context, tx *sql.Tx) error {
+	db := database.DB
+
+	stmts := []string{
+		`DROP INDEX CONCURRENTLY IF EXISTS idx_deliveries_ordering;`,
+		`ALTER TABLE webhook_deliveries DROP COLUMN IF EXISTS ordering_key;`,
+	}
+
+	for _, s := range stmts {
+		if err := db.Exec(s).Error; err != nil {
+			pterm.DefaultLogger.Error(fmt.Sprintf("migration downWebhookDeliveryOrdering failed on: %s\nerr: %v", s, err))
+			return err
+		}
+		pterm.DefaultLogger.Info("Executed: " + s)
+	}
+
+	pterm.DefaultLogger.Info("webhook_deliveries: ordering key dropped.")
+	return nil
+}
@@ -0,0 +1,64 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Astervia/wacraft-server/src/database"
+	"github.com/pressly/goose/v3"
+	"github.com/pterm/pterm"
+)
+
+func init() {
+	goose.AddMigrationContext(upInboundReceipts, downInboundReceipts)
+}
+
+func upInboundReceipts(ctx context.Context, tx *sql.Tx) error {
+	db := database.DB
+
+	stmts := []string{
+		// Receipts are pruned by age once Meta no longer retries them.
+		`CREATE INDEX IF NOT EXISTS idx_inbound_receipts_created_at ON inbound_receipts (created_at);`,
+		// Backfill the receipts of the messages received during the last 7 days,
+		// the window in which Meta retries webhooks, so redeliveries of messages
+		// stored before the upgrade are skipped.
+		`INSERT INTO inbound_receipts (id, messaging_product_id, wam_id, type, created_at, updated_at)
+		 SELECT gen_random_uuid(), m.messaging_product_id, m.receiver_data->>'id', 'message', NOW(), NOW()
+		   FROM messages m
+		  WHERE m.receiver_data->>'id' IS NOT NULL
+		    AND m.created_at > NOW() - INTERVAL '7 days'
+		 ON CONFLICT (messaging_product_id, wam_id, type) DO NOTHING;`,
+	}
+
+	for _, s := range stmts {
+		if err := db.Exec(s).Error; err != nil {
+			pterm.DefaultLogger.Error(fmt.Sprintf("migration upInboundReceipts failed on: %s\nerr: %v", s, err))
+			return err
+		}
+		pterm.DefaultLogger.Info("Executed: " + s)
+	}
+
+	pterm.DefaultLogger.Info("inbound_receipts: recent messages backfilled.")
+	return nil
+}
+
+func downInboundReceipts(ctx context.Context, tx *sql.Tx) error {
+	db := database.DB
+
+	stmts := []string{
+		`DROP INDEX IF EXISTS idx_inbound_receipts_created_at;`,
+		`DELETE FROM inbound_receipts;`,
+	}
+
+	for _, s := range stmts {
+		if err := db.Exec(s).Error; err != nil {
+			pterm.DefaultLogger.Error(fmt.Sprintf("migration downInboundReceipts failed on: %s\nerr: %v", s, err))
+			return err
+		}
+		pterm.DefaultLogger.Info("Executed: " + s)
+	}
+
+	pterm.DefaultLogger.Info("inbound_receipts: receipts removed.")
+	return nil
+}
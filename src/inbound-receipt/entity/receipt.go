@@ -0,0 +1,25 @@
+package inbound_receipt_entity
+
+import (
+	common_model "github.com/Astervia/wacraft-core/src/common/model"
+	"github.com/google/uuid"
+)
+
+// ReceiptType tells what was received for a wamid: the message itself or one
+// of its statuses.
+type ReceiptType string
+
+// ReceiptMessage is the type of the receipt of an inbound message. The
+// receipts of statuses use the status, e.g. "delivered" or "read".
+const ReceiptMessage ReceiptType = "message"
+
+// InboundReceipt records that a message or status received from Meta was
+// handled. Meta delivers webhooks at least once, so a wamid and type that
+// already have a receipt were delivered again and are skipped.
+type InboundReceipt struct {
+	MessagingProductID uuid.UUID   `json:"messaging_product_id" gorm:"type:uuid;not null;uniqueIndex:idx_inbound_receipts_wam_id"`
+	WamID              string      `json:"wam_id" gorm:"not null;uniqueIndex:idx_inbound_receipts_wam_id"`
+	Type               ReceiptType `json:"type" gorm:"type:varchar(20);not null;uniqueIndex:idx_inbound_receipts_wam_id"`
+
+	common_model.Audit
+}
@@ -0,0 +1,80 @@
+package inbound_receipt_service
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/Astervia/wacraft-server/src/database"
+	inbound_receipt_entity "github.com/Astervia/wacraft-server/src/inbound-receipt/entity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Duplicates counts the messages and statuses this instance skipped since it
+// started because Meta delivered them again.
+type Duplicates struct {
+	Messages int64 `json:"messages"`
+	Statuses int64 `json:"statuses"`
+}
+
+var (
+	duplicateMessages atomic.Int64
+	duplicateStatuses atomic.Int64
+)
+
+// Claim records the receipt of the wamid with the type within tx. Returns
+// false when the receipt already exists, i.e. Meta delivered the message or
+// status again and it must be skipped.
+//
+// A concurrent delivery of the same receipt waits for tx to end: it is a
+// duplicate if tx commits and is handled if tx rolls back.
+func Claim(mpID uuid.UUID, wamID string, receiptType inbound_receipt_entity.ReceiptType, tx *gorm.DB) (bool, error) {
+	if wamID == "" {
+		return true, nil
+	}
+
+	receipt := inbound_receipt_entity.InboundReceipt{
+		MessagingProductID: mpID,
+		WamID:              wamID,
+		Type:               receiptType,
+	}
+	result := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "messaging_product_id"}, {Name: "wam_id"}, {Name: "type"}},
+		DoNothing: true,
+	}).Create(&receipt)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected > 0 {
+		return true, nil
+	}
+
+	if receiptType == inbound_receipt_entity.ReceiptMessage {
+		duplicateMessages.Add(1)
+	} else {
+		duplicateStatuses.Add(1)
+	}
+	return false, nil
+}
+
+// GetDuplicates returns how many duplicates this instance skipped.
+func GetDuplicates() Duplicates {
+	return Duplicates{
+		Messages: duplicateMessages.Load(),
+		Statuses: duplicateStatuses.Load(),
+	}
+}
+
+// PruneReceipts deletes up to limit receipts recorded before the given time,
+// oldest first. Returns how many receipts were deleted.
+func PruneReceipts(before time.Time, limit int) (int64, error) {
+	result := database.DB.Unscoped().
+		Where("id IN (?)", database.DB.Model(&inbound_receipt_entity.InboundReceipt{}).
+			Select("id").
+			Where("created_at < ?", before).
+			Order("created_at ASC").
+			Limit(limit)).
+		Delete(&inbound_receipt_entity.InboundReceipt{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,101 @@
+package inbound_receipt_service
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Astervia/wacraft-server/src/database"
+	inbound_receipt_entity "github.com/Astervia/wacraft-server/src/inbound-receipt/entity"
+	"github.com/google/uuid"
+)
+
+// --- Test bootstrap ---
+
+func TestMain(m *testing.M) {
+	database.DB.AutoMigrate(&inbound_receipt_entity.InboundReceipt{})
+	os.Exit(m.Run())
+}
+
+// newTestMessagingProduct returns a messaging product ID whose receipts are
+// deleted after the test.
+func newTestMessagingProduct(t *testing.T) uuid.UUID {
+	t.Helper()
+	mpID := uuid.New()
+	t.Cleanup(func() {
+		database.DB.Exec("DELETE FROM inbound_receipts WHERE messaging_product_id = ?", mpID)
+	})
+	return mpID
+}
+
+func claim(t *testing.T, mpID uuid.UUID, wamID string, receiptType inbound_receipt_entity.ReceiptType) bool {
+	t.Helper()
+	claimed, err := Claim(mpID, wamID, receiptType, database.DB)
+	if err != nil {
+		t.Fatalf("Claim: %v", err)
+	}
+	return claimed
+}
+
+func TestClaim(t *testing.T) {
+	mpID := newTestMessagingProduct(t)
+	before := GetDuplicates()
+
+	if !claim(t, mpID, "wamid.claim", inbound_receipt_entity.ReceiptMessage) {
+		t.Fatal("expected the first receipt of the message to be claimed")
+	}
+	if claim(t, mpID, "wamid.claim", inbound_receipt_entity.ReceiptMessage) {
+		t.Fatal("expected the redelivered message to be a duplicate")
+	}
+	if !claim(t, mpID, "wamid.claim", "delivered") || !claim(t, mpID, "wamid.claim", "read") {
+		t.Fatal("expected each status of the message to be claimed")
+	}
+	if claim(t, mpID, "wamid.claim", "read") {
+		t.Fatal("expected the redelivered status to be a duplicate")
+	}
+	if !claim(t, newTestMessagingProduct(t), "wamid.claim", inbound_receipt_entity.ReceiptMessage) {
+		t.Fatal("expected the wamid to be claimed for another messaging product")
+	}
+	if !claim(t, mpID, "", inbound_receipt_entity.ReceiptMessage) || !claim(t, mpID, "", inbound_receipt_entity.ReceiptMessage) {
+		t.Fatal("expected messages without wamid never to be duplicates")
+	}
+
+	after := GetDuplicates()
+	if after.Messages-before.Messages != 1 || after.Statuses-before.Statuses != 1 {
+		t.Errorf("expected one duplicate message and status, got %+v", Duplicates{
+			Messages: after.Messages - before.Messages,
+			Statuses: after.Statuses - before.Statuses,
+		})
+	}
+}
+
+func TestPruneReceipts(t *testing.T) {
+	mpID := newTestMessagingProduct(t)
+	for _, wamID := range []string{"wamid.old-1", "wamid.old-2", "wamid.old-3", "wamid.new"} {
+		claim(t, mpID, wamID, inbound_receipt_entity.ReceiptMessage)
+	}
+	database.DB.Exec(
+		"UPDATE inbound_receipts SET created_at = ? WHERE messaging_product_id = ? AND wam_id LIKE 'wamid.old-%'",
+		time.Now().Add(-8*24*time.Hour), mpID,
+	)
+
+	before := time.Now().Add(-7 * 24 * time.Hour)
+	deleted, err := PruneReceipts(before, 2)
+	if err != nil {
+		t.Fatalf("PruneReceipts: %v", err)
+	}
+	if deleted != 2 {
+		t.Fatalf("expected the batch to be limited to 2 receipts, got %d", deleted)
+	}
+	if deleted, err = PruneReceipts(before, 2); err != nil || deleted != 1 {
+		t.Fatalf("expected the last expired receipt to be pruned, got %d (err=%v)", deleted, err)
+	}
+
+	// Pruned messages are no longer duplicates; recent ones still are.
+	if !claim(t, mpID, "wamid.old-1", inbound_receipt_entity.ReceiptMessage) {
+		t.Error("expected the pruned receipt to be claimable again")
+	}
+	if claim(t, mpID, "wamid.new", inbound_receipt_entity.ReceiptMessage) {
+		t.Error("expected the recent receipt to be kept")
+	}
+}
@@ -0,0 +1,92 @@
+package inbound_receipt_worker
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Astervia/wacraft-server/src/config/env"
+	inbound_receipt_service "github.com/Astervia/wacraft-server/src/inbound-receipt/service"
+	"github.com/pterm/pterm"
+)
+
+const (
+	// PruneInterval is how often receipts past env.InboundReceiptRetention are deleted.
+	PruneInterval = time.Hour
+	// PruneBatchSize is the max number of receipts deleted per statement.
+	PruneBatchSize = 5000
+)
+
+// PruneWorker deletes the inbound receipts Meta can no longer deliver again.
+type PruneWorker struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewPruneWorker creates a new receipt prune worker.
+func NewPruneWorker() *PruneWorker {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &PruneWorker{
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Start prunes the receipts that expired while the server was down and
+// begins the pruning loop.
+func (w *PruneWorker) Start() {
+	w.wg.Add(1)
+	go w.run()
+	pterm.DefaultLogger.Info("Inbound receipt prune worker started")
+}
+
+// Stop gracefully stops the prune worker.
+func (w *PruneWorker) Stop() {
+	pterm.DefaultLogger.Info("Stopping inbound receipt prune worker...")
+	w.cancel()
+	w.wg.Wait()
+	pterm.DefaultLogger.Info("Inbound receipt prune worker stopped")
+}
+
+// run is the main pruning loop.
+func (w *PruneWorker) run() {
+	defer w.wg.Done()
+
+	w.pruneExpiredReceipts()
+
+	ticker := time.NewTicker(PruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.ctx.Done():
+			return
+		case <-ticker.C:
+			w.pruneExpiredReceipts()
+		}
+	}
+}
+
+// pruneExpiredReceipts deletes expired receipts batch by batch. Deleting is
+// idempotent, so several instances can run the worker at once.
+func (w *PruneWorker) pruneExpiredReceipts() {
+	before := time.Now().Add(-env.InboundReceiptRetention)
+	var pruned int64
+	for w.ctx.Err() == nil {
+		deleted, err := inbound_receipt_service.PruneReceipts(before, PruneBatchSize)
+		if err != nil {
+			pterm.DefaultLogger.Error("Inbound receipts: failed to prune expired receipts: " + err.Error())
+			break
+		}
+		pruned += deleted
+		if deleted < PruneBatchSize {
+			break
+		}
+	}
+
+	if pruned > 0 {
+		pterm.DefaultLogger.Info("Inbound receipts: pruned " + strconv.FormatInt(pruned, 10) + " expired receipts")
+	}
+}
@@ -160,21 +160,36 @@ func DownloadFromMediaInfo(ctx *fiber.Ctx) error {
 //
 //	@Summary		Upload media file
 //	@Description	Uploads a media file to WhatsApp. Files remain available for up to 30 days unless deleted earlier.
+//	@Description	Media is uploaded with the selected sender so it can be used when sending from that number.
 //	@Tags			Media
 //	@Accept			multipart/form-data
 //	@Produce		json
-//	@Param			file	formData	file							true	"Media file"
-//	@Param			type	formData	string							true	"MIME type of the media file"
+//	@Param			file					formData	file							true	"Media file"
+//	@Param			type					formData	string							true	"MIME type of the media file"
+//	@Param			phone_config_id			formData	string							false	"Phone config used as sender"
+//	@Param			messaging_product_id	formData	string							false	"Messaging product used as sender"
 //	@Success		200		{object}	common_model.ID					"Media ID returned from WhatsApp"
-//	@Failure		400		{object}	cmn_model.DescriptiveError	"Missing file or MIME type"
+//	@Failure		400		{object}	cmn_model.DescriptiveError	"Missing file, MIME type or invalid sender"
 //	@Failure		415		{object}	cmn_model.DescriptiveError	"Unsupported media type"
 //	@Failure		500		{object}	cmn_model.DescriptiveError	"Failed to upload media"
 //	@Security		ApiKeyAuth
 //	@Security		WorkspaceAuth
 //	@Router			/media/whatsapp/upload [post]
 func UploadWhatsAppMedia(ctx *fiber.Ctx) error {
+	var selection phone_config_service.SenderSelection
+	if err := ctx.BodyParser(&selection); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(
+			cmn_model.NewParseJsonError(err).Send(),
+		)
+	}
+
 	workspace := workspace_middleware.GetWorkspace(ctx)
-	wabaApi, err := phone_config_service.GetFirstConfigAPIByWorkspace(workspace.ID)
+	wabaApi, err := phone_config_service.GetWorkspaceSenderAPI(workspace.ID, selection)
+	if errors.Is(err, phone_config_service.ErrSenderNotInWorkspace) {
+		return ctx.Status(fiber.StatusBadRequest).JSON(
+			cmn_model.NewApiError("invalid sender", err, "service").Send(),
+		)
+	}
 	if err != nil {
 		return ctx.Status(fiber.StatusNotFound).JSON(
 			cmn_model.NewApiError("workspace WhatsApp API not found", err, "service").Send(),
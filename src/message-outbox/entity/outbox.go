@@ -0,0 +1,55 @@
+package message_outbox_entity
+
+import (
+	"time"
+
+	common_model "github.com/Astervia/wacraft-core/src/common/model"
+	message_entity "github.com/Astervia/wacraft-core/src/message/entity"
+	message_model "github.com/Rfluid/whatsapp-cloud-api/src/message"
+	"github.com/google/uuid"
+)
+
+// OutboxStatus is the lifecycle state of an outbox entry.
+type OutboxStatus string
+
+const (
+	// OutboxPending entries are waiting to be sent (or retried) by the outbox worker.
+	OutboxPending OutboxStatus = "pending"
+	// OutboxProcessing entries have been claimed by a worker and are being sent.
+	OutboxProcessing OutboxStatus = "processing"
+	// OutboxSent entries were accepted by Meta; the message row holds the wamid.
+	OutboxSent OutboxStatus = "sent"
+	// OutboxUnconfirmed entries were accepted by Meta but its response could not
+	// be stored on the message row. The worker retries storing the response and
+	// never sends them again.
+	OutboxUnconfirmed OutboxStatus = "unconfirmed"
+	// OutboxFailed entries exhausted their attempts and will not be retried.
+	OutboxFailed OutboxStatus = "failed"
+)
+
+// MessageOutbox is a pending Graph API call for a message that was accepted
+// asynchronously. The message row is created in the same transaction as the
+// outbox entry, so the message ID can be returned before Meta is contacted.
+type MessageOutbox struct {
+	MessageID          uuid.UUID               `json:"message_id" gorm:"type:uuid;not null;uniqueIndex"`
+	Message            *message_entity.Message `json:"message,omitempty" gorm:"foreignKey:MessageID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	MessagingProductID uuid.UUID               `json:"messaging_product_id" gorm:"type:uuid;not null"`
+	WorkspaceID        uuid.UUID               `json:"workspace_id" gorm:"type:uuid;not null;index"`
+	Status             OutboxStatus            `json:"status" gorm:"type:varchar(20);not null;default:'pending'"`
+	AttemptCount       int                     `json:"attempt_count" gorm:"not null;default:0"`
+	MaxAttempts        int                     `json:"max_attempts" gorm:"not null;default:5"`
+	NextAttemptAt      time.Time               `json:"next_attempt_at" gorm:"not null"`
+	LastAttemptAt      *time.Time              `json:"last_attempt_at,omitempty"`
+	LastError          string                  `json:"last_error,omitempty" gorm:"type:text"`
+	SentAt             *time.Time              `json:"sent_at,omitempty"`
+	// Response is Meta's response to an unconfirmed entry, kept until it is
+	// stored on the message row.
+	Response *message_model.Response `json:"-" gorm:"type:jsonb;serializer:json"`
+
+	common_model.Audit
+}
+
+// TableName keeps the table name singular, matching how the outbox is referred to.
+func (MessageOutbox) TableName() string {
+	return "message_outbox"
+}
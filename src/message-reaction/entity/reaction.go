@@ -0,0 +1,30 @@
+package message_reaction_entity
+
+import (
+	"time"
+
+	common_model "github.com/Astervia/wacraft-core/src/common/model"
+	message_entity "github.com/Astervia/wacraft-core/src/message/entity"
+	"github.com/google/uuid"
+)
+
+// MessageReaction is the current reaction of a reactor to a message. Each
+// reactor has at most one reaction per message: a new emoji replaces the
+// previous one and an empty emoji clears it. Cleared reactions keep their
+// ReactedAt so older reactions delivered late do not bring them back.
+//
+// The reactor is the messaging product contact for inbound reactions and the
+// messaging product itself for reactions sent by the business. MessageID
+// stays nil while the reacted message is unknown.
+type MessageReaction struct {
+	MessagingProductID uuid.UUID               `json:"messaging_product_id" gorm:"type:uuid;not null;uniqueIndex:idx_message_reactions_reactor"`
+	TargetWamID        string                  `json:"target_wam_id" gorm:"not null;uniqueIndex:idx_message_reactions_reactor"`
+	ReactorID          uuid.UUID               `json:"reactor_id" gorm:"type:uuid;not null;uniqueIndex:idx_message_reactions_reactor"`
+	Outbound           bool                    `json:"outbound" gorm:"not null;default:false"`
+	MessageID          *uuid.UUID              `json:"message_id,omitempty" gorm:"type:uuid;index"`
+	Message            *message_entity.Message `json:"message,omitempty" gorm:"foreignKey:MessageID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Emoji              string                  `json:"emoji" gorm:"not null"` // Empty when cleared.
+	ReactedAt          time.Time               `json:"reacted_at" gorm:"not null"`
+
+	common_model.Audit
+}
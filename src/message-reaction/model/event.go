@@ -0,0 +1,22 @@
+package message_reaction_model
+
+import (
+	message_reaction_entity "github.com/Astervia/wacraft-server/src/message-reaction/entity"
+)
+
+// ReactionAction identifies how a reaction changed.
+type ReactionAction string
+
+const (
+	ReactionAdded   ReactionAction = "added"
+	ReactionChanged ReactionAction = "changed"
+	ReactionRemoved ReactionAction = "removed"
+)
+
+// ReactionEvent is broadcast to the workspace and sent to webhooks whenever a
+// reaction is added, changed or removed.
+type ReactionEvent struct {
+	Action        ReactionAction                          `json:"action"`
+	Reaction      message_reaction_entity.MessageReaction `json:"reaction"`
+	PreviousEmoji string                                  `json:"previous_emoji,omitempty"` // Set when changed or removed.
+}
@@ -0,0 +1,34 @@
+package message_reaction_service
+
+import (
+	message_entity "github.com/Astervia/wacraft-core/src/message/entity"
+	"github.com/Astervia/wacraft-server/src/database"
+	message_reaction_entity "github.com/Astervia/wacraft-server/src/message-reaction/entity"
+	message_thread_service "github.com/Astervia/wacraft-server/src/message-thread/service"
+	"github.com/google/uuid"
+)
+
+// GetByMessage returns the current reactions to a message of the workspace,
+// oldest first. Reactions stored before the message are matched by its wamid
+// and cleared reactions are left out.
+// Returns gorm.ErrRecordNotFound when the message is not in the workspace.
+func GetByMessage(messageID uuid.UUID, workspaceID uuid.UUID) ([]message_reaction_entity.MessageReaction, error) {
+	var message message_entity.Message
+	err := database.DB.Model(&message).
+		Joins("JOIN messaging_products ON messages.messaging_product_id = messaging_products.id AND messaging_products.workspace_id = ?", workspaceID).
+		Where("messages.id = ?", messageID).
+		First(&message).Error
+	if err != nil {
+		return nil, err
+	}
+
+	reactions := []message_reaction_entity.MessageReaction{}
+	db := database.DB.Where("messaging_product_id = ? AND emoji <> ''", message.MessagingProductID)
+	if wamID := message_thread_service.WamID(message); wamID != "" {
+		db = db.Where("message_id = ? OR target_wam_id = ?", message.ID, wamID)
+	} else {
+		db = db.Where("message_id = ?", message.ID)
+	}
+	err = db.Order("reacted_at ASC, id ASC").Find(&reactions).Error
+	return reactions, err
+}
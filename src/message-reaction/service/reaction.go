@@ -0,0 +1,159 @@
+package message_reaction_service
+
+import (
+	"time"
+
+	"github.com/Astervia/wacraft-server/src/database"
+	message_reaction_entity "github.com/Astervia/wacraft-server/src/message-reaction/entity"
+	message_reaction_model "github.com/Astervia/wacraft-server/src/message-reaction/model"
+	message_thread_service "github.com/Astervia/wacraft-server/src/message-thread/service"
+	"github.com/Rfluid/whatsapp-cloud-api/src/message/content"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ApplyInboundReaction applies a reaction received from a contact.
+func ApplyInboundReaction(
+	messagingProductID uuid.UUID,
+	contactID uuid.UUID,
+	data content.ReactionData,
+	reactedAt time.Time,
+	db *gorm.DB,
+) (*message_reaction_model.ReactionEvent, error) {
+	return ApplyReaction(message_reaction_entity.MessageReaction{
+		MessagingProductID: messagingProductID,
+		TargetWamID:        data.MessageID,
+		ReactorID:          contactID,
+		Emoji:              data.Emoji,
+		ReactedAt:          reactedAt,
+	}, db)
+}
+
+// ApplyOutboundReaction applies a reaction sent by the business. Outbound
+// reactions are not stored as messages; the messaging product is the reactor.
+func ApplyOutboundReaction(
+	messagingProductID uuid.UUID,
+	data content.ReactionData,
+	reactedAt time.Time,
+	db *gorm.DB,
+) (*message_reaction_model.ReactionEvent, error) {
+	return ApplyReaction(message_reaction_entity.MessageReaction{
+		MessagingProductID: messagingProductID,
+		TargetWamID:        data.MessageID,
+		ReactorID:          messagingProductID,
+		Outbound:           true,
+		Emoji:              data.Emoji,
+		ReactedAt:          reactedAt,
+	}, db)
+}
+
+// ApplyReaction stores the reaction as the current reaction of its reactor to
+// the target message, replacing the previous one, or clears it when the emoji
+// is empty. Cleared reactions keep their row and reacted_at, so reactions older
+// than the stored one are ignored even after a removal.
+//
+// Returns the resulting event, or nil when nothing visible changed.
+func ApplyReaction(reaction message_reaction_entity.MessageReaction, db *gorm.DB) (*message_reaction_model.ReactionEvent, error) {
+	if db == nil {
+		db = database.DB
+	}
+
+	var existing message_reaction_entity.MessageReaction
+	result := db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("messaging_product_id = ? AND target_wam_id = ? AND reactor_id = ?",
+			reaction.MessagingProductID, reaction.TargetWamID, reaction.ReactorID).
+		Limit(1).
+		Find(&existing)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	var stored *message_reaction_entity.MessageReaction
+	if result.RowsAffected > 0 {
+		stored = &existing
+	}
+
+	action, write := reactionAction(stored, reaction)
+	if !write {
+		return nil, nil
+	}
+
+	messageID, err := message_thread_service.FindIDByWamID(reaction.MessagingProductID, reaction.TargetWamID, db)
+	if err != nil {
+		return nil, err
+	}
+	reaction.MessageID = messageID
+
+	if stored != nil {
+		err = db.Model(&existing).Updates(map[string]any{
+			"emoji":      reaction.Emoji,
+			"reacted_at": reaction.ReactedAt,
+			"message_id": reaction.MessageID,
+		}).Error
+		if err != nil {
+			return nil, err
+		}
+		previous := existing.Emoji
+		existing.Emoji = reaction.Emoji
+		existing.ReactedAt = reaction.ReactedAt
+		existing.MessageID = reaction.MessageID
+		return newReactionEvent(action, existing, previous), nil
+	}
+
+	// A concurrent first reaction of the same reactor is resolved by the
+	// unique index; the latest reaction wins.
+	err = db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "messaging_product_id"}, {Name: "target_wam_id"}, {Name: "reactor_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"emoji", "reacted_at", "message_id", "updated_at"}),
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Expr{SQL: "message_reactions.reacted_at <= EXCLUDED.reacted_at"},
+		}},
+	}).Create(&reaction).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return newReactionEvent(action, reaction, ""), nil
+}
+
+// reactionAction decides how a reaction changes the stored one, nil when the
+// reactor never reacted to the message. write is false when the reaction is
+// older than the stored one or changes nothing. A removal without stored
+// reaction is written, with no event, so an older reaction delivered late is ignored.
+func reactionAction(
+	stored *message_reaction_entity.MessageReaction,
+	reaction message_reaction_entity.MessageReaction,
+) (action message_reaction_model.ReactionAction, write bool) {
+	if stored != nil && stored.ReactedAt.After(reaction.ReactedAt) {
+		return "", false
+	}
+
+	switch {
+	case stored == nil && reaction.Emoji == "":
+		return "", true
+	case stored == nil:
+		return message_reaction_model.ReactionAdded, true
+	case stored.Emoji == reaction.Emoji:
+		return "", false
+	case reaction.Emoji == "":
+		return message_reaction_model.ReactionRemoved, true
+	case stored.Emoji == "":
+		return message_reaction_model.ReactionAdded, true
+	}
+	return message_reaction_model.ReactionChanged, true
+}
+
+func newReactionEvent(
+	action message_reaction_model.ReactionAction,
+	reaction message_reaction_entity.MessageReaction,
+	previousEmoji string,
+) *message_reaction_model.ReactionEvent {
+	if action == "" {
+		return nil
+	}
+	return &message_reaction_model.ReactionEvent{
+		Action:        action,
+		Reaction:      reaction,
+		PreviousEmoji: previousEmoji,
+	}
+}
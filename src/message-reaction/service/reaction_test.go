@@ -0,0 +1,45 @@
+package message_reaction_service
+
+import (
+	"testing"
+	"time"
+
+	message_reaction_entity "github.com/Astervia/wacraft-server/src/message-reaction/entity"
+	message_reaction_model "github.com/Astervia/wacraft-server/src/message-reaction/model"
+)
+
+func TestReactionAction(t *testing.T) {
+	now := time.Now()
+	stored := func(emoji string) *message_reaction_entity.MessageReaction {
+		return &message_reaction_entity.MessageReaction{Emoji: emoji, ReactedAt: now}
+	}
+	incoming := func(emoji string, at time.Time) message_reaction_entity.MessageReaction {
+		return message_reaction_entity.MessageReaction{Emoji: emoji, ReactedAt: at}
+	}
+	later := now.Add(time.Second)
+	earlier := now.Add(-time.Second)
+
+	cases := []struct {
+		name       string
+		stored     *message_reaction_entity.MessageReaction
+		reaction   message_reaction_entity.MessageReaction
+		wantAction message_reaction_model.ReactionAction
+		wantWrite  bool
+	}{
+		{"first reaction", nil, incoming("👍", now), message_reaction_model.ReactionAdded, true},
+		{"removal without reaction is kept as a tombstone", nil, incoming("", now), "", true},
+		{"changed", stored("👍"), incoming("❤️", later), message_reaction_model.ReactionChanged, true},
+		{"same emoji", stored("👍"), incoming("👍", later), "", false},
+		{"removed", stored("👍"), incoming("", later), message_reaction_model.ReactionRemoved, true},
+		{"already removed", stored(""), incoming("", later), "", false},
+		{"added after removal", stored(""), incoming("👍", later), message_reaction_model.ReactionAdded, true},
+		{"older reaction", stored("👍"), incoming("❤️", earlier), "", false},
+		{"older reaction after removal", stored(""), incoming("👍", earlier), "", false},
+	}
+	for _, c := range cases {
+		action, write := reactionAction(c.stored, c.reaction)
+		if action != c.wantAction || write != c.wantWrite {
+			t.Errorf("%s: expected %q/%v, got %q/%v", c.name, c.wantAction, c.wantWrite, action, write)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package message_thread_entity
+
+import (
+	common_model "github.com/Astervia/wacraft-core/src/common/model"
+	message_entity "github.com/Astervia/wacraft-core/src/message/entity"
+	"github.com/google/uuid"
+)
+
+// MessageReply links a message to the message it replies to, resolved from the
+// wamid of the WhatsApp context. RepliedToID stays nil while the referenced
+// message is unknown, e.g. when its wamid is not stored yet or it was sent
+// outside wacraft.
+type MessageReply struct {
+	MessageID          uuid.UUID               `json:"message_id" gorm:"type:uuid;not null;uniqueIndex"`
+	Message            *message_entity.Message `json:"message,omitempty" gorm:"foreignKey:MessageID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	MessagingProductID uuid.UUID               `json:"messaging_product_id" gorm:"type:uuid;not null;index:idx_message_replies_wam_id"`
+	RepliedToWamID     string                  `json:"replied_to_wam_id" gorm:"not null;index:idx_message_replies_wam_id"`
+	RepliedToID        *uuid.UUID              `json:"replied_to_id,omitempty" gorm:"type:uuid;index"`
+	RepliedTo          *message_entity.Message `json:"replied_to,omitempty" gorm:"foreignKey:RepliedToID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
+
+	common_model.Audit
+}
@@ -0,0 +1,26 @@
+package message_thread_model
+
+import (
+	"time"
+
+	message_entity "github.com/Astervia/wacraft-core/src/message/entity"
+	"github.com/google/uuid"
+)
+
+// RepliedTo summarizes the message a message replies to.
+type RepliedTo struct {
+	ID        *uuid.UUID `json:"id,omitempty"` // Nil when the referenced message is not stored.
+	WamID     string     `json:"wam_id"`
+	Type      string     `json:"type,omitempty"`
+	Preview   string     `json:"preview,omitempty"` // Text, caption or reply title of the referenced message.
+	FromID    *uuid.UUID `json:"from_id,omitempty"`
+	ToID      *uuid.UUID `json:"to_id,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+// ThreadedMessage is the message payload sent over WebSocket and webhooks. It
+// carries the summary of the replied message when the message is a reply.
+type ThreadedMessage struct {
+	message_entity.Message
+	RepliedTo *RepliedTo `json:"replied_to,omitempty"`
+}
@@ -0,0 +1,140 @@
+package message_thread_service
+
+import (
+	message_entity "github.com/Astervia/wacraft-core/src/message/entity"
+	"github.com/Astervia/wacraft-server/src/database"
+	message_thread_entity "github.com/Astervia/wacraft-server/src/message-thread/entity"
+	message_thread_model "github.com/Astervia/wacraft-server/src/message-thread/model"
+	"github.com/google/uuid"
+	"github.com/pterm/pterm"
+)
+
+// Threaded returns the payload of a message for WebSocket and webhook consumers.
+// Failing to load the replied message is logged and the payload is sent without it.
+func Threaded(message message_entity.Message) message_thread_model.ThreadedMessage {
+	return ThreadedAll([]message_entity.Message{message})[0]
+}
+
+// ThreadedAll is the batch variant of Threaded. The replied messages are loaded with a single query.
+func ThreadedAll(messages []message_entity.Message) []message_thread_model.ThreadedMessage {
+	threaded, err := WithRepliedTo(messages)
+	if err == nil {
+		return threaded
+	}
+
+	pterm.DefaultLogger.Error("Unable to load replied messages: " + err.Error())
+	threaded = make([]message_thread_model.ThreadedMessage, len(messages))
+	for i, message := range messages {
+		threaded[i].Message = message
+	}
+	return threaded
+}
+
+// WithRepliedTo attaches the replied message summary to each message, keeping the order.
+func WithRepliedTo(messages []message_entity.Message) ([]message_thread_model.ThreadedMessage, error) {
+	threaded := make([]message_thread_model.ThreadedMessage, len(messages))
+	ids := make([]uuid.UUID, 0, len(messages))
+	for i, message := range messages {
+		threaded[i].Message = message
+		if ContextWamID(message) != "" {
+			ids = append(ids, message.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return threaded, nil
+	}
+
+	var replies []message_thread_entity.MessageReply
+	if err := database.DB.Preload("RepliedTo").Where("message_id IN ?", ids).Find(&replies).Error; err != nil {
+		return nil, err
+	}
+
+	byMessage := make(map[uuid.UUID]*message_thread_model.RepliedTo, len(replies))
+	for _, reply := range replies {
+		byMessage[reply.MessageID] = summarize(reply)
+	}
+
+	for i := range threaded {
+		if summary, ok := byMessage[threaded[i].ID]; ok {
+			threaded[i].RepliedTo = summary
+			continue
+		}
+		// Not recorded yet (or recorded by an older version): the wamid is still useful.
+		if wamID := ContextWamID(threaded[i].Message); wamID != "" {
+			threaded[i].RepliedTo = &message_thread_model.RepliedTo{WamID: wamID}
+		}
+	}
+
+	return threaded, nil
+}
+
+func summarize(reply message_thread_entity.MessageReply) *message_thread_model.RepliedTo {
+	summary := message_thread_model.RepliedTo{WamID: reply.RepliedToWamID}
+	if reply.RepliedTo == nil {
+		return &summary
+	}
+
+	target := reply.RepliedTo
+	summary.ID = &target.ID
+	summary.FromID = target.FromID
+	summary.ToID = target.ToID
+	summary.CreatedAt = &target.CreatedAt
+	summary.Type, summary.Preview = describe(*target)
+	return &summary
+}
+
+// describe returns the type of a message and a short human readable preview.
+func describe(message message_entity.Message) (string, string) {
+	if message.ReceiverData != nil && message.ReceiverData.MessageReceived != nil {
+		received := message.ReceiverData.MessageReceived
+		preview := ""
+		switch {
+		case received.Text != nil:
+			preview = received.Text.Body
+		case received.Image != nil:
+			preview = received.Image.Caption
+		case received.Video != nil:
+			preview = received.Video.Caption
+		case received.Document != nil:
+			preview = firstNonEmpty(received.Document.Caption, received.Document.Filename)
+		case received.Button != nil:
+			preview = received.Button.Text
+		case received.Interactive != nil && received.Interactive.ButtonReply != nil:
+			preview = received.Interactive.ButtonReply.Title
+		case received.Interactive != nil && received.Interactive.ListReply != nil:
+			preview = received.Interactive.ListReply.Title
+		}
+		return string(received.Type), preview
+	}
+
+	if message.SenderData != nil && message.SenderData.Message != nil {
+		sent := message.SenderData.Message
+		preview := ""
+		switch {
+		case sent.Text != nil:
+			preview = sent.Text.Body
+		case sent.Image != nil:
+			preview = sent.Image.Caption
+		case sent.Video != nil:
+			preview = sent.Video.Caption
+		case sent.Document != nil:
+			preview = firstNonEmpty(sent.Document.Caption, sent.Document.Filename)
+		case sent.Interactive != nil && sent.Interactive.Body != nil && sent.Interactive.Body.Text != nil:
+			preview = *sent.Interactive.Body.Text
+		case sent.Template != nil:
+			preview = sent.Template.Name
+		}
+		return string(sent.Type), preview
+	}
+
+	return "", ""
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
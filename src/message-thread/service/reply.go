@@ -0,0 +1,120 @@
+package message_thread_service
+
+import (
+	message_entity "github.com/Astervia/wacraft-core/src/message/entity"
+	"github.com/Astervia/wacraft-server/src/database"
+	message_thread_entity "github.com/Astervia/wacraft-server/src/message-thread/entity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// wamIDWhere matches a message by its wamid, received or returned by Meta on send.
+// The inbound wamid is served by idx_messages_receiver_wam_id and the containment
+// check by the GIN index on product_data, so the lookup never scans the table.
+const wamIDWhere = "(receiver_data->>'id' = ? OR " +
+	"product_data @> jsonb_build_object('messages', jsonb_build_array(jsonb_build_object('id', ?::text))))"
+
+// WamID returns the wamid of the message, or "" when it is not known yet.
+func WamID(message message_entity.Message) string {
+	if message.ReceiverData != nil && message.ReceiverData.MessageReceived != nil {
+		return message.ReceiverData.MessageReceived.ID
+	}
+	if message.ProductData != nil && message.ProductData.Response != nil && len(message.ProductData.Messages) > 0 {
+		return message.ProductData.Messages[0].ID.ID
+	}
+	return ""
+}
+
+// ContextWamID returns the wamid the message replies to, or "" when it is not a reply.
+func ContextWamID(message message_entity.Message) string {
+	if message.ReceiverData != nil && message.ReceiverData.MessageReceived != nil {
+		if ctx := message.ReceiverData.MessageReceived.Context; ctx != nil {
+			return ctx.ID
+		}
+		return ""
+	}
+	if message.SenderData != nil && message.SenderData.Message != nil {
+		if ctx := message.SenderData.Message.Context; ctx != nil {
+			return ctx.MessageID
+		}
+	}
+	return ""
+}
+
+// FindIDByWamID returns the ID of the message of the messaging product with the
+// given wamid, or nil when it is not stored.
+func FindIDByWamID(messagingProductID uuid.UUID, wamID string, db *gorm.DB) (*uuid.UUID, error) {
+	if db == nil {
+		db = database.DB
+	}
+
+	var ids []uuid.UUID
+	err := db.Model(&message_entity.Message{}).
+		Where("messaging_product_id = ?", messagingProductID).
+		Where(wamIDWhere, wamID, wamID).
+		Order("created_at ASC").
+		Limit(1).
+		Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+	return &ids[0], nil
+}
+
+// RecordMessageReplies keeps the reply links of a stored message up to date:
+// it links the message to the message it replies to and links earlier replies
+// that were waiting for its wamid. Call it whenever a message is created or
+// gets its wamid.
+func RecordMessageReplies(message message_entity.Message, db *gorm.DB) error {
+	if db == nil {
+		db = database.DB
+	}
+
+	if err := recordReply(message, db); err != nil {
+		return err
+	}
+	return linkPendingReplies(message, db)
+}
+
+// recordReply stores the link between a reply and the message it references.
+func recordReply(message message_entity.Message, db *gorm.DB) error {
+	contextWamID := ContextWamID(message)
+	if contextWamID == "" {
+		return nil
+	}
+
+	reply := message_thread_entity.MessageReply{
+		MessageID:          message.ID,
+		MessagingProductID: message.MessagingProductID,
+		RepliedToWamID:     contextWamID,
+	}
+
+	repliedToID, err := FindIDByWamID(message.MessagingProductID, contextWamID, db)
+	if err != nil {
+		return err
+	}
+	reply.RepliedToID = repliedToID
+
+	return db.
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "message_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"replied_to_id", "updated_at"}),
+			Where: clause.Where{Exprs: []clause.Expression{
+				clause.Expr{SQL: "message_replies.replied_to_id IS NULL"},
+			}},
+		}).
+		Create(&reply).Error
+}
+
+// linkPendingReplies resolves replies stored before the referenced message had its wamid.
+func linkPendingReplies(message message_entity.Message, db *gorm.DB) error {
+	wamID := WamID(message)
+	if wamID == "" {
+		return nil
+	}
+
+	return db.Model(&message_thread_entity.MessageReply{}).
+		Where("messaging_product_id = ? AND replied_to_wam_id = ? AND replied_to_id IS NULL", message.MessagingProductID, wamID).
+		Update("replied_to_id", message.ID).Error
+}
@@ -0,0 +1,83 @@
+package message_thread_service
+
+import (
+	"testing"
+
+	message_entity "github.com/Astervia/wacraft-core/src/message/entity"
+	message_model "github.com/Astervia/wacraft-core/src/message/model"
+	"github.com/Rfluid/whatsapp-cloud-api/src/common"
+	"github.com/Rfluid/whatsapp-cloud-api/src/message"
+	"github.com/Rfluid/whatsapp-cloud-api/src/message/content"
+)
+
+func receivedMessage(received message.MessageReceived) message_entity.Message {
+	var m message_entity.Message
+	m.ReceiverData = &message_model.ReceiverData{MessageReceived: &received}
+	return m
+}
+
+func sentMessage(sent message.Message, wamIDs ...string) message_entity.Message {
+	var m message_entity.Message
+	m.SenderData = &message_model.SenderData{Message: &sent}
+	if len(wamIDs) > 0 {
+		response := message.Response{}
+		for _, id := range wamIDs {
+			response.Messages = append(response.Messages, message.MessageResponse{ID: common.ID{ID: id}})
+		}
+		m.ProductData = &message_model.ProductData{Response: &response}
+	}
+	return m
+}
+
+func TestWamID(t *testing.T) {
+	inbound := receivedMessage(message.MessageReceived{ReceivedDirection: message.ReceivedDirection{ID: "wamid.in"}})
+	if got := WamID(inbound); got != "wamid.in" {
+		t.Errorf("inbound: expected wamid.in, got %q", got)
+	}
+
+	outbound := sentMessage(message.Message{}, "wamid.out", "wamid.other")
+	if got := WamID(outbound); got != "wamid.out" {
+		t.Errorf("outbound: expected wamid.out, got %q", got)
+	}
+
+	if got := WamID(sentMessage(message.Message{})); got != "" {
+		t.Errorf("unsent: expected no wamid, got %q", got)
+	}
+}
+
+func TestContextWamID(t *testing.T) {
+	inbound := receivedMessage(message.MessageReceived{Context: &message.ReceivedContext{ID: "wamid.target"}})
+	if got := ContextWamID(inbound); got != "wamid.target" {
+		t.Errorf("inbound reply: expected wamid.target, got %q", got)
+	}
+
+	outbound := sentMessage(message.Message{Context: &message.Context{MessageID: "wamid.target"}})
+	if got := ContextWamID(outbound); got != "wamid.target" {
+		t.Errorf("outbound reply: expected wamid.target, got %q", got)
+	}
+
+	if got := ContextWamID(receivedMessage(message.MessageReceived{})); got != "" {
+		t.Errorf("not a reply: expected no wamid, got %q", got)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	inbound := receivedMessage(message.MessageReceived{
+		Type:            content.ReceiveType("text"),
+		ReceivedContent: message.ReceivedContent{Text: &content.TextData{Body: "hello"}},
+	})
+	if kind, preview := describe(inbound); kind != "text" || preview != "hello" {
+		t.Errorf("inbound: expected text/hello, got %s/%s", kind, preview)
+	}
+
+	var outbound message.Message
+	outbound.Type = content.Type("text")
+	outbound.Text = &content.TextData{Body: "hi"}
+	if kind, preview := describe(sentMessage(outbound)); kind != "text" || preview != "hi" {
+		t.Errorf("outbound: expected text/hi, got %s/%s", kind, preview)
+	}
+
+	if kind, preview := describe(message_entity.Message{}); kind != "" || preview != "" {
+		t.Errorf("empty: expected nothing, got %s/%s", kind, preview)
+	}
+}
@@ -0,0 +1,82 @@
+package message_thread_service
+
+import (
+	"database/sql"
+
+	message_entity "github.com/Astervia/wacraft-core/src/message/entity"
+	"github.com/Astervia/wacraft-server/src/database"
+	message_thread_model "github.com/Astervia/wacraft-server/src/message-thread/model"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MaxThreadDepth bounds how many replies are followed in each direction.
+const MaxThreadDepth = 100
+
+// threadIDsQuery returns the messages the root replies to (recursively), the
+// root itself and every reply to it (recursively).
+const threadIDsQuery = `
+WITH RECURSIVE ancestors AS (
+    SELECT replied_to_id, 1 AS depth
+      FROM message_replies
+     WHERE message_id = @root AND replied_to_id IS NOT NULL
+    UNION ALL
+    SELECT r.replied_to_id, a.depth + 1
+      FROM message_replies r
+      JOIN ancestors a ON r.message_id = a.replied_to_id
+     WHERE r.replied_to_id IS NOT NULL AND a.depth < @max_depth
+), descendants AS (
+    SELECT message_id, 1 AS depth
+      FROM message_replies
+     WHERE replied_to_id = @root
+    UNION ALL
+    SELECT r.message_id, d.depth + 1
+      FROM message_replies r
+      JOIN descendants d ON r.replied_to_id = d.message_id
+     WHERE d.depth < @max_depth
+)
+SELECT replied_to_id FROM ancestors
+UNION
+SELECT @root::uuid
+UNION
+SELECT message_id FROM descendants`
+
+// GetThread returns the reply chain of a message of the workspace, oldest first:
+// the messages it replies to, the message itself and the replies to it.
+// Returns gorm.ErrRecordNotFound when the message is not in the workspace.
+func GetThread(messageID uuid.UUID, workspaceID uuid.UUID) ([]message_thread_model.ThreadedMessage, error) {
+	var ids []uuid.UUID
+	err := database.DB.
+		Raw(threadIDsQuery, sql.Named("root", messageID), sql.Named("max_depth", MaxThreadDepth)).
+		Scan(&ids).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []message_entity.Message
+	err = database.DB.Model(&message_entity.Message{}).
+		Joins("From").
+		Joins("To").
+		Joins("From.Contact").
+		Joins("To.Contact").
+		Joins("JOIN messaging_products ON messages.messaging_product_id = messaging_products.id AND messaging_products.workspace_id = ?", workspaceID).
+		Where("messages.id IN ?", ids).
+		Order("messages.created_at ASC, messages.id ASC").
+		Find(&messages).Error
+	if err != nil {
+		return nil, err
+	}
+
+	found := false
+	for _, message := range messages {
+		if message.ID == messageID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return WithRepliedTo(messages)
+}
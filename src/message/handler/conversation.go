@@ -7,6 +7,7 @@ import (
 	message_entity "github.com/Astervia/wacraft-core/src/message/entity"
 	message_model "github.com/Astervia/wacraft-core/src/message/model"
 	"github.com/Astervia/wacraft-server/src/database"
+	database_cursor "github.com/Astervia/wacraft-server/src/database/cursor"
 	message_service "github.com/Astervia/wacraft-server/src/message/service"
 	"github.com/Astervia/wacraft-server/src/validators"
 	workspace_middleware "github.com/Astervia/wacraft-server/src/workspace/middleware"
@@ -18,11 +19,13 @@ import (
 //
 //	@Summary		Get conversation messages
 //	@Description	Retrieves a paginated list of messages sent or received by the specified messaging product contact.
+//	@Description	Set pagination=cursor to receive a page with next_cursor and prev_cursor instead of a plain array, newest first; pass either cursor back to move between pages. Offset and order are ignored in cursor mode.
 //	@Tags			Message conversation
 //	@Accept			json
 //	@Produce		json
 //	@Param			message						query		message_model.QueryPaginated	true	"Pagination and filter parameters"
 //	@Param			messagingProductContactID	path		string							true	"Messaging product contact ID"
+//	@Param			cursor						query		database_cursor.Query			false	"Cursor pagination"
 //	@Success		200							{array}		message_entity.Message			"Conversation messages"
 //	@Failure		400							{object}	common_model.DescriptiveError	"Invalid query or ID"
 //	@Failure		500							{object}	common_model.DescriptiveError	"Failed to retrieve messages"
@@ -50,23 +53,57 @@ func GetConversation(c *fiber.Ctx) error {
 		)
 	}
 
+	cursorQuery := new(database_cursor.Query)
+	if err := c.QueryParser(cursorQuery); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewParseJsonError(err).Send(),
+		)
+	}
+
 	workspace := workspace_middleware.GetWorkspace(c)
 	db := database.DB.Joins("JOIN messaging_products ON messages.messaging_product_id = messaging_products.id AND messaging_products.workspace_id = ?", workspace.ID)
 
-	messages, err := message_service.GetConversation(
-		mpcID,
-		message_entity.Message{
-			MessageFields: message_model.MessageFields{
-				FromID:             query.FromID,
-				ToID:               query.ToID,
-				MessagingProductID: query.MessagingProductID,
-				AuditWithDeleted: common_model.AuditWithDeleted{
-					Audit: common_model.Audit{
-						ID: query.ID,
-					},
+	entity := message_entity.Message{
+		MessageFields: message_model.MessageFields{
+			FromID:             query.FromID,
+			ToID:               query.ToID,
+			MessagingProductID: query.MessagingProductID,
+			AuditWithDeleted: common_model.AuditWithDeleted{
+				Audit: common_model.Audit{
+					ID: query.ID,
 				},
 			},
 		},
+	}
+
+	if cursorQuery.Enabled() {
+		cursor, err := cursorQuery.Parse()
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(
+				common_model.NewApiError("unable to parse cursor", err, "database_cursor").Send(),
+			)
+		}
+
+		page, err := message_service.GetConversationByCursor(
+			mpcID,
+			entity,
+			cursor,
+			database_cursor.Limit(query.Limit),
+			&query.DateWhereWithDeletedAt,
+			db,
+		)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(
+				common_model.NewApiError("unable to get conversation messages", err, "message_service").Send(),
+			)
+		}
+
+		return c.Status(fiber.StatusOK).JSON(page)
+	}
+
+	messages, err := message_service.GetConversation(
+		mpcID,
+		entity,
 		&query.Paginate,
 		&query.DateOrder,
 		&query.DateWhereWithDeletedAt,
@@ -85,11 +122,13 @@ func GetConversation(c *fiber.Ctx) error {
 // GetConversations returns the latest message in each conversation.
 //
 //	@Summary		Get conversations
-//	@Description	Retrieves a paginated list of the latest messages per conversation, enriched with contact information.
+//	@Description	Retrieves a paginated list of the latest messages per conversation, enriched with contact information, most recent conversations first.
+//	@Description	Set pagination=cursor to receive a page with next_cursor and prev_cursor instead of a plain array; pass either cursor back to move between pages. Offset and order are ignored in cursor mode.
 //	@Tags			Message conversation
 //	@Accept			json
 //	@Produce		json
 //	@Param			message	query		message_model.QueryPaginated	true	"Pagination and filter parameters"
+//	@Param			cursor	query		database_cursor.Query			false	"Cursor pagination"
 //	@Success		200		{array}		message_entity.Message			"Latest messages per conversation"
 //	@Failure		400		{object}	common_model.DescriptiveError	"Invalid query"
 //	@Failure		500		{object}	common_model.DescriptiveError	"Failed to retrieve conversations"
@@ -110,8 +149,45 @@ func GetConversations(c *fiber.Ctx) error {
 		)
 	}
 
+	cursorQuery := new(database_cursor.Query)
+	if err := c.QueryParser(cursorQuery); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewParseJsonError(err),
+		)
+	}
+
 	workspace := workspace_middleware.GetWorkspace(c)
 
+	if cursorQuery.Enabled() {
+		cursor, err := cursorQuery.Parse()
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(
+				common_model.NewApiError("unable to parse cursor", err, "database_cursor").Send(),
+			)
+		}
+
+		page, err := message_service.GetLatestMessagesForEachUserByCursor(
+			message_entity.Message{
+				MessageFields: message_model.MessageFields{
+					FromID:             query.FromID,
+					ToID:               query.ToID,
+					MessagingProductID: query.MessagingProductID,
+				},
+			},
+			cursor,
+			database_cursor.Limit(query.Limit),
+			&query.DateWhereWithDeletedAt,
+			workspace.ID,
+		)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(
+				common_model.NewApiError("unable to get conversations", err, "message_service"),
+			)
+		}
+
+		return c.Status(fiber.StatusOK).JSON(page)
+	}
+
 	messages, err := message_service.GetLatestMessagesForEachUser(
 		message_entity.Message{
 			MessageFields: message_model.MessageFields{
@@ -6,6 +6,7 @@ import (
 	message_model "github.com/Astervia/wacraft-core/src/message/model"
 	"github.com/Astervia/wacraft-core/src/repository"
 	"github.com/Astervia/wacraft-server/src/database"
+	database_cursor "github.com/Astervia/wacraft-server/src/database/cursor"
 	"github.com/Astervia/wacraft-server/src/validators"
 	workspace_middleware "github.com/Astervia/wacraft-server/src/workspace/middleware"
 	"github.com/gofiber/fiber/v2"
@@ -15,10 +16,12 @@ import (
 //
 //	@Summary		Retrieve messages
 //	@Description	Fetches a paginated list of messages filtered by sender, receiver, messaging product, etc.
+//	@Description	Set pagination=cursor to receive a page with next_cursor and prev_cursor instead of a plain array, newest first; pass either cursor back to move between pages. Offset and order are ignored in cursor mode.
 //	@Tags			Message
 //	@Accept			json
 //	@Produce		json
 //	@Param			message	query		message_model.QueryPaginated	true	"Pagination and query parameters"
+//	@Param			cursor	query		database_cursor.Query			false	"Cursor pagination"
 //	@Success		200		{array}		message_entity.Message			"List of messages"
 //	@Failure		400		{object}	common_model.DescriptiveError	"Invalid query parameters"
 //	@Failure		500		{object}	common_model.DescriptiveError	"Failed to retrieve messages"
@@ -39,22 +42,57 @@ func Get(c *fiber.Ctx) error {
 		)
 	}
 
+	cursorQuery := new(database_cursor.Query)
+	if err := c.QueryParser(cursorQuery); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewParseJsonError(err).Send(),
+		)
+	}
+
 	workspace := workspace_middleware.GetWorkspace(c)
 	db := database.DB.Joins("JOIN messaging_products ON messages.messaging_product_id = messaging_products.id AND messaging_products.workspace_id = ?", workspace.ID)
 
-	messages, err := repository.GetPaginated(
-		message_entity.Message{
-			MessageFields: message_model.MessageFields{
-				FromID:             query.FromID,
-				ToID:               query.ToID,
-				MessagingProductID: query.MessagingProductID,
-				AuditWithDeleted: common_model.AuditWithDeleted{
-					Audit: common_model.Audit{
-						ID: query.ID,
-					},
+	entity := message_entity.Message{
+		MessageFields: message_model.MessageFields{
+			FromID:             query.FromID,
+			ToID:               query.ToID,
+			MessagingProductID: query.MessagingProductID,
+			AuditWithDeleted: common_model.AuditWithDeleted{
+				Audit: common_model.Audit{
+					ID: query.ID,
 				},
 			},
 		},
+	}
+
+	if cursorQuery.Enabled() {
+		cursor, err := cursorQuery.Parse()
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(
+				common_model.NewApiError("unable to parse cursor", err, "database_cursor").Send(),
+			)
+		}
+
+		page, err := database_cursor.GetPage(
+			entity,
+			&query.DateWhereWithDeletedAt,
+			"messages",
+			cursor,
+			database_cursor.Limit(query.Limit),
+			func(m message_entity.Message) database_cursor.Cursor { return database_cursor.AuditKey(m.Audit) },
+			db,
+		)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(
+				common_model.NewApiError("unable to get messages", err, "database_cursor").Send(),
+			)
+		}
+
+		return c.Status(fiber.StatusOK).JSON(page)
+	}
+
+	messages, err := repository.GetPaginated(
+		entity,
 		&query.Paginate,
 		&query.DateOrder,
 		&query.DateWhereWithDeletedAt,
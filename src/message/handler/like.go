@@ -85,6 +85,7 @@ func ContentLike(c *fiber.Ctx) error {
 //
 //	@Summary		Search messages by content field
 //	@Description	Uses ILIKE to match the given text in the specified key field. The fields `from` and `to` are populated in the result.
+//	@Description	Deprecated: use POST /message/search with an ilike condition.
 //	@Tags			Message
 //	@Accept			json
 //	@Produce		json
@@ -95,6 +96,7 @@ func ContentLike(c *fiber.Ctx) error {
 //	@Failure		500			{object}	common_model.DescriptiveError		"Failed to query messages"
 //	@Security		ApiKeyAuth
 //	@Security		WorkspaceAuth
+//	@Deprecated
 //	@Router			/message/content/{keyName}/like/{likeText} [get]
 func ContentKeyLike(c *fiber.Ctx) error {
 	params := new(message_model.ContentKeyLikeParams)
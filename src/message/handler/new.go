@@ -4,10 +4,10 @@ import (
 	"sync"
 
 	_ "github.com/Astervia/wacraft-core/src/common/model"
-	message_entity "github.com/Astervia/wacraft-core/src/message/entity"
 	user_entity "github.com/Astervia/wacraft-core/src/user/entity"
 	websocket_model "github.com/Astervia/wacraft-core/src/websocket/model"
 	workspace_entity "github.com/Astervia/wacraft-core/src/workspace/entity"
+	message_thread_model "github.com/Astervia/wacraft-server/src/message-thread/model"
 	websocket_workspace_manager "github.com/Astervia/wacraft-server/src/websocket/workspace-manager"
 	"github.com/gofiber/contrib/websocket"
 )
@@ -15,13 +15,13 @@ import (
 // newMessageClientPool maintains all WebSocket clients connected for new messages
 var (
 	newMessageClientPool       = websocket_model.CreateClientPool()
-	NewMessageWorkspaceManager = websocket_workspace_manager.CreateWorkspaceChannelManager[message_entity.Message]()
+	NewMessageWorkspaceManager = websocket_workspace_manager.CreateWorkspaceChannelManager[message_thread_model.ThreadedMessage]()
 )
 
 // NewMessageSubscription upgrades the connection to WebSocket and streams new WhatsApp messages.
 //
 //	@Summary		Subscribe to new messages
-//	@Description	Establishes a WebSocket connection and streams incoming and outgoing WhatsApp messages in real-time for a specific workspace.
+//	@Description	Establishes a WebSocket connection and streams incoming and outgoing WhatsApp messages in real-time for a specific workspace. Replies carry a `replied_to` summary of the message they answer.
 //	@Tags			Message Websocket
 //	@Accept			json
 //	@Produce		json
@@ -0,0 +1,133 @@
+package message_handler
+
+import (
+	"errors"
+	"sync"
+
+	common_model "github.com/Astervia/wacraft-core/src/common/model"
+	user_entity "github.com/Astervia/wacraft-core/src/user/entity"
+	websocket_model "github.com/Astervia/wacraft-core/src/websocket/model"
+	workspace_entity "github.com/Astervia/wacraft-core/src/workspace/entity"
+	message_reaction_model "github.com/Astervia/wacraft-server/src/message-reaction/model"
+	message_reaction_service "github.com/Astervia/wacraft-server/src/message-reaction/service"
+	webhook_event_model "github.com/Astervia/wacraft-server/src/webhook-event/model"
+	webhook_service "github.com/Astervia/wacraft-server/src/webhook/service"
+	websocket_workspace_manager "github.com/Astervia/wacraft-server/src/websocket/workspace-manager"
+	workspace_middleware "github.com/Astervia/wacraft-server/src/workspace/middleware"
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	messageReactionClientPool       = websocket_model.CreateClientPool()
+	MessageReactionWorkspaceManager = websocket_workspace_manager.CreateWorkspaceChannelManager[message_reaction_model.ReactionEvent]()
+)
+
+// PropagateReactionEvent broadcasts a reaction change to the workspace and
+// sends it to the message.reaction webhooks.
+func PropagateReactionEvent(workspaceID *uuid.UUID, event message_reaction_model.ReactionEvent) {
+	if workspaceID != nil {
+		go MessageReactionWorkspaceManager.BroadcastToWorkspace(*workspaceID, event)
+	}
+	go webhook_service.SendEvent(webhook_event_model.MessageReaction, workspaceID, event)
+}
+
+// MessageReactionSubscription upgrades the connection to WebSocket and streams reaction changes.
+//
+//	@Summary		Subscribe to message reactions
+//	@Description	Establishes a WebSocket connection and streams reactions added, changed or removed on the messages of a specific workspace, inbound and outbound.
+//	@Tags			Message Websocket
+//	@Accept			json
+//	@Produce		json
+//	@Param			workspace_id	query		string							false	"Workspace ID (alternative to header)"
+//	@Success		101				{string}	string							"WebSocket connection established"
+//	@Failure		400				{object}	common_model.DescriptiveError	"Invalid connection request"
+//	@Failure		500				{object}	common_model.DescriptiveError	"Internal server error"
+//	@Security		ApiKeyAuth
+//	@Security		WorkspaceAuth
+//	@Router			/websocket/message/reaction [get]
+func MessageReactionSubscription(ctx *websocket.Conn) {
+	defer ctx.Close()
+
+	// Registering user and workspace
+	user := ctx.Locals("user").(*user_entity.User)                     // This must be paired with the UserMiddleware. Otherwise will panic.
+	workspace := ctx.Locals("workspace").(*workspace_entity.Workspace) // This must be paired with the WebSocketWorkspaceMiddleware. Otherwise will panic.
+
+	clientID := messageReactionClientPool.CreateID(user.ID)
+	client := websocket_model.Client[websocket_model.ClientID]{
+		Connection: ctx,
+		Data:       *clientID,
+	}
+	MessageReactionWorkspaceManager.AppendClient(workspace.ID, client, clientID.String())
+
+	// Configuring disconnection
+	defer func() {
+		var deleteWg sync.WaitGroup
+
+		deleteWg.Go(func() {
+			messageReactionClientPool.DeleteID(*clientID)
+		})
+
+		deleteWg.Go(func() {
+			MessageReactionWorkspaceManager.RemoveClient(workspace.ID, client.Data.String())
+		})
+
+		deleteWg.Wait()
+	}()
+
+	for {
+		// Read message from WebSocket
+		msgType, data, err := ctx.ReadMessage()
+		if err != nil {
+			break // connection closed or other error
+		}
+
+		// Only handle text frames; ignore others
+		if msgType == websocket.TextMessage && string(data) == string(websocket_model.Ping) {
+			if writeErr := ctx.WriteMessage(websocket.TextMessage, []byte(websocket_model.Pong)); writeErr != nil {
+				break // stop if the write fails
+			}
+		}
+	}
+}
+
+// GetReactions returns the current reactions to a message.
+//
+//	@Summary		Get message reactions
+//	@Description	Returns the current reaction of each contact, and of the business, to the given message, oldest first.
+//	@Tags			Message
+//	@Produce		json
+//	@Param			id	path		string										true	"Message ID"
+//	@Success		200	{array}		message_reaction_entity.MessageReaction		"Reactions to the message"
+//	@Failure		400	{object}	common_model.DescriptiveError				"Invalid message ID"
+//	@Failure		404	{object}	common_model.DescriptiveError				"Message not found in the workspace"
+//	@Failure		500	{object}	common_model.DescriptiveError				"Failed to get the reactions"
+//	@Security		ApiKeyAuth
+//	@Security		WorkspaceAuth
+//	@Router			/message/{id}/reaction [get]
+func GetReactions(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewApiError("unable to parse message id string to UUID", err, "github.com/google/uuid").Send(),
+		)
+	}
+
+	workspace := workspace_middleware.GetWorkspace(c)
+
+	reactions, err := message_reaction_service.GetByMessage(id, workspace.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(
+				common_model.NewApiError("message not found", err, "message_reaction_service").Send(),
+			)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			common_model.NewApiError("unable to get message reactions", err, "message_reaction_service").Send(),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(reactions)
+}
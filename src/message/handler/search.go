@@ -0,0 +1,59 @@
+package message_handler
+
+import (
+	"errors"
+
+	common_model "github.com/Astervia/wacraft-core/src/common/model"
+	"github.com/Astervia/wacraft-server/src/database"
+	message_service "github.com/Astervia/wacraft-server/src/message/service"
+	"github.com/Astervia/wacraft-server/src/validators"
+	workspace_middleware "github.com/Astervia/wacraft-server/src/workspace/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Search returns messages ranked by full-text relevance with highlighted snippets.
+//
+//	@Summary		Full-text search messages
+//	@Description	Searches text bodies, captions, button and list replies and template parameters, ignoring accents.
+//	@Description	`q` accepts words (all must match), "quoted phrases" and prefixes ending with `*`. Results are ordered by relevance, then recency; the snippet is HTML: the message text is escaped and matches are wrapped in `<mark>`.
+//	@Tags			Message
+//	@Accept			json
+//	@Produce		json
+//	@Param			search	query		message_service.SearchQuery		true	"Search text, filters and pagination"
+//	@Success		200		{array}		message_service.SearchResult	"Ranked messages"
+//	@Failure		400		{object}	common_model.DescriptiveError	"Invalid query"
+//	@Failure		500		{object}	common_model.DescriptiveError	"Failed to search messages"
+//	@Security		ApiKeyAuth
+//	@Security		WorkspaceAuth
+//	@Router			/message/search [get]
+func Search(c *fiber.Ctx) error {
+	query := new(message_service.SearchQuery)
+	if err := c.QueryParser(query); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewParseJsonError(err).Send(),
+		)
+	}
+
+	if err := validators.Validator().Struct(query); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewValidationError(err).Send(),
+		)
+	}
+
+	workspace := workspace_middleware.GetWorkspace(c)
+	db := database.DB.Joins("JOIN messaging_products ON messages.messaging_product_id = messaging_products.id AND messaging_products.workspace_id = ?", workspace.ID)
+
+	results, err := message_service.Search(*query, db)
+	if err != nil {
+		if errors.Is(err, message_service.ErrEmptySearch) {
+			return c.Status(fiber.StatusBadRequest).JSON(
+				common_model.NewApiError("invalid search text", err, "message_service").Send(),
+			)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			common_model.NewApiError("unable to search messages", err, "message_service").Send(),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(results)
+}
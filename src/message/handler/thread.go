@@ -0,0 +1,51 @@
+package message_handler
+
+import (
+	"errors"
+
+	common_model "github.com/Astervia/wacraft-core/src/common/model"
+	message_thread_service "github.com/Astervia/wacraft-server/src/message-thread/service"
+	workspace_middleware "github.com/Astervia/wacraft-server/src/workspace/middleware"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GetThread returns the reply chain of a message.
+//
+//	@Summary		Get message thread
+//	@Description	Returns the messages the given message replies to, the message itself and every reply to it, oldest first. Replies are linked through the WhatsApp context wamid and carry a `replied_to` summary.
+//	@Tags			Message
+//	@Produce		json
+//	@Param			id	path		string										true	"Message ID"
+//	@Success		200	{array}		message_thread_model.ThreadedMessage		"Messages of the thread"
+//	@Failure		400	{object}	common_model.DescriptiveError				"Invalid message ID"
+//	@Failure		404	{object}	common_model.DescriptiveError				"Message not found in the workspace"
+//	@Failure		500	{object}	common_model.DescriptiveError				"Failed to get the thread"
+//	@Security		ApiKeyAuth
+//	@Security		WorkspaceAuth
+//	@Router			/message/{id}/thread [get]
+func GetThread(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewApiError("unable to parse message id string to UUID", err, "github.com/google/uuid").Send(),
+		)
+	}
+
+	workspace := workspace_middleware.GetWorkspace(c)
+
+	thread, err := message_thread_service.GetThread(id, workspace.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(
+				common_model.NewApiError("message not found", err, "message_thread_service").Send(),
+			)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			common_model.NewApiError("unable to get message thread", err, "message_thread_service").Send(),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(thread)
+}
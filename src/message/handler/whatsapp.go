@@ -1,29 +1,40 @@
 package message_handler
 
 import (
+	"errors"
+
 	common_model "github.com/Astervia/wacraft-core/src/common/model"
 	message_entity "github.com/Astervia/wacraft-core/src/message/entity"
 	message_model "github.com/Astervia/wacraft-core/src/message/model"
 	webhook_entity "github.com/Astervia/wacraft-core/src/webhook/entity"
 	webhook_model "github.com/Astervia/wacraft-core/src/webhook/model"
+	message_thread_service "github.com/Astervia/wacraft-server/src/message-thread/service"
 	message_service "github.com/Astervia/wacraft-server/src/message/service"
+	messaging_product_service "github.com/Astervia/wacraft-server/src/messaging-product/service"
+	phone_config_service "github.com/Astervia/wacraft-server/src/phone-config/service"
 	"github.com/Astervia/wacraft-server/src/validators"
 	webhook_service "github.com/Astervia/wacraft-server/src/webhook/service"
 	workspace_middleware "github.com/Astervia/wacraft-server/src/workspace/middleware"
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 )
 
 // SendMessage sends a new WhatsApp message and stores it if successful.
 //
 //	@Summary		Send WhatsApp message
 //	@Description	Sends a WhatsApp message and stores it in the database if the operation is successful.
+//	@Description	The sender may be chosen with "phone_config_id" or "messaging_product_id" in the body. Otherwise the number the contact last wrote to is used, then the workspace default sender.
+//	@Description	Reactions are not stored as messages: they are recorded on the reacted message and the response is the reaction event, or 204 when the reaction was already the current one.
 //	@Tags			WhatsApp message
 //	@Accept			json
 //	@Produce		json
-//	@Param			message	body		message_model.SendWhatsAppMessage	true	"Message data"
-//	@Success		201		{object}	message_entity.Message				"Message sent successfully"
-//	@Failure		400		{object}	common_model.DescriptiveError		"Invalid message payload"
-//	@Failure		500		{object}	common_model.DescriptiveError		"Failed to send or save the message"
+//	@Param			message	body		message_model.SendWhatsAppMessage		true	"Message data"
+//	@Param			sender	body		phone_config_service.SenderSelection	false	"Optional sender selection (same body)"
+//	@Success		201		{object}	message_entity.Message					"Message sent successfully"
+//	@Success		204		{string}	string									"Reaction already current"
+//	@Failure		400		{object}	common_model.DescriptiveError						"Invalid message payload or sender"
+//	@Failure		422		{object}	messaging_product_service.ServiceWindowClosedError	"Free-form message outside the 24-hour customer service window"
+//	@Failure		500		{object}	common_model.DescriptiveError						"Failed to send or save the message"
 //	@Security		ApiKeyAuth
 //	@Security		WorkspaceAuth
 //	@Router			/message/whatsapp [post]
@@ -43,15 +54,27 @@ func SendMessage(c *fiber.Ctx) error {
 		)
 	}
 
+	var selection phone_config_service.SenderSelection
+	if err := c.BodyParser(&selection); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewParseJsonError(err).Send(),
+		)
+	}
+
+	if message_service.IsReaction(body) {
+		return sendReaction(c, body, workspace.ID, selection)
+	}
+
 	propagateCallback := func(data message_entity.Message) {
+		payload := message_thread_service.Threaded(data)
 		// Broadcast to workspace-scoped WebSocket clients
-		go NewMessageWorkspaceManager.BroadcastToWorkspace(workspace.ID, data)
+		go NewMessageWorkspaceManager.BroadcastToWorkspace(workspace.ID, payload)
 		go webhook_service.SendAllByQuery(
 			webhook_entity.Webhook{
 				Event:       webhook_model.SendWhatsAppMessage,
 				WorkspaceID: &workspace.ID,
 			},
-			data,
+			payload,
 		)
 	}
 
@@ -63,8 +86,18 @@ func SendMessage(c *fiber.Ctx) error {
 	entity, err = message_service.FindMessagingProductByWorkspaceAndSendMessage(
 		body,
 		workspace.ID,
+		selection,
 		propagateCallback,
 	)
+	var windowErr *messaging_product_service.ServiceWindowClosedError
+	if errors.As(err, &windowErr) {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(windowErr)
+	}
+	if errors.Is(err, phone_config_service.ErrSenderNotInWorkspace) {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewApiError("invalid sender", err, "message_service").Send(),
+		)
+	}
 	if err != nil {
 		// Fall back to legacy method if workspace-specific fails
 		// entity, err = message_service.FindMessagingProductAndSendMessage(
@@ -80,3 +113,100 @@ func SendMessage(c *fiber.Ctx) error {
 
 	return c.Status(fiber.StatusCreated).JSON(entity)
 }
+
+// SendMessageAsync accepts a WhatsApp message and sends it in the background.
+//
+//	@Summary		Send WhatsApp message asynchronously
+//	@Description	Stores the message in an outbox and returns immediately with its ID. A background worker calls the WhatsApp Cloud API and updates the message; follow its progress through the status WebSocket and outbound webhooks. If every attempt fails a "failed" status is stored for the message.
+//	@Description	The sender is chosen like in POST /message/whatsapp. Reactions are rejected: send them through POST /message/whatsapp.
+//	@Tags			WhatsApp message
+//	@Accept			json
+//	@Produce		json
+//	@Param			message	body		message_model.SendWhatsAppMessage		true	"Message data"
+//	@Param			sender	body		phone_config_service.SenderSelection	false	"Optional sender selection (same body)"
+//	@Success		202		{object}	message_entity.Message					"Message accepted for sending"
+//	@Failure		400		{object}	common_model.DescriptiveError								"Invalid message payload or sender"
+//	@Failure		422		{object}	messaging_product_service.ServiceWindowClosedError			"Free-form message outside the 24-hour customer service window"
+//	@Failure		500		{object}	common_model.DescriptiveError								"Failed to enqueue the message"
+//	@Security		ApiKeyAuth
+//	@Security		WorkspaceAuth
+//	@Router			/message/whatsapp/async [post]
+func SendMessageAsync(c *fiber.Ctx) error {
+	workspace := workspace_middleware.GetWorkspace(c)
+
+	var body message_model.SendWhatsAppMessage
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewParseJsonError(err).Send(),
+		)
+	}
+
+	if err := validators.Validator().Struct(&body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewValidationError(err).Send(),
+		)
+	}
+
+	var selection phone_config_service.SenderSelection
+	if err := c.BodyParser(&selection); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewParseJsonError(err).Send(),
+		)
+	}
+
+	entity, err := message_service.EnqueueWhatsAppMessageByWorkspace(body, workspace.ID, selection)
+	var windowErr *messaging_product_service.ServiceWindowClosedError
+	if errors.As(err, &windowErr) {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(windowErr)
+	}
+	if errors.Is(err, message_service.ErrAsyncReaction) {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewApiError("reactions cannot be sent asynchronously", err, "message_service").Send(),
+		)
+	}
+	if errors.Is(err, phone_config_service.ErrSenderNotInWorkspace) {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewApiError("invalid sender", err, "message_service").Send(),
+		)
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			common_model.NewApiError("unable to enqueue message", err, "message_service").Send(),
+		)
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(entity)
+}
+
+// sendReaction sends a reaction and propagates the change of the reacted
+// message. Reactions are not stored as messages.
+func sendReaction(
+	c *fiber.Ctx,
+	body message_model.SendWhatsAppMessage,
+	workspaceID uuid.UUID,
+	selection phone_config_service.SenderSelection,
+) error {
+	event, err := message_service.FindMessagingProductByWorkspaceAndSendReaction(body, workspaceID, selection)
+	var windowErr *messaging_product_service.ServiceWindowClosedError
+	if errors.As(err, &windowErr) {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(windowErr)
+	}
+	if errors.Is(err, phone_config_service.ErrSenderNotInWorkspace) {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewApiError("invalid sender", err, "message_service").Send(),
+		)
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			common_model.NewApiError("unable to send reaction", err, "message_service").Send(),
+		)
+	}
+
+	if event == nil {
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+
+	PropagateReactionEvent(&workspaceID, *event)
+
+	return c.Status(fiber.StatusCreated).JSON(event)
+}
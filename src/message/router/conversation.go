@@ -36,6 +36,14 @@ func conversationRoutes(group fiber.Router) {
 		billing_middleware.ThroughputMiddleware,
 		message_handler.GetConversation)
 
+	convGroup.Get("/messaging-product-contact/:messagingProductContactID/service-window",
+		auth_middleware.UserMiddleware,
+		auth_middleware.EmailVerifiedMiddleware,
+		workspace_middleware.WorkspaceMiddleware,
+		workspace_middleware.RequirePolicy(workspace_model.PolicyMessageRead),
+		billing_middleware.ThroughputMiddleware,
+		message_handler.GetConversationServiceWindow)
+
 	convGroup.Get("/count/messaging-product-contact/:messagingProductContactID",
 		auth_middleware.UserMiddleware,
 		auth_middleware.EmailVerifiedMiddleware,
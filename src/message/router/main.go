@@ -34,6 +34,22 @@ func mainRoutes(group fiber.Router) {
 		billing_middleware.ThroughputMiddleware,
 		message_handler.Count)
 
+	group.Get("/search",
+		auth_middleware.UserMiddleware,
+		auth_middleware.EmailVerifiedMiddleware,
+		workspace_middleware.WorkspaceMiddleware,
+		workspace_middleware.RequirePolicy(workspace_model.PolicyMessageRead),
+		billing_middleware.ThroughputMiddleware,
+		message_handler.Search)
+
+	group.Post("/search",
+		auth_middleware.UserMiddleware,
+		auth_middleware.EmailVerifiedMiddleware,
+		workspace_middleware.WorkspaceMiddleware,
+		workspace_middleware.RequirePolicy(workspace_model.PolicyMessageRead),
+		billing_middleware.ThroughputMiddleware,
+		message_handler.SearchByFilter)
+
 	group.Get("/content/like/:likeText",
 		auth_middleware.UserMiddleware,
 		auth_middleware.EmailVerifiedMiddleware,
@@ -57,4 +73,20 @@ func mainRoutes(group fiber.Router) {
 		workspace_middleware.RequirePolicy(workspace_model.PolicyMessageRead),
 		billing_middleware.ThroughputMiddleware,
 		message_handler.ContentKeyLike)
+
+	group.Get("/:id/thread",
+		auth_middleware.UserMiddleware,
+		auth_middleware.EmailVerifiedMiddleware,
+		workspace_middleware.WorkspaceMiddleware,
+		workspace_middleware.RequirePolicy(workspace_model.PolicyMessageRead),
+		billing_middleware.ThroughputMiddleware,
+		message_handler.GetThread)
+
+	group.Get("/:id/reaction",
+		auth_middleware.UserMiddleware,
+		auth_middleware.EmailVerifiedMiddleware,
+		workspace_middleware.WorkspaceMiddleware,
+		workspace_middleware.RequirePolicy(workspace_model.PolicyMessageRead),
+		billing_middleware.ThroughputMiddleware,
+		message_handler.GetReactions)
 }
@@ -25,6 +25,13 @@ func whatsappRoutes(group fiber.Router) {
 		workspace_middleware.RequirePolicy(workspace_model.PolicyMessageSend),
 		billing_middleware.ThroughputMiddleware,
 		message_handler.SendMessage)
+	wppGroup.Post("/async",
+		auth_middleware.UserMiddleware,
+		auth_middleware.EmailVerifiedMiddleware,
+		workspace_middleware.WorkspaceMiddleware,
+		workspace_middleware.RequirePolicy(workspace_model.PolicyMessageSend),
+		billing_middleware.ThroughputMiddleware,
+		message_handler.SendMessageAsync)
 	wppGroup.Get("/wam-id/:wamID",
 		auth_middleware.UserMiddleware,
 		auth_middleware.EmailVerifiedMiddleware,
@@ -5,6 +5,7 @@ import (
 	message_entity "github.com/Astervia/wacraft-core/src/message/entity"
 	"github.com/Astervia/wacraft-core/src/repository"
 	"github.com/Astervia/wacraft-server/src/database"
+	database_cursor "github.com/Astervia/wacraft-server/src/database/cursor"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
@@ -43,6 +44,44 @@ func GetConversation(
 	)
 }
 
+// GetConversationByCursor is the keyset variant of GetConversation. Messages are ordered from newest to oldest.
+func GetConversationByCursor(
+	mpcID uuid.UUID, // ID of messaging product contact
+	entity message_entity.Message,
+	cursor *database_cursor.Cursor,
+	limit int,
+	whereable database_model.Whereable,
+	db *gorm.DB,
+) (database_cursor.Page[message_entity.Message], error) {
+	if db == nil {
+		db = database.DB
+	}
+	db = db.
+		Where("from_id = ? OR to_id = ?", mpcID, mpcID).
+		Preload("Statuses", func(db *gorm.DB) *gorm.DB {
+			return db.
+				Select("DISTINCT ON (message_id) *").
+				Order("message_id").
+				Order(`
+                    CASE statuses.product_data->>'status'
+                        WHEN 'read' THEN 1
+                        WHEN 'delivered' THEN 2
+                        WHEN 'sent' THEN 3
+                        ELSE 0
+                    END ASC
+                `)
+		})
+
+	return database_cursor.GetPage(
+		entity, whereable, "messages", cursor, limit,
+		func(m message_entity.Message) database_cursor.Cursor { return database_cursor.AuditKey(m.Audit) },
+		db,
+	)
+}
+
+// GetLatestMessagesForEachUser returns the last message of each conversation of the
+// workspace, read from the conversations table. Unless another order is given the
+// most recent conversations come first.
 func GetLatestMessagesForEachUser(
 	message message_entity.Message,
 	paginable database_model.Paginable,
@@ -52,26 +91,75 @@ func GetLatestMessagesForEachUser(
 ) ([]message_entity.Message, error) {
 	var messages []message_entity.Message
 
-	subquery := database.DB.
-		Model(&message_entity.Message{}).
-		Select(`
-		    DISTINCT ON (COALESCE(from_id, to_id))
-			COALESCE(from_id, to_id) AS contact_id,
-			messages.*
-		`).
-		Joins(`
-		    JOIN messaging_products
-				ON messages.messaging_product_id = messaging_products.id
-				AND messaging_products.workspace_id = ?
-			`,
-			workspaceID,
-		).
-		Where(&message).
-		Order("COALESCE(from_id, to_id)").
-		Order(`"messages".created_at DESC`)
+	db := latestMessagesQuery(message, workspaceID)
 
-	db := database.DB.
-		Table("(?) AS sq", subquery).
+	if paginable != nil {
+		paginable.PaginateQuery(&db)
+	}
+
+	if order != nil {
+		order.OrderQuery(&db, `"messages"`)
+	}
+	if whereable != nil {
+		whereable.Where(&db, `"messages"`)
+	}
+
+	err := db.
+		Order("conversations.last_message_at DESC").
+		Order("conversations.messaging_product_contact_id DESC").
+		Find(&messages).Error
+
+	return messages, err
+}
+
+// GetLatestMessagesForEachUserByCursor is the keyset variant of GetLatestMessagesForEachUser.
+// Conversations are ordered from the most recent to the oldest.
+func GetLatestMessagesForEachUserByCursor(
+	message message_entity.Message,
+	cursor *database_cursor.Cursor,
+	limit int,
+	whereable database_model.Whereable,
+	workspaceID uuid.UUID,
+) (database_cursor.Page[message_entity.Message], error) {
+	var messages []message_entity.Message
+
+	db := latestMessagesQuery(message, workspaceID)
+	if whereable != nil {
+		whereable.Where(&db, `"messages"`)
+	}
+	db = database_cursor.Apply(
+		db,
+		cursor,
+		"conversations.last_message_at",
+		"conversations.messaging_product_contact_id",
+		limit,
+	)
+
+	if err := db.Find(&messages).Error; err != nil {
+		return database_cursor.Page[message_entity.Message]{}, err
+	}
+
+	return database_cursor.NewPage(messages, cursor, limit, latestMessageCursor), nil
+}
+
+// latestMessageCursor returns the keyset position of a conversation from its last message.
+func latestMessageCursor(message message_entity.Message) database_cursor.Cursor {
+	mpcID := message.FromID
+	if mpcID == nil {
+		mpcID = message.ToID
+	}
+
+	c := database_cursor.Cursor{Time: message.CreatedAt}
+	if mpcID != nil {
+		c.ID = *mpcID
+	}
+	return c
+}
+
+func latestMessagesQuery(message message_entity.Message, workspaceID uuid.UUID) *gorm.DB {
+	return database.DB.
+		Model(&message_entity.Message{}).
+		Joins("JOIN conversations ON conversations.last_message_id = messages.id AND conversations.workspace_id = ?", workspaceID).
 		Joins("From").
 		Joins("To").
 		Joins("From.Contact").
@@ -89,22 +177,7 @@ func GetLatestMessagesForEachUser(
                     END ASC
                 `)
 		}).
-		Select("sq.*")
-
-	if paginable != nil {
-		paginable.PaginateQuery(&db)
-	}
-
-	if order != nil {
-		order.OrderQuery(&db, `"sq"`)
-	}
-	if whereable != nil {
-		whereable.Where(&db, `"sq"`)
-	}
-
-	err := db.Find(&messages).Error
-
-	return messages, err
+		Where(&message)
 }
 
 func CountConversations(
@@ -132,8 +205,7 @@ func CountDistinctConversations(
 	workspaceID uuid.UUID,
 ) (int64, error) {
 	db := database.DB.
-		Joins("JOIN messaging_products ON messages.messaging_product_id = messaging_products.id AND messaging_products.workspace_id = ?", workspaceID).
-		Group("COALESCE(from_id, to_id)")
+		Joins("JOIN conversations ON conversations.last_message_id = messages.id AND conversations.workspace_id = ?", workspaceID)
 
 	return repository.Count(entity, order, whereable, prefix, db)
 }
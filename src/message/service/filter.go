@@ -0,0 +1,41 @@
+package message_service
+
+import (
+	message_entity "github.com/Astervia/wacraft-core/src/message/entity"
+	"github.com/Astervia/wacraft-server/src/database"
+	database_filter "github.com/Astervia/wacraft-server/src/database/filter"
+	"gorm.io/gorm"
+)
+
+// FilterSchema whitelists the fields usable in message filters.
+var FilterSchema = database_filter.Schema{
+	Table: "messages",
+	Fields: map[string]database_filter.Field{
+		"id":                   {Column: "messages.id", Type: database_filter.UUID},
+		"created_at":           {Column: "messages.created_at", Type: database_filter.Time},
+		"updated_at":           {Column: "messages.updated_at", Type: database_filter.Time},
+		"from_id":              {Column: "messages.from_id", Type: database_filter.UUID},
+		"to_id":                {Column: "messages.to_id", Type: database_filter.UUID},
+		"messaging_product_id": {Column: "messages.messaging_product_id", Type: database_filter.UUID},
+	},
+	JSON: map[string]string{
+		"sender_data":   "messages.sender_data",
+		"receiver_data": "messages.receiver_data",
+		"product_data":  "messages.product_data",
+	},
+}
+
+// SearchByFilter returns the messages matching the search, with their contacts.
+func SearchByFilter(search database_filter.Search, db *gorm.DB) ([]message_entity.Message, error) {
+	if db == nil {
+		db = database.DB
+	}
+
+	db = db.Model(&message_entity.Message{}).
+		Joins("From").
+		Joins("To").
+		Joins("From.Contact").
+		Joins("To.Contact")
+
+	return database_filter.Find[message_entity.Message](search, FilterSchema, db)
+}
@@ -0,0 +1,402 @@
+package message_service
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	common_model "github.com/Astervia/wacraft-core/src/common/model"
+	message_entity "github.com/Astervia/wacraft-core/src/message/entity"
+	message_model "github.com/Astervia/wacraft-core/src/message/model"
+	messaging_product_entity "github.com/Astervia/wacraft-core/src/messaging-product/entity"
+	status_entity "github.com/Astervia/wacraft-core/src/status/entity"
+	status_model "github.com/Astervia/wacraft-core/src/status/model"
+	"github.com/Astervia/wacraft-server/src/config/env"
+	conversation_service "github.com/Astervia/wacraft-server/src/conversation/service"
+	"github.com/Astervia/wacraft-server/src/database"
+	message_outbox_entity "github.com/Astervia/wacraft-server/src/message-outbox/entity"
+	message_thread_service "github.com/Astervia/wacraft-server/src/message-thread/service"
+	phone_config_service "github.com/Astervia/wacraft-server/src/phone-config/service"
+	wa_common "github.com/Rfluid/whatsapp-cloud-api/src/common"
+	message_service "github.com/Rfluid/whatsapp-cloud-api/src/message"
+	wh_model "github.com/Rfluid/whatsapp-cloud-api/src/webhook"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrOutboxMessageNotStored is returned when Meta accepted an outbox message but
+// its response could not be stored. The entry must not be sent again, otherwise
+// the contact would receive the message twice; mark it with MarkOutboxUnconfirmed
+// so only the response is stored again.
+var ErrOutboxMessageNotStored = errors.New("message accepted by Meta but its response could not be stored")
+
+// outboxWakeup nudges the local outbox worker when a message is enqueued so it
+// does not wait for the next poll. Other instances pick the entry up on their poll.
+var outboxWakeup = make(chan struct{}, 1)
+
+// OutboxWakeup returns the channel that receives a signal whenever a message is
+// enqueued on this instance.
+func OutboxWakeup() <-chan struct{} {
+	return outboxWakeup
+}
+
+func notifyOutbox() {
+	select {
+	case outboxWakeup <- struct{}{}:
+	default:
+	}
+}
+
+// EnqueueWhatsAppMessageByWorkspace stores the message and its outbox entry using the
+// sender chosen by ResolveMessageSender. Meta is contacted later by the outbox worker.
+func EnqueueWhatsAppMessageByWorkspace(
+	body message_model.SendWhatsAppMessage,
+	workspaceID uuid.UUID,
+	selection phone_config_service.SenderSelection,
+) (message_entity.Message, error) {
+	if IsReaction(body) {
+		return message_entity.Message{}, ErrAsyncReaction
+	}
+
+	mp, err := ResolveMessageSender(&body, workspaceID, selection)
+	if err != nil {
+		return message_entity.Message{}, err
+	}
+
+	if err := CheckServiceWindow(body, workspaceID); err != nil {
+		return message_entity.Message{}, err
+	}
+
+	return EnqueueWhatsAppMessage(body, mp.ID, workspaceID)
+}
+
+// EnqueueWhatsAppMessage creates the message row and its outbox entry in a single
+// transaction and returns the message without waiting for Meta.
+func EnqueueWhatsAppMessage(
+	body message_model.SendWhatsAppMessage,
+	messagingProductID uuid.UUID,
+	workspaceID uuid.UUID,
+) (message_entity.Message, error) {
+	var message message_entity.Message
+	body.SenderData.SetDefault()
+	message.ToID = &body.ToID
+	message.MessagingProductID = messagingProductID
+
+	// Adding contact to message
+	contact := messaging_product_entity.MessagingProductContact{
+		Audit:              common_model.Audit{ID: body.ToID},
+		MessagingProductID: messagingProductID,
+	}
+	if err := database.DB.Model(&contact).Where(&contact).Joins("Contact").First(&contact).Error; err != nil {
+		return message, err
+	}
+	message.To = &contact
+
+	// Building message content
+	body.SenderData.To = contact.ProductDetails.PhoneNumber
+	message.SenderData = &message_model.SenderData{
+		Message: &body.SenderData,
+	}
+
+	tx := database.DB.Begin()
+	if tx.Error != nil {
+		return message, tx.Error
+	}
+
+	if err := tx.Create(&message).Error; err != nil {
+		tx.Rollback()
+		return message, err
+	}
+
+	if err := conversation_service.RecordMessageInConversation(message, tx); err != nil {
+		tx.Rollback()
+		return message, err
+	}
+
+	if err := message_thread_service.RecordMessageReplies(message, tx); err != nil {
+		tx.Rollback()
+		return message, err
+	}
+
+	entry := message_outbox_entity.MessageOutbox{
+		MessageID:          message.ID,
+		MessagingProductID: messagingProductID,
+		WorkspaceID:        workspaceID,
+		Status:             message_outbox_entity.OutboxPending,
+		MaxAttempts:        env.MessageOutboxMaxAttempts,
+		NextAttemptAt:      time.Now(),
+	}
+	if err := tx.Create(&entry).Error; err != nil {
+		tx.Rollback()
+		return message, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return message, err
+	}
+
+	notifyOutbox()
+
+	return message, nil
+}
+
+// GetDueOutboxEntries returns pending outbox entries whose next attempt is due.
+func GetDueOutboxEntries(limit int) ([]message_outbox_entity.MessageOutbox, error) {
+	var entries []message_outbox_entity.MessageOutbox
+	err := database.DB.
+		Where("status = ? AND next_attempt_at <= ?", message_outbox_entity.OutboxPending, time.Now()).
+		Order("next_attempt_at ASC").
+		Limit(limit).
+		Find(&entries).Error
+	return entries, err
+}
+
+// ClaimOutboxEntry atomically moves an entry from pending to processing.
+// Returns false when another worker claimed it first.
+func ClaimOutboxEntry(id uuid.UUID) (bool, error) {
+	result := database.DB.Model(&message_outbox_entity.MessageOutbox{}).
+		Where("id = ? AND status = ?", id, message_outbox_entity.OutboxPending).
+		Update("status", message_outbox_entity.OutboxProcessing)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// RecoverProcessingOutboxEntries puts entries left in processing by a crashed
+// instance back to pending. Only entries untouched for longer than staleAfter are
+// recovered so that entries being sent by other instances are left alone.
+// Returns the number of recovered entries.
+func RecoverProcessingOutboxEntries(staleAfter time.Duration) (int64, error) {
+	result := database.DB.Model(&message_outbox_entity.MessageOutbox{}).
+		Where("status = ? AND updated_at <= ?", message_outbox_entity.OutboxProcessing, time.Now().Add(-staleAfter)).
+		Update("status", message_outbox_entity.OutboxPending)
+	return result.RowsAffected, result.Error
+}
+
+// SendOutboxMessage calls the Graph API for a claimed outbox entry, stores Meta's
+// response on the message row and marks the entry as sent.
+//
+// If the send fails the entry is left untouched so the caller can record the
+// failure with UpdateOutboxStatus. If Meta accepted the message but the response
+// could not be stored, ErrOutboxMessageNotStored is returned.
+func SendOutboxMessage(
+	entry message_outbox_entity.MessageOutbox,
+) (message_entity.Message, error) {
+	var message message_entity.Message
+	if err := database.DB.Preload("To.Contact").First(&message, "id = ?", entry.MessageID).Error; err != nil {
+		return message, err
+	}
+	if message.SenderData == nil || message.SenderData.Message == nil {
+		return message, errors.New("outbox message has no sender data")
+	}
+
+	wabaApi, err := phone_config_service.GetWhatsAppAPIByMessagingProductID(entry.MessagingProductID)
+	if err != nil {
+		return message, err
+	}
+
+	// Sending message
+	response, err := message_service.Send(*wabaApi, *message.SenderData.Message)
+	if err != nil {
+		return message, err
+	}
+
+	message.ProductData = &message_model.ProductData{
+		Response: &response,
+	}
+	if len(message.ProductData.Messages) == 0 {
+		return message, errors.New("no message id returned by Meta")
+	}
+	wamID := message.ProductData.Messages[0].ID.ID
+
+	// A status may arrive before the row holds the wamid; AddStatus waits for MessageSaved.
+	addMessageCh := make(chan error)
+	go func() {
+		addMessageCh <- StatusSynchronizer.AddMessage(wamID, env.MessageStatusSyncTimeout)
+	}()
+
+	now := time.Now()
+	stored, err := storeOutboxResponse(message, entry.ID, message_outbox_entity.OutboxProcessing, map[string]any{
+		"status":          message_outbox_entity.OutboxSent,
+		"attempt_count":   entry.AttemptCount + 1,
+		"last_attempt_at": now,
+		"sent_at":         now,
+		"last_error":      "",
+	})
+	if err == nil && !stored {
+		err = errors.New("outbox entry left processing while it was sent")
+	}
+	if err != nil {
+		go func() {
+			if <-addMessageCh != nil {
+				return
+			}
+			StatusSynchronizer.RollbackMessage(wamID, env.MessageStatusSyncTimeout)
+		}()
+		return message, errors.Join(ErrOutboxMessageNotStored, err)
+	}
+
+	go func() {
+		if <-addMessageCh != nil {
+			return
+		}
+		StatusSynchronizer.MessageSaved(wamID, message.ID, env.MessageStatusSyncTimeout)
+	}()
+
+	return message, nil
+}
+
+// storeOutboxResponse stores Meta's response on the message row, links the
+// replies waiting for its wamid and updates the entry in a single transaction.
+// The entry is only updated while it is in the from status; false is returned
+// when another worker moved it first.
+func storeOutboxResponse(message message_entity.Message, entryID uuid.UUID, from message_outbox_entity.OutboxStatus, updates map[string]any) (bool, error) {
+	stored := false
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&message_outbox_entity.MessageOutbox{}).
+			Where("id = ? AND status = ?", entryID, from).
+			Updates(updates)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		err := tx.Model(&message_entity.Message{}).
+			Where("id = ?", message.ID).
+			Update("product_data", message.ProductData).Error
+		if err != nil {
+			return err
+		}
+		// The wamid is known now; replies waiting for it can be linked.
+		if err := message_thread_service.RecordMessageReplies(message, tx); err != nil {
+			return err
+		}
+		stored = true
+		return nil
+	})
+	return stored, err
+}
+
+// MarkOutboxUnconfirmed records that Meta accepted the message of a claimed
+// entry but its response could not be stored. The response is kept on the
+// entry so ConfirmOutboxEntry can store it later without sending again.
+func MarkOutboxUnconfirmed(entry *message_outbox_entity.MessageOutbox, response message_service.Response, errMsg string) error {
+	now := time.Now()
+	entry.Status = message_outbox_entity.OutboxUnconfirmed
+	entry.AttemptCount++
+	entry.LastAttemptAt = &now
+	entry.LastError = errMsg
+	entry.Response = &response
+
+	// A pending entry was recovered while it was sent; it must not be sent again either.
+	return database.DB.Model(entry).
+		Where("status IN ?", []message_outbox_entity.OutboxStatus{message_outbox_entity.OutboxProcessing, message_outbox_entity.OutboxPending}).
+		Select("status", "attempt_count", "last_attempt_at", "last_error", "response").
+		Updates(entry).Error
+}
+
+// GetUnconfirmedOutboxEntries returns the unconfirmed entries, oldest first.
+func GetUnconfirmedOutboxEntries(limit int) ([]message_outbox_entity.MessageOutbox, error) {
+	var entries []message_outbox_entity.MessageOutbox
+	err := database.DB.
+		Where("status = ?", message_outbox_entity.OutboxUnconfirmed).
+		Order("updated_at ASC").
+		Limit(limit).
+		Find(&entries).Error
+	return entries, err
+}
+
+// ConfirmOutboxEntry stores the response kept on an unconfirmed entry on its
+// message row and marks the entry sent. Returns false when the entry has no
+// response or was confirmed by another worker first.
+func ConfirmOutboxEntry(entry message_outbox_entity.MessageOutbox) (message_entity.Message, bool, error) {
+	var message message_entity.Message
+	if entry.Response == nil {
+		return message, false, nil
+	}
+	if err := database.DB.Preload("To.Contact").First(&message, "id = ?", entry.MessageID).Error; err != nil {
+		return message, false, err
+	}
+	message.ProductData = &message_model.ProductData{
+		Response: entry.Response,
+	}
+
+	confirmed, err := storeOutboxResponse(message, entry.ID, message_outbox_entity.OutboxUnconfirmed, map[string]any{
+		"status":     message_outbox_entity.OutboxSent,
+		"sent_at":    entry.LastAttemptAt,
+		"last_error": "",
+		"response":   nil,
+	})
+	return message, confirmed, err
+}
+
+// UpdateOutboxStatus records a failed attempt. The entry is rescheduled with an
+// exponential backoff (capped at 5 minutes) unless it ran out of attempts.
+// Returns true when the entry reached the failed state.
+func UpdateOutboxStatus(entry *message_outbox_entity.MessageOutbox, errMsg string) (bool, error) {
+	now := time.Now()
+	entry.LastAttemptAt = &now
+	entry.AttemptCount++
+	entry.LastError = errMsg
+
+	if entry.AttemptCount >= entry.MaxAttempts {
+		entry.Status = message_outbox_entity.OutboxFailed
+	} else {
+		entry.Status = message_outbox_entity.OutboxPending
+		entry.NextAttemptAt = now.Add(outboxBackoff(entry.AttemptCount))
+	}
+
+	return entry.Status == message_outbox_entity.OutboxFailed, database.DB.Save(entry).Error
+}
+
+// outboxBackoff returns the delay before the next attempt: 1s * 2^(attempt-1), capped at 5 minutes.
+func outboxBackoff(attemptCount int) time.Duration {
+	maxDelay := 5 * time.Minute
+	if attemptCount < 1 {
+		return time.Second
+	}
+	if attemptCount > 20 {
+		return maxDelay
+	}
+	delay := time.Second * time.Duration(1<<uint(attemptCount-1))
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	return delay
+}
+
+// CreateOutboxFailedStatus stores a failed status for a message that never reached
+// Meta, so clients following the status WebSocket learn about the failure.
+func CreateOutboxFailedStatus(
+	message message_entity.Message,
+	errMsg string,
+) (status_entity.Status, error) {
+	failed := message_service.Failed
+	recipientID := ""
+	if message.SenderData != nil && message.SenderData.Message != nil {
+		recipientID = message.SenderData.Message.To
+	}
+
+	status := status_entity.Status{
+		StatusFields: status_model.StatusFields{
+			MessageID: message.ID,
+			ProductData: &status_model.ProductData{
+				Status: &wh_model.Status{
+					RecipientID: recipientID,
+					Status:      &failed,
+					Timestamp:   strconv.FormatInt(time.Now().Unix(), 10),
+					Errors: &[]wa_common.Error{
+						{Message: errMsg},
+					},
+				},
+			},
+		},
+	}
+
+	if err := database.DB.Create(&status).Error; err != nil {
+		return status, err
+	}
+
+	err := conversation_service.RecordStatusInConversation(message.ID, string(failed), time.Now(), nil)
+	return status, err
+}
@@ -0,0 +1,29 @@
+package message_service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOutboxBackoff_Exponential(t *testing.T) {
+	cases := map[int]time.Duration{
+		0: time.Second,
+		1: time.Second,
+		2: 2 * time.Second,
+		3: 4 * time.Second,
+		5: 16 * time.Second,
+	}
+	for attempt, want := range cases {
+		if got := outboxBackoff(attempt); got != want {
+			t.Errorf("attempt %d: expected %s, got %s", attempt, want, got)
+		}
+	}
+}
+
+func TestOutboxBackoff_Capped(t *testing.T) {
+	for _, attempt := range []int{10, 11, 30, 64} {
+		if got := outboxBackoff(attempt); got != 5*time.Minute {
+			t.Errorf("attempt %d: expected backoff capped at 5m, got %s", attempt, got)
+		}
+	}
+}
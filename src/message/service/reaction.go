@@ -0,0 +1,66 @@
+package message_service
+
+import (
+	"errors"
+	"time"
+
+	common_model "github.com/Astervia/wacraft-core/src/common/model"
+	message_model "github.com/Astervia/wacraft-core/src/message/model"
+	messaging_product_entity "github.com/Astervia/wacraft-core/src/messaging-product/entity"
+	"github.com/Astervia/wacraft-server/src/database"
+	message_reaction_model "github.com/Astervia/wacraft-server/src/message-reaction/model"
+	message_reaction_service "github.com/Astervia/wacraft-server/src/message-reaction/service"
+	phone_config_service "github.com/Astervia/wacraft-server/src/phone-config/service"
+	message_service "github.com/Rfluid/whatsapp-cloud-api/src/message"
+	"github.com/google/uuid"
+)
+
+// ErrAsyncReaction is returned when a reaction is enqueued. Reactions are not
+// stored as messages, so they can only be sent synchronously.
+var ErrAsyncReaction = errors.New("reactions must be sent through POST /message/whatsapp")
+
+// IsReaction reports whether body sends a reaction.
+func IsReaction(body message_model.SendWhatsAppMessage) bool {
+	return body.SenderData.Reaction != nil
+}
+
+// FindMessagingProductByWorkspaceAndSendReaction sends a reaction with the sender
+// chosen by ResolveMessageSender and records it on the reacted message. No
+// message row is stored for the reaction.
+//
+// Returns nil when the reaction was already the current one.
+func FindMessagingProductByWorkspaceAndSendReaction(
+	body message_model.SendWhatsAppMessage,
+	workspaceID uuid.UUID,
+	selection phone_config_service.SenderSelection,
+) (*message_reaction_model.ReactionEvent, error) {
+	mp, err := ResolveMessageSender(&body, workspaceID, selection)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := CheckServiceWindow(body, workspaceID); err != nil {
+		return nil, err
+	}
+
+	wabaApi, err := phone_config_service.GetWhatsAppAPIByPhoneConfigID(*mp.PhoneConfigID)
+	if err != nil {
+		return nil, err
+	}
+
+	contact := messaging_product_entity.MessagingProductContact{
+		Audit:              common_model.Audit{ID: body.ToID},
+		MessagingProductID: mp.ID,
+	}
+	if err := database.DB.Model(&contact).Where(&contact).Joins("Contact").First(&contact).Error; err != nil {
+		return nil, err
+	}
+
+	body.SenderData.SetDefault()
+	body.SenderData.To = contact.ProductDetails.PhoneNumber
+	if _, err := message_service.Send(*wabaApi, body.SenderData); err != nil {
+		return nil, err
+	}
+
+	return message_reaction_service.ApplyOutboundReaction(mp.ID, *body.SenderData.Reaction, time.Now(), nil)
+}
@@ -0,0 +1,271 @@
+package message_service
+
+import (
+	"errors"
+	"html"
+	"strings"
+	"unicode"
+
+	database_model "github.com/Astervia/wacraft-core/src/database/model"
+	message_entity "github.com/Astervia/wacraft-core/src/message/entity"
+	"github.com/Astervia/wacraft-server/src/database"
+	database_cursor "github.com/Astervia/wacraft-server/src/database/cursor"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Expression MUST match the generated column messages.search_vector
+// (migration 20261016000004_message_search_vector).
+const searchDocumentExpr = "concat_ws(' ', message_search_document(messages.sender_data), message_search_document(messages.receiver_data))"
+
+// searchConfig is the unaccent-aware text search configuration used by search_vector.
+const searchConfig = "'wacraft_unaccent'"
+
+// Matches are delimited with control characters in the headline, which are
+// stripped from the document first, so the customer text can be HTML escaped
+// before the delimiters become <mark> tags.
+const (
+	searchMatchStart = "\x02"
+	searchMatchStop  = "\x03"
+)
+
+// searchHeadlineOptions delimits the matches of the snippet.
+const searchHeadlineOptions = `StartSel="` + searchMatchStart + `", StopSel="` + searchMatchStop + `", MaxWords=30, MinWords=10, MaxFragments=2, FragmentDelimiter= … `
+
+// searchHeadlineDocumentExpr is the document of the snippet, without the
+// characters delimiting the matches.
+const searchHeadlineDocumentExpr = "translate(" + searchDocumentExpr + ", chr(2) || chr(3), '')"
+
+// highlightSnippet escapes the headline and wraps its matches in <mark> tags.
+func highlightSnippet(headline string) string {
+	return strings.NewReplacer(searchMatchStart, "<mark>", searchMatchStop, "</mark>").Replace(html.EscapeString(headline))
+}
+
+// ErrEmptySearch is returned when the search text has no searchable word.
+var ErrEmptySearch = errors.New("search text has no searchable words")
+
+// SearchDirection restricts the search to received or sent messages.
+type SearchDirection string
+
+const (
+	SearchInbound  SearchDirection = "inbound"  // Messages sent by contacts.
+	SearchOutbound SearchDirection = "outbound" // Messages sent to contacts.
+)
+
+// SearchQuery holds the full-text search parameters.
+//
+// Text accepts plain terms (all must match), "quoted phrases" and prefixes ending
+// with * (e.g. "deliv*").
+type SearchQuery struct {
+	Text                      string          `json:"q" query:"q" validate:"required"`
+	Direction                 SearchDirection `json:"direction,omitempty" query:"direction" validate:"omitempty,oneof=inbound outbound"`
+	MessagingProductContactID *uuid.UUID      `json:"messaging_product_contact_id,omitempty" query:"messaging_product_contact_id"`
+	MessagingProductID        *uuid.UUID      `json:"messaging_product_id,omitempty" query:"messaging_product_id"`
+
+	database_model.Paginate
+	database_model.DateWhere
+}
+
+// SearchResult is a message matched by Search with its relevance and highlighted snippet.
+type SearchResult struct {
+	Message message_entity.Message `json:"message"`
+	Rank    float64                `json:"rank"`
+	// Snippet is HTML: the message text is escaped and the matches are
+	// wrapped in <mark> tags.
+	Snippet string `json:"snippet"`
+}
+
+// SearchTermKind tells how a search term is matched.
+type SearchTermKind int
+
+const (
+	SearchWord SearchTermKind = iota
+	SearchPhrase
+	SearchPrefix
+)
+
+// SearchTerm is a single piece of the search text.
+type SearchTerm struct {
+	Kind SearchTermKind
+	Text string
+}
+
+// ParseSearchText splits the search text into words, "quoted phrases" and prefixes.
+// An unterminated quote extends the phrase to the end of the text.
+func ParseSearchText(text string) []SearchTerm {
+	var terms []SearchTerm
+
+	for {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return terms
+		}
+
+		if text[0] == '"' {
+			text = text[1:]
+			end := strings.IndexByte(text, '"')
+			if end < 0 {
+				end = len(text)
+			}
+			if phrase := strings.TrimSpace(text[:end]); phrase != "" {
+				terms = append(terms, SearchTerm{Kind: SearchPhrase, Text: phrase})
+			}
+			text = text[min(end+1, len(text)):]
+			continue
+		}
+
+		end := strings.IndexFunc(text, func(r rune) bool { return unicode.IsSpace(r) || r == '"' })
+		if end < 0 {
+			end = len(text)
+		}
+		word := text[:end]
+		text = text[end:]
+
+		if prefix, ok := strings.CutSuffix(word, "*"); ok {
+			if prefix = strings.TrimRight(prefix, "*"); prefix != "" {
+				terms = append(terms, SearchTerm{Kind: SearchPrefix, Text: prefix})
+			}
+			continue
+		}
+		terms = append(terms, SearchTerm{Kind: SearchWord, Text: word})
+	}
+}
+
+// prefixTsQuery turns a prefix into to_tsquery input. Only letters and digits are
+// kept so user input cannot inject tsquery operators; the last lexeme gets :*.
+func prefixTsQuery(prefix string) string {
+	lexemes := strings.FieldsFunc(prefix, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(lexemes) == 0 {
+		return ""
+	}
+	return strings.Join(lexemes, " & ") + ":*"
+}
+
+// tsQuery builds the SQL expression (and its args) matching every term.
+func tsQuery(terms []SearchTerm) (string, []any, error) {
+	var parts []string
+	var args []any
+
+	for _, term := range terms {
+		switch term.Kind {
+		case SearchPhrase:
+			parts = append(parts, "phraseto_tsquery("+searchConfig+", ?)")
+			args = append(args, term.Text)
+		case SearchPrefix:
+			input := prefixTsQuery(term.Text)
+			if input == "" {
+				continue
+			}
+			parts = append(parts, "to_tsquery("+searchConfig+", ?)")
+			args = append(args, input)
+		default:
+			parts = append(parts, "plainto_tsquery("+searchConfig+", ?)")
+			args = append(args, term.Text)
+		}
+	}
+
+	if len(parts) == 0 {
+		return "", nil, ErrEmptySearch
+	}
+
+	return "(" + strings.Join(parts, " && ") + ")", args, nil
+}
+
+// Search runs a ranked full-text search over messages.search_vector.
+//
+// Results are ordered by ts_rank_cd and then by recency. db may carry extra
+// joins and conditions, such as the workspace scope.
+func Search(query SearchQuery, db *gorm.DB) ([]SearchResult, error) {
+	tsq, args, err := tsQuery(ParseSearchText(query.Text))
+	if err != nil {
+		return nil, err
+	}
+
+	if db == nil {
+		db = database.DB
+	}
+
+	// Rank and paginate first so the costly headline is only built for the returned page.
+	hitsQuery := db.Model(&message_entity.Message{}).
+		Select("messages.id, messages.created_at, ts_rank_cd(messages.search_vector, "+tsq+") AS rank", args...).
+		Where("messages.search_vector @@ "+tsq, args...)
+
+	switch query.Direction {
+	case SearchInbound:
+		hitsQuery = hitsQuery.Where("messages.from_id IS NOT NULL")
+	case SearchOutbound:
+		hitsQuery = hitsQuery.Where("messages.to_id IS NOT NULL")
+	}
+	if query.MessagingProductContactID != nil {
+		hitsQuery = hitsQuery.Where(
+			"(messages.from_id = ? OR messages.to_id = ?)",
+			*query.MessagingProductContactID, *query.MessagingProductContactID,
+		)
+	}
+	if query.MessagingProductID != nil {
+		hitsQuery = hitsQuery.Where("messages.messaging_product_id = ?", *query.MessagingProductID)
+	}
+	query.DateWhere.Where(&hitsQuery, `"messages"`)
+
+	hitsQuery = hitsQuery.
+		Order("rank DESC, messages.created_at DESC, messages.id DESC").
+		Offset(query.Offset).
+		Limit(database_cursor.Limit(query.Limit))
+
+	var hits []struct {
+		ID      uuid.UUID
+		Rank    float64
+		Snippet string
+	}
+	headlineArgs := append(append([]any{}, args...), searchHeadlineOptions)
+	err = database.DB.
+		Table("(?) AS hits", hitsQuery).
+		Joins("JOIN messages ON messages.id = hits.id").
+		Select(
+			"hits.id, hits.rank, ts_headline("+searchConfig+", "+searchHeadlineDocumentExpr+", "+tsq+", ?) AS snippet",
+			headlineArgs...,
+		).
+		Order("hits.rank DESC, hits.created_at DESC, hits.id DESC").
+		Scan(&hits).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(hits) == 0 {
+		return []SearchResult{}, nil
+	}
+
+	ids := make([]uuid.UUID, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.ID
+	}
+
+	var messages []message_entity.Message
+	err = database.DB.Model(&message_entity.Message{}).
+		Joins("From").
+		Joins("To").
+		Joins("From.Contact").
+		Joins("To.Contact").
+		Where("messages.id IN ?", ids).
+		Find(&messages).Error
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]message_entity.Message, len(messages))
+	for _, message := range messages {
+		byID[message.ID] = message
+	}
+
+	results := make([]SearchResult, 0, len(hits))
+	for _, hit := range hits {
+		message, ok := byID[hit.ID]
+		if !ok {
+			continue
+		}
+		results = append(results, SearchResult{Message: message, Rank: hit.Rank, Snippet: highlightSnippet(hit.Snippet)})
+	}
+
+	return results, nil
+}
@@ -0,0 +1,80 @@
+package message_service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseSearchText_WordsPhrasesAndPrefixes(t *testing.T) {
+	got := ParseSearchText(`  order "not delivered" deliv*  café `)
+	want := []SearchTerm{
+		{Kind: SearchWord, Text: "order"},
+		{Kind: SearchPhrase, Text: "not delivered"},
+		{Kind: SearchPrefix, Text: "deliv"},
+		{Kind: SearchWord, Text: "café"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestParseSearchText_UnterminatedQuote(t *testing.T) {
+	got := ParseSearchText(`refund "still waiting`)
+	want := []SearchTerm{
+		{Kind: SearchWord, Text: "refund"},
+		{Kind: SearchPhrase, Text: "still waiting"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestPrefixTsQuery_StripsOperators(t *testing.T) {
+	cases := map[string]string{
+		"deliv":       "deliv:*",
+		"e-mai":       "e & mai:*",
+		"a|b&!c":      "a & b & c:*",
+		"':*()":       "",
+		"atenção":     "atenção:*",
+		"order123":    "order123:*",
+		"foo<->bar":   "foo & bar:*",
+		"  spaced  x": "spaced & x:*",
+	}
+	for input, want := range cases {
+		if got := prefixTsQuery(input); got != want {
+			t.Errorf("%q: expected %q, got %q", input, want, got)
+		}
+	}
+}
+
+func TestTsQuery_CombinesTerms(t *testing.T) {
+	expr, args, err := tsQuery(ParseSearchText(`hello "good morning" wor*`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wantExpr := "(plainto_tsquery('wacraft_unaccent', ?) && phraseto_tsquery('wacraft_unaccent', ?) && to_tsquery('wacraft_unaccent', ?))"
+	if expr != wantExpr {
+		t.Errorf("expected %q, got %q", wantExpr, expr)
+	}
+	wantArgs := []any{"hello", "good morning", "wor:*"}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Errorf("expected args %v, got %v", wantArgs, args)
+	}
+}
+
+func TestTsQuery_Empty(t *testing.T) {
+	for _, text := range []string{"", "   ", `""`, "*", "-*"} {
+		if _, _, err := tsQuery(ParseSearchText(text)); !errors.Is(err, ErrEmptySearch) {
+			t.Errorf("%q: expected ErrEmptySearch, got %v", text, err)
+		}
+	}
+}
+
+func TestHighlightSnippet_EscapesTheMessageText(t *testing.T) {
+	headline := `<img src=x onerror="alert(1)"> ` + searchMatchStart + "refund" + searchMatchStop + " & more"
+	want := `&lt;img src=x onerror=&#34;alert(1)&#34;&gt; <mark>refund</mark> &amp; more`
+	if got := highlightSnippet(headline); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,123 @@
+package message_service
+
+import (
+	message_entity "github.com/Astervia/wacraft-core/src/message/entity"
+	message_model "github.com/Astervia/wacraft-core/src/message/model"
+	messaging_product_entity "github.com/Astervia/wacraft-core/src/messaging-product/entity"
+	"github.com/Astervia/wacraft-server/src/database"
+	messaging_product_service "github.com/Astervia/wacraft-server/src/messaging-product/service"
+	phone_config_service "github.com/Astervia/wacraft-server/src/phone-config/service"
+	workspace_setting_service "github.com/Astervia/wacraft-server/src/workspace-setting/service"
+	"github.com/google/uuid"
+)
+
+// ResolveMessageSender picks the messaging product (phone number) used to send body and
+// points body.ToID at the contact of that messaging product.
+//
+// Resolution order: the explicit selection, the number the contact last wrote to,
+// the workspace default phone config and finally the messaging product of the contact.
+func ResolveMessageSender(
+	body *message_model.SendWhatsAppMessage,
+	workspaceID uuid.UUID,
+	selection phone_config_service.SenderSelection,
+) (messaging_product_entity.MessagingProduct, error) {
+	var contact messaging_product_entity.MessagingProductContact
+	if err := database.DB.
+		Joins("JOIN messaging_products ON messaging_product_contacts.messaging_product_id = messaging_products.id AND messaging_products.workspace_id = ?", workspaceID).
+		Where("messaging_product_contacts.id = ?", body.ToID).
+		First(&contact).Error; err != nil {
+		return messaging_product_entity.MessagingProduct{}, err
+	}
+
+	if selection.IsEmpty() {
+		var err error
+		selection, err = defaultMessageSender(workspaceID, contact)
+		if err != nil {
+			return messaging_product_entity.MessagingProduct{}, err
+		}
+	}
+
+	mp, err := phone_config_service.FindWorkspaceSender(workspaceID, selection)
+	if err != nil {
+		return mp, err
+	}
+
+	if mp.ID != contact.MessagingProductID {
+		contact, err = messaging_product_service.GetContactForMessagingProduct(contact, mp.ID, nil)
+		if err != nil {
+			return mp, err
+		}
+		body.ToID = contact.ID
+	}
+
+	return mp, nil
+}
+
+// defaultMessageSender returns the sender used when the request did not select one.
+func defaultMessageSender(
+	workspaceID uuid.UUID,
+	contact messaging_product_entity.MessagingProductContact,
+) (phone_config_service.SenderSelection, error) {
+	lastInbound, err := lastInboundMessagingProductID(workspaceID, contact)
+	if err != nil {
+		return phone_config_service.SenderSelection{}, err
+	}
+	if lastInbound != nil {
+		return chooseMessageSender(lastInbound, nil, contact.MessagingProductID), nil
+	}
+
+	setting, err := workspace_setting_service.GetWorkspaceSetting(workspaceID, nil)
+	if err != nil {
+		return phone_config_service.SenderSelection{}, err
+	}
+	return chooseMessageSender(nil, setting.DefaultPhoneConfigID, contact.MessagingProductID), nil
+}
+
+// chooseMessageSender picks, in order, the messaging product the contact last wrote
+// to, the workspace default phone config and the messaging product of the contact.
+func chooseMessageSender(
+	lastInbound *uuid.UUID,
+	defaultPhoneConfigID *uuid.UUID,
+	contactMessagingProductID uuid.UUID,
+) phone_config_service.SenderSelection {
+	if lastInbound != nil {
+		return phone_config_service.SenderSelection{MessagingProductID: lastInbound}
+	}
+	if defaultPhoneConfigID != nil {
+		return phone_config_service.SenderSelection{PhoneConfigID: defaultPhoneConfigID}
+	}
+	return phone_config_service.SenderSelection{MessagingProductID: &contactMessagingProductID}
+}
+
+// lastInboundMessagingProductID returns the messaging product that last received a message
+// from the person behind contact, on any number of the workspace. Returns nil if none did.
+func lastInboundMessagingProductID(
+	workspaceID uuid.UUID,
+	contact messaging_product_entity.MessagingProductContact,
+) (*uuid.UUID, error) {
+	db := database.DB.Model(&message_entity.Message{}).
+		Joins("JOIN messaging_product_contacts ON messages.from_id = messaging_product_contacts.id").
+		Joins("JOIN messaging_products ON messages.messaging_product_id = messaging_products.id AND messaging_products.workspace_id = ?", workspaceID)
+
+	if contact.ProductDetails != nil && contact.ProductDetails.WhatsAppProductDetails != nil && contact.ProductDetails.WaID != "" {
+		db = db.Where(
+			"messaging_product_contacts.contact_id = ? OR messaging_product_contacts.product_details->>'wa_id' = ?",
+			contact.ContactID, contact.ProductDetails.WaID,
+		)
+	} else {
+		db = db.Where("messaging_product_contacts.contact_id = ?", contact.ContactID)
+	}
+
+	var mpIDs []uuid.UUID
+	if err := db.
+		Order("messages.created_at DESC").
+		Limit(1).
+		Pluck("messages.messaging_product_id", &mpIDs).Error; err != nil {
+		return nil, err
+	}
+	if len(mpIDs) == 0 {
+		return nil, nil
+	}
+
+	return &mpIDs[0], nil
+}
@@ -0,0 +1,44 @@
+package message_service
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestChooseMessageSender_LastInboundWins(t *testing.T) {
+	lastInbound := uuid.New()
+	defaultPhoneConfig := uuid.New()
+
+	selection := chooseMessageSender(&lastInbound, &defaultPhoneConfig, uuid.New())
+	if selection.MessagingProductID == nil || *selection.MessagingProductID != lastInbound {
+		t.Errorf("expected the messaging product the contact last wrote to, got %+v", selection)
+	}
+	if selection.PhoneConfigID != nil {
+		t.Errorf("expected no phone config, got %s", *selection.PhoneConfigID)
+	}
+}
+
+func TestChooseMessageSender_FallsBackToWorkspaceDefault(t *testing.T) {
+	defaultPhoneConfig := uuid.New()
+
+	selection := chooseMessageSender(nil, &defaultPhoneConfig, uuid.New())
+	if selection.PhoneConfigID == nil || *selection.PhoneConfigID != defaultPhoneConfig {
+		t.Errorf("expected the workspace default phone config, got %+v", selection)
+	}
+	if selection.MessagingProductID != nil {
+		t.Errorf("expected no messaging product, got %s", *selection.MessagingProductID)
+	}
+}
+
+func TestChooseMessageSender_FallsBackToContactMessagingProduct(t *testing.T) {
+	contactMP := uuid.New()
+
+	selection := chooseMessageSender(nil, nil, contactMP)
+	if selection.MessagingProductID == nil || *selection.MessagingProductID != contactMP {
+		t.Errorf("expected the messaging product of the contact, got %+v", selection)
+	}
+	if selection.IsEmpty() {
+		t.Error("expected a selection")
+	}
+}
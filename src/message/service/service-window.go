@@ -0,0 +1,31 @@
+package message_service
+
+import (
+	message_model "github.com/Astervia/wacraft-core/src/message/model"
+	messaging_product_service "github.com/Astervia/wacraft-server/src/messaging-product/service"
+	workspace_setting_service "github.com/Astervia/wacraft-server/src/workspace-setting/service"
+	"github.com/Rfluid/whatsapp-cloud-api/src/message/content"
+	"github.com/google/uuid"
+)
+
+// CheckServiceWindow rejects free-form messages to contacts outside the 24-hour customer
+// service window with a *messaging_product_service.ServiceWindowClosedError.
+// Templates are always allowed and workspaces can disable the check in their settings.
+func CheckServiceWindow(
+	body message_model.SendWhatsAppMessage,
+	workspaceID uuid.UUID,
+) error {
+	if body.SenderData.Type == content.Template {
+		return nil
+	}
+
+	setting, err := workspace_setting_service.GetWorkspaceSetting(workspaceID, nil)
+	if err != nil {
+		return err
+	}
+	if setting.DisableServiceWindowCheck {
+		return nil
+	}
+
+	return messaging_product_service.EnsureServiceWindowOpen(body.ToID, nil)
+}
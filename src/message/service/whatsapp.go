@@ -2,16 +2,16 @@ package message_service
 
 import (
 	"errors"
-	"fmt"
 
 	common_model "github.com/Astervia/wacraft-core/src/common/model"
 	message_entity "github.com/Astervia/wacraft-core/src/message/entity"
 	message_model "github.com/Astervia/wacraft-core/src/message/model"
 	messaging_product_entity "github.com/Astervia/wacraft-core/src/messaging-product/entity"
-	messaging_product_model "github.com/Astervia/wacraft-core/src/messaging-product/model"
 	common_service "github.com/Astervia/wacraft-server/src/common/service"
 	"github.com/Astervia/wacraft-server/src/config/env"
+	conversation_service "github.com/Astervia/wacraft-server/src/conversation/service"
 	"github.com/Astervia/wacraft-server/src/database"
+	message_thread_service "github.com/Astervia/wacraft-server/src/message-thread/service"
 	phone_config_service "github.com/Astervia/wacraft-server/src/phone-config/service"
 	bootstrap_module "github.com/Rfluid/whatsapp-cloud-api/src/bootstrap"
 	message_service "github.com/Rfluid/whatsapp-cloud-api/src/message"
@@ -20,23 +20,20 @@ import (
 )
 
 // FindMessagingProductByWorkspaceAndSendMessage finds the messaging product for a workspace and sends a message.
-// Uses the PhoneConfig associated with the messaging product.
+// Uses the PhoneConfig associated with the messaging product chosen by ResolveMessageSender.
 func FindMessagingProductByWorkspaceAndSendMessage(
 	body message_model.SendWhatsAppMessage,
 	workspaceID uuid.UUID,
+	selection phone_config_service.SenderSelection,
 	propagateCallback func(message_entity.Message),
 ) (message_entity.Message, error) {
-	mp := messaging_product_entity.MessagingProduct{
-		Name:        messaging_product_model.WhatsApp,
-		WorkspaceID: &workspaceID,
-	}
-	err := database.DB.Model(&mp).Where(&mp).First(&mp).Error
+	mp, err := ResolveMessageSender(&body, workspaceID, selection)
 	if err != nil {
-		return message_entity.Message{}, fmt.Errorf("no messaging product found for workspace: %w", err)
+		return message_entity.Message{}, err
 	}
 
-	if mp.PhoneConfigID == nil {
-		return message_entity.Message{}, errors.New("messaging product has no phone config configured")
+	if err := CheckServiceWindow(body, workspaceID); err != nil {
+		return message_entity.Message{}, err
 	}
 
 	// Get WhatsApp API from phone config
@@ -117,6 +114,12 @@ func SendWhatsAppMessageWithAPI(
 
 	// Creating message at database
 	err = tx.Create(&message).Error
+	if err == nil {
+		err = conversation_service.RecordMessageInConversation(message, tx)
+	}
+	if err == nil {
+		err = message_thread_service.RecordMessageReplies(message, tx)
+	}
 	if err != nil {
 		StatusSynchronizer.RollbackMessage(
 			message.ProductData.Messages[0].ID.ID,
@@ -195,6 +198,12 @@ func SendWhatsAppMessageWithAPIWithoutWaitingForStatus(
 
 	// Creating message at database
 	err = tx.Create(&message).Error
+	if err == nil {
+		err = conversation_service.RecordMessageInConversation(message, tx)
+	}
+	if err == nil {
+		err = message_thread_service.RecordMessageReplies(message, tx)
+	}
 	if err != nil {
 		go func() {
 			if <-addMessageCh != nil {
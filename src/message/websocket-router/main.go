@@ -14,4 +14,9 @@ func Route(app fiber.Router) {
 		"/new",
 		websocket.New(message_handler.NewMessageSubscription),
 	)
+
+	group.Get(
+		"/reaction",
+		websocket.New(message_handler.MessageReactionSubscription),
+	)
 }
@@ -0,0 +1,280 @@
+package message_worker
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	message_entity "github.com/Astervia/wacraft-core/src/message/entity"
+	synch_contract "github.com/Astervia/wacraft-core/src/synch/contract"
+	webhook_entity "github.com/Astervia/wacraft-core/src/webhook/entity"
+	webhook_model "github.com/Astervia/wacraft-core/src/webhook/model"
+	"github.com/Astervia/wacraft-server/src/config/env"
+	message_outbox_entity "github.com/Astervia/wacraft-server/src/message-outbox/entity"
+	message_thread_service "github.com/Astervia/wacraft-server/src/message-thread/service"
+	message_handler "github.com/Astervia/wacraft-server/src/message/handler"
+	message_service "github.com/Astervia/wacraft-server/src/message/service"
+	status_handler "github.com/Astervia/wacraft-server/src/status/handler"
+	webhook_service "github.com/Astervia/wacraft-server/src/webhook/service"
+	"github.com/pterm/pterm"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// OutboxPoolSize is the max number of outbox entries sent concurrently.
+	OutboxPoolSize = 10
+	// OutboxBatchSize is the max number of outbox entries fetched per poll.
+	OutboxBatchSize = 50
+	// OutboxStaleAfter is how long an entry may stay in processing before it is
+	// considered abandoned by a crashed instance and put back to pending.
+	OutboxStaleAfter = 5 * time.Minute
+	// OutboxRecoveryInterval is how often abandoned entries are put back to
+	// pending and the responses of unconfirmed entries are stored again.
+	OutboxRecoveryInterval = time.Minute
+)
+
+// OutboxWorker sends messages accepted through the asynchronous send endpoint.
+type OutboxWorker struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	// lock guards per-entry execution across instances.
+	// nil in memory mode (single instance — no distributed contention).
+	lock synch_contract.DistributedLock[string]
+}
+
+// outboxLock is the package-level lock set during init from src/synch/main.go.
+var outboxLock synch_contract.DistributedLock[string]
+
+// SetOutboxLock sets the distributed lock used by new OutboxWorker instances.
+// Called from src/synch/main.go when SYNC_BACKEND=redis.
+func SetOutboxLock(l synch_contract.DistributedLock[string]) {
+	outboxLock = l
+}
+
+// NewOutboxWorker creates a new outbox worker, picking up the package-level lock.
+func NewOutboxWorker() *OutboxWorker {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &OutboxWorker{
+		ctx:    ctx,
+		cancel: cancel,
+		lock:   outboxLock,
+	}
+}
+
+// Start recovers abandoned entries and begins the polling loop.
+func (w *OutboxWorker) Start() {
+	w.recoverProcessingEntries()
+	w.confirmUnconfirmedEntries()
+
+	w.wg.Add(1)
+	go w.runRecovery()
+
+	w.wg.Add(1)
+	go w.run()
+	pterm.DefaultLogger.Info("Message outbox worker started")
+}
+
+// Stop gracefully stops the outbox worker.
+func (w *OutboxWorker) Stop() {
+	pterm.DefaultLogger.Info("Stopping message outbox worker...")
+	w.cancel()
+	w.wg.Wait()
+	pterm.DefaultLogger.Info("Message outbox worker stopped")
+}
+
+// runRecovery periodically recovers the entries of crashed instances, which
+// would otherwise wait for an instance to restart, and confirms unconfirmed entries.
+func (w *OutboxWorker) runRecovery() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(OutboxRecoveryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.ctx.Done():
+			return
+		case <-ticker.C:
+			w.recoverProcessingEntries()
+			w.confirmUnconfirmedEntries()
+		}
+	}
+}
+
+// recoverProcessingEntries resets entries left in processing by a crashed instance.
+func (w *OutboxWorker) recoverProcessingEntries() {
+	recovered, err := message_service.RecoverProcessingOutboxEntries(OutboxStaleAfter)
+	if err != nil {
+		pterm.DefaultLogger.Error("Message outbox: failed to recover processing entries: " + err.Error())
+		return
+	}
+	if recovered > 0 {
+		pterm.DefaultLogger.Warn(
+			"Message outbox: reset " + strconv.FormatInt(recovered, 10) + " stale processing outbox entries to pending",
+		)
+	}
+}
+
+// confirmUnconfirmedEntries stores again the responses of the entries Meta
+// accepted while the database was failing, and propagates them as sent.
+func (w *OutboxWorker) confirmUnconfirmedEntries() {
+	entries, err := message_service.GetUnconfirmedOutboxEntries(OutboxBatchSize)
+	if err != nil {
+		pterm.DefaultLogger.Error("Message outbox: failed to fetch unconfirmed entries: " + err.Error())
+		return
+	}
+
+	for i := range entries {
+		entry := &entries[i]
+		message, confirmed, err := message_service.ConfirmOutboxEntry(*entry)
+		if err != nil {
+			pterm.DefaultLogger.Error("Message outbox: failed to confirm " + entry.ID.String() + ": " + err.Error())
+			continue
+		}
+		if confirmed {
+			w.propagateSent(entry, message)
+		}
+	}
+}
+
+// run is the main polling loop. Besides the ticker it wakes up whenever a
+// message is enqueued on this instance.
+func (w *OutboxWorker) run() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(env.MessageOutboxPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.ctx.Done():
+			return
+		case <-ticker.C:
+			w.processDueEntries()
+		case <-message_service.OutboxWakeup():
+			w.processDueEntries()
+		}
+	}
+}
+
+// processDueEntries fetches due outbox entries and sends them.
+func (w *OutboxWorker) processDueEntries() {
+	entries, err := message_service.GetDueOutboxEntries(OutboxBatchSize)
+	if err != nil {
+		pterm.DefaultLogger.Error("Message outbox: failed to fetch due entries: " + err.Error())
+		return
+	}
+
+	if len(entries) == 0 {
+		return
+	}
+
+	g, ctx := errgroup.WithContext(w.ctx)
+	g.SetLimit(OutboxPoolSize)
+
+	for i := range entries {
+		entry := &entries[i]
+		g.Go(func() error {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			default:
+				w.processEntry(entry)
+				return nil
+			}
+		})
+	}
+
+	if err := g.Wait(); err != nil && err != context.Canceled {
+		pterm.DefaultLogger.Error("Message outbox: error processing entries: " + err.Error())
+	}
+}
+
+// processEntry sends a single outbox entry and propagates the result.
+func (w *OutboxWorker) processEntry(entry *message_outbox_entity.MessageOutbox) {
+	entryID := entry.ID.String()
+
+	// Acquire distributed lock (Redis mode) to prevent duplicate sends.
+	lockKey := "message_outbox:" + entryID
+	if w.lock != nil {
+		acquired, err := w.lock.TryLock(lockKey)
+		if err != nil {
+			pterm.DefaultLogger.Error("Message outbox: lock error for " + entryID + ": " + err.Error())
+			return
+		}
+		if !acquired {
+			return // Another instance is already processing this entry.
+		}
+		defer w.lock.Unlock(lockKey) //nolint:errcheck
+	}
+
+	claimed, err := message_service.ClaimOutboxEntry(entry.ID)
+	if err != nil {
+		pterm.DefaultLogger.Error("Message outbox: failed to claim " + entryID + ": " + err.Error())
+		return
+	}
+	if !claimed {
+		return // Already claimed by another instance.
+	}
+
+	message, err := message_service.SendOutboxMessage(*entry)
+	if err == nil {
+		w.propagateSent(entry, message)
+		return
+	}
+
+	if errors.Is(err, message_service.ErrOutboxMessageNotStored) {
+		// Meta accepted the message: it is neither sent again nor reported
+		// failed, only its response is stored again.
+		pterm.DefaultLogger.Warn("Message outbox: " + entryID + " is unconfirmed: " + err.Error())
+		if updateErr := message_service.MarkOutboxUnconfirmed(entry, *message.ProductData.Response, err.Error()); updateErr != nil {
+			pterm.DefaultLogger.Error("Message outbox: failed to mark " + entryID + " unconfirmed: " + updateErr.Error())
+		}
+		return
+	}
+
+	failed, updateErr := message_service.UpdateOutboxStatus(entry, err.Error())
+	if updateErr != nil {
+		pterm.DefaultLogger.Error("Message outbox: failed to update " + entryID + ": " + updateErr.Error())
+		return
+	}
+	if !failed {
+		pterm.DefaultLogger.Warn("Message outbox: attempt " + strconv.Itoa(entry.AttemptCount) + " for " + entryID + " failed: " + err.Error())
+		return
+	}
+
+	pterm.DefaultLogger.Error("Message outbox: giving up on " + entryID + ": " + err.Error())
+	w.propagateFailed(entry, message, err.Error())
+}
+
+// propagateSent broadcasts the sent message exactly like the synchronous send endpoint.
+func (w *OutboxWorker) propagateSent(entry *message_outbox_entity.MessageOutbox, message message_entity.Message) {
+	payload := message_thread_service.Threaded(message)
+	go message_handler.NewMessageWorkspaceManager.BroadcastToWorkspace(entry.WorkspaceID, payload)
+	go webhook_service.SendAllByQuery(
+		webhook_entity.Webhook{
+			Event:       webhook_model.SendWhatsAppMessage,
+			WorkspaceID: &entry.WorkspaceID,
+		},
+		payload,
+	)
+}
+
+// propagateFailed stores a failed status for the message and broadcasts it on
+// the status WebSocket, which is how clients follow asynchronous sends.
+func (w *OutboxWorker) propagateFailed(entry *message_outbox_entity.MessageOutbox, message message_entity.Message, errMsg string) {
+	// The message may not have been loaded when the failure happened.
+	message.ID = entry.MessageID
+
+	status, err := message_service.CreateOutboxFailedStatus(message, errMsg)
+	if err != nil {
+		pterm.DefaultLogger.Error("Message outbox: failed to store failed status for " + entry.MessageID.String() + ": " + err.Error())
+		return
+	}
+
+	go status_handler.NewStatusWorkspaceManager.BroadcastToWorkspace(entry.WorkspaceID, status)
+	go webhook_service.SendStatusEvent(&entry.WorkspaceID, status)
+}
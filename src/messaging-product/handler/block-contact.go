@@ -6,6 +6,8 @@ import (
 	"github.com/Astervia/wacraft-core/src/repository"
 	"github.com/Astervia/wacraft-server/src/database"
 	"github.com/Astervia/wacraft-server/src/validators"
+	webhook_event_model "github.com/Astervia/wacraft-server/src/webhook-event/model"
+	webhook_service "github.com/Astervia/wacraft-server/src/webhook/service"
 	workspace_middleware "github.com/Astervia/wacraft-server/src/workspace/middleware"
 	"github.com/gofiber/fiber/v2"
 )
@@ -55,6 +57,8 @@ func BlockContact(c *fiber.Ctx) error {
 		)
 	}
 
+	go webhook_service.SendEvent(webhook_event_model.ContactBlocked, &workspace.ID, updated)
+
 	return c.Status(fiber.StatusCreated).JSON(updated)
 }
 
@@ -103,5 +107,7 @@ func UnblockContact(c *fiber.Ctx) error {
 		)
 	}
 
+	go webhook_service.SendEvent(webhook_event_model.ContactUnblocked, &workspace.ID, updated)
+
 	return c.Status(fiber.StatusCreated).JSON(updated)
 }
@@ -0,0 +1,53 @@
+package messaging_product_handler
+
+import (
+	"errors"
+
+	common_model "github.com/Astervia/wacraft-core/src/common/model"
+	"github.com/Astervia/wacraft-server/src/database"
+	messaging_product_service "github.com/Astervia/wacraft-server/src/messaging-product/service"
+	workspace_middleware "github.com/Astervia/wacraft-server/src/workspace/middleware"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GetContactServiceWindow returns the 24-hour customer service window of a messaging product contact.
+//
+//	@Summary		Get contact service window
+//	@Description	Returns when the contact last wrote to the workspace number and until when free-form (non-template) messages can be sent to it.
+//	@Tags			Messaging product contact
+//	@Produce		json
+//	@Param			messagingProductContactID	path		string									true	"Messaging product contact ID"
+//	@Success		200							{object}	messaging_product_service.ServiceWindow	"Service window of the contact"
+//	@Failure		400							{object}	common_model.DescriptiveError			"Invalid contact ID format"
+//	@Failure		404							{object}	common_model.DescriptiveError			"Contact not found in the workspace"
+//	@Failure		500							{object}	common_model.DescriptiveError			"Failed to get the service window"
+//	@Security		ApiKeyAuth
+//	@Security		WorkspaceAuth
+//	@Router			/messaging-product/contact/service-window/{messagingProductContactID} [get]
+func GetContactServiceWindow(c *fiber.Ctx) error {
+	mpcID, err := uuid.Parse(c.Params("messagingProductContactID"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewApiError("unable to parse messaging product contact id string to UUID", err, "github.com/google/uuid").Send(),
+		)
+	}
+
+	workspace := workspace_middleware.GetWorkspace(c)
+	db := database.DB.Joins("JOIN messaging_products ON messaging_product_contacts.messaging_product_id = messaging_products.id AND messaging_products.workspace_id = ?", workspace.ID)
+
+	window, err := messaging_product_service.GetServiceWindow(mpcID, db)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(
+			common_model.NewApiError("messaging product contact not found", err, "messaging_product_service").Send(),
+		)
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			common_model.NewApiError("unable to get service window", err, "messaging_product_service").Send(),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(window)
+}
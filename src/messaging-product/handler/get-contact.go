@@ -7,6 +7,8 @@ import (
 	messaging_product_model "github.com/Astervia/wacraft-core/src/messaging-product/model"
 	"github.com/Astervia/wacraft-core/src/repository"
 	"github.com/Astervia/wacraft-server/src/database"
+	database_cursor "github.com/Astervia/wacraft-server/src/database/cursor"
+	messaging_product_service "github.com/Astervia/wacraft-server/src/messaging-product/service"
 	"github.com/Astervia/wacraft-server/src/validators"
 	workspace_middleware "github.com/Astervia/wacraft-server/src/workspace/middleware"
 	"github.com/gofiber/fiber/v2"
@@ -16,11 +18,13 @@ import (
 //
 //	@Summary		Retrieve messaging product contacts
 //	@Description	Fetches a paginated list of messaging product contacts, joining with the contact entity.
+//	@Description	Set pagination=cursor to receive a page with next_cursor and prev_cursor instead of a plain array, newest first; pass either cursor back to move between pages. Offset and order are ignored in cursor mode.
 //	@Tags			Messaging product contact
 //	@Accept			json
 //	@Produce		json
 //	@Param			paginate	query		messaging_product_model.QueryContactPaginated		true	"Query and pagination parameters"
-//	@Success		200			{array}		messaging_product_entity.MessagingProductContact	"List of messaging product contacts"
+//	@Param			cursor		query		database_cursor.Query								false	"Cursor pagination"
+//	@Success		200			{array}		messaging_product_service.ContactWithServiceWindow	"List of messaging product contacts with their service window"
 //	@Failure		400			{object}	common_model.DescriptiveError						"Invalid query parameters"
 //	@Failure		500			{object}	common_model.DescriptiveError						"Failed to retrieve contacts"
 //	@Security		ApiKeyAuth
@@ -57,6 +61,52 @@ func GetContact(c *fiber.Ctx) error {
 	}
 	db = db.Joins("Contact")
 
+	cursorQuery := new(database_cursor.Query)
+	if err := c.QueryParser(cursorQuery); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewParseJsonError(err).Send(),
+		)
+	}
+
+	if cursorQuery.Enabled() {
+		cursor, err := cursorQuery.Parse()
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(
+				common_model.NewApiError("unable to parse cursor", err, "database_cursor").Send(),
+			)
+		}
+
+		page, err := database_cursor.GetPage(
+			mpc,
+			&query.DateWhere,
+			"messaging_product_contacts",
+			cursor,
+			database_cursor.Limit(query.Limit),
+			func(m messaging_product_entity.MessagingProductContact) database_cursor.Cursor {
+				return database_cursor.AuditKey(m.Audit)
+			},
+			db,
+		)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(
+				common_model.NewApiError("unable to get messaging product contacts", err, "database_cursor").Send(),
+			)
+		}
+
+		contacts, err := messaging_product_service.WithServiceWindows(page.Data, nil)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(
+				common_model.NewApiError("unable to get service windows", err, "messaging_product_service").Send(),
+			)
+		}
+
+		return c.Status(fiber.StatusOK).JSON(database_cursor.Page[messaging_product_service.ContactWithServiceWindow]{
+			Data:       contacts,
+			NextCursor: page.NextCursor,
+			PrevCursor: page.PrevCursor,
+		})
+	}
+
 	mps, err := repository.GetPaginated(
 		mpc,
 		&query.Paginate,
@@ -71,7 +121,14 @@ func GetContact(c *fiber.Ctx) error {
 		)
 	}
 
-	return c.Status(fiber.StatusOK).JSON(mps)
+	contacts, err := messaging_product_service.WithServiceWindows(mps, nil)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			common_model.NewApiError("unable to get service windows", err, "messaging_product_service").Send(),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(contacts)
 }
 
 // GetWhatsAppContact returns a paginated list of WhatsApp messaging product contacts.
@@ -82,7 +139,7 @@ func GetContact(c *fiber.Ctx) error {
 //	@Accept			json
 //	@Produce		json
 //	@Param			paginate	query		messaging_product_model.QueryWhatsAppContactPaginated	true	"Query and pagination parameters"
-//	@Success		200			{array}		messaging_product_entity.MessagingProductContact		"List of WhatsApp messaging product contacts"
+//	@Success		200			{array}		messaging_product_service.ContactWithServiceWindow		"List of WhatsApp messaging product contacts with their service window"
 //	@Failure		400			{object}	common_model.DescriptiveError							"Invalid query parameters"
 //	@Failure		500			{object}	common_model.DescriptiveError							"Failed to retrieve WhatsApp contacts"
 //	@Security		ApiKeyAuth
@@ -127,6 +184,52 @@ func GetWhatsAppContact(c *fiber.Ctx) error {
 	}
 	db = db.Joins("Contact")
 
+	cursorQuery := new(database_cursor.Query)
+	if err := c.QueryParser(cursorQuery); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewParseJsonError(err).Send(),
+		)
+	}
+
+	if cursorQuery.Enabled() {
+		cursor, err := cursorQuery.Parse()
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(
+				common_model.NewApiError("unable to parse cursor", err, "database_cursor").Send(),
+			)
+		}
+
+		page, err := database_cursor.GetPage(
+			mpc,
+			&query.DateWhere,
+			"messaging_product_contacts",
+			cursor,
+			database_cursor.Limit(query.Limit),
+			func(m messaging_product_entity.MessagingProductContact) database_cursor.Cursor {
+				return database_cursor.AuditKey(m.Audit)
+			},
+			db,
+		)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(
+				common_model.NewApiError("unable to get messaging product contacts", err, "database_cursor").Send(),
+			)
+		}
+
+		contacts, err := messaging_product_service.WithServiceWindows(page.Data, nil)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(
+				common_model.NewApiError("unable to get service windows", err, "messaging_product_service").Send(),
+			)
+		}
+
+		return c.Status(fiber.StatusOK).JSON(database_cursor.Page[messaging_product_service.ContactWithServiceWindow]{
+			Data:       contacts,
+			NextCursor: page.NextCursor,
+			PrevCursor: page.PrevCursor,
+		})
+	}
+
 	mps, err := repository.GetPaginated(
 		mpc,
 		&query.Paginate,
@@ -141,5 +244,12 @@ func GetWhatsAppContact(c *fiber.Ctx) error {
 		)
 	}
 
-	return c.Status(fiber.StatusOK).JSON(mps)
+	contacts, err := messaging_product_service.WithServiceWindows(mps, nil)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			common_model.NewApiError("unable to get service windows", err, "messaging_product_service").Send(),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(contacts)
 }
@@ -88,6 +88,14 @@ func mainContactRoutes(contactGroup fiber.Router) {
 		workspace_middleware.RequirePolicy(workspace_model.PolicyContactManage),
 		billing_middleware.ThroughputMiddleware,
 		messaging_product_handler.UpdateContactLastReadAt)
+
+	contactGroup.Get("/service-window/:messagingProductContactID",
+		auth_middleware.UserMiddleware,
+		auth_middleware.EmailVerifiedMiddleware,
+		workspace_middleware.WorkspaceMiddleware,
+		workspace_middleware.RequirePolicy(workspace_model.PolicyContactRead),
+		billing_middleware.ThroughputMiddleware,
+		messaging_product_handler.GetContactServiceWindow)
 }
 
 func whatsAppContactRoutes(contactGroup fiber.Router) {
@@ -0,0 +1,63 @@
+package messaging_product_service
+
+import (
+	"errors"
+
+	messaging_product_entity "github.com/Astervia/wacraft-core/src/messaging-product/entity"
+	"github.com/Astervia/wacraft-server/src/database"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GetContactForMessagingProduct returns the messaging product contact that represents the same
+// person as mpContact on another messaging product (i.e. another phone number of the workspace).
+// It matches by contact first and then by WhatsApp ID / phone number, creating the contact
+// on the target messaging product when it does not exist yet.
+func GetContactForMessagingProduct(
+	mpContact messaging_product_entity.MessagingProductContact,
+	messagingProductID uuid.UUID,
+	db *gorm.DB,
+) (messaging_product_entity.MessagingProductContact, error) {
+	var out messaging_product_entity.MessagingProductContact
+
+	if db == nil {
+		db = database.DB
+	}
+
+	if mpContact.MessagingProductID == messagingProductID {
+		return mpContact, nil
+	}
+
+	// Same contact already linked to the target messaging product
+	err := db.Model(&messaging_product_entity.MessagingProductContact{}).
+		Where("messaging_product_id = ? AND contact_id = ?", messagingProductID, mpContact.ContactID).
+		First(&out).Error
+	if err == nil {
+		return out, nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return out, err
+	}
+
+	// Same WhatsApp user saved as another contact of the target messaging product
+	if mpContact.ProductDetails != nil {
+		q := db.Model(&messaging_product_entity.MessagingProductContact{}).
+			Where("messaging_product_id = ?", messagingProductID)
+		mpContact.ProductDetails.ParseIndividualFieldQueries(&q)
+
+		err = q.First(&out).Error
+		if err == nil {
+			return out, nil
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return out, err
+		}
+	}
+
+	out = messaging_product_entity.MessagingProductContact{
+		MessagingProductID: messagingProductID,
+		ContactID:          mpContact.ContactID,
+		ProductDetails:     mpContact.ProductDetails,
+	}
+	err = db.Model(&messaging_product_entity.MessagingProductContact{}).Create(&out).Error
+
+	return out, err
+}
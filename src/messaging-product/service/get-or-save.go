@@ -46,6 +46,17 @@ func GetContactOrSave(
 	contact contact_entity.Contact,
 	db *gorm.DB,
 ) (messaging_product_entity.MessagingProductContact, error) {
+	out, _, err := GetContactOrSaveCreated(mpContact, contact, db)
+	return out, err
+}
+
+// GetContactOrSaveCreated is GetContactOrSave that also reports whether the
+// contact was created.
+func GetContactOrSaveCreated(
+	mpContact messaging_product_entity.MessagingProductContact,
+	contact contact_entity.Contact,
+	db *gorm.DB,
+) (messaging_product_entity.MessagingProductContact, bool, error) {
 	var out messaging_product_entity.MessagingProductContact
 
 	if db == nil {
@@ -66,21 +77,21 @@ func GetContactOrSave(
 
 	// Try to find existing MPC
 	if err := q.First(&out).Error; err == nil {
-		return out, nil // found
+		return out, false, nil // found
 	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
-		return out, err // real DB error
+		return out, false, err // real DB error
 	}
 
 	// --- 2) Not found → create the Contact, then the MPC ---
 	if err := db.Model(&contact).Create(&contact).Error; err != nil {
-		return out, err
+		return out, false, err
 	}
 
 	mpContact.ContactID = contact.ID
 	// Make sure ProductDetails is preserved on insert
 	// (it already is in mpContact, so just Create)
 	if err := db.Model(&messaging_product_entity.MessagingProductContact{}).Create(&mpContact).Error; err != nil {
-		return out, err
+		return out, false, err
 	}
 
 	// Reload with preload for a fully populated return
@@ -90,5 +101,5 @@ func GetContactOrSave(
 		Preload("Contact").
 		First(&out).Error
 
-	return out, err
+	return out, err == nil, err
 }
@@ -0,0 +1,171 @@
+package messaging_product_service
+
+import (
+	"strconv"
+	"time"
+
+	messaging_product_entity "github.com/Astervia/wacraft-core/src/messaging-product/entity"
+	"github.com/Astervia/wacraft-server/src/database"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ServiceWindowDuration is how long after the last inbound message WhatsApp accepts
+// free-form (non-template) messages to a contact.
+const ServiceWindowDuration = 24 * time.Hour
+
+// ServiceWindowClosedCode identifies ServiceWindowClosedError in API responses.
+const ServiceWindowClosedCode = "service_window_closed"
+
+// ServiceWindow describes the customer service window of a messaging product contact.
+type ServiceWindow struct {
+	MessagingProductContactID uuid.UUID  `json:"messaging_product_contact_id"`
+	LastInboundAt             *time.Time `json:"last_inbound_at,omitempty"` // Last time the contact wrote to the number.
+	ExpiresAt                 *time.Time `json:"expires_at,omitempty"`      // When free-form messages stop being accepted.
+	IsOpen                    bool       `json:"is_open"`
+}
+
+// ContactWithServiceWindow is a messaging product contact listed along with its customer service window.
+type ContactWithServiceWindow struct {
+	messaging_product_entity.MessagingProductContact
+	ServiceWindow ServiceWindow `json:"service_window"`
+}
+
+// ServiceWindowClosedError is returned when a free-form message is sent to a contact
+// whose customer service window is closed.
+type ServiceWindowClosedError struct {
+	Message                   string     `json:"message"`
+	Code                      string     `json:"code"`
+	MessagingProductContactID uuid.UUID  `json:"messaging_product_contact_id"`
+	LastInboundAt             *time.Time `json:"last_inbound_at,omitempty"`
+	ExpiredAt                 *time.Time `json:"expired_at,omitempty"`
+}
+
+func (e *ServiceWindowClosedError) Error() string {
+	return e.Message
+}
+
+// NewServiceWindow computes the window state from the last inbound message time.
+func NewServiceWindow(mpcID uuid.UUID, lastInboundAt *time.Time, now time.Time) ServiceWindow {
+	window := ServiceWindow{
+		MessagingProductContactID: mpcID,
+		LastInboundAt:             lastInboundAt,
+	}
+	if lastInboundAt != nil {
+		expiresAt := lastInboundAt.Add(ServiceWindowDuration)
+		window.ExpiresAt = &expiresAt
+		window.IsOpen = now.Before(expiresAt)
+	}
+	return window
+}
+
+// GetServiceWindow returns the customer service window of a messaging product contact.
+func GetServiceWindow(mpcID uuid.UUID, db *gorm.DB) (ServiceWindow, error) {
+	if db == nil {
+		db = database.DB
+	}
+
+	var lastInboundAt []*time.Time
+	err := db.Model(&messaging_product_entity.MessagingProductContact{}).
+		Where("messaging_product_contacts.id = ?", mpcID).
+		Limit(1).
+		Pluck("messaging_product_contacts.last_inbound_at", &lastInboundAt).Error
+	if err != nil {
+		return ServiceWindow{}, err
+	}
+	if len(lastInboundAt) == 0 {
+		return ServiceWindow{}, gorm.ErrRecordNotFound
+	}
+
+	return NewServiceWindow(mpcID, lastInboundAt[0], time.Now()), nil
+}
+
+// WithServiceWindows loads the service windows of the contacts with a single query.
+func WithServiceWindows(
+	contacts []messaging_product_entity.MessagingProductContact,
+	db *gorm.DB,
+) ([]ContactWithServiceWindow, error) {
+	if db == nil {
+		db = database.DB
+	}
+
+	result := make([]ContactWithServiceWindow, len(contacts))
+	if len(contacts) == 0 {
+		return result, nil
+	}
+
+	ids := make([]uuid.UUID, len(contacts))
+	for i, contact := range contacts {
+		ids[i] = contact.ID
+	}
+
+	var rows []struct {
+		ID            uuid.UUID
+		LastInboundAt *time.Time
+	}
+	if err := db.Model(&messaging_product_entity.MessagingProductContact{}).
+		Select("messaging_product_contacts.id, messaging_product_contacts.last_inbound_at").
+		Where("messaging_product_contacts.id IN ?", ids).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	lastInbound := make(map[uuid.UUID]*time.Time, len(rows))
+	for _, row := range rows {
+		lastInbound[row.ID] = row.LastInboundAt
+	}
+
+	now := time.Now()
+	for i, contact := range contacts {
+		result[i] = ContactWithServiceWindow{
+			MessagingProductContact: contact,
+			ServiceWindow:           NewServiceWindow(contact.ID, lastInbound[contact.ID], now),
+		}
+	}
+	return result, nil
+}
+
+// EnsureServiceWindowOpen returns a *ServiceWindowClosedError when the contact's window is closed.
+func EnsureServiceWindowOpen(mpcID uuid.UUID, db *gorm.DB) error {
+	window, err := GetServiceWindow(mpcID, db)
+	if err != nil {
+		return err
+	}
+	if window.IsOpen {
+		return nil
+	}
+
+	message := "the contact has not written in the last 24 hours; only template messages can be sent"
+	if window.LastInboundAt == nil {
+		message = "the contact never wrote to this number; only template messages can be sent"
+	}
+
+	return &ServiceWindowClosedError{
+		Message:                   message,
+		Code:                      ServiceWindowClosedCode,
+		MessagingProductContactID: mpcID,
+		LastInboundAt:             window.LastInboundAt,
+		ExpiredAt:                 window.ExpiresAt,
+	}
+}
+
+// TouchLastInboundAt records that the contact wrote at the given time. The stored value
+// only moves forward, so out-of-order webhooks do not shorten the window.
+func TouchLastInboundAt(mpcID uuid.UUID, at time.Time, db *gorm.DB) error {
+	if db == nil {
+		db = database.DB
+	}
+
+	return db.Model(&messaging_product_entity.MessagingProductContact{}).
+		Where("id = ?", mpcID).
+		Update("last_inbound_at", gorm.Expr("GREATEST(COALESCE(last_inbound_at, ?), ?)", at, at)).Error
+}
+
+// ParseWhatsAppTimestamp parses the unix timestamp sent by WhatsApp webhooks,
+// falling back to the current time when it is missing or malformed.
+func ParseWhatsAppTimestamp(timestamp string) time.Time {
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || seconds <= 0 {
+		return time.Now()
+	}
+	return time.Unix(seconds, 0)
+}
@@ -0,0 +1,52 @@
+package messaging_product_service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestNewServiceWindow_NeverWrote(t *testing.T) {
+	window := NewServiceWindow(uuid.New(), nil, time.Now())
+	if window.IsOpen {
+		t.Error("expected window to be closed when the contact never wrote")
+	}
+	if window.ExpiresAt != nil {
+		t.Errorf("expected no expiration, got %s", window.ExpiresAt)
+	}
+}
+
+func TestNewServiceWindow_Open(t *testing.T) {
+	now := time.Now()
+	last := now.Add(-23 * time.Hour)
+
+	window := NewServiceWindow(uuid.New(), &last, now)
+	if !window.IsOpen {
+		t.Error("expected window to be open 23h after the last inbound message")
+	}
+	if want := last.Add(ServiceWindowDuration); !window.ExpiresAt.Equal(want) {
+		t.Errorf("expected expiration at %s, got %s", want, window.ExpiresAt)
+	}
+}
+
+func TestNewServiceWindow_Expired(t *testing.T) {
+	now := time.Now()
+	last := now.Add(-ServiceWindowDuration)
+
+	window := NewServiceWindow(uuid.New(), &last, now)
+	if window.IsOpen {
+		t.Error("expected window to be closed exactly 24h after the last inbound message")
+	}
+}
+
+func TestParseWhatsAppTimestamp(t *testing.T) {
+	if got := ParseWhatsAppTimestamp("1700000000"); !got.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("expected unix 1700000000, got %s", got)
+	}
+
+	before := time.Now()
+	if got := ParseWhatsAppTimestamp("not-a-timestamp"); got.Before(before) {
+		t.Errorf("expected fallback to now, got %s", got)
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/Astervia/wacraft-core/src/repository"
 	"github.com/Astervia/wacraft-server/src/database"
 	"github.com/Astervia/wacraft-server/src/validators"
+	webhook_event_model "github.com/Astervia/wacraft-server/src/webhook-event/model"
+	webhook_service "github.com/Astervia/wacraft-server/src/webhook/service"
 	workspace_middleware "github.com/Astervia/wacraft-server/src/workspace/middleware"
 	bootstrap_service "github.com/Rfluid/whatsapp-cloud-api/src/bootstrap"
 	profile_service "github.com/Rfluid/whatsapp-cloud-api/src/profile"
@@ -369,6 +371,14 @@ func Update(c *fiber.Ctx) error {
 		)
 	}
 
+	if len(updates) > 0 {
+		go webhook_service.SendEvent(
+			webhook_event_model.PhoneConfigUpdated,
+			&workspace.ID,
+			webhook_event_model.PhoneConfigData(phoneConfig),
+		)
+	}
+
 	return c.Status(fiber.StatusOK).JSON(phoneConfig)
 }
 
@@ -0,0 +1,102 @@
+package phone_config_service
+
+import (
+	"errors"
+	"fmt"
+
+	messaging_product_entity "github.com/Astervia/wacraft-core/src/messaging-product/entity"
+	messaging_product_model "github.com/Astervia/wacraft-core/src/messaging-product/model"
+	"github.com/Astervia/wacraft-server/src/database"
+	workspace_setting_service "github.com/Astervia/wacraft-server/src/workspace-setting/service"
+	bootstrap_module "github.com/Rfluid/whatsapp-cloud-api/src/bootstrap"
+	"github.com/google/uuid"
+)
+
+// SenderSelection lets a request choose which of the workspace numbers is used.
+// At most one of the fields should be set; MessagingProductID wins when both are.
+type SenderSelection struct {
+	PhoneConfigID      *uuid.UUID `json:"phone_config_id,omitempty" query:"phone_config_id" form:"phone_config_id"`
+	MessagingProductID *uuid.UUID `json:"messaging_product_id,omitempty" query:"messaging_product_id" form:"messaging_product_id"`
+}
+
+// IsEmpty reports whether the request did not select a sender.
+func (s SenderSelection) IsEmpty() bool {
+	return s.PhoneConfigID == nil && s.MessagingProductID == nil
+}
+
+// ErrSenderNotInWorkspace is returned when the selected sender does not belong to the workspace.
+var ErrSenderNotInWorkspace = errors.New("selected sender does not belong to the workspace")
+
+// ResolveWorkspaceSender returns the WhatsApp messaging product used to talk on behalf of the workspace.
+//
+// Resolution order: the explicit selection, the workspace default phone config and
+// finally the first WhatsApp messaging product of the workspace linked to a phone
+// config. A wrapped gorm.ErrRecordNotFound is returned when the workspace has none.
+func ResolveWorkspaceSender(
+	workspaceID uuid.UUID,
+	selection SenderSelection,
+) (messaging_product_entity.MessagingProduct, error) {
+	if !selection.IsEmpty() {
+		return FindWorkspaceSender(workspaceID, selection)
+	}
+
+	setting, err := workspace_setting_service.GetWorkspaceSetting(workspaceID, nil)
+	if err != nil {
+		return messaging_product_entity.MessagingProduct{}, err
+	}
+	if setting.DefaultPhoneConfigID != nil {
+		return FindWorkspaceSender(workspaceID, SenderSelection{PhoneConfigID: setting.DefaultPhoneConfigID})
+	}
+
+	var mp messaging_product_entity.MessagingProduct
+	err = database.DB.Model(&mp).
+		Where("workspace_id = ? AND name = ? AND phone_config_id IS NOT NULL", workspaceID, messaging_product_model.WhatsApp).
+		First(&mp).Error
+	if err != nil {
+		return mp, fmt.Errorf("no messaging product with a phone config found for workspace: %w", err)
+	}
+
+	return mp, nil
+}
+
+// FindWorkspaceSender returns the WhatsApp messaging product matching an explicit
+// selection, making sure it belongs to the workspace.
+func FindWorkspaceSender(
+	workspaceID uuid.UUID,
+	selection SenderSelection,
+) (messaging_product_entity.MessagingProduct, error) {
+	var mp messaging_product_entity.MessagingProduct
+
+	db := database.DB.Model(&mp).
+		Where("workspace_id = ? AND name = ?", workspaceID, messaging_product_model.WhatsApp)
+	switch {
+	case selection.MessagingProductID != nil:
+		db = db.Where("id = ?", *selection.MessagingProductID)
+	case selection.PhoneConfigID != nil:
+		db = db.Where("phone_config_id = ?", *selection.PhoneConfigID)
+	default:
+		return mp, errors.New("no sender selected")
+	}
+
+	if err := db.First(&mp).Error; err != nil {
+		return mp, fmt.Errorf("%w: %w", ErrSenderNotInWorkspace, err)
+	}
+	if mp.PhoneConfigID == nil {
+		return mp, errors.New("messaging product has no phone config configured")
+	}
+
+	return mp, nil
+}
+
+// GetWorkspaceSenderAPI returns the WhatsApp API of the sender resolved by ResolveWorkspaceSender.
+func GetWorkspaceSenderAPI(
+	workspaceID uuid.UUID,
+	selection SenderSelection,
+) (*bootstrap_module.WhatsAppAPI, error) {
+	mp, err := ResolveWorkspaceSender(workspaceID, selection)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetWhatsAppAPIByPhoneConfigID(*mp.PhoneConfigID)
+}
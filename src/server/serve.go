@@ -6,6 +6,8 @@ import (
 	"os/signal"
 	"syscall"
 
+	account_update_router "github.com/Astervia/wacraft-server/src/account-update/router"
+	account_update_websocket "github.com/Astervia/wacraft-server/src/account-update/websocket-router"
 	auth_middleware "github.com/Astervia/wacraft-server/src/auth/middleware"
 	auth_router "github.com/Astervia/wacraft-server/src/auth/router"
 	billing_router "github.com/Astervia/wacraft-server/src/billing/router"
@@ -15,17 +17,24 @@ import (
 	campaign_router "github.com/Astervia/wacraft-server/src/campaign/router"
 	campaign_websocket "github.com/Astervia/wacraft-server/src/campaign/websocket-router"
 	campaign_worker "github.com/Astervia/wacraft-server/src/campaign/worker"
+	conversation_worker "github.com/Astervia/wacraft-server/src/conversation/worker"
 	// PREMIUM ENDS
 	"github.com/Astervia/wacraft-server/src/config/env"
 	contact_router "github.com/Astervia/wacraft-server/src/contact/router"
+	conversation_router "github.com/Astervia/wacraft-server/src/conversation/router"
+	conversation_websocket "github.com/Astervia/wacraft-server/src/conversation/websocket-router"
+	inbound_receipt_worker "github.com/Astervia/wacraft-server/src/inbound-receipt/worker"
 	media_router "github.com/Astervia/wacraft-server/src/media/router"
 	message_router "github.com/Astervia/wacraft-server/src/message/router"
 	message_websocket "github.com/Astervia/wacraft-server/src/message/websocket-router"
+	message_worker "github.com/Astervia/wacraft-server/src/message/worker"
 	messaging_product_router "github.com/Astervia/wacraft-server/src/messaging-product/router"
 	status_router "github.com/Astervia/wacraft-server/src/status/router"
 	status_websocket "github.com/Astervia/wacraft-server/src/status/websocket-router"
 	user_router "github.com/Astervia/wacraft-server/src/user/router"
 	"github.com/Astervia/wacraft-server/src/validators"
+	webhook_in_event_router "github.com/Astervia/wacraft-server/src/webhook-in-event/router"
+	webhook_in_event_worker "github.com/Astervia/wacraft-server/src/webhook-in-event/worker"
 	webhook_config "github.com/Astervia/wacraft-server/src/webhook-in/config"
 	webhook_router "github.com/Astervia/wacraft-server/src/webhook/router"
 	webhook_worker "github.com/Astervia/wacraft-server/src/webhook/worker"
@@ -63,11 +72,14 @@ func serve() {
 	contact_router.Route(app)
 	messaging_product_router.Route(app)
 	message_router.Route(app)
+	conversation_router.Route(app)
 	// PREMIUM STARTS
 	campaign_router.Route(app)
 	// PREMIUM ENDS
 	media_router.Route(app)
 	webhook_router.Route(app)
+	webhook_in_event_router.Route(app)
+	account_update_router.Route(app)
 	whatsapp_template_router.Route(app)
 	status_router.Route(app)
 	billing_router.Route(app)
@@ -79,11 +91,32 @@ func serve() {
 	campaign_websocket.Route(websocketRouter)
 	// PREMIUM ENDS
 	status_websocket.Route(websocketRouter)
+	conversation_websocket.Route(websocketRouter)
+	account_update_websocket.Route(websocketRouter)
 
 	// Start webhook delivery worker
 	deliveryWorker := webhook_worker.NewDeliveryWorker()
 	deliveryWorker.Start()
 
+	// Start message outbox worker (asynchronous sends)
+	outboxWorker := message_worker.NewOutboxWorker()
+	outboxWorker.Start()
+
+	// Start conversation snooze worker (reopens conversations whose snooze ended)
+	snoozeWorker := conversation_worker.NewSnoozeWorker()
+	snoozeWorker.Start()
+
+	// Start inbound receipt prune worker (forgets messages Meta no longer retries)
+	receiptWorker := inbound_receipt_worker.NewPruneWorker()
+	receiptWorker.Start()
+
+	// Start webhook-in worker (asynchronous processing of Meta webhooks)
+	var inboundWorker *webhook_in_event_worker.InboundWorker
+	if env.WebhookInAsync {
+		inboundWorker = webhook_in_event_worker.NewInboundWorker(app)
+		inboundWorker.Start()
+	}
+
 	// PREMIUM STARTS
 	// Wire the channel pool into the campaign scheduler worker so that WebSocket
 	// clients connecting during a scheduled run receive real-time progress.
@@ -104,6 +137,12 @@ func serve() {
 
 		pterm.DefaultLogger.Info("Shutdown signal received, stopping services...")
 		deliveryWorker.Stop()
+		outboxWorker.Stop()
+		snoozeWorker.Stop()
+		receiptWorker.Stop()
+		if inboundWorker != nil {
+			inboundWorker.Stop()
+		}
 		// PREMIUM STARTS
 		schedulerWorker.Stop()
 		// PREMIUM ENDS
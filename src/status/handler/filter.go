@@ -0,0 +1,62 @@
+package status_handler
+
+import (
+	"errors"
+
+	common_model "github.com/Astervia/wacraft-core/src/common/model"
+	"github.com/Astervia/wacraft-server/src/database"
+	database_filter "github.com/Astervia/wacraft-server/src/database/filter"
+	status_service "github.com/Astervia/wacraft-server/src/status/service"
+	"github.com/Astervia/wacraft-server/src/validators"
+	workspace_middleware "github.com/Astervia/wacraft-server/src/workspace/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+// SearchByFilter returns statuses matching a JSON filter.
+//
+//	@Summary		Search statuses with a filter
+//	@Description	Filters statuses with nested and/or/not groups of conditions. Operators: eq, neq, gt, gte, lt, lte, in, exists and ilike.
+//	@Description	Fields: id, created_at, updated_at, message_id and any path below product_data, e.g. `product_data.status`.
+//	@Tags			Status
+//	@Accept			json
+//	@Produce		json
+//	@Param			search	body		database_filter.Search			true	"Filter, order and pagination"
+//	@Success		200		{array}		status_entity.Status	"Matching statuses"
+//	@Failure		400		{object}	common_model.DescriptiveError	"Invalid filter"
+//	@Failure		500		{object}	common_model.DescriptiveError	"Failed to query statuses"
+//	@Security		ApiKeyAuth
+//	@Security		WorkspaceAuth
+//	@Router			/status/search [post]
+func SearchByFilter(c *fiber.Ctx) error {
+	search := new(database_filter.Search)
+	if err := c.BodyParser(search); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewParseJsonError(err).Send(),
+		)
+	}
+
+	if err := validators.Validator().Struct(search); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewValidationError(err).Send(),
+		)
+	}
+
+	workspace := workspace_middleware.GetWorkspace(c)
+	db := database.DB.
+		Joins("JOIN messages ON statuses.message_id = messages.id").
+		Joins("JOIN messaging_products ON messages.messaging_product_id = messaging_products.id AND messaging_products.workspace_id = ?", workspace.ID)
+
+	statuses, err := status_service.SearchByFilter(*search, db)
+	if err != nil {
+		if errors.Is(err, database_filter.ErrInvalidFilter) {
+			return c.Status(fiber.StatusBadRequest).JSON(
+				common_model.NewApiError("invalid filter", err, "database_filter").Send(),
+			)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			common_model.NewApiError("unable to get statuses", err, "database_filter").Send(),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(statuses)
+}
@@ -6,6 +6,7 @@ import (
 	status_entity "github.com/Astervia/wacraft-core/src/status/entity"
 	status_model "github.com/Astervia/wacraft-core/src/status/model"
 	"github.com/Astervia/wacraft-server/src/database"
+	database_cursor "github.com/Astervia/wacraft-server/src/database/cursor"
 	"github.com/Astervia/wacraft-server/src/validators"
 	"github.com/gofiber/fiber/v2"
 )
@@ -14,10 +15,12 @@ import (
 //
 //	@Summary		Retrieve statuses
 //	@Description	Returns a paginated list of statuses based on optional filters.
+//	@Description	Set pagination=cursor to receive a page with next_cursor and prev_cursor instead of a plain array, newest first; pass either cursor back to move between pages. Offset and order are ignored in cursor mode.
 //	@Tags			Status
 //	@Accept			json
 //	@Produce		json
 //	@Param			status	query		status_model.QueryPaginated		true	"Pagination and query parameters"
+//	@Param			cursor	query		database_cursor.Query			false	"Cursor pagination"
 //	@Success		200		{array}		status_entity.Status			"List of statuses"
 //	@Failure		400		{object}	common_model.DescriptiveError	"Invalid query parameters"
 //	@Failure		500		{object}	common_model.DescriptiveError	"Failed to retrieve statuses"
@@ -37,15 +40,50 @@ func Get(c *fiber.Ctx) error {
 		)
 	}
 
-	statuses, err := repository.GetPaginated(
-		status_entity.Status{
-			StatusFields: status_model.StatusFields{
-				MessageID: query.MessageID,
-				Audit: common_model.Audit{
-					ID: query.ID,
-				},
+	cursorQuery := new(database_cursor.Query)
+	if err := c.QueryParser(cursorQuery); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewParseJsonError(err).Send(),
+		)
+	}
+
+	entity := status_entity.Status{
+		StatusFields: status_model.StatusFields{
+			MessageID: query.MessageID,
+			Audit: common_model.Audit{
+				ID: query.ID,
 			},
 		},
+	}
+
+	if cursorQuery.Enabled() {
+		cursor, err := cursorQuery.Parse()
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(
+				common_model.NewApiError("unable to parse cursor", err, "database_cursor").Send(),
+			)
+		}
+
+		page, err := database_cursor.GetPage(
+			entity,
+			&query.DateWhereWithDeletedAt,
+			"statuses",
+			cursor,
+			database_cursor.Limit(query.Limit),
+			func(s status_entity.Status) database_cursor.Cursor { return database_cursor.AuditKey(s.Audit) },
+			database.DB,
+		)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(
+				common_model.NewApiError("unable to get statuses", err, "database_cursor").Send(),
+			)
+		}
+
+		return c.Status(fiber.StatusOK).JSON(page)
+	}
+
+	statuses, err := repository.GetPaginated(
+		entity,
 		&query.Paginate,
 		&query.DateOrder,
 		&query.DateWhereWithDeletedAt,
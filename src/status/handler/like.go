@@ -75,6 +75,7 @@ func ContentLike(c *fiber.Ctx) error {
 //
 //	@Summary		Search statuses by key and content
 //	@Description	Returns a paginated list of statuses where a given key matches a partial value using regex (~).
+//	@Description	Deprecated: use POST /status/search with an ilike condition.
 //	@Tags			Status
 //	@Accept			json
 //	@Produce		json
@@ -84,6 +85,7 @@ func ContentLike(c *fiber.Ctx) error {
 //	@Failure		400			{object}	common_model.DescriptiveError	"Invalid query or path parameter"
 //	@Failure		500			{object}	common_model.DescriptiveError	"Failed to retrieve statuses"
 //	@Security		ApiKeyAuth
+//	@Deprecated
 //	@Router			/status/content/{keyName}/like/{likeText} [get]
 func ContentKeyLike(c *fiber.Ctx) error {
 	params := new(status_model.ContentKeyLikeParams)
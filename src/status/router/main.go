@@ -1,9 +1,11 @@
 package status_router
 
 import (
+	workspace_model "github.com/Astervia/wacraft-core/src/workspace/model"
 	auth_middleware "github.com/Astervia/wacraft-server/src/auth/middleware"
 	billing_middleware "github.com/Astervia/wacraft-server/src/billing/middleware"
 	status_handler "github.com/Astervia/wacraft-server/src/status/handler"
+	workspace_middleware "github.com/Astervia/wacraft-server/src/workspace/middleware"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -19,4 +21,11 @@ func mainRoutes(group fiber.Router) {
 	group.Get("/count", auth_middleware.UserMiddleware, auth_middleware.EmailVerifiedMiddleware, billing_middleware.ThroughputMiddleware, status_handler.Count)
 	group.Get("/content/like/:likeText", auth_middleware.UserMiddleware, auth_middleware.EmailVerifiedMiddleware, billing_middleware.ThroughputMiddleware, status_handler.ContentLike)
 	group.Get("/content/:keyName/like/:likeText", auth_middleware.UserMiddleware, auth_middleware.EmailVerifiedMiddleware, billing_middleware.ThroughputMiddleware, status_handler.ContentKeyLike)
+	group.Post("/search",
+		auth_middleware.UserMiddleware,
+		auth_middleware.EmailVerifiedMiddleware,
+		workspace_middleware.WorkspaceMiddleware,
+		workspace_middleware.RequirePolicy(workspace_model.PolicyMessageRead),
+		billing_middleware.ThroughputMiddleware,
+		status_handler.SearchByFilter)
 }
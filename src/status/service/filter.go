@@ -0,0 +1,31 @@
+package status_service
+
+import (
+	status_entity "github.com/Astervia/wacraft-core/src/status/entity"
+	"github.com/Astervia/wacraft-server/src/database"
+	database_filter "github.com/Astervia/wacraft-server/src/database/filter"
+	"gorm.io/gorm"
+)
+
+// FilterSchema whitelists the fields usable in status filters.
+var FilterSchema = database_filter.Schema{
+	Table: "statuses",
+	Fields: map[string]database_filter.Field{
+		"id":         {Column: "statuses.id", Type: database_filter.UUID},
+		"created_at": {Column: "statuses.created_at", Type: database_filter.Time},
+		"updated_at": {Column: "statuses.updated_at", Type: database_filter.Time},
+		"message_id": {Column: "statuses.message_id", Type: database_filter.UUID},
+	},
+	JSON: map[string]string{
+		"product_data": "statuses.product_data",
+	},
+}
+
+// SearchByFilter returns the statuses matching the search.
+func SearchByFilter(search database_filter.Search, db *gorm.DB) ([]status_entity.Status, error) {
+	if db == nil {
+		db = database.DB
+	}
+
+	return database_filter.Find[status_entity.Status](search, FilterSchema, db.Model(&status_entity.Status{}))
+}
@@ -12,15 +12,20 @@ import (
 	campaign_model "github.com/Astervia/wacraft-core/src/campaign/model"
 	synch "github.com/Astervia/wacraft-core/src/synch"
 	synch_redis "github.com/Astervia/wacraft-core/src/synch/redis"
+	account_update_handler "github.com/Astervia/wacraft-server/src/account-update/handler"
 	billing_service "github.com/Astervia/wacraft-server/src/billing/service"
 	campaign_handler "github.com/Astervia/wacraft-server/src/campaign/handler"
 	campaign_service "github.com/Astervia/wacraft-server/src/campaign/service"
 	campaign_worker "github.com/Astervia/wacraft-server/src/campaign/worker"
 	"github.com/Astervia/wacraft-server/src/config/env"
+	conversation_handler "github.com/Astervia/wacraft-server/src/conversation/handler"
 	message_handler "github.com/Astervia/wacraft-server/src/message/handler"
 	message_service "github.com/Astervia/wacraft-server/src/message/service"
+	message_worker "github.com/Astervia/wacraft-server/src/message/worker"
 	status_handler "github.com/Astervia/wacraft-server/src/status/handler"
+	webhook_in_event_service "github.com/Astervia/wacraft-server/src/webhook-in-event/service"
 	whk_service "github.com/Astervia/wacraft-server/src/webhook-in/service"
+	webhook_service "github.com/Astervia/wacraft-server/src/webhook/service"
 	webhook_worker "github.com/Astervia/wacraft-server/src/webhook/worker"
 	"github.com/pterm/pterm"
 )
@@ -87,10 +92,29 @@ func init() {
 		pterm.DefaultLogger.Info("CampaignChannelPool: using Redis backend")
 	}
 
-	// Wire delivery worker lock (Redis mode only — memory mode uses nil, no locking needed).
+	// Wire delivery worker lock and wake-ups (Redis mode only — memory mode uses no lock and Postgres NOTIFY).
 	if backend == synch.BackendRedis {
 		webhook_worker.SetDeliveryLock(synch.NewLock[string](SyncFactory))
-		pterm.DefaultLogger.Info("DeliveryWorker: using Redis lock backend")
+		webhook_service.SetDeliveryPubSub(SyncFactory.NewPubSub())
+		pterm.DefaultLogger.Info("DeliveryWorker: using Redis lock and PubSub backend")
+	}
+
+	// Wire message outbox worker lock (Redis mode only).
+	if backend == synch.BackendRedis {
+		message_worker.SetOutboxLock(synch.NewLock[string](SyncFactory))
+		pterm.DefaultLogger.Info("MessageOutboxWorker: using Redis lock backend")
+	}
+
+	// Wire webhook-in queue (Redis mode only — memory mode keeps the events of this instance).
+	if backend == synch.BackendRedis {
+		webhook_in_event_service.SetQueue(
+			webhook_in_event_service.NewRedisQueue(
+				redisClient,
+				synch.NewLock[string](SyncFactory),
+				env.WebhookInQueueCapacity,
+			),
+		)
+		pterm.DefaultLogger.Info("WebhookInQueue: using Redis backend")
 	}
 
 	// Wire campaign scheduler lock and factory (Redis mode only).
@@ -106,10 +130,22 @@ func init() {
 			SyncFactory.NewPubSub(),
 			"workspace:messages",
 		)
+		message_handler.MessageReactionWorkspaceManager.SetPubSub(
+			SyncFactory.NewPubSub(),
+			"workspace:message-reactions",
+		)
 		status_handler.NewStatusWorkspaceManager.SetPubSub(
 			SyncFactory.NewPubSub(),
 			"workspace:statuses",
 		)
+		conversation_handler.ConversationEventWorkspaceManager.SetPubSub(
+			SyncFactory.NewPubSub(),
+			"workspace:conversations",
+		)
+		account_update_handler.AccountUpdateWorkspaceManager.SetPubSub(
+			SyncFactory.NewPubSub(),
+			"workspace:account-updates",
+		)
 		pterm.DefaultLogger.Info("WorkspaceChannelManagers: using Redis PubSub backend")
 	}
 
@@ -0,0 +1,63 @@
+package webhook_destination_service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Astervia/wacraft-server/src/config/env"
+	"github.com/pterm/pterm"
+)
+
+var (
+	policy     Policy
+	policyOnce sync.Once
+)
+
+// Current returns the policy configured with the WEBHOOK_* destination
+// variables, or BlockAll when they are invalid.
+func Current() Policy {
+	policyOnce.Do(func() {
+		var err error
+		policy, err = NewPolicy(
+			env.WebhookAllowedSchemes,
+			env.WebhookAllowedPorts,
+			env.WebhookBlockedCIDRs,
+			env.WebhookAllowedDestinations,
+			env.WebhookMaxRedirects,
+		)
+		if err != nil {
+			pterm.DefaultLogger.Error("Invalid webhook destination policy, refusing every webhook destination: " + err.Error())
+			policy = BlockAll()
+		}
+	})
+	return policy
+}
+
+// CheckURL validates a webhook URL against the current policy.
+func CheckURL(rawURL string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return Current().CheckURL(ctx, rawURL)
+}
+
+// Client returns an HTTP client that enforces the current policy.
+func Client(timeout time.Duration) *http.Client {
+	return Current().Client(timeout)
+}
+
+// IsBlocked reports whether err was caused by the destination policy.
+func IsBlocked(err error) bool {
+	return errors.Is(err, ErrBlockedDestination)
+}
+
+// BlockedResponseData is stored as the response data of the WebhookLog of an
+// attempt refused by the policy, so the reason shows up in the logs.
+func BlockedResponseData(err error) map[string]any {
+	return map[string]any{
+		"blocked": true,
+		"error":   err.Error(),
+	}
+}
@@ -0,0 +1,257 @@
+// Package webhook_destination_service keeps outgoing webhooks away from
+// internal networks. URLs are checked when webhooks are created or updated,
+// and every connection is checked again on the resolved address when it is
+// dialed, so hostnames that later resolve to a blocked address (DNS
+// rebinding) are refused too.
+package webhook_destination_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrBlockedDestination is wrapped by every error caused by the destination policy.
+var ErrBlockedDestination = errors.New("blocked webhook destination")
+
+// DefaultBlockedCIDRs are loopback, private, link-local (including cloud
+// metadata endpoints), shared, multicast and reserved ranges.
+var DefaultBlockedCIDRs = []string{
+	"0.0.0.0/8",
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"127.0.0.0/8",
+	"169.254.0.0/16",
+	"172.16.0.0/12",
+	"192.0.0.0/24",
+	"192.0.2.0/24",
+	"192.168.0.0/16",
+	"198.18.0.0/15",
+	"198.51.100.0/24",
+	"203.0.113.0/24",
+	"224.0.0.0/4",
+	"240.0.0.0/4",
+	"::/128",
+	"::1/128",
+	"64:ff9b::/96",
+	"100::/64",
+	"2001:db8::/32",
+	"fc00::/7",
+	"fe80::/10",
+	"ff00::/8",
+}
+
+// Policy tells which destinations webhooks may be sent to.
+type Policy struct {
+	// Schemes allowed in webhook URLs, lower case.
+	Schemes []string
+	// Ports allowed in webhook URLs. Empty allows any port.
+	Ports []int
+	// Blocked holds the address ranges webhooks may not connect to.
+	Blocked []netip.Prefix
+	// AllowedPrefixes and AllowedHosts are exempt from Blocked. Hosts
+	// starting with a dot match their subdomains.
+	AllowedPrefixes []netip.Prefix
+	AllowedHosts    []string
+	// MaxRedirects is how many redirects are followed. 0 follows none.
+	MaxRedirects int
+}
+
+// NewPolicy builds a policy that blocks DefaultBlockedCIDRs and blockedCIDRs.
+// Entries of allowed are CIDRs, IP addresses or hostnames.
+func NewPolicy(schemes []string, ports []int, blockedCIDRs []string, allowed []string, maxRedirects int) (Policy, error) {
+	p := Policy{Ports: ports, MaxRedirects: maxRedirects}
+
+	for _, s := range schemes {
+		p.Schemes = append(p.Schemes, strings.ToLower(s))
+	}
+
+	for _, cidr := range append(slices.Clone(DefaultBlockedCIDRs), blockedCIDRs...) {
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			return p, fmt.Errorf("invalid blocked CIDR %q: %w", cidr, err)
+		}
+		p.Blocked = append(p.Blocked, prefix.Masked())
+	}
+
+	for _, entry := range allowed {
+		if prefix, err := netip.ParsePrefix(entry); err == nil {
+			p.AllowedPrefixes = append(p.AllowedPrefixes, prefix.Masked())
+		} else if addr, err := netip.ParseAddr(entry); err == nil {
+			p.AllowedPrefixes = append(p.AllowedPrefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+		} else {
+			p.AllowedHosts = append(p.AllowedHosts, strings.ToLower(entry))
+		}
+	}
+
+	return p, nil
+}
+
+// BlockAll returns a policy that refuses every destination. It is used when
+// the configured policy is invalid, so a typo never opens internal networks.
+func BlockAll() Policy {
+	return Policy{
+		Blocked: []netip.Prefix{netip.MustParsePrefix("0.0.0.0/0"), netip.MustParsePrefix("::/0")},
+	}
+}
+
+func blocked(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrBlockedDestination, fmt.Sprintf(format, args...))
+}
+
+// CheckURL validates the scheme, port and host of a webhook URL. Hostnames
+// are resolved and every address they resolve to must be allowed; hostnames
+// that do not resolve are left to the dial time check.
+func (p Policy) CheckURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return blocked("invalid URL: %v", err)
+	}
+
+	scheme := strings.ToLower(u.Scheme)
+	if !slices.Contains(p.Schemes, scheme) {
+		return blocked("scheme %q is not allowed", u.Scheme)
+	}
+	if u.User != nil {
+		return blocked("credentials in the URL are not allowed")
+	}
+
+	host := u.Hostname()
+	if host == "" {
+		return blocked("URL has no host")
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if scheme == "https" {
+			port = "443"
+		}
+	}
+	if err := p.checkPort(port); err != nil {
+		return err
+	}
+
+	if p.hostAllowed(host) {
+		return nil
+	}
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return p.CheckAddr(host, addr)
+	}
+
+	name := strings.TrimSuffix(strings.ToLower(host), ".")
+	if name == "localhost" || strings.HasSuffix(name, ".localhost") {
+		return blocked("host %q is a loopback name", host)
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return nil
+	}
+	for _, addr := range addrs {
+		if err := p.CheckAddr(host, addr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckAddr validates an address host resolved to.
+func (p Policy) CheckAddr(host string, addr netip.Addr) error {
+	addr = addr.Unmap().WithZone("")
+	if p.hostAllowed(host) {
+		return nil
+	}
+	for _, prefix := range p.AllowedPrefixes {
+		if prefix.Contains(addr) {
+			return nil
+		}
+	}
+	for _, prefix := range p.Blocked {
+		if prefix.Contains(addr) {
+			if host != addr.String() {
+				return blocked("host %q resolves to %s, which is in blocked range %s", host, addr, prefix)
+			}
+			return blocked("address %s is in blocked range %s", addr, prefix)
+		}
+	}
+	return nil
+}
+
+func (p Policy) checkPort(port string) error {
+	if len(p.Ports) == 0 {
+		return nil
+	}
+	n, err := strconv.Atoi(port)
+	if err != nil || !slices.Contains(p.Ports, n) {
+		return blocked("port %s is not allowed", port)
+	}
+	return nil
+}
+
+func (p Policy) hostAllowed(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	for _, allowed := range p.AllowedHosts {
+		if host == allowed || (strings.HasPrefix(allowed, ".") && strings.HasSuffix(host, allowed)) {
+			return true
+		}
+	}
+	return false
+}
+
+// DialContext dials like dialer but refuses connections to blocked addresses.
+// The check runs on the address actually connected to, after DNS resolution.
+func (p Policy) DialContext(dialer *net.Dialer) func(ctx context.Context, network, address string) (net.Conn, error) {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.checkPort(port); err != nil {
+			return nil, err
+		}
+
+		d := *dialer
+		d.Control = func(_, resolved string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(resolved)
+			if err != nil {
+				return blocked("unexpected address %q", resolved)
+			}
+			return p.CheckAddr(host, addrPort.Addr())
+		}
+		return d.DialContext(ctx, network, address)
+	}
+}
+
+// Client returns an HTTP client that enforces the policy on every connection
+// and redirect. Proxies from the environment are not used, since they would
+// hide the destination from the dial time check.
+func (p Policy) Client(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: 10 * time.Second, KeepAlive: 30 * time.Second}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:           p.DialContext(dialer),
+			ForceAttemptHTTP2:     true,
+			MaxIdleConns:          100,
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ExpectContinueTimeout: 1 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) > p.MaxRedirects {
+				return blocked("stopped after %d redirects", p.MaxRedirects)
+			}
+			return p.CheckURL(req.Context(), req.URL.String())
+		},
+	}
+}
@@ -0,0 +1,23 @@
+package webhook_event_model
+
+import (
+	campaign_entity "github.com/Astervia/wacraft-core/src/campaign/entity"
+	campaign_model "github.com/Astervia/wacraft-core/src/campaign/model"
+	phone_config_entity "github.com/Astervia/wacraft-core/src/phone-config/entity"
+)
+
+// CampaignData is the payload of the campaign events.
+type CampaignData struct {
+	Campaign campaign_entity.Campaign        `json:"campaign"`
+	Results  *campaign_model.CampaignResults `json:"results,omitempty"` // Set when the campaign finished or failed.
+	Error    string                          `json:"error,omitempty"`   // Set when the campaign failed.
+}
+
+// PhoneConfigData returns the phone config without its credentials, which
+// must never leave the server.
+func PhoneConfigData(phoneConfig phone_config_entity.PhoneConfig) phone_config_entity.PhoneConfig {
+	phoneConfig.AccessToken = ""
+	phoneConfig.MetaAppSecret = ""
+	phoneConfig.WebhookVerifyToken = ""
+	return phoneConfig
+}
@@ -0,0 +1,121 @@
+// Package webhook_event_model is the catalogue of outbound webhook events and
+// the versioned envelope their payloads are delivered in.
+package webhook_event_model
+
+import (
+	"time"
+
+	webhook_model "github.com/Astervia/wacraft-core/src/webhook/model"
+	wh_model "github.com/Rfluid/whatsapp-cloud-api/src/webhook"
+	"github.com/google/uuid"
+)
+
+// EnvelopeVersion is the version of the Envelope layout. It changes only when
+// the envelope or an event payload changes incompatibly.
+const EnvelopeVersion = 1
+
+// Events delivered in an Envelope. Webhooks subscribe to them by setting
+// "event" to one of these names.
+const (
+	MessageStatusSent      = "message.status.sent"
+	MessageStatusDelivered = "message.status.delivered"
+	MessageStatusRead      = "message.status.read"
+	MessageStatusFailed    = "message.status.failed"
+	MessageReaction        = "message.reaction"
+	ContactCreated         = "contact.created"
+	ContactBlocked         = "contact.blocked"
+	ContactUnblocked       = "contact.unblocked"
+	CampaignStarted        = "campaign.started"
+	CampaignCompleted      = "campaign.completed"
+	CampaignFailed         = "campaign.failed"
+	PhoneConfigUpdated     = "phone_config.updated"
+
+	TemplateStatusUpdated     = "template.status_updated"
+	PhoneNumberQualityUpdated = "phone_number.quality_updated"
+	AccountUpdated            = "account.updated"
+	AccountAlert              = "account.alert"
+	AccountCapabilityUpdated  = "account.capability_updated"
+)
+
+// Envelope wraps the payload of every catalogue event.
+type Envelope struct {
+	ID          uuid.UUID  `json:"id"` // Unique per event; repeated deliveries of the same event share it.
+	Event       string     `json:"event"`
+	Version     int        `json:"version"`
+	OccurredAt  time.Time  `json:"occurred_at"`
+	WorkspaceID *uuid.UUID `json:"workspace_id,omitempty"`
+	Data        any        `json:"data"`
+}
+
+// NewEnvelope wraps data as a new occurrence of event.
+func NewEnvelope(event string, workspaceID *uuid.UUID, data any) Envelope {
+	return Envelope{
+		ID:          uuid.New(),
+		Event:       event,
+		Version:     EnvelopeVersion,
+		OccurredAt:  time.Now().UTC(),
+		WorkspaceID: workspaceID,
+		Data:        data,
+	}
+}
+
+// MessageStatusEvent returns the event of a WhatsApp status update, e.g.
+// "message.status.read" for "read". Returns false for statuses outside the
+// catalogue.
+func MessageStatusEvent(status string) (string, bool) {
+	switch event := "message.status." + status; event {
+	case MessageStatusSent, MessageStatusDelivered, MessageStatusRead, MessageStatusFailed:
+		return event, true
+	}
+	return "", false
+}
+
+// AccountUpdateEvent returns the event of a change of an account level
+// webhook field, e.g. "template.status_updated" for
+// message_template_status_update. Returns false for other fields.
+func AccountUpdateEvent(field wh_model.Field) (string, bool) {
+	switch field {
+	case wh_model.MessageTemplateStatusUpdate:
+		return TemplateStatusUpdated, true
+	case wh_model.PhoneNumberQualityUpdate:
+		return PhoneNumberQualityUpdated, true
+	case wh_model.AccountUpdate:
+		return AccountUpdated, true
+	case wh_model.AccountAlerts:
+		return AccountAlert, true
+	case wh_model.BusinessCapabilityUpdate:
+		return AccountCapabilityUpdated, true
+	}
+	return "", false
+}
+
+// EventDescription documents an event of the catalogue.
+type EventDescription struct {
+	Event       string `json:"event"`
+	Description string `json:"description"`
+	Data        string `json:"data"`      // Type of the payload, or of Envelope.data when enveloped.
+	Enveloped   bool   `json:"enveloped"` // False for the legacy events, whose payload is sent as is.
+}
+
+// Catalogue lists every outbound webhook event.
+var Catalogue = []EventDescription{
+	{string(webhook_model.ReceiveWhatsAppMessage), "A WhatsApp message was received.", "message_thread_model.ThreadedMessage", false},
+	{string(webhook_model.SendWhatsAppMessage), "A WhatsApp message was sent.", "message_thread_model.ThreadedMessage", false},
+	{MessageStatusSent, "Meta accepted an outbound message.", "status_entity.Status", true},
+	{MessageStatusDelivered, "An outbound message was delivered to the contact's device.", "status_entity.Status", true},
+	{MessageStatusRead, "The contact read an outbound message.", "status_entity.Status", true},
+	{MessageStatusFailed, "An outbound message could not be sent or delivered; errors are in product_data.errors.", "status_entity.Status", true},
+	{MessageReaction, "A reaction was added, changed or removed on a message.", "message_reaction_model.ReactionEvent", true},
+	{ContactCreated, "A contact was created, manually or by its first inbound message.", "contact_entity.Contact", true},
+	{ContactBlocked, "A messaging product contact was blocked.", "messaging_product_entity.MessagingProductContact", true},
+	{ContactUnblocked, "A messaging product contact was unblocked.", "messaging_product_entity.MessagingProductContact", true},
+	{CampaignStarted, "A campaign started sending.", "webhook_event_model.CampaignData", true},
+	{CampaignCompleted, "A campaign finished sending.", "webhook_event_model.CampaignData", true},
+	{CampaignFailed, "A campaign stopped because of an error.", "webhook_event_model.CampaignData", true},
+	{PhoneConfigUpdated, "A phone config was updated. Credentials are never included.", "phone_config_entity.PhoneConfig", true},
+	{TemplateStatusUpdated, "Meta approved, rejected, paused or disabled a message template; the reason is in value.reason.", "account_update_entity.AccountUpdate", true},
+	{PhoneNumberQualityUpdated, "The quality or messaging limit tier of a phone number changed.", "account_update_entity.AccountUpdate", true},
+	{AccountUpdated, "The WABA was verified, restricted, banned or flagged for a policy violation.", "account_update_entity.AccountUpdate", true},
+	{AccountAlert, "Meta raised an alert about the WABA, a phone number or the business.", "account_update_entity.AccountUpdate", true},
+	{AccountCapabilityUpdated, "The conversation or phone number limits of the business changed.", "account_update_entity.AccountUpdate", true},
+}
@@ -0,0 +1,128 @@
+package webhook_event_model
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	wh_model "github.com/Rfluid/whatsapp-cloud-api/src/webhook"
+	"github.com/google/uuid"
+)
+
+func TestMessageStatusEvent(t *testing.T) {
+	cases := map[string]string{
+		"sent":      MessageStatusSent,
+		"delivered": MessageStatusDelivered,
+		"read":      MessageStatusRead,
+		"failed":    MessageStatusFailed,
+	}
+	for status, want := range cases {
+		if got, ok := MessageStatusEvent(status); !ok || got != want {
+			t.Errorf("%s: expected %s, got %q (ok=%v)", status, want, got, ok)
+		}
+	}
+
+	for _, status := range []string{"", "deleted", "warning"} {
+		if got, ok := MessageStatusEvent(status); ok {
+			t.Errorf("%q: expected no event, got %s", status, got)
+		}
+	}
+}
+
+func TestAccountUpdateEvent(t *testing.T) {
+	cases := map[wh_model.Field]string{
+		wh_model.MessageTemplateStatusUpdate: TemplateStatusUpdated,
+		wh_model.PhoneNumberQualityUpdate:    PhoneNumberQualityUpdated,
+		wh_model.AccountUpdate:               AccountUpdated,
+		wh_model.AccountAlerts:               AccountAlert,
+		wh_model.BusinessCapabilityUpdate:    AccountCapabilityUpdated,
+	}
+	for field, want := range cases {
+		if got, ok := AccountUpdateEvent(field); !ok || got != want {
+			t.Errorf("%s: expected %s, got %q (ok=%v)", field, want, got, ok)
+		}
+	}
+
+	if got, ok := AccountUpdateEvent(wh_model.Messages); ok {
+		t.Errorf("messages: expected no event, got %s", got)
+	}
+}
+
+func TestCatalogueListsEveryEventOnce(t *testing.T) {
+	events := []string{
+		MessageStatusSent, MessageStatusDelivered, MessageStatusRead, MessageStatusFailed,
+		MessageReaction, ContactCreated, ContactBlocked, ContactUnblocked,
+		CampaignStarted, CampaignCompleted, CampaignFailed, PhoneConfigUpdated,
+		TemplateStatusUpdated, PhoneNumberQualityUpdated, AccountUpdated, AccountAlert, AccountCapabilityUpdated,
+	}
+
+	listed := make(map[string]EventDescription, len(Catalogue))
+	for _, description := range Catalogue {
+		if _, ok := listed[description.Event]; ok {
+			t.Errorf("%s: listed twice", description.Event)
+		}
+		if description.Description == "" || description.Data == "" {
+			t.Errorf("%s: missing description or data type", description.Event)
+		}
+		listed[description.Event] = description
+	}
+
+	for _, event := range events {
+		description, ok := listed[event]
+		if !ok {
+			t.Errorf("%s: missing from the catalogue", event)
+			continue
+		}
+		if !description.Enveloped {
+			t.Errorf("%s: expected to be enveloped", event)
+		}
+	}
+}
+
+func TestNewEnvelope(t *testing.T) {
+	workspaceID := uuid.New()
+	before := time.Now().UTC()
+	envelope := NewEnvelope(ContactCreated, &workspaceID, map[string]string{"name": "Ada"})
+
+	if envelope.ID == uuid.Nil {
+		t.Error("expected an envelope ID")
+	}
+	if envelope.Event != ContactCreated || envelope.Version != EnvelopeVersion {
+		t.Errorf("expected %s v%d, got %s v%d", ContactCreated, EnvelopeVersion, envelope.Event, envelope.Version)
+	}
+	if envelope.OccurredAt.Before(before) || envelope.OccurredAt.Location() != time.UTC {
+		t.Errorf("expected occurred_at in UTC after %s, got %s", before, envelope.OccurredAt)
+	}
+	if other := NewEnvelope(ContactCreated, &workspaceID, nil); other.ID == envelope.ID {
+		t.Error("expected a new ID per event")
+	}
+
+	var payload map[string]any
+	body, err := json.Marshal(envelope)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"id", "event", "version", "occurred_at", "workspace_id", "data"} {
+		if _, ok := payload[key]; !ok {
+			t.Errorf("expected %q in the envelope, got %s", key, body)
+		}
+	}
+	if payload["workspace_id"] != workspaceID.String() {
+		t.Errorf("expected workspace_id %s, got %v", workspaceID, payload["workspace_id"])
+	}
+
+	body, err = json.Marshal(NewEnvelope(ContactCreated, nil, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload = nil
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := payload["workspace_id"]; ok {
+		t.Errorf("expected no workspace_id without workspace, got %s", body)
+	}
+}
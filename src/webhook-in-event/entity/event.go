@@ -0,0 +1,45 @@
+package webhook_in_event_entity
+
+import (
+	"time"
+
+	common_model "github.com/Astervia/wacraft-core/src/common/model"
+	phone_config_entity "github.com/Astervia/wacraft-core/src/phone-config/entity"
+	"github.com/google/uuid"
+)
+
+// EventStatus is the processing state of an inbound webhook event.
+type EventStatus string
+
+const (
+	// EventReceived events are stored and waiting to be processed.
+	EventReceived EventStatus = "received"
+	// EventProcessing events were claimed by a worker. Received and
+	// processing events that stop changing were interrupted, e.g. by a
+	// crash, and can be replayed.
+	EventProcessing EventStatus = "processing"
+	// EventProcessed events were handled by every change handler.
+	EventProcessed EventStatus = "processed"
+	// EventFailed events returned an error from a change handler and can be replayed.
+	EventFailed EventStatus = "failed"
+)
+
+// WebhookInEvent is the raw body of a verified webhook Meta posted to
+// /webhook-in/:waba_id, stored before it is processed so a failure never
+// loses the payload. Meta retries a webhook with the same body, so retries
+// update the event of the first delivery instead of adding one.
+type WebhookInEvent struct {
+	PhoneConfigID uuid.UUID                        `json:"phone_config_id" gorm:"type:uuid;not null;uniqueIndex:idx_webhook_in_events_body"`
+	PhoneConfig   *phone_config_entity.PhoneConfig `json:"-" gorm:"foreignKey:PhoneConfigID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	WorkspaceID   *uuid.UUID                       `json:"workspace_id,omitempty" gorm:"type:uuid;index"`
+	WabaID        string                           `json:"waba_id" gorm:"type:varchar(255);not null"`
+	Headers       map[string][]string              `json:"headers" gorm:"type:jsonb;serializer:json"`
+	Body          string                           `json:"body" gorm:"type:text;not null"`
+	BodyDigest    string                           `json:"-" gorm:"type:char(64);not null;uniqueIndex:idx_webhook_in_events_body"`
+	Status        EventStatus                      `json:"status" gorm:"type:varchar(20);not null;default:'received';index"`
+	Attempts      int                              `json:"attempts" gorm:"not null;default:0"`
+	LastError     string                           `json:"last_error,omitempty" gorm:"type:text"`
+	ProcessedAt   *time.Time                       `json:"processed_at,omitempty"`
+
+	common_model.Audit
+}
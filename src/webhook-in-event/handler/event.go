@@ -0,0 +1,138 @@
+package webhook_in_event_handler
+
+import (
+	"errors"
+
+	common_model "github.com/Astervia/wacraft-core/src/common/model"
+	"github.com/Astervia/wacraft-server/src/validators"
+	webhook_in_event_service "github.com/Astervia/wacraft-server/src/webhook-in-event/service"
+	webhook_config "github.com/Astervia/wacraft-server/src/webhook-in/config"
+	workspace_middleware "github.com/Astervia/wacraft-server/src/workspace/middleware"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GetEvents returns the webhooks Meta sent to the phone configs of the workspace.
+//
+//	@Summary		Get inbound webhook events
+//	@Description	Lists the raw webhooks received at /webhook-in/:waba_id for the phone configs of the workspace, newest first, with their headers, processing status and last error. Filter by phone config, status (received, processing, processed, failed) and creation time.
+//	@Tags			Webhook in event
+//	@Produce		json
+//	@Param			query	query		webhook_in_event_service.EventQuery			true	"Filters and pagination"
+//	@Success		200		{array}		webhook_in_event_entity.WebhookInEvent		"Events"
+//	@Failure		400		{object}	common_model.DescriptiveError				"Invalid query parameters"
+//	@Failure		500		{object}	common_model.DescriptiveError				"Failed to get events"
+//	@Router			/webhook-in-event [get]
+//	@Security		ApiKeyAuth
+//	@Security		WorkspaceAuth
+func GetEvents(c *fiber.Ctx) error {
+	query := new(webhook_in_event_service.EventQuery)
+	if err := c.QueryParser(query); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewParseJsonError(err).Send(),
+		)
+	}
+
+	if err := validators.Validator().Struct(query); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewValidationError(err).Send(),
+		)
+	}
+
+	workspace := workspace_middleware.GetWorkspace(c)
+
+	events, err := webhook_in_event_service.GetEvents(*query, workspace.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			common_model.NewApiError("unable to get webhook events", err, "webhook_in_event_service").Send(),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(events)
+}
+
+// ReplayEvent processes a failed inbound webhook again.
+//
+//	@Summary		Replay inbound webhook event
+//	@Description	Runs a failed event, or one interrupted while being processed, through the webhook-in change handlers again. The returned event holds the new status and error.
+//	@Tags			Webhook in event
+//	@Produce		json
+//	@Param			id	path		string									true	"Event ID"
+//	@Success		200	{object}	webhook_in_event_entity.WebhookInEvent	"Replayed event"
+//	@Failure		400	{object}	common_model.DescriptiveError			"Invalid event ID"
+//	@Failure		404	{object}	common_model.DescriptiveError			"Event not found"
+//	@Failure		409	{object}	common_model.DescriptiveError			"Event is processed or being processed"
+//	@Failure		500	{object}	common_model.DescriptiveError			"Failed to replay the event"
+//	@Router			/webhook-in-event/{id}/replay [post]
+//	@Security		ApiKeyAuth
+//	@Security		WorkspaceAuth
+func ReplayEvent(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewApiError("unable to parse event id string to UUID", err, "github.com/google/uuid").Send(),
+		)
+	}
+
+	workspace := workspace_middleware.GetWorkspace(c)
+
+	event, err := webhook_in_event_service.Replay(c, &webhook_config.WabaHook, id, workspace.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(
+			common_model.NewApiError("event not found", err, "webhook_in_event_service").Send(),
+		)
+	}
+	if errors.Is(err, webhook_in_event_service.ErrEventNotReplayable) {
+		return c.Status(fiber.StatusConflict).JSON(
+			common_model.NewApiError(err.Error(), err, "webhook_in_event_service").Send(),
+		)
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			common_model.NewApiError("unable to replay webhook event", err, "webhook_in_event_service").Send(),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(event)
+}
+
+// ReplayEvents processes the failed inbound webhooks again.
+//
+//	@Summary		Replay inbound webhook events
+//	@Description	Runs the failed and interrupted events of the workspace received within the optional window through the webhook-in change handlers again, oldest first and one at a time. At most limit events (default 100) are replayed per request.
+//	@Tags			Webhook in event
+//	@Accept			json
+//	@Produce		json
+//	@Param			window	body		webhook_in_event_service.ReplayWindow	true	"Phone config, time window and limit"
+//	@Success		200		{object}	webhook_in_event_service.ReplayResult	"Number of events replayed"
+//	@Failure		400		{object}	common_model.DescriptiveError			"Invalid request body"
+//	@Failure		500		{object}	common_model.DescriptiveError			"Failed to replay events"
+//	@Router			/webhook-in-event/replay [post]
+//	@Security		ApiKeyAuth
+//	@Security		WorkspaceAuth
+func ReplayEvents(c *fiber.Ctx) error {
+	var window webhook_in_event_service.ReplayWindow
+	if err := c.BodyParser(&window); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewParseJsonError(err).Send(),
+		)
+	}
+
+	if err := validators.Validator().Struct(&window); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewValidationError(err).Send(),
+		)
+	}
+
+	workspace := workspace_middleware.GetWorkspace(c)
+
+	result, err := webhook_in_event_service.ReplayEvents(c, &webhook_config.WabaHook, window, workspace.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			common_model.NewApiError("unable to replay webhook events", err, "webhook_in_event_service").Send(),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(result)
+}
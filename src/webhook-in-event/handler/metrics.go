@@ -0,0 +1,29 @@
+package webhook_in_event_handler
+
+import (
+	common_model "github.com/Astervia/wacraft-core/src/common/model"
+	webhook_in_event_service "github.com/Astervia/wacraft-server/src/webhook-in-event/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetMetrics returns the state of the queue of webhooks waiting to be processed.
+//
+//	@Summary		Get inbound webhook queue metrics (Admin only)
+//	@Description	Returns the worker pool size, queue capacity and depth, the events being processed and the counters of this instance, and the number and age of the events of every instance waiting to be processed. A growing backlog or rejected count means webhooks arrive faster than they are processed.
+//	@Tags			Webhook in event
+//	@Produce		json
+//	@Success		200	{object}	webhook_in_event_service.QueueMetrics	"Queue metrics"
+//	@Failure		403	{object}	common_model.DescriptiveError			"Forbidden - Admin role required"
+//	@Failure		500	{object}	common_model.DescriptiveError			"Failed to get metrics"
+//	@Router			/webhook-in-event/metrics [get]
+//	@Security		ApiKeyAuth
+func GetMetrics(c *fiber.Ctx) error {
+	metrics, err := webhook_in_event_service.GetMetrics()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			common_model.NewApiError("unable to get webhook-in metrics", err, "webhook_in_event_service").Send(),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(metrics)
+}
@@ -0,0 +1,72 @@
+package webhook_in_event_middleware
+
+import (
+	"errors"
+
+	phone_config_entity "github.com/Astervia/wacraft-core/src/phone-config/entity"
+	"github.com/Astervia/wacraft-server/src/config/env"
+	webhook_in_event_entity "github.com/Astervia/wacraft-server/src/webhook-in-event/entity"
+	webhook_in_event_service "github.com/Astervia/wacraft-server/src/webhook-in-event/service"
+	"github.com/gofiber/fiber/v2"
+	"github.com/pterm/pterm"
+)
+
+// ArchiveMiddleware stores the raw webhook with its headers before the change
+// handlers run. It must run after the Meta signature is verified so only
+// webhooks sent by Meta are stored.
+//
+// With env.WebhookInAsync the stored webhook is queued for the inbound
+// workers and acknowledged right away. Otherwise the change handlers run
+// before the response and their outcome is recorded on the event.
+func ArchiveMiddleware(phoneConfigCtxKey string) func(*fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		phoneConfig, ok := c.Locals(phoneConfigCtxKey).(*phone_config_entity.PhoneConfig)
+		if !ok || phoneConfig == nil {
+			return c.Next()
+		}
+
+		event, skip, err := webhook_in_event_service.Archive(*phoneConfig, c.GetReqHeaders(), c.Body())
+		if err != nil {
+			pterm.DefaultLogger.Error("Unable to archive webhook for waba_id " + phoneConfig.WabaID + ": " + err.Error())
+			// Not acknowledging the webhook makes Meta send it again.
+			return c.Status(fiber.StatusInternalServerError).SendString("unable to archive webhook")
+		}
+
+		if skip {
+			// Already processed or being processed, e.g. a retry of Meta.
+			return c.SendStatus(fiber.StatusOK)
+		}
+
+		if env.WebhookInAsync {
+			return enqueue(c, event)
+		}
+
+		err = c.Next()
+
+		// The webhook handler reports processing errors in the response.
+		processErr := err
+		if processErr == nil && c.Response().StatusCode() >= fiber.StatusBadRequest {
+			processErr = errors.New(string(c.Response().Body()))
+		}
+		if recordErr := webhook_in_event_service.RecordOutcome(&event, processErr); recordErr != nil {
+			pterm.DefaultLogger.Error("Unable to record outcome of webhook event " + event.ID.String() + ": " + recordErr.Error())
+		}
+
+		return err
+	}
+}
+
+// enqueue queues the stored event and acknowledges the webhook. An event
+// queued twice is processed once.
+func enqueue(c *fiber.Ctx, event webhook_in_event_entity.WebhookInEvent) error {
+	err := webhook_in_event_service.Enqueue(event)
+	if errors.Is(err, webhook_in_event_service.ErrQueueFull) {
+		pterm.DefaultLogger.Warn("Webhook-in queue is full, asking Meta to retry event " + event.ID.String())
+		return c.Status(fiber.StatusServiceUnavailable).SendString(err.Error())
+	}
+	if err != nil {
+		// The event is stored and queued again once it is interrupted.
+		pterm.DefaultLogger.Error("Unable to queue webhook event " + event.ID.String() + ": " + err.Error())
+	}
+	return c.SendStatus(fiber.StatusOK)
+}
@@ -0,0 +1,41 @@
+package webhook_in_event_router
+
+import (
+	workspace_model "github.com/Astervia/wacraft-core/src/workspace/model"
+	auth_middleware "github.com/Astervia/wacraft-server/src/auth/middleware"
+	billing_middleware "github.com/Astervia/wacraft-server/src/billing/middleware"
+	webhook_in_event_handler "github.com/Astervia/wacraft-server/src/webhook-in-event/handler"
+	workspace_middleware "github.com/Astervia/wacraft-server/src/workspace/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+func Route(app *fiber.App) {
+	group := app.Group("/webhook-in-event")
+
+	group.Get("/",
+		auth_middleware.UserMiddleware,
+		auth_middleware.EmailVerifiedMiddleware,
+		workspace_middleware.WorkspaceMiddleware,
+		workspace_middleware.RequirePolicy(workspace_model.PolicyPhoneConfigRead),
+		billing_middleware.ThroughputMiddleware,
+		webhook_in_event_handler.GetEvents)
+	group.Get("/metrics",
+		auth_middleware.UserMiddleware,
+		auth_middleware.SuMiddleware,
+		billing_middleware.ThroughputMiddleware,
+		webhook_in_event_handler.GetMetrics)
+	group.Post("/replay",
+		auth_middleware.UserMiddleware,
+		auth_middleware.EmailVerifiedMiddleware,
+		workspace_middleware.WorkspaceMiddleware,
+		workspace_middleware.RequirePolicy(workspace_model.PolicyPhoneConfigManage),
+		billing_middleware.ThroughputMiddleware,
+		webhook_in_event_handler.ReplayEvents)
+	group.Post("/:id/replay",
+		auth_middleware.UserMiddleware,
+		auth_middleware.EmailVerifiedMiddleware,
+		workspace_middleware.WorkspaceMiddleware,
+		workspace_middleware.RequirePolicy(workspace_model.PolicyPhoneConfigManage),
+		billing_middleware.ThroughputMiddleware,
+		webhook_in_event_handler.ReplayEvent)
+}
@@ -0,0 +1,86 @@
+package webhook_in_event_service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	phone_config_entity "github.com/Astervia/wacraft-core/src/phone-config/entity"
+	"github.com/Astervia/wacraft-server/src/database"
+	webhook_in_event_entity "github.com/Astervia/wacraft-server/src/webhook-in-event/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Archive stores the raw body of a webhook received for the phone config
+// before it is processed. A body that was already stored, e.g. because Meta
+// retried it, reuses its event so the event keeps the latest outcome.
+//
+// Retries of failed, received and interrupted events are stored as received
+// again. skip is true when the body was already processed or is being
+// processed; the stored event is then left as is and the webhook only needs
+// to be acknowledged.
+func Archive(phoneConfig phone_config_entity.PhoneConfig, headers map[string][]string, body []byte) (event webhook_in_event_entity.WebhookInEvent, skip bool, err error) {
+	digest := sha256.Sum256(body)
+	event = webhook_in_event_entity.WebhookInEvent{
+		PhoneConfigID: phoneConfig.ID,
+		WorkspaceID:   phoneConfig.WorkspaceID,
+		WabaID:        phoneConfig.WabaID,
+		Headers:       headers,
+		Body:          string(body),
+		BodyDigest:    hex.EncodeToString(digest[:]),
+		Status:        webhook_in_event_entity.EventReceived,
+	}
+
+	// A single upsert keeps concurrent retries of the body from conflicting.
+	result := database.DB.Clauses(
+		clause.OnConflict{
+			Columns: []clause.Column{{Name: "phone_config_id"}, {Name: "body_digest"}},
+			DoUpdates: clause.Assignments(map[string]any{
+				"headers":    gorm.Expr("EXCLUDED.headers"),
+				"status":     webhook_in_event_entity.EventReceived,
+				"updated_at": gorm.Expr("NOW()"),
+			}),
+			Where: clause.Where{Exprs: []clause.Expression{gorm.Expr(
+				"webhook_in_events.status IN ? OR (webhook_in_events.status = ? AND webhook_in_events.updated_at < ?)",
+				[]webhook_in_event_entity.EventStatus{webhook_in_event_entity.EventReceived, webhook_in_event_entity.EventFailed},
+				webhook_in_event_entity.EventProcessing,
+				time.Now().Add(-interruptedAfter),
+			)}},
+		},
+		clause.Returning{},
+	).Create(&event)
+	if result.Error != nil || result.RowsAffected > 0 {
+		return event, false, result.Error
+	}
+
+	// The stored event was processed or is being processed.
+	var stored webhook_in_event_entity.WebhookInEvent
+	err = database.DB.
+		Where("phone_config_id = ? AND body_digest = ?", event.PhoneConfigID, event.BodyDigest).
+		First(&stored).Error
+	return stored, true, err
+}
+
+// RecordOutcome stores the result of processing the event. A nil processErr
+// marks the event processed; otherwise it failed with processErr.
+func RecordOutcome(event *webhook_in_event_entity.WebhookInEvent, processErr error) error {
+	now := time.Now()
+	event.Attempts++
+	if processErr == nil {
+		event.Status = webhook_in_event_entity.EventProcessed
+		event.LastError = ""
+		event.ProcessedAt = &now
+	} else {
+		event.Status = webhook_in_event_entity.EventFailed
+		event.LastError = processErr.Error()
+	}
+
+	return database.DB.Model(event).Updates(map[string]any{
+		"status":       event.Status,
+		"attempts":     gorm.Expr("attempts + 1"),
+		"last_error":   event.LastError,
+		"processed_at": event.ProcessedAt,
+		"updated_at":   now,
+	}).Error
+}
@@ -0,0 +1,221 @@
+package webhook_in_event_service
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	phone_config_entity "github.com/Astervia/wacraft-core/src/phone-config/entity"
+	"github.com/Astervia/wacraft-server/src/database"
+	database_fixture "github.com/Astervia/wacraft-server/src/database/fixture"
+	webhook_in_event_entity "github.com/Astervia/wacraft-server/src/webhook-in-event/entity"
+	"github.com/google/uuid"
+)
+
+// --- Test bootstrap ---
+
+func TestMain(m *testing.M) {
+	database.DB.AutoMigrate(&webhook_in_event_entity.WebhookInEvent{})
+	// Test events use random phone config IDs.
+	database_fixture.DropForeignKeys("webhook_in_events", "fk_webhook_in_events_phone_config")
+	os.Exit(m.Run())
+}
+
+// --- Helpers ---
+
+// newTestPhoneConfig returns a phone config of a new workspace whose events
+// are deleted after the test. It is not stored.
+func newTestPhoneConfig(t *testing.T) phone_config_entity.PhoneConfig {
+	t.Helper()
+	workspaceID := uuid.New()
+	phoneConfig := phone_config_entity.PhoneConfig{WabaID: "waba-" + uuid.NewString()}
+	phoneConfig.ID = uuid.New()
+	phoneConfig.WorkspaceID = &workspaceID
+	t.Cleanup(func() {
+		database.DB.Exec("DELETE FROM webhook_in_events WHERE phone_config_id = ?", phoneConfig.ID)
+	})
+	return phoneConfig
+}
+
+func archive(t *testing.T, phoneConfig phone_config_entity.PhoneConfig, body string) (webhook_in_event_entity.WebhookInEvent, bool) {
+	t.Helper()
+	event, skip, err := Archive(phoneConfig, map[string][]string{"X-Test": {"1"}}, []byte(body))
+	if err != nil {
+		t.Fatalf("Archive: %v", err)
+	}
+	return event, skip
+}
+
+// setStatus moves the event to status, last updated at updatedAt.
+func setStatus(t *testing.T, id uuid.UUID, status webhook_in_event_entity.EventStatus, updatedAt time.Time) {
+	t.Helper()
+	err := database.DB.Exec("UPDATE webhook_in_events SET status = ?, updated_at = ? WHERE id = ?", status, updatedAt, id).Error
+	if err != nil {
+		t.Fatalf("setStatus: %v", err)
+	}
+}
+
+// --- Archive tests ---
+
+func TestArchive_Retries(t *testing.T) {
+	phoneConfig := newTestPhoneConfig(t)
+	body := `{"object":"whatsapp_business_account","entry":[]}`
+
+	event, skip := archive(t, phoneConfig, body)
+	if skip || event.ID == uuid.Nil || event.Status != webhook_in_event_entity.EventReceived {
+		t.Fatalf("expected a new received event, got %s (skip=%v)", event.Status, skip)
+	}
+	if event.WabaID != phoneConfig.WabaID || *event.WorkspaceID != *phoneConfig.WorkspaceID {
+		t.Errorf("expected the event of the phone config, got waba %s", event.WabaID)
+	}
+
+	cases := []struct {
+		name      string
+		status    webhook_in_event_entity.EventStatus
+		updatedAt time.Time
+		wantSkip  bool
+	}{
+		{"received", webhook_in_event_entity.EventReceived, time.Now(), false},
+		{"failed", webhook_in_event_entity.EventFailed, time.Now(), false},
+		{"processing", webhook_in_event_entity.EventProcessing, time.Now(), true},
+		{"interrupted", webhook_in_event_entity.EventProcessing, time.Now().Add(-2 * interruptedAfter), false},
+		{"processed", webhook_in_event_entity.EventProcessed, time.Now(), true},
+	}
+	for _, c := range cases {
+		setStatus(t, event.ID, c.status, c.updatedAt)
+
+		retried, skip := archive(t, phoneConfig, body)
+		if retried.ID != event.ID {
+			t.Fatalf("%s: expected the retry to reuse event %s, got %s", c.name, event.ID, retried.ID)
+		}
+		wantStatus := webhook_in_event_entity.EventReceived
+		if c.wantSkip {
+			wantStatus = c.status
+		}
+		if skip != c.wantSkip || retried.Status != wantStatus {
+			t.Errorf("%s: expected skip=%v and %s, got skip=%v and %s", c.name, c.wantSkip, wantStatus, skip, retried.Status)
+		}
+	}
+
+	var count int64
+	database.DB.Model(&webhook_in_event_entity.WebhookInEvent{}).Where("phone_config_id = ?", phoneConfig.ID).Count(&count)
+	if count != 1 {
+		t.Errorf("expected a single event for the body, got %d", count)
+	}
+}
+
+func TestArchive_ConcurrentRetries(t *testing.T) {
+	phoneConfig := newTestPhoneConfig(t)
+	body := `{"object":"whatsapp_business_account","entry":[{"id":"concurrent"}]}`
+
+	var wg sync.WaitGroup
+	ids := make([]uuid.UUID, 10)
+	for i := range ids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			event, _, err := Archive(phoneConfig, nil, []byte(body))
+			if err != nil {
+				t.Errorf("Archive: %v", err)
+				return
+			}
+			ids[i] = event.ID
+		}()
+	}
+	wg.Wait()
+
+	for _, id := range ids {
+		if id != ids[0] {
+			t.Fatalf("expected every retry to share one event, got %v", ids)
+		}
+	}
+}
+
+// --- Outcome tests ---
+
+func TestRecordOutcome(t *testing.T) {
+	phoneConfig := newTestPhoneConfig(t)
+	event, _ := archive(t, phoneConfig, `{"entry":[{"id":"outcome"}]}`)
+
+	if err := RecordOutcome(&event, errors.New("handler failed")); err != nil {
+		t.Fatalf("RecordOutcome: %v", err)
+	}
+	var stored webhook_in_event_entity.WebhookInEvent
+	database.DB.First(&stored, "id = ?", event.ID)
+	if stored.Status != webhook_in_event_entity.EventFailed || stored.Attempts != 1 || stored.LastError != "handler failed" || stored.ProcessedAt != nil {
+		t.Fatalf("expected a failed attempt, got %s with %d attempts and error %q", stored.Status, stored.Attempts, stored.LastError)
+	}
+
+	if err := RecordOutcome(&event, nil); err != nil {
+		t.Fatalf("RecordOutcome: %v", err)
+	}
+	database.DB.First(&stored, "id = ?", event.ID)
+	if stored.Status != webhook_in_event_entity.EventProcessed || stored.Attempts != 2 || stored.LastError != "" || stored.ProcessedAt == nil {
+		t.Fatalf("expected a processed event, got %s with %d attempts and error %q", stored.Status, stored.Attempts, stored.LastError)
+	}
+	if event.Status != stored.Status || event.Attempts != stored.Attempts {
+		t.Errorf("expected the outcome on the event, got %s with %d attempts", event.Status, event.Attempts)
+	}
+}
+
+// --- Replay tests ---
+
+func TestReplay_ClaimsReplayableEventsOnly(t *testing.T) {
+	phoneConfig := newTestPhoneConfig(t)
+	old := time.Now().Add(-2 * interruptedAfter)
+
+	events := map[string]webhook_in_event_entity.WebhookInEvent{}
+	for _, c := range []struct {
+		name      string
+		status    webhook_in_event_entity.EventStatus
+		updatedAt time.Time
+	}{
+		{"failed", webhook_in_event_entity.EventFailed, time.Now()},
+		{"received", webhook_in_event_entity.EventReceived, time.Now()},
+		{"interrupted received", webhook_in_event_entity.EventReceived, old},
+		{"processing", webhook_in_event_entity.EventProcessing, time.Now()},
+		{"interrupted processing", webhook_in_event_entity.EventProcessing, old},
+		{"processed", webhook_in_event_entity.EventProcessed, old},
+	} {
+		event, _ := archive(t, phoneConfig, `{"entry":[{"id":"`+c.name+`"}]}`)
+		setStatus(t, event.ID, c.status, c.updatedAt)
+		events[c.name] = event
+	}
+
+	var ids []uuid.UUID
+	replayable(database.DB.Model(&webhook_in_event_entity.WebhookInEvent{})).
+		Where("phone_config_id = ?", phoneConfig.ID).
+		Pluck("id", &ids)
+	replayableIDs := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		replayableIDs[id] = true
+	}
+	for name, event := range events {
+		want := name == "failed" || name == "interrupted received" || name == "interrupted processing"
+		if replayableIDs[event.ID] != want {
+			t.Errorf("%s: expected replayable=%v", name, want)
+		}
+	}
+
+	// Events that are not replayable are left as is.
+	for _, name := range []string{"received", "processing", "processed"} {
+		if _, err := Replay(nil, nil, events[name].ID, *phoneConfig.WorkspaceID); !errors.Is(err, ErrEventNotReplayable) {
+			t.Errorf("%s: expected ErrEventNotReplayable, got %v", name, err)
+		}
+	}
+
+	// The phone config is not stored, so the replayed event fails again.
+	replayed, err := Replay(nil, nil, events["failed"].ID, *phoneConfig.WorkspaceID)
+	if err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	if replayed.Status != webhook_in_event_entity.EventFailed || replayed.Attempts != 1 {
+		t.Errorf("expected the replay to be recorded, got %s with %d attempts", replayed.Status, replayed.Attempts)
+	}
+
+	if _, err := Replay(nil, nil, events["failed"].ID, uuid.New()); err == nil {
+		t.Error("expected events of other workspaces not to be found")
+	}
+}
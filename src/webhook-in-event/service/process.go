@@ -0,0 +1,176 @@
+package webhook_in_event_service
+
+import (
+	"database/sql"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/Astervia/wacraft-server/src/config/env"
+	"github.com/Astervia/wacraft-server/src/database"
+	inbound_receipt_service "github.com/Astervia/wacraft-server/src/inbound-receipt/service"
+	webhook_in_event_entity "github.com/Astervia/wacraft-server/src/webhook-in-event/entity"
+	webhook_service "github.com/Rfluid/whatsapp-webhook-server/src/webhook/service"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// pendingStatuses are waiting to be processed or being processed.
+var pendingStatuses = []webhook_in_event_entity.EventStatus{
+	webhook_in_event_entity.EventReceived,
+	webhook_in_event_entity.EventProcessing,
+}
+
+// processingAssignments claim an event for processing.
+var processingAssignments = map[string]any{
+	"status":     webhook_in_event_entity.EventProcessing,
+	"updated_at": gorm.Expr("NOW()"),
+}
+
+// Counters of this instance since it started, reported by GetMetrics.
+var (
+	enqueued  atomic.Int64
+	rejected  atomic.Int64
+	processed atomic.Int64
+	failed    atomic.Int64
+	inFlight  atomic.Int64
+)
+
+// QueueMetrics describes the inbound queue, so operators can tell whether
+// webhooks are processed as fast as Meta sends them.
+type QueueMetrics struct {
+	Async    bool `json:"async"`
+	Workers  int  `json:"workers"`
+	Capacity int  `json:"capacity"`
+	// Queued events wait in the queue; InFlight events are being processed.
+	Queued   int64 `json:"queued"`
+	InFlight int64 `json:"in_flight"`
+	// Enqueued, Rejected, Processed and Failed count the events of this
+	// instance since it started. Rejected events arrived while the queue was full.
+	Enqueued  int64 `json:"enqueued"`
+	Rejected  int64 `json:"rejected"`
+	Processed int64 `json:"processed"`
+	Failed    int64 `json:"failed"`
+	// Backlog counts the received and processing events of every instance;
+	// OldestPendingAt is when the oldest of them was received.
+	Backlog         int64      `json:"backlog"`
+	OldestPendingAt *time.Time `json:"oldest_pending_at,omitempty"`
+	// Duplicates counts the messages and statuses this instance skipped
+	// because Meta delivered them again.
+	Duplicates inbound_receipt_service.Duplicates `json:"duplicates"`
+}
+
+// Enqueue hands a stored event to the inbound workers. Returns ErrQueueFull
+// when the queue holds its capacity; the event stays received and is queued
+// again once it is interrupted or when Meta retries it.
+func Enqueue(event webhook_in_event_entity.WebhookInEvent) error {
+	err := queue.Push(event.WabaID, event.ID)
+	if errors.Is(err, ErrQueueFull) {
+		rejected.Add(1)
+	} else if err == nil {
+		enqueued.Add(1)
+	}
+	return err
+}
+
+// ProcessEvent claims a received event and runs the change handlers of hook
+// on it. Events that are no longer received, e.g. because they were queued
+// twice, are skipped.
+func ProcessEvent(ctx *fiber.Ctx, hook *webhook_service.Config, id uuid.UUID) error {
+	result := database.DB.Model(&webhook_in_event_entity.WebhookInEvent{}).
+		Where("id = ? AND status = ?", id, webhook_in_event_entity.EventReceived).
+		Updates(processingAssignments)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return result.Error
+	}
+
+	var event webhook_in_event_entity.WebhookInEvent
+	if err := database.DB.First(&event, "id = ?", id).Error; err != nil {
+		return err
+	}
+
+	inFlight.Add(1)
+	defer inFlight.Add(-1)
+
+	processErr := process(ctx, hook, event)
+	if processErr == nil {
+		processed.Add(1)
+	} else {
+		failed.Add(1)
+	}
+	return RecordOutcome(&event, processErr)
+}
+
+// RequeueInterrupted queues again up to limit interrupted events, oldest
+// first, e.g. after the instance that queued or processed them crashed.
+// Returns how many events were queued.
+func RequeueInterrupted(limit int) (int, error) {
+	var events []webhook_in_event_entity.WebhookInEvent
+	err := database.DB.
+		Where("status IN ? AND updated_at < ?", pendingStatuses, time.Now().Add(-interruptedAfter)).
+		Order("created_at ASC, id ASC").
+		Limit(limit).
+		Find(&events).Error
+	if err != nil {
+		return 0, err
+	}
+
+	requeued := 0
+	for _, event := range events {
+		// The status check keeps instances from queuing the event twice.
+		result := database.DB.Model(&webhook_in_event_entity.WebhookInEvent{}).
+			Where("id = ? AND status = ? AND updated_at = ?", event.ID, event.Status, event.UpdatedAt).
+			Updates(map[string]any{
+				"status":     webhook_in_event_entity.EventReceived,
+				"updated_at": gorm.Expr("NOW()"),
+			})
+		if result.Error != nil {
+			return requeued, result.Error
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+
+		if err := Enqueue(event); err != nil {
+			return requeued, err
+		}
+		requeued++
+	}
+	return requeued, nil
+}
+
+// GetMetrics returns the state of the inbound queue.
+func GetMetrics() (QueueMetrics, error) {
+	metrics := QueueMetrics{
+		Async:     env.WebhookInAsync,
+		Workers:   env.WebhookInPoolSize,
+		Capacity:  env.WebhookInQueueCapacity,
+		InFlight:  inFlight.Load(),
+		Enqueued:  enqueued.Load(),
+		Rejected:  rejected.Load(),
+		Processed: processed.Load(),
+		Failed:    failed.Load(),
+
+		Duplicates: inbound_receipt_service.GetDuplicates(),
+	}
+
+	queued, err := queue.Depth()
+	if err != nil {
+		return metrics, err
+	}
+	metrics.Queued = queued
+
+	var oldest sql.NullTime
+	err = database.DB.Model(&webhook_in_event_entity.WebhookInEvent{}).
+		Select("COUNT(*), MIN(created_at)").
+		Where("status IN ?", pendingStatuses).
+		Row().Scan(&metrics.Backlog, &oldest)
+	if err != nil {
+		return metrics, err
+	}
+	if oldest.Valid {
+		metrics.OldestPendingAt = &oldest.Time
+	}
+	return metrics, nil
+}
@@ -0,0 +1,178 @@
+package webhook_in_event_service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	synch_contract "github.com/Astervia/wacraft-core/src/synch/contract"
+	synch_redis "github.com/Astervia/wacraft-core/src/synch/redis"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisQueue is the Queue of multi-instance deployments. The events of each
+// WABA are kept in a Redis list and the lock of the WABA keeps the instances
+// from processing them concurrently.
+//
+//	{prefix}webhook-in:waba:{wabaID} – event IDs of the WABA, oldest first
+//	{prefix}webhook-in:ready         – WABAs that may have queued events
+//	{prefix}webhook-in:depth         – number of queued events
+//
+// Events are pushed and popped by Lua scripts so the depth always matches
+// the lists, and the ready list holds a WABA at most once. A WABA whose
+// events were queued while it was owned is handed out again by Release.
+type RedisQueue struct {
+	client   *synch_redis.Client
+	lock     synch_contract.DistributedLock[string]
+	capacity int
+}
+
+// pushScript appends an event to the list of its WABA unless the queue
+// holds its capacity, and marks the WABA ready unless it already is. Returns
+// -1 when the queue is full.
+//
+// Checking the ready list rather than whether the events list was empty also
+// hands out WABAs whose owner stopped without Release, e.g. in a crash, once
+// their interrupted events are queued again.
+//
+//	KEYS: depth, events of the WABA, ready
+//	ARGV: capacity, event ID, WABA ID
+var pushScript = redis.NewScript(`
+local depth = tonumber(redis.call('GET', KEYS[1]) or '0')
+if depth >= tonumber(ARGV[1]) then
+	return -1
+end
+redis.call('INCR', KEYS[1])
+local queued = redis.call('RPUSH', KEYS[2], ARGV[2])
+if not redis.call('LPOS', KEYS[3], ARGV[3]) then
+	redis.call('RPUSH', KEYS[3], ARGV[3])
+end
+return queued
+`)
+
+// popScript removes the next event of a WABA and counts it out of the depth.
+//
+//	KEYS: events of the WABA, depth
+var popScript = redis.NewScript(`
+local eventID = redis.call('LPOP', KEYS[1])
+if eventID then
+	redis.call('DECR', KEYS[2])
+end
+return eventID
+`)
+
+// redisQueuePoll bounds how long Next waits on the ready list before
+// checking whether its context is done.
+const redisQueuePoll = time.Second
+
+// NewRedisQueue creates a Redis queue holding up to capacity events.
+func NewRedisQueue(client *synch_redis.Client, lock synch_contract.DistributedLock[string], capacity int) *RedisQueue {
+	return &RedisQueue{client: client, lock: lock, capacity: capacity}
+}
+
+func (q *RedisQueue) eventsKey(wabaID string) string {
+	return q.client.PrefixKey("webhook-in:waba:" + wabaID)
+}
+
+func (q *RedisQueue) readyKey() string {
+	return q.client.PrefixKey("webhook-in:ready")
+}
+
+func (q *RedisQueue) depthKey() string {
+	return q.client.PrefixKey("webhook-in:depth")
+}
+
+func lockKey(wabaID string) string {
+	return "webhook_in:waba:" + wabaID
+}
+
+func (q *RedisQueue) Push(wabaID string, eventID uuid.UUID) error {
+	queued, err := pushScript.Run(
+		context.Background(),
+		q.client.Redis(),
+		[]string{q.depthKey(), q.eventsKey(wabaID), q.readyKey()},
+		q.capacity, eventID.String(), wabaID,
+	).Int64()
+	if err != nil {
+		return err
+	}
+	if queued < 0 {
+		return ErrQueueFull
+	}
+	return nil
+}
+
+// Next pops WABAs from the ready list until it gets one whose lock is free.
+// A WABA whose lock is taken is skipped: its owner hands it out again on
+// Release when events were queued meanwhile.
+func (q *RedisQueue) Next(ctx context.Context) (string, error) {
+	rdb := q.client.Redis()
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+
+		result, err := rdb.BLPop(ctx, redisQueuePoll, q.readyKey()).Result()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+
+		wabaID := result[1]
+		acquired, err := q.lock.TryLock(lockKey(wabaID))
+		if err != nil {
+			// Hand the WABA out again, its events are only reached through it.
+			rdb.RPush(context.Background(), q.readyKey(), wabaID)
+			return "", err
+		}
+		if acquired {
+			return wabaID, nil
+		}
+	}
+}
+
+func (q *RedisQueue) Pop(wabaID string) (uuid.UUID, bool, error) {
+	value, err := popScript.Run(
+		context.Background(),
+		q.client.Redis(),
+		[]string{q.eventsKey(wabaID), q.depthKey()},
+	).Text()
+	if errors.Is(err, redis.Nil) {
+		return uuid.Nil, false, nil
+	}
+	if err != nil {
+		return uuid.Nil, false, err
+	}
+
+	eventID, err := uuid.Parse(value)
+	return eventID, err == nil, err
+}
+
+func (q *RedisQueue) Release(wabaID string) error {
+	ctx := context.Background()
+	rdb := q.client.Redis()
+
+	if err := q.lock.Unlock(lockKey(wabaID)); err != nil {
+		return err
+	}
+
+	// Events pushed after the owner found the list empty are handed out
+	// again here, since their ready entry may have been skipped.
+	queued, err := rdb.LLen(ctx, q.eventsKey(wabaID)).Result()
+	if err != nil || queued == 0 {
+		return err
+	}
+	return rdb.RPush(ctx, q.readyKey(), wabaID).Err()
+}
+
+func (q *RedisQueue) Depth() (int64, error) {
+	depth, err := q.client.Redis().Get(context.Background(), q.depthKey()).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	return depth, err
+}
@@ -0,0 +1,159 @@
+package webhook_in_event_service
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/Astervia/wacraft-server/src/config/env"
+	"github.com/google/uuid"
+)
+
+// ErrQueueFull is returned when an event is queued while the queue holds its capacity.
+var ErrQueueFull = errors.New("webhook-in queue is full")
+
+// Queue hands stored events to the inbound workers. Events are queued per
+// WABA and a WABA belongs to a single worker at a time, so the events of each
+// WABA are processed in the order they were received.
+type Queue interface {
+	// Push appends the event to the queue of its WABA. Returns ErrQueueFull
+	// when the queue holds its capacity.
+	Push(wabaID string, eventID uuid.UUID) error
+	// Next blocks until a WABA with queued events is free and returns it. The
+	// WABA belongs to the caller until Release.
+	Next(ctx context.Context) (string, error)
+	// Pop removes the next event of a WABA owned by the caller. ok is false
+	// when the WABA has no queued event.
+	Pop(wabaID string) (eventID uuid.UUID, ok bool, err error)
+	// Release gives the WABA back. It is handed out again if it has events.
+	Release(wabaID string) error
+	// Depth returns the number of queued events.
+	Depth() (int64, error)
+}
+
+// queue is the package-level queue. The in-memory queue is replaced during
+// init from src/synch/main.go when SYNC_BACKEND=redis.
+var queue Queue = NewMemoryQueue(env.WebhookInQueueCapacity)
+
+// SetQueue sets the queue events are handed to the workers through.
+// Called from src/synch/main.go when SYNC_BACKEND=redis.
+func SetQueue(q Queue) {
+	queue = q
+}
+
+// GetQueue returns the queue set by SetQueue, or the in-memory queue.
+func GetQueue() Queue {
+	return queue
+}
+
+// MemoryQueue is the Queue of single instance deployments.
+type MemoryQueue struct {
+	mu       sync.Mutex
+	capacity int
+	depth    int
+	events   map[string][]uuid.UUID
+	// ready holds the WABAs with queued events that no worker owns.
+	ready []string
+	owned map[string]bool
+	// wake is signalled when a WABA becomes ready. Buffered with size 1; a
+	// worker taking a WABA signals it again while others are ready.
+	wake chan struct{}
+}
+
+// NewMemoryQueue creates an in-memory queue holding up to capacity events.
+func NewMemoryQueue(capacity int) *MemoryQueue {
+	return &MemoryQueue{
+		capacity: capacity,
+		events:   make(map[string][]uuid.UUID),
+		owned:    make(map[string]bool),
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+func (q *MemoryQueue) Push(wabaID string, eventID uuid.UUID) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.depth >= q.capacity {
+		return ErrQueueFull
+	}
+	q.depth++
+
+	if len(q.events[wabaID]) == 0 && !q.owned[wabaID] {
+		q.markReady(wabaID)
+	}
+	q.events[wabaID] = append(q.events[wabaID], eventID)
+	return nil
+}
+
+func (q *MemoryQueue) Next(ctx context.Context) (string, error) {
+	for {
+		q.mu.Lock()
+		if len(q.ready) > 0 {
+			wabaID := q.ready[0]
+			q.ready = q.ready[1:]
+			q.owned[wabaID] = true
+			if len(q.ready) > 0 {
+				q.signal()
+			}
+			q.mu.Unlock()
+			return wabaID, nil
+		}
+		q.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-q.wake:
+		}
+	}
+}
+
+func (q *MemoryQueue) Pop(wabaID string) (uuid.UUID, bool, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	events := q.events[wabaID]
+	if len(events) == 0 {
+		return uuid.Nil, false, nil
+	}
+
+	eventID := events[0]
+	if len(events) == 1 {
+		delete(q.events, wabaID)
+	} else {
+		q.events[wabaID] = events[1:]
+	}
+	q.depth--
+	return eventID, true, nil
+}
+
+func (q *MemoryQueue) Release(wabaID string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	delete(q.owned, wabaID)
+	if len(q.events[wabaID]) > 0 {
+		q.markReady(wabaID)
+	}
+	return nil
+}
+
+func (q *MemoryQueue) Depth() (int64, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return int64(q.depth), nil
+}
+
+// markReady must be called with mu held.
+func (q *MemoryQueue) markReady(wabaID string) {
+	q.ready = append(q.ready, wabaID)
+	q.signal()
+}
+
+func (q *MemoryQueue) signal() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
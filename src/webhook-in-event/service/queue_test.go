@@ -0,0 +1,281 @@
+package webhook_in_event_service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	synch_redis "github.com/Astervia/wacraft-core/src/synch/redis"
+	"github.com/google/uuid"
+)
+
+// ─── helpers ────────────────────────────────────────────────────────────────
+
+func nextWithin(t *testing.T, q Queue, d time.Duration) (string, error) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	return q.Next(ctx)
+}
+
+func newTestRedisQueue(t *testing.T, capacity int) (*RedisQueue, *synch_redis.Client) {
+	t.Helper()
+	redisURL := os.Getenv("REDIS_URL")
+	if redisURL == "" {
+		t.Skip("REDIS_URL not set — skipping Redis integration test")
+	}
+
+	client, err := synch_redis.NewClient(synch_redis.Config{URL: redisURL, DB: 13, LockTTL: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("client: %v", err)
+	}
+	client.Redis().FlushDB(t.Context())
+	t.Cleanup(func() { client.Redis().FlushDB(context.Background()) })
+
+	return NewRedisQueue(client, synch_redis.NewRedisLock[string](client), capacity), client
+}
+
+// ─── Memory implementation tests ─────────────────────────────────────────────
+
+func TestMemoryQueue_PerWabaOrder(t *testing.T) {
+	q := NewMemoryQueue(10)
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	for _, id := range ids {
+		if err := q.Push("waba-1", id); err != nil {
+			t.Fatalf("Push: %v", err)
+		}
+	}
+
+	wabaID, err := nextWithin(t, q, time.Second)
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	if wabaID != "waba-1" {
+		t.Fatalf("got WABA %q, want waba-1", wabaID)
+	}
+
+	for i, want := range ids {
+		got, ok, err := q.Pop(wabaID)
+		if err != nil || !ok {
+			t.Fatalf("Pop %d: ok=%v err=%v", i, ok, err)
+		}
+		if got != want {
+			t.Errorf("Pop %d: got %v, want %v", i, got, want)
+		}
+	}
+	if _, ok, _ := q.Pop(wabaID); ok {
+		t.Error("expected no event left")
+	}
+}
+
+func TestMemoryQueue_OwnedWabaNotHandedOut(t *testing.T) {
+	q := NewMemoryQueue(10)
+	_ = q.Push("waba-1", uuid.New())
+
+	wabaID, err := nextWithin(t, q, time.Second)
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+
+	// An event pushed while the WABA is owned must wait for Release.
+	_ = q.Push("waba-1", uuid.New())
+	if _, err := nextWithin(t, q, 50*time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected owned WABA to be withheld, got err=%v", err)
+	}
+
+	if err := q.Release(wabaID); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	got, err := nextWithin(t, q, time.Second)
+	if err != nil {
+		t.Fatalf("Next after Release: %v", err)
+	}
+	if got != "waba-1" {
+		t.Errorf("got WABA %q, want waba-1", got)
+	}
+}
+
+func TestMemoryQueue_WabasHandedOutConcurrently(t *testing.T) {
+	q := NewMemoryQueue(10)
+	_ = q.Push("waba-1", uuid.New())
+	_ = q.Push("waba-2", uuid.New())
+
+	first, err := nextWithin(t, q, time.Second)
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	second, err := nextWithin(t, q, time.Second)
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	if first == second {
+		t.Errorf("got WABA %q twice", first)
+	}
+}
+
+func TestMemoryQueue_Capacity(t *testing.T) {
+	q := NewMemoryQueue(2)
+	_ = q.Push("waba-1", uuid.New())
+	_ = q.Push("waba-2", uuid.New())
+
+	if err := q.Push("waba-1", uuid.New()); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("expected ErrQueueFull, got %v", err)
+	}
+	if depth, _ := q.Depth(); depth != 2 {
+		t.Errorf("got depth %d, want 2", depth)
+	}
+
+	wabaID, _ := nextWithin(t, q, time.Second)
+	if _, ok, _ := q.Pop(wabaID); !ok {
+		t.Fatal("expected an event")
+	}
+	if err := q.Push("waba-1", uuid.New()); err != nil {
+		t.Errorf("Push after Pop: %v", err)
+	}
+}
+
+func TestMemoryQueue_NextCancelled(t *testing.T) {
+	q := NewMemoryQueue(10)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := q.Next(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+// ─── Redis implementation tests ──────────────────────────────────────────────
+
+func TestRedisQueue_PerWabaOrder(t *testing.T) {
+	q, client := newTestRedisQueue(t, 10)
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	for _, id := range ids {
+		if err := q.Push("waba-1", id); err != nil {
+			t.Fatalf("Push: %v", err)
+		}
+	}
+
+	// The WABA is marked ready once, however many events it has.
+	if ready, _ := client.Redis().LLen(t.Context(), q.readyKey()).Result(); ready != 1 {
+		t.Fatalf("got %d ready entries, want 1", ready)
+	}
+
+	wabaID, err := nextWithin(t, q, time.Second)
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	if wabaID != "waba-1" {
+		t.Fatalf("got WABA %q, want waba-1", wabaID)
+	}
+
+	for i, want := range ids {
+		got, ok, err := q.Pop(wabaID)
+		if err != nil || !ok {
+			t.Fatalf("Pop %d: ok=%v err=%v", i, ok, err)
+		}
+		if got != want {
+			t.Errorf("Pop %d: got %v, want %v", i, got, want)
+		}
+	}
+	if _, ok, err := q.Pop(wabaID); ok || err != nil {
+		t.Errorf("expected no event left, got ok=%v err=%v", ok, err)
+	}
+	if err := q.Release(wabaID); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	if ready, _ := client.Redis().LLen(t.Context(), q.readyKey()).Result(); ready != 0 {
+		t.Errorf("got %d ready entries after draining the WABA, want 0", ready)
+	}
+}
+
+func TestRedisQueue_OwnedWabaHandedOutOnRelease(t *testing.T) {
+	q, _ := newTestRedisQueue(t, 10)
+	_ = q.Push("waba-1", uuid.New())
+
+	wabaID, err := nextWithin(t, q, time.Second)
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	if _, ok, _ := q.Pop(wabaID); !ok {
+		t.Fatal("expected an event")
+	}
+
+	// An event pushed while the WABA is owned must wait for Release.
+	_ = q.Push("waba-1", uuid.New())
+	if got, err := nextWithin(t, q, 200*time.Millisecond); err == nil {
+		t.Fatalf("expected owned WABA to be withheld, got %q", got)
+	}
+
+	if err := q.Release(wabaID); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	got, err := nextWithin(t, q, time.Second)
+	if err != nil {
+		t.Fatalf("Next after Release: %v", err)
+	}
+	if got != "waba-1" {
+		t.Errorf("got WABA %q, want waba-1", got)
+	}
+}
+
+func TestRedisQueue_Capacity(t *testing.T) {
+	q, _ := newTestRedisQueue(t, 2)
+	_ = q.Push("waba-1", uuid.New())
+	_ = q.Push("waba-2", uuid.New())
+
+	if err := q.Push("waba-1", uuid.New()); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("expected ErrQueueFull, got %v", err)
+	}
+	if depth, _ := q.Depth(); depth != 2 {
+		t.Errorf("got depth %d, want 2", depth)
+	}
+
+	wabaID, _ := nextWithin(t, q, time.Second)
+	if _, ok, _ := q.Pop(wabaID); !ok {
+		t.Fatal("expected an event")
+	}
+	if depth, _ := q.Depth(); depth != 1 {
+		t.Errorf("got depth %d after Pop, want 1", depth)
+	}
+	if err := q.Push("waba-1", uuid.New()); err != nil {
+		t.Errorf("Push after Pop: %v", err)
+	}
+}
+
+func TestRedisQueue_ConcurrentPushKeepsDepth(t *testing.T) {
+	const capacity = 50
+	q, client := newTestRedisQueue(t, capacity)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	pushed := 0
+	for range 2 * capacity {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := q.Push("waba-1", uuid.New())
+			if err != nil && !errors.Is(err, ErrQueueFull) {
+				t.Errorf("Push: %v", err)
+				return
+			}
+			if err == nil {
+				mu.Lock()
+				pushed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	depth, err := q.Depth()
+	if err != nil {
+		t.Fatalf("Depth: %v", err)
+	}
+	queued, _ := client.Redis().LLen(t.Context(), q.eventsKey("waba-1")).Result()
+	if pushed != capacity || depth != capacity || queued != capacity {
+		t.Errorf("got %d pushed, depth %d and %d queued, want %d", pushed, depth, queued, capacity)
+	}
+}
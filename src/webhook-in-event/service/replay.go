@@ -0,0 +1,189 @@
+package webhook_in_event_service
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	database_model "github.com/Astervia/wacraft-core/src/database/model"
+	phone_config_entity "github.com/Astervia/wacraft-core/src/phone-config/entity"
+	"github.com/Astervia/wacraft-server/src/database"
+	database_cursor "github.com/Astervia/wacraft-server/src/database/cursor"
+	webhook_in_event_entity "github.com/Astervia/wacraft-server/src/webhook-in-event/entity"
+	webhook_handler "github.com/Astervia/wacraft-server/src/webhook-in/handler"
+	wh_model "github.com/Rfluid/whatsapp-cloud-api/src/webhook"
+	webhook_service "github.com/Rfluid/whatsapp-webhook-server/src/webhook/service"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrEventNotReplayable is returned when replaying an event that was processed
+// or is still being processed.
+var ErrEventNotReplayable = errors.New("only failed and interrupted events can be replayed")
+
+// interruptedAfter is how long an event stays received or processing before
+// it is considered interrupted. Processing a webhook takes seconds.
+const interruptedAfter = 5 * time.Minute
+
+// defaultReplayLimit is how many events a bulk replay handles when no limit is set.
+const defaultReplayLimit = 100
+
+// EventQuery filters the events listing.
+type EventQuery struct {
+	PhoneConfigID *uuid.UUID                          `json:"phone_config_id,omitempty" query:"phone_config_id"`
+	Status        webhook_in_event_entity.EventStatus `json:"status,omitempty" query:"status" validate:"omitempty,oneof=received processing processed failed"`
+
+	database_model.Paginate
+	database_model.DateWhere
+}
+
+// ReplayWindow selects the replayable events of the workspace received within
+// a time range, optionally of a single phone config.
+type ReplayWindow struct {
+	PhoneConfigID *uuid.UUID `json:"phone_config_id,omitempty"`
+	From          *time.Time `json:"from,omitempty"`
+	To            *time.Time `json:"to,omitempty"`
+	Limit         int        `json:"limit,omitempty" validate:"omitempty,min=1,max=1000"`
+}
+
+// ReplayResult is the response of bulk replays.
+type ReplayResult struct {
+	Replayed  int `json:"replayed"`
+	Processed int `json:"processed"`
+	Failed    int `json:"failed"`
+}
+
+// GetEvents lists the events of the workspace, newest first.
+func GetEvents(query EventQuery, workspaceID uuid.UUID) ([]webhook_in_event_entity.WebhookInEvent, error) {
+	db := database.DB.Model(&webhook_in_event_entity.WebhookInEvent{}).
+		Where("webhook_in_events.workspace_id = ?", workspaceID)
+
+	if query.PhoneConfigID != nil {
+		db = db.Where("webhook_in_events.phone_config_id = ?", *query.PhoneConfigID)
+	}
+	if query.Status != "" {
+		db = db.Where("webhook_in_events.status = ?", query.Status)
+	}
+	query.DateWhere.Where(&db, `"webhook_in_events"`)
+
+	events := []webhook_in_event_entity.WebhookInEvent{}
+	err := db.
+		Order("webhook_in_events.created_at DESC, webhook_in_events.id DESC").
+		Offset(query.Offset).
+		Limit(database_cursor.Limit(query.Limit)).
+		Find(&events).Error
+	return events, err
+}
+
+// Replay processes a failed or interrupted event of the workspace again with
+// the change handlers of hook. The outcome is recorded on the returned event;
+// the error is only set when the event could not be replayed. Returns
+// gorm.ErrRecordNotFound when the event is not in the workspace.
+//
+// The phone config of the event is stored in the locals of ctx, as the
+// webhook-in route does, so the handlers run exactly as they did for Meta.
+func Replay(ctx *fiber.Ctx, hook *webhook_service.Config, id uuid.UUID, workspaceID uuid.UUID) (webhook_in_event_entity.WebhookInEvent, error) {
+	var event webhook_in_event_entity.WebhookInEvent
+	err := database.DB.
+		Where("webhook_in_events.workspace_id = ? AND webhook_in_events.id = ?", workspaceID, id).
+		First(&event).Error
+	if err != nil {
+		return event, err
+	}
+
+	// Claiming the event keeps concurrent replays from processing it twice.
+	result := replayable(database.DB.Model(&webhook_in_event_entity.WebhookInEvent{})).
+		Where("webhook_in_events.id = ?", id).
+		Updates(processingAssignments)
+	if result.Error != nil {
+		return event, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return event, ErrEventNotReplayable
+	}
+
+	err = RecordOutcome(&event, process(ctx, hook, event))
+	return event, err
+}
+
+// ReplayEvents replays the failed and interrupted events in the window, oldest
+// first so messages are stored in the order they were received.
+func ReplayEvents(ctx *fiber.Ctx, hook *webhook_service.Config, window ReplayWindow, workspaceID uuid.UUID) (ReplayResult, error) {
+	var result ReplayResult
+
+	db := replayable(database.DB.Model(&webhook_in_event_entity.WebhookInEvent{})).
+		Where("webhook_in_events.workspace_id = ?", workspaceID)
+	if window.PhoneConfigID != nil {
+		db = db.Where("webhook_in_events.phone_config_id = ?", *window.PhoneConfigID)
+	}
+	if window.From != nil {
+		db = db.Where("webhook_in_events.created_at >= ?", *window.From)
+	}
+	if window.To != nil {
+		db = db.Where("webhook_in_events.created_at <= ?", *window.To)
+	}
+
+	limit := window.Limit
+	if limit <= 0 {
+		limit = defaultReplayLimit
+	}
+
+	var ids []uuid.UUID
+	err := db.
+		Order("webhook_in_events.created_at ASC, webhook_in_events.id ASC").
+		Limit(limit).
+		Pluck("webhook_in_events.id", &ids).Error
+	if err != nil {
+		return result, err
+	}
+
+	for _, id := range ids {
+		event, err := Replay(ctx, hook, id, workspaceID)
+		if errors.Is(err, ErrEventNotReplayable) {
+			// Replayed concurrently since it was listed.
+			continue
+		}
+		if err != nil {
+			return result, err
+		}
+
+		result.Replayed++
+		if event.Status == webhook_in_event_entity.EventProcessed {
+			result.Processed++
+		} else {
+			result.Failed++
+		}
+	}
+
+	return result, nil
+}
+
+// process runs the change handlers of hook on the stored body of the event.
+// The stored body is also set as the request body of ctx for the handlers
+// reading the raw webhook.
+func process(ctx *fiber.Ctx, hook *webhook_service.Config, event webhook_in_event_entity.WebhookInEvent) error {
+	var phoneConfig phone_config_entity.PhoneConfig
+	if err := database.DB.First(&phoneConfig, "id = ?", event.PhoneConfigID).Error; err != nil {
+		return err
+	}
+
+	var body wh_model.WebhookBody
+	if err := json.Unmarshal([]byte(event.Body), &body); err != nil {
+		return err
+	}
+
+	ctx.Request().SetBody([]byte(event.Body))
+	ctx.Locals(webhook_handler.PhoneConfigCtxKey, &phoneConfig)
+	return hook.Exec(ctx, &body)
+}
+
+// replayable scopes db to failed and interrupted events.
+func replayable(db *gorm.DB) *gorm.DB {
+	return db.Where(
+		"(webhook_in_events.status = ? OR (webhook_in_events.status IN ? AND webhook_in_events.updated_at < ?))",
+		webhook_in_event_entity.EventFailed,
+		pendingStatuses,
+		time.Now().Add(-interruptedAfter),
+	)
+}
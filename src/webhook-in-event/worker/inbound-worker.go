@@ -0,0 +1,151 @@
+package webhook_in_event_worker
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Astervia/wacraft-server/src/config/env"
+	webhook_in_event_service "github.com/Astervia/wacraft-server/src/webhook-in-event/service"
+	webhook_config "github.com/Astervia/wacraft-server/src/webhook-in/config"
+	"github.com/gofiber/fiber/v2"
+	"github.com/pterm/pterm"
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	// InboundDrainLimit is the max number of events of a WABA processed
+	// before the WABA is handed back, so busy WABAs do not starve the others.
+	InboundDrainLimit = 10
+	// InboundRequeueInterval is how often interrupted events are queued again.
+	InboundRequeueInterval = time.Minute
+	// InboundRequeueBatchSize is the max number of events queued again per pass.
+	InboundRequeueBatchSize = 500
+)
+
+// InboundWorker processes the webhooks stored by webhook-in. Each of its
+// goroutines owns one WABA at a time, so at most env.WebhookInPoolSize WABAs
+// are processed concurrently and the events of a WABA are processed in order.
+type InboundWorker struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	// app creates the request contexts the change handlers run with.
+	app   *fiber.App
+	queue webhook_in_event_service.Queue
+}
+
+// NewInboundWorker creates a new inbound worker using the package-level queue.
+func NewInboundWorker(app *fiber.App) *InboundWorker {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &InboundWorker{
+		ctx:    ctx,
+		cancel: cancel,
+		app:    app,
+		queue:  webhook_in_event_service.GetQueue(),
+	}
+}
+
+// Start queues interrupted events again and starts the worker goroutines.
+func (w *InboundWorker) Start() {
+	w.requeueInterrupted()
+
+	w.wg.Add(1)
+	go w.runRequeue()
+
+	for range env.WebhookInPoolSize {
+		w.wg.Add(1)
+		go w.run()
+	}
+	pterm.DefaultLogger.Info("Webhook-in worker started with " + strconv.Itoa(env.WebhookInPoolSize) + " workers")
+}
+
+// Stop gracefully stops the inbound worker. Events being processed finish first.
+func (w *InboundWorker) Stop() {
+	pterm.DefaultLogger.Info("Stopping webhook-in worker...")
+	w.cancel()
+	w.wg.Wait()
+	pterm.DefaultLogger.Info("Webhook-in worker stopped")
+}
+
+// run takes WABAs with queued events and processes their events.
+func (w *InboundWorker) run() {
+	defer w.wg.Done()
+
+	for {
+		wabaID, err := w.queue.Next(w.ctx)
+		if errors.Is(err, context.Canceled) {
+			return
+		}
+		if err != nil {
+			pterm.DefaultLogger.Error("Webhook-in: failed to take a WABA from the queue: " + err.Error())
+			select {
+			case <-w.ctx.Done():
+				return
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+
+		w.drain(wabaID)
+
+		if err := w.queue.Release(wabaID); err != nil {
+			pterm.DefaultLogger.Error("Webhook-in: failed to release WABA " + wabaID + ": " + err.Error())
+		}
+	}
+}
+
+// drain processes up to InboundDrainLimit events of the WABA, in order.
+func (w *InboundWorker) drain(wabaID string) {
+	for range InboundDrainLimit {
+		if w.ctx.Err() != nil {
+			return
+		}
+
+		eventID, ok, err := w.queue.Pop(wabaID)
+		if err != nil {
+			pterm.DefaultLogger.Error("Webhook-in: failed to pop an event of WABA " + wabaID + ": " + err.Error())
+			return
+		}
+		if !ok {
+			return
+		}
+
+		ctx := w.app.AcquireCtx(&fasthttp.RequestCtx{})
+		err = webhook_in_event_service.ProcessEvent(ctx, &webhook_config.WabaHook, eventID)
+		w.app.ReleaseCtx(ctx)
+		if err != nil {
+			pterm.DefaultLogger.Error("Webhook-in: failed to process event " + eventID.String() + ": " + err.Error())
+		}
+	}
+}
+
+// runRequeue periodically queues again the events of crashed instances and
+// the events rejected while the queue was full.
+func (w *InboundWorker) runRequeue() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(InboundRequeueInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.ctx.Done():
+			return
+		case <-ticker.C:
+			w.requeueInterrupted()
+		}
+	}
+}
+
+func (w *InboundWorker) requeueInterrupted() {
+	requeued, err := webhook_in_event_service.RequeueInterrupted(InboundRequeueBatchSize)
+	if err != nil && !errors.Is(err, webhook_in_event_service.ErrQueueFull) {
+		pterm.DefaultLogger.Error("Webhook-in: failed to queue interrupted events again: " + err.Error())
+	}
+	if requeued > 0 {
+		pterm.DefaultLogger.Warn("Webhook-in: queued " + strconv.Itoa(requeued) + " interrupted events again")
+	}
+}
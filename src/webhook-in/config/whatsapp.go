@@ -6,6 +6,7 @@ import (
 	"github.com/Astervia/wacraft-server/src/config/env"
 	"github.com/Astervia/wacraft-server/src/database"
 	phone_config_service "github.com/Astervia/wacraft-server/src/phone-config/service"
+	webhook_in_event_middleware "github.com/Astervia/wacraft-server/src/webhook-in-event/middleware"
 	webhook_handler "github.com/Astervia/wacraft-server/src/webhook-in/handler"
 	wh_model "github.com/Rfluid/whatsapp-cloud-api/src/webhook"
 	auth_middleware "github.com/Rfluid/whatsapp-webhook-server/src/auth/middleware"
@@ -57,45 +58,50 @@ func ServeWebhook(app *fiber.App) {
 	registerWabaWebhook(app)
 }
 
-// registerWabaWebhook registers a single webhook endpoint at /webhook-in/:waba_id.
-func registerWabaWebhook(app *fiber.App) {
-	hook := webhook_service.Config{
-		Path: "/:waba_id",
-		ChangeHandlers: []webhook_model.ChangeHandler{
-			webhook_handler.PhoneConfigMessageHandler,
-		},
-		CtxHandler: defaultCtxHandler,
-		PostMiddlewares: []func(ctx *fiber.Ctx) error{
-			func(ctx *fiber.Ctx) error {
-				phoneConfig, err := requirePhoneConfig(ctx)
-				if err != nil {
-					return err
-				}
-				appSecret := phone_config_service.GetMetaAppSecret(phoneConfig)
-				if appSecret == "" {
-					return ctx.Next()
-				}
-				return auth_middleware.VerifyMetaSignature(appSecret)(ctx)
-			},
-			billing_middleware.WebhookInThroughputMiddleware(webhook_handler.PhoneConfigCtxKey),
+// WabaHook is the webhook config of /webhook-in/:waba_id. It routes each
+// webhook to the phone config with the WABA ID of the path.
+var WabaHook = webhook_service.Config{
+	Path: "/:waba_id",
+	ChangeHandlers: []webhook_model.ChangeHandler{
+		webhook_handler.PhoneConfigMessageHandler,
+		webhook_handler.PhoneConfigAccountHandler,
+		webhook_handler.PhoneConfigEchoHandler,
+	},
+	CtxHandler: webhook_handler.RawChangesCtxHandler,
+	PostMiddlewares: []func(ctx *fiber.Ctx) error{
+		func(ctx *fiber.Ctx) error {
+			phoneConfig, err := requirePhoneConfig(ctx)
+			if err != nil {
+				return err
+			}
+			appSecret := phone_config_service.GetMetaAppSecret(phoneConfig)
+			if appSecret == "" {
+				return ctx.Next()
+			}
+			return auth_middleware.VerifyMetaSignature(appSecret)(ctx)
 		},
-		GetMiddlewares: []func(ctx *fiber.Ctx) error{
-			func(ctx *fiber.Ctx) error {
-				phoneConfig, err := requirePhoneConfig(ctx)
-				if err != nil {
-					return err
-				}
-				verifyToken := phone_config_service.GetVerifyToken(phoneConfig)
-				if verifyToken == "" {
-					return ctx.Next()
-				}
-				return auth_middleware.MetaVerificationRequestToken(verifyToken)(ctx)
-			},
+		billing_middleware.WebhookInThroughputMiddleware(webhook_handler.PhoneConfigCtxKey),
+		webhook_in_event_middleware.ArchiveMiddleware(webhook_handler.PhoneConfigCtxKey),
+	},
+	GetMiddlewares: []func(ctx *fiber.Ctx) error{
+		func(ctx *fiber.Ctx) error {
+			phoneConfig, err := requirePhoneConfig(ctx)
+			if err != nil {
+				return err
+			}
+			verifyToken := phone_config_service.GetVerifyToken(phoneConfig)
+			if verifyToken == "" {
+				return ctx.Next()
+			}
+			return auth_middleware.MetaVerificationRequestToken(verifyToken)(ctx)
 		},
-	}
+	},
+}
 
+// registerWabaWebhook registers a single webhook endpoint at /webhook-in/:waba_id.
+func registerWabaWebhook(app *fiber.App) {
 	server := server_service.NewConfig(app, "/webhook-in")
-	server_service.Bootstrap(server, &hook)
+	server_service.Bootstrap(server, &WabaHook)
 
 	pterm.DefaultLogger.Info("Registered webhook for all phone configs at /webhook-in/:waba_id")
 }
@@ -0,0 +1,56 @@
+package webhook_handler
+
+import (
+	"fmt"
+
+	phone_config_entity "github.com/Astervia/wacraft-core/src/phone-config/entity"
+	account_update_handler "github.com/Astervia/wacraft-server/src/account-update/handler"
+	account_update_service "github.com/Astervia/wacraft-server/src/account-update/service"
+	wh_model "github.com/Rfluid/whatsapp-cloud-api/src/webhook"
+	webhook_model "github.com/Rfluid/whatsapp-webhook-server/src/webhook/model"
+	"github.com/gofiber/fiber/v2"
+	"github.com/pterm/pterm"
+)
+
+// PhoneConfigAccountHandler records the template status, phone number quality,
+// account, alert and capability changes of the phone config in context.
+// Requires RawChangesCtxHandler as the context handler of the webhook.
+var PhoneConfigAccountHandler = webhook_model.ChangeHandler{
+	Callback: func(ctx *fiber.Ctx, body *wh_model.WebhookBody, change *wh_model.Change) error {
+		phoneConfig, ok := ctx.Locals(PhoneConfigCtxKey).(*phone_config_entity.PhoneConfig)
+		if !ok || phoneConfig == nil {
+			return fiber.NewError(fiber.StatusNotFound, "phone config not found")
+		}
+		changes, ok := ctx.Locals(rawChangesCtxKey).(*rawChanges)
+		if !ok || changes == nil {
+			return nil
+		}
+		return accountCallback(*phoneConfig, changes.takeAccount())
+	},
+	ExecutionContexts: &account_update_service.Fields,
+}
+
+// accountCallback records the account level changes of the webhook and
+// propagates them once every change is stored. The handler runs once per
+// change, so the first run records every change.
+func accountCallback(phoneConfig phone_config_entity.PhoneConfig, changes []account_update_service.Change) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	updates, err := account_update_service.Record(phoneConfig, changes)
+	if err != nil {
+		pterm.DefaultLogger.Error(
+			fmt.Sprintf("Error while handling account changes for phone config %s: %s", phoneConfig.ID, err.Error()),
+		)
+		return err
+	}
+
+	go func() {
+		for _, update := range updates {
+			account_update_handler.PropagateAccountUpdate(update)
+		}
+	}()
+
+	return nil
+}
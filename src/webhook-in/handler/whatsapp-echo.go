@@ -0,0 +1,434 @@
+package webhook_handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	contact_entity "github.com/Astervia/wacraft-core/src/contact/entity"
+	message_entity "github.com/Astervia/wacraft-core/src/message/entity"
+	message_model "github.com/Astervia/wacraft-core/src/message/model"
+	messaging_product_entity "github.com/Astervia/wacraft-core/src/messaging-product/entity"
+	messaging_product_model "github.com/Astervia/wacraft-core/src/messaging-product/model"
+	phone_config_entity "github.com/Astervia/wacraft-core/src/phone-config/entity"
+	"github.com/Astervia/wacraft-core/src/repository"
+	status_entity "github.com/Astervia/wacraft-core/src/status/entity"
+	status_model "github.com/Astervia/wacraft-core/src/status/model"
+	webhook_entity "github.com/Astervia/wacraft-core/src/webhook/entity"
+	webhook_out_model "github.com/Astervia/wacraft-core/src/webhook/model"
+	conversation_service "github.com/Astervia/wacraft-server/src/conversation/service"
+	"github.com/Astervia/wacraft-server/src/database"
+	inbound_receipt_entity "github.com/Astervia/wacraft-server/src/inbound-receipt/entity"
+	inbound_receipt_service "github.com/Astervia/wacraft-server/src/inbound-receipt/service"
+	message_reaction_service "github.com/Astervia/wacraft-server/src/message-reaction/service"
+	message_thread_service "github.com/Astervia/wacraft-server/src/message-thread/service"
+	message_handler "github.com/Astervia/wacraft-server/src/message/handler"
+	messaging_product_service "github.com/Astervia/wacraft-server/src/messaging-product/service"
+	webhook_event_model "github.com/Astervia/wacraft-server/src/webhook-event/model"
+	webhook_service "github.com/Astervia/wacraft-server/src/webhook/service"
+	"github.com/Rfluid/whatsapp-cloud-api/src/common"
+	"github.com/Rfluid/whatsapp-cloud-api/src/message"
+	"github.com/Rfluid/whatsapp-cloud-api/src/message/content"
+	wh_model "github.com/Rfluid/whatsapp-cloud-api/src/webhook"
+	webhook_model "github.com/Rfluid/whatsapp-webhook-server/src/webhook/model"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/pterm/pterm"
+	"gorm.io/gorm"
+)
+
+// Fields of the phone numbers that also use the WhatsApp Business app
+// (coexistence). They are not part of the webhook library yet.
+const (
+	// SmbMessageEchoes carries the messages sent from the WhatsApp Business app.
+	SmbMessageEchoes wh_model.Field = "smb_message_echoes"
+	// History carries the chats of the WhatsApp Business app, shared in
+	// chunks once the business onboards the phone number.
+	History wh_model.Field = "history"
+)
+
+var echoExecutionContexts = []wh_model.Field{SmbMessageEchoes, History}
+
+// echoMessage is a message of the WhatsApp Business app. Meta sends it with
+// the shape of a received message plus the customer it was sent to.
+type echoMessage struct {
+	message.MessageReceived
+	To string `json:"to"`
+	// HistoryContext is set on the messages of the history sync.
+	HistoryContext *struct {
+		// Status is the last status of the message, e.g. DELIVERED, READ or PLAYED.
+		Status string `json:"status"`
+	} `json:"history_context,omitempty"`
+}
+
+// historyStatus returns the sending status of a message of the history sync.
+// Played media counts as read and errors as failed; PENDING and unknown
+// statuses have no sending status.
+func historyStatus(msg echoMessage) (message.SendingStatus, bool) {
+	if msg.HistoryContext == nil {
+		return "", false
+	}
+	switch status := strings.ToLower(msg.HistoryContext.Status); status {
+	case "played":
+		return message.Read, true
+	case "error":
+		return message.Failed, true
+	default:
+		return message.SendingStatus(status), message.SendingStatus(status).IsValid()
+	}
+}
+
+// echoValue is the value of a smb_message_echoes change.
+type echoValue struct {
+	MessageEchoes []echoMessage `json:"message_echoes"`
+}
+
+// historyValue is the value of a history change. Each chunk holds one thread
+// per customer with the messages of both sides. Errors are set instead when
+// the business declined to share its history.
+type historyValue struct {
+	History []struct {
+		Metadata struct {
+			Phase      int `json:"phase"`
+			ChunkOrder int `json:"chunk_order"`
+			Progress   int `json:"progress"`
+		} `json:"metadata"`
+		Threads []struct {
+			// ID is the WhatsApp ID of the customer.
+			ID       string        `json:"id"`
+			Messages []echoMessage `json:"messages"`
+		} `json:"threads"`
+	} `json:"history"`
+	Errors []common.Error `json:"errors"`
+}
+
+// importedMessage is a message of the WhatsApp Business app with the
+// customer of its chat and whether the business sent it.
+type importedMessage struct {
+	customer string
+	outbound bool
+	message  echoMessage
+}
+
+// parseEchoes returns the messages of a smb_message_echoes value. Echoes are
+// always sent by the business.
+func parseEchoes(raw json.RawMessage) ([]importedMessage, error) {
+	var value echoValue
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil, err
+	}
+
+	imported := make([]importedMessage, 0, len(value.MessageEchoes))
+	for _, echo := range value.MessageEchoes {
+		imported = append(imported, importedMessage{customer: echo.To, outbound: true, message: echo})
+	}
+	return imported, nil
+}
+
+// parseHistory returns the messages of a history value in the order Meta sent them.
+// Messages not sent by the customer of the thread were sent by the business.
+func parseHistory(raw json.RawMessage) ([]importedMessage, []common.Error, error) {
+	var value historyValue
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil, nil, err
+	}
+
+	var imported []importedMessage
+	for _, chunk := range value.History {
+		for _, thread := range chunk.Threads {
+			for _, msg := range thread.Messages {
+				imported = append(imported, importedMessage{
+					customer: thread.ID,
+					outbound: msg.From != thread.ID,
+					message:  msg,
+				})
+			}
+		}
+	}
+	return imported, value.Errors, nil
+}
+
+// PhoneConfigEchoHandler stores the messages of the WhatsApp Business app of
+// the phone config in context: the echoes of the messages the business sends
+// from the app and the chats shared by the history sync.
+// Requires RawChangesCtxHandler as the context handler of the webhook.
+var PhoneConfigEchoHandler = webhook_model.ChangeHandler{
+	Callback: func(ctx *fiber.Ctx, body *wh_model.WebhookBody, change *wh_model.Change) error {
+		phoneConfig, ok := ctx.Locals(PhoneConfigCtxKey).(*phone_config_entity.PhoneConfig)
+		if !ok || phoneConfig == nil {
+			return fiber.NewError(fiber.StatusNotFound, "phone config not found")
+		}
+		changes, ok := ctx.Locals(rawChangesCtxKey).(*rawChanges)
+		if !ok || changes == nil {
+			return nil
+		}
+		return echoCallback(phoneConfig.ID, change.Field, changes.take(change.Field))
+	},
+	ExecutionContexts: &echoExecutionContexts,
+}
+
+// echoCallback imports the messages of the changes of the field in a single
+// transaction. Echoes are propagated like the messages sent through the API;
+// history is imported silently since it is not new activity.
+func echoCallback(phoneConfigID uuid.UUID, field wh_model.Field, values []json.RawMessage) error {
+	var imported []importedMessage
+	for _, value := range values {
+		var messages []importedMessage
+		var err error
+		if field == History {
+			var errs []common.Error
+			messages, errs, err = parseHistory(value)
+			for _, historyErr := range errs {
+				pterm.DefaultLogger.Warn(
+					fmt.Sprintf("History of phone config %s not shared: %d %s", phoneConfigID, historyErr.Code, historyErr.Message),
+				)
+			}
+		} else {
+			messages, err = parseEchoes(value)
+		}
+		if err != nil {
+			return err
+		}
+		imported = append(imported, messages...)
+	}
+	if len(imported) == 0 {
+		return nil
+	}
+
+	mp := messaging_product_entity.MessagingProduct{
+		Name:          messaging_product_model.WhatsApp,
+		PhoneConfigID: &phoneConfigID,
+	}
+	if err := database.DB.Model(&mp).Where(&mp).First(&mp).Error; err != nil {
+		pterm.DefaultLogger.Error(
+			fmt.Sprintf("No messaging product found for phone config %s: %s", phoneConfigID, err.Error()),
+		)
+		return err
+	}
+
+	tx := database.DB.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	// Messages are imported in order since most of them share their contact.
+	handled := &handledMessages{}
+	for _, msg := range imported {
+		if err := importMessage(msg, tx, mp.ID, mp.WorkspaceID, handled); err != nil {
+			pterm.DefaultLogger.Error(
+				fmt.Sprintf("Error while handling %s for phone config %s: %s", field, phoneConfigID, err.Error()),
+			)
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	if field != History {
+		go func() {
+			for _, payload := range message_thread_service.ThreadedAll(handled.messages) {
+				if mp.WorkspaceID != nil {
+					go message_handler.NewMessageWorkspaceManager.BroadcastToWorkspace(*mp.WorkspaceID, payload)
+				}
+				go webhook_service.SendAllByQuery(
+					webhook_entity.Webhook{
+						Event:       webhook_out_model.SendWhatsAppMessage,
+						WorkspaceID: mp.WorkspaceID,
+					},
+					payload,
+				)
+			}
+		}()
+
+		go func() {
+			for _, event := range handled.reactionEvents {
+				message_handler.PropagateReactionEvent(mp.WorkspaceID, event)
+			}
+		}()
+	}
+
+	go func() {
+		for _, contact := range handled.createdContacts {
+			go webhook_service.SendEvent(webhook_event_model.ContactCreated, mp.WorkspaceID, contact)
+		}
+	}()
+
+	return nil
+}
+
+// importMessage stores a message of the WhatsApp Business app with its
+// original timestamp, tied to the contact of the customer. Messages whose
+// wamid was already stored, e.g. an echo shared again by the history sync,
+// are skipped.
+func importMessage(imported importedMessage, tx *gorm.DB, mpID uuid.UUID, workspaceID *uuid.UUID, handled *handledMessages) error {
+	received := imported.message.MessageReceived
+
+	claimed, err := inbound_receipt_service.Claim(mpID, received.ID, inbound_receipt_entity.ReceiptMessage, tx)
+	if err != nil || !claimed {
+		return err
+	}
+
+	var name string
+	mpContact, created, err := messaging_product_service.GetContactOrSaveCreated(
+		messaging_product_entity.MessagingProductContact{
+			MessagingProductID: mpID,
+			ProductDetails: &messaging_product_model.ProductDetails{
+				WhatsAppProductDetails: &messaging_product_model.WhatsAppProductDetails{
+					WaID:        imported.customer,
+					PhoneNumber: imported.customer,
+				},
+			},
+		},
+		contact_entity.Contact{
+			Name:        &name,
+			Email:       nil,
+			WorkspaceID: workspaceID,
+		},
+		tx,
+	)
+	if err != nil {
+		return err
+	}
+	if created && mpContact.Contact != nil {
+		handled.createdContacts = append(handled.createdContacts, *mpContact.Contact)
+	}
+
+	sentAt := messaging_product_service.ParseWhatsAppTimestamp(received.Timestamp)
+	msg := message_entity.Message{
+		MessageFields: message_model.MessageFields{
+			MessagingProductID: mpID,
+		},
+	}
+	msg.CreatedAt = sentAt
+
+	if imported.outbound {
+		if received.Reaction != nil {
+			event, err := message_reaction_service.ApplyOutboundReaction(mpID, *received.Reaction, sentAt, tx)
+			if err != nil {
+				return err
+			}
+			if event != nil {
+				handled.reactionEvents = append(handled.reactionEvents, *event)
+			}
+			return nil
+		}
+		sent := sentMessage(received, imported.customer)
+		msg.SenderData = &message_model.SenderData{Message: &sent}
+		msg.ProductData = &message_model.ProductData{
+			Response: &message.Response{
+				Contacts: []message.ResponseContact{{Input: imported.customer, WAID: imported.customer}},
+				Messages: []message.MessageResponse{{ID: common.ID{ID: received.ID}}},
+			},
+		}
+		msg.ToID = &mpContact.ID
+		msg.To = &mpContact
+	} else {
+		if mpContact.Blocked {
+			return nil
+		}
+		if received.Reaction != nil {
+			event, err := applyInboundReaction(*received.Reaction, received.Timestamp, mpID, mpContact.ID, tx)
+			if err != nil {
+				return err
+			}
+			if event != nil {
+				handled.reactionEvents = append(handled.reactionEvents, *event)
+			}
+			return nil
+		}
+		msg.ReceiverData = &message_model.ReceiverData{MessageReceived: &received}
+		msg.FromID = &mpContact.ID
+		msg.From = &mpContact
+	}
+
+	if err := tx.Model(&msg).Create(&msg).Error; err != nil {
+		return err
+	}
+	if err := conversation_service.RecordMessageInConversation(msg, tx); err != nil {
+		return err
+	}
+	if err := message_thread_service.RecordMessageReplies(msg, tx); err != nil {
+		return err
+	}
+	if status, ok := historyStatus(imported.message); ok {
+		if err := importStatus(msg, imported, status, sentAt, tx); err != nil {
+			return err
+		}
+	}
+
+	if !imported.outbound {
+		if err := messaging_product_service.TouchLastInboundAt(mpContact.ID, sentAt, tx); err != nil {
+			return err
+		}
+	}
+
+	handled.messages = append(handled.messages, msg)
+	return nil
+}
+
+// importStatus stores the status the history sync reports for an imported
+// message, as the status webhook would have. Meta does not report when the
+// status was reached, so it is dated with the message.
+func importStatus(msg message_entity.Message, imported importedMessage, status message.SendingStatus, sentAt time.Time, tx *gorm.DB) error {
+	received := imported.message.MessageReceived
+	recipientID := ""
+	if imported.outbound {
+		recipientID = imported.customer
+	}
+
+	_, err := repository.Create(
+		status_entity.Status{
+			StatusFields: status_model.StatusFields{
+				MessageID: msg.ID,
+				ProductData: &status_model.ProductData{
+					Status: &wh_model.Status{
+						ID:          received.ID,
+						RecipientID: recipientID,
+						Status:      &status,
+						Timestamp:   received.Timestamp,
+					},
+				},
+			},
+		},
+		tx,
+	)
+	if err != nil {
+		return err
+	}
+	return conversation_service.RecordStatusInConversation(msg.ID, string(status), sentAt, tx)
+}
+
+// sentMessage builds the message the business sent to the customer from the
+// received shape Meta uses for echoes. Interactive content is left out since
+// its received shape differs from the sent one.
+func sentMessage(received message.MessageReceived, to string) message.Message {
+	sent := message.Message{
+		Direction: message.Direction{
+			To:   to,
+			Type: content.Type(received.Type),
+		},
+		Content: message.Content{
+			Text:     received.Text,
+			Reaction: received.Reaction,
+			Image:    received.Image,
+			Video:    received.Video,
+			Document: received.Document,
+			Audio:    received.Audio,
+			Sticker:  received.Sticker,
+			Location: received.Location,
+			Template: received.Template,
+			Contacts: received.Contacts,
+			Button:   received.Button,
+			Order:    received.Order,
+		},
+	}
+	sent.SetDefault()
+	if received.Context != nil {
+		sent.Context = &message.Context{MessageID: received.Context.ID}
+	}
+	return sent
+}
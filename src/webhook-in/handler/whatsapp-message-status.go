@@ -10,7 +10,11 @@ import (
 	status_entity "github.com/Astervia/wacraft-core/src/status/entity"
 	status_model "github.com/Astervia/wacraft-core/src/status/model"
 	"github.com/Astervia/wacraft-server/src/config/env"
+	conversation_service "github.com/Astervia/wacraft-server/src/conversation/service"
+	inbound_receipt_entity "github.com/Astervia/wacraft-server/src/inbound-receipt/entity"
+	inbound_receipt_service "github.com/Astervia/wacraft-server/src/inbound-receipt/service"
 	message_service "github.com/Astervia/wacraft-server/src/message/service"
+	messaging_product_service "github.com/Astervia/wacraft-server/src/messaging-product/service"
 	whk_service "github.com/Astervia/wacraft-server/src/webhook-in/service"
 	wh_model "github.com/Rfluid/whatsapp-cloud-api/src/webhook"
 	"github.com/google/uuid"
@@ -88,6 +92,16 @@ func handleStatuses(
 				msgID = msg.ID
 			}
 
+			// Skipping statuses Meta delivered again
+			var receiptType inbound_receipt_entity.ReceiptType
+			if status.Status != nil {
+				receiptType = inbound_receipt_entity.ReceiptType(*status.Status)
+			}
+			claimed, err := inbound_receipt_service.Claim(mpID, wamID, receiptType, tx)
+			if err != nil || !claimed {
+				return err
+			}
+
 			s, err := repository.Create(
 				status_entity.Status{
 					StatusFields: status_model.StatusFields{
@@ -102,6 +116,17 @@ func handleStatuses(
 			if err != nil {
 				return err
 			}
+			if status.Status != nil {
+				err = conversation_service.RecordStatusInConversation(
+					msgID,
+					string(*status.Status),
+					messaging_product_service.ParseWhatsAppTimestamp(status.Timestamp),
+					tx,
+				)
+				if err != nil {
+					return err
+				}
+			}
 			statMu.Lock()
 			statuses = append(statuses, s)
 			statMu.Unlock()
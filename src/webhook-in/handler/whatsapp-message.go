@@ -13,11 +13,21 @@ import (
 	status_entity "github.com/Astervia/wacraft-core/src/status/entity"
 	webhook_entity "github.com/Astervia/wacraft-core/src/webhook/entity"
 	webhook_out_model "github.com/Astervia/wacraft-core/src/webhook/model"
+	conversation_handler "github.com/Astervia/wacraft-server/src/conversation/handler"
+	conversation_model "github.com/Astervia/wacraft-server/src/conversation/model"
+	conversation_service "github.com/Astervia/wacraft-server/src/conversation/service"
 	"github.com/Astervia/wacraft-server/src/database"
+	inbound_receipt_entity "github.com/Astervia/wacraft-server/src/inbound-receipt/entity"
+	inbound_receipt_service "github.com/Astervia/wacraft-server/src/inbound-receipt/service"
+	message_reaction_model "github.com/Astervia/wacraft-server/src/message-reaction/model"
+	message_reaction_service "github.com/Astervia/wacraft-server/src/message-reaction/service"
+	message_thread_service "github.com/Astervia/wacraft-server/src/message-thread/service"
 	message_handler "github.com/Astervia/wacraft-server/src/message/handler"
 	messaging_product_service "github.com/Astervia/wacraft-server/src/messaging-product/service"
 	status_handler "github.com/Astervia/wacraft-server/src/status/handler"
+	webhook_event_model "github.com/Astervia/wacraft-server/src/webhook-event/model"
 	webhook_service "github.com/Astervia/wacraft-server/src/webhook/service"
+	"github.com/Rfluid/whatsapp-cloud-api/src/message/content"
 	wh_model "github.com/Rfluid/whatsapp-cloud-api/src/webhook"
 	webhook_model "github.com/Rfluid/whatsapp-webhook-server/src/webhook/model"
 	"github.com/gofiber/fiber/v2"
@@ -67,8 +77,8 @@ func messageCallbackForPhoneConfig(ctx *fiber.Ctx, body *wh_model.WebhookBody, c
 		return nil
 	}
 	var eg errgroup.Group
-	var msgs []message_entity.Message
 	var statuses []status_entity.Status
+	handled := &handledMessages{}
 
 	// Find messaging product by phone config ID
 	mp := messaging_product_entity.MessagingProduct{
@@ -94,9 +104,7 @@ func messageCallbackForPhoneConfig(ctx *fiber.Ctx, body *wh_model.WebhookBody, c
 
 	eg.Go(func() error {
 		if value.Messages != nil {
-			var err error
-			msgs, err = handleMessagesWithWorkspace(value, tx, mp.ID, mp.WorkspaceID)
-			return err
+			return handleMessagesWithWorkspace(value, tx, mp.ID, mp.WorkspaceID, handled)
 		}
 		return nil
 	})
@@ -123,17 +131,17 @@ func messageCallbackForPhoneConfig(ctx *fiber.Ctx, body *wh_model.WebhookBody, c
 	}
 
 	go func() {
-		for _, msg := range msgs {
+		for _, payload := range message_thread_service.ThreadedAll(handled.messages) {
 			// Broadcast to workspace-scoped WebSocket clients
 			if mp.WorkspaceID != nil {
-				go message_handler.NewMessageWorkspaceManager.BroadcastToWorkspace(*mp.WorkspaceID, msg)
+				go message_handler.NewMessageWorkspaceManager.BroadcastToWorkspace(*mp.WorkspaceID, payload)
 			}
 			go webhook_service.SendAllByQuery(
 				webhook_entity.Webhook{
 					Event:       webhook_out_model.ReceiveWhatsAppMessage,
 					WorkspaceID: mp.WorkspaceID,
 				},
-				msg,
+				payload,
 			)
 		}
 	}()
@@ -144,6 +152,25 @@ func messageCallbackForPhoneConfig(ctx *fiber.Ctx, body *wh_model.WebhookBody, c
 			if mp.WorkspaceID != nil {
 				go status_handler.NewStatusWorkspaceManager.BroadcastToWorkspace(*mp.WorkspaceID, status)
 			}
+			go webhook_service.SendStatusEvent(mp.WorkspaceID, status)
+		}
+	}()
+
+	go func() {
+		for _, event := range handled.conversationEvents {
+			go conversation_handler.BroadcastConversationEvent(*mp.WorkspaceID, event)
+		}
+	}()
+
+	go func() {
+		for _, event := range handled.reactionEvents {
+			message_handler.PropagateReactionEvent(mp.WorkspaceID, event)
+		}
+	}()
+
+	go func() {
+		for _, contact := range handled.createdContacts {
+			go webhook_service.SendEvent(webhook_event_model.ContactCreated, mp.WorkspaceID, contact)
 		}
 	}()
 
@@ -156,8 +183,8 @@ func messageCallback(ctx *fiber.Ctx, body *wh_model.WebhookBody, change *wh_mode
 		return nil
 	}
 	var eg errgroup.Group
-	var msgs []message_entity.Message
 	var statuses []status_entity.Status
+	handled := &handledMessages{}
 
 	mp := messaging_product_entity.MessagingProduct{Name: messaging_product_model.WhatsApp}
 
@@ -175,9 +202,7 @@ func messageCallback(ctx *fiber.Ctx, body *wh_model.WebhookBody, change *wh_mode
 
 	eg.Go(func() error {
 		if value.Messages != nil {
-			var err error
-			msgs, err = handleMessages(value, tx, mp.ID)
-			return err
+			return handleMessages(value, tx, mp.ID, handled)
 		}
 		return nil
 	})
@@ -204,16 +229,16 @@ func messageCallback(ctx *fiber.Ctx, body *wh_model.WebhookBody, change *wh_mode
 	}
 
 	go func() {
-		for _, msg := range msgs {
+		for _, payload := range message_thread_service.ThreadedAll(handled.messages) {
 			// Broadcast to workspace-scoped WebSocket clients
 			if mp.WorkspaceID != nil {
-				go message_handler.NewMessageWorkspaceManager.BroadcastToWorkspace(*mp.WorkspaceID, msg)
+				go message_handler.NewMessageWorkspaceManager.BroadcastToWorkspace(*mp.WorkspaceID, payload)
 			}
 			go webhook_service.SendAllByQuery(
 				webhook_entity.Webhook{
 					Event: webhook_out_model.ReceiveWhatsAppMessage,
 				},
-				msg,
+				payload,
 			)
 		}
 	}()
@@ -224,30 +249,59 @@ func messageCallback(ctx *fiber.Ctx, body *wh_model.WebhookBody, change *wh_mode
 			if mp.WorkspaceID != nil {
 				go status_handler.NewStatusWorkspaceManager.BroadcastToWorkspace(*mp.WorkspaceID, status)
 			}
+			go webhook_service.SendStatusEvent(mp.WorkspaceID, status)
+		}
+	}()
+
+	go func() {
+		for _, event := range handled.reactionEvents {
+			message_handler.PropagateReactionEvent(mp.WorkspaceID, event)
+		}
+	}()
+
+	go func() {
+		for _, contact := range handled.createdContacts {
+			go webhook_service.SendEvent(webhook_event_model.ContactCreated, mp.WorkspaceID, contact)
 		}
 	}()
 
 	return nil
 }
 
+// handledMessages collects what handling the messages of a change produced, to
+// be propagated once the transaction commits.
+type handledMessages struct {
+	mu                 sync.Mutex
+	messages           []message_entity.Message
+	conversationEvents []conversation_model.ConversationEvent
+	reactionEvents     []message_reaction_model.ReactionEvent
+	createdContacts    []contact_entity.Contact
+}
+
 // handleMessagesWithWorkspace handles messages with workspace context.
 // Contacts created through this handler will be associated with the workspace.
-func handleMessagesWithWorkspace(value wh_model.Value, tx *gorm.DB, mpID uuid.UUID, workspaceID *uuid.UUID) ([]message_entity.Message, error) {
+//
+// Reactions are not stored as messages; they are applied to the reaction set
+// of the message they target and collected as reaction events.
+func handleMessagesWithWorkspace(value wh_model.Value, tx *gorm.DB, mpID uuid.UUID, workspaceID *uuid.UUID, handled *handledMessages) error {
 	var eg errgroup.Group
 
-	msgs := []message_entity.Message{}
-	var msgsMu sync.Mutex
-
 	// Handling each message
 	for index, message := range *value.Messages {
 		eg.Go(func() error {
+			// Skipping messages Meta delivered again
+			claimed, err := inbound_receipt_service.Claim(mpID, message.ID, inbound_receipt_entity.ReceiptMessage, tx)
+			if err != nil || !claimed {
+				return err
+			}
+
 			// Interpolating message properties
 			var name string
 			if value.Contacts != nil && len(*value.Contacts) >= index {
 				name = (*value.Contacts)[index].Profile.Name
 			}
 
-			mpContact, err := messaging_product_service.GetContactOrSave(
+			mpContact, created, err := messaging_product_service.GetContactOrSaveCreated(
 				messaging_product_entity.MessagingProductContact{
 					MessagingProductID: mpID,
 					ProductDetails: &messaging_product_model.ProductDetails{
@@ -267,6 +321,11 @@ func handleMessagesWithWorkspace(value wh_model.Value, tx *gorm.DB, mpID uuid.UU
 			if err != nil {
 				return err
 			}
+			if created && mpContact.Contact != nil {
+				handled.mu.Lock()
+				handled.createdContacts = append(handled.createdContacts, *mpContact.Contact)
+				handled.mu.Unlock()
+			}
 
 			// Building the message entity and creating with the mp contact found
 			msg := message_entity.Message{
@@ -280,13 +339,66 @@ func handleMessagesWithWorkspace(value wh_model.Value, tx *gorm.DB, mpID uuid.UU
 			if msg.From.Blocked {
 				return nil
 			}
+			if message.Reaction != nil {
+				event, err := applyInboundReaction(*message.Reaction, message.Timestamp, mpID, mpContact.ID, tx)
+				if err != nil {
+					return err
+				}
+				// A reaction is an inbound message for the inbox as well, so it
+				// reopens the conversation of the contact like any other.
+				var conversationEvent *conversation_model.ConversationEvent
+				if workspaceID != nil {
+					conversationEvent, err = conversation_service.RegisterInboundMessage(
+						mpContact.ID, *workspaceID, messaging_product_service.ParseWhatsAppTimestamp(message.Timestamp), tx,
+					)
+					if err != nil {
+						return err
+					}
+				}
+				handled.mu.Lock()
+				if event != nil {
+					handled.reactionEvents = append(handled.reactionEvents, *event)
+				}
+				if conversationEvent != nil {
+					handled.conversationEvents = append(handled.conversationEvents, *conversationEvent)
+				}
+				handled.mu.Unlock()
+				return nil
+			}
 			err = tx.Model(&msg).Create(&msg).Error
 			if err != nil {
 				return err
 			}
-			msgsMu.Lock()
-			msgs = append(msgs, msg)
-			msgsMu.Unlock()
+			receivedAt := messaging_product_service.ParseWhatsAppTimestamp(message.Timestamp)
+
+			// Creating or reopening the inbox conversation of the contact before the
+			// message is recorded on it, so a new conversation is reported as created
+			var event *conversation_model.ConversationEvent
+			if workspaceID != nil {
+				event, err = conversation_service.RegisterInboundMessage(mpContact.ID, *workspaceID, receivedAt, tx)
+				if err != nil {
+					return err
+				}
+			}
+			err = conversation_service.RecordMessageInConversation(msg, tx)
+			if err != nil {
+				return err
+			}
+			err = message_thread_service.RecordMessageReplies(msg, tx)
+			if err != nil {
+				return err
+			}
+			err = messaging_product_service.TouchLastInboundAt(mpContact.ID, receivedAt, tx)
+			if err != nil {
+				return err
+			}
+
+			handled.mu.Lock()
+			handled.messages = append(handled.messages, msg)
+			if event != nil {
+				handled.conversationEvents = append(handled.conversationEvents, *event)
+			}
+			handled.mu.Unlock()
 			return nil
 		})
 	}
@@ -298,26 +410,29 @@ func handleMessagesWithWorkspace(value wh_model.Value, tx *gorm.DB, mpID uuid.UU
 		)
 	}
 
-	return msgs, err
+	return err
 }
 
-// Returns messages from unblocked contacts (legacy handler without workspace context)
-func handleMessages(value wh_model.Value, tx *gorm.DB, mpID uuid.UUID) ([]message_entity.Message, error) {
+// Collects messages and reaction changes from unblocked contacts (legacy handler without workspace context)
+func handleMessages(value wh_model.Value, tx *gorm.DB, mpID uuid.UUID, handled *handledMessages) error {
 	var eg errgroup.Group
 
-	msgs := []message_entity.Message{}
-	var msgsMu sync.Mutex
-
 	// Handling each message
 	for index, message := range *value.Messages {
 		eg.Go(func() error {
+			// Skipping messages Meta delivered again
+			claimed, err := inbound_receipt_service.Claim(mpID, message.ID, inbound_receipt_entity.ReceiptMessage, tx)
+			if err != nil || !claimed {
+				return err
+			}
+
 			// Interpolating message properties
 			var name string
 			if value.Contacts != nil && len(*value.Contacts) >= index {
 				name = (*value.Contacts)[index].Profile.Name
 			}
 
-			mpContact, err := messaging_product_service.GetContactOrSave(
+			mpContact, created, err := messaging_product_service.GetContactOrSaveCreated(
 				messaging_product_entity.MessagingProductContact{
 					MessagingProductID: mpID,
 					ProductDetails: &messaging_product_model.ProductDetails{
@@ -336,6 +451,11 @@ func handleMessages(value wh_model.Value, tx *gorm.DB, mpID uuid.UUID) ([]messag
 			if err != nil {
 				return err
 			}
+			if created && mpContact.Contact != nil {
+				handled.mu.Lock()
+				handled.createdContacts = append(handled.createdContacts, *mpContact.Contact)
+				handled.mu.Unlock()
+			}
 
 			// Building the message entity and creating with the mp contact found
 			msg := message_entity.Message{
@@ -349,13 +469,41 @@ func handleMessages(value wh_model.Value, tx *gorm.DB, mpID uuid.UUID) ([]messag
 			if msg.From.Blocked {
 				return nil
 			}
+			if message.Reaction != nil {
+				event, err := applyInboundReaction(*message.Reaction, message.Timestamp, mpID, mpContact.ID, tx)
+				if err != nil {
+					return err
+				}
+				if event != nil {
+					handled.mu.Lock()
+					handled.reactionEvents = append(handled.reactionEvents, *event)
+					handled.mu.Unlock()
+				}
+				return nil
+			}
 			err = tx.Model(&msg).Create(&msg).Error
 			if err != nil {
 				return err
 			}
-			msgsMu.Lock()
-			msgs = append(msgs, msg)
-			msgsMu.Unlock()
+			err = conversation_service.RecordMessageInConversation(msg, tx)
+			if err != nil {
+				return err
+			}
+			err = message_thread_service.RecordMessageReplies(msg, tx)
+			if err != nil {
+				return err
+			}
+			err = messaging_product_service.TouchLastInboundAt(
+				mpContact.ID,
+				messaging_product_service.ParseWhatsAppTimestamp(message.Timestamp),
+				tx,
+			)
+			if err != nil {
+				return err
+			}
+			handled.mu.Lock()
+			handled.messages = append(handled.messages, msg)
+			handled.mu.Unlock()
 			return nil
 		})
 	}
@@ -367,5 +515,20 @@ func handleMessages(value wh_model.Value, tx *gorm.DB, mpID uuid.UUID) ([]messag
 		)
 	}
 
-	return msgs, err
+	return err
+}
+
+// applyInboundReaction applies a reaction received from a contact to the
+// message it targets. Reactions count as inbound messages for the customer
+// service window.
+func applyInboundReaction(
+	reaction content.ReactionData, timestamp string, mpID uuid.UUID, contactID uuid.UUID, tx *gorm.DB,
+) (*message_reaction_model.ReactionEvent, error) {
+	reactedAt := messaging_product_service.ParseWhatsAppTimestamp(timestamp)
+	event, err := message_reaction_service.ApplyInboundReaction(mpID, contactID, reaction, reactedAt, tx)
+	if err != nil {
+		return nil, err
+	}
+	err = messaging_product_service.TouchLastInboundAt(contactID, reactedAt, tx)
+	return event, err
 }
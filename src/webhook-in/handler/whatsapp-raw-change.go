@@ -0,0 +1,100 @@
+package webhook_handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	account_update_service "github.com/Astervia/wacraft-server/src/account-update/service"
+	wh_model "github.com/Rfluid/whatsapp-cloud-api/src/webhook"
+	"github.com/gofiber/fiber/v2"
+)
+
+// rawChangesCtxKey stores the raw changes of a webhook request.
+const rawChangesCtxKey = "raw_changes"
+
+// rawChanges holds the raw values of the changes of a webhook. The parsed
+// webhook body only keeps the values of the messages field, so handlers of
+// other fields read their values from the raw body through RawChangesCtxHandler.
+type rawChanges struct {
+	mu     sync.Mutex
+	values map[wh_model.Field][]json.RawMessage
+	// account holds the changes of the account level fields, in the order
+	// of the webhook.
+	account []account_update_service.Change
+}
+
+// take returns the values of the field the first time it is called for the
+// field, and nil afterwards. Change handlers run once per change, so the
+// first run handles every change of the field.
+func (r *rawChanges) take(field wh_model.Field) []json.RawMessage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	values := r.values[field]
+	delete(r.values, field)
+	return values
+}
+
+// takeAccount returns the changes of every account level field the first
+// time it is called, and nil afterwards, so they are recorded together.
+func (r *rawChanges) takeAccount() []account_update_service.Change {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	changes := r.account
+	r.account = nil
+	return changes
+}
+
+// rawWebhookBody is the webhook body with the change values left raw.
+type rawWebhookBody struct {
+	Entry []struct {
+		// Time is when Meta sent the entry, in Unix seconds.
+		Time    int64 `json:"time"`
+		Changes []struct {
+			Field wh_model.Field  `json:"field"`
+			Value json.RawMessage `json:"value"`
+		} `json:"changes"`
+	} `json:"entry"`
+}
+
+// RawChangesCtxHandler reads the raw values of the changes of the webhook for
+// PhoneConfigAccountHandler and PhoneConfigEchoHandler. It runs once per
+// webhook, before the change handlers.
+func RawChangesCtxHandler(ctx *fiber.Ctx, body *wh_model.WebhookBody) error {
+	var raw rawWebhookBody
+	if err := json.Unmarshal(ctx.Body(), &raw); err != nil {
+		return err
+	}
+
+	// Meta delivers a webhook again with the same body, so the digest of the
+	// body and the index of a change identify the change across deliveries.
+	digest := sha256.Sum256(ctx.Body())
+	receivedAt := time.Now()
+
+	changes := &rawChanges{values: make(map[wh_model.Field][]json.RawMessage)}
+	index := 0
+	for _, entry := range raw.Entry {
+		reportedAt := receivedAt
+		if entry.Time > 0 {
+			reportedAt = time.Unix(entry.Time, 0)
+		}
+		for _, change := range entry.Changes {
+			changes.values[change.Field] = append(changes.values[change.Field], change.Value)
+			if slices.Contains(account_update_service.Fields, change.Field) {
+				changes.account = append(changes.account, account_update_service.Change{
+					Field:      change.Field,
+					Value:      change.Value,
+					Key:        hex.EncodeToString(digest[:]) + ":" + strconv.Itoa(index),
+					ReportedAt: reportedAt,
+				})
+			}
+			index++
+		}
+	}
+	ctx.Locals(rawChangesCtxKey, changes)
+	return nil
+}
@@ -0,0 +1,156 @@
+package webhook_handler
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Rfluid/whatsapp-cloud-api/src/message"
+	"github.com/Rfluid/whatsapp-cloud-api/src/message/content"
+)
+
+const echoValueBody = `{
+	"messaging_product": "whatsapp",
+	"metadata": {"display_phone_number": "15550000000", "phone_number_id": "123"},
+	"message_echoes": [{
+		"from": "15550000000",
+		"to": "5511999999999",
+		"id": "wamid.echo",
+		"timestamp": "1739321024",
+		"type": "text",
+		"text": {"body": "Sent from the app"},
+		"context": {"from": "5511999999999", "id": "wamid.question"}
+	}]
+}`
+
+const historyValueBody = `{
+	"messaging_product": "whatsapp",
+	"metadata": {"display_phone_number": "15550000000", "phone_number_id": "123"},
+	"history": [{
+		"metadata": {"phase": 0, "chunk_order": 1, "progress": 100},
+		"threads": [{
+			"id": "5511999999999",
+			"messages": [
+				{"from": "5511999999999", "id": "wamid.in", "timestamp": "1739320000", "type": "text", "text": {"body": "Hi"}, "history_context": {"status": "READ"}},
+				{"from": "15550000000", "to": "5511999999999", "id": "wamid.out", "timestamp": "1739320060", "type": "text", "text": {"body": "Hello"}, "history_context": {"status": "DELIVERED"}}
+			]
+		}]
+	}]
+}`
+
+func TestParseEchoes(t *testing.T) {
+	imported, err := parseEchoes(json.RawMessage(echoValueBody))
+	if err != nil {
+		t.Fatalf("parseEchoes: %v", err)
+	}
+	if len(imported) != 1 {
+		t.Fatalf("expected 1 echo, got %d", len(imported))
+	}
+
+	echo := imported[0]
+	if !echo.outbound || echo.customer != "5511999999999" {
+		t.Errorf("got customer %q outbound %v, want the recipient as an outbound message", echo.customer, echo.outbound)
+	}
+	if echo.message.ID != "wamid.echo" || echo.message.Timestamp != "1739321024" {
+		t.Errorf("got id %q timestamp %q", echo.message.ID, echo.message.Timestamp)
+	}
+}
+
+func TestParseHistory_DirectionFollowsTheThread(t *testing.T) {
+	imported, errs, err := parseHistory(json.RawMessage(historyValueBody))
+	if err != nil {
+		t.Fatalf("parseHistory: %v", err)
+	}
+	if len(errs) != 0 {
+		t.Errorf("expected no errors, got %d", len(errs))
+	}
+	if len(imported) != 2 {
+		t.Fatalf("expected 2 messages, got %d", len(imported))
+	}
+
+	if imported[0].outbound || imported[0].message.ID != "wamid.in" {
+		t.Errorf("expected the customer message first and inbound, got %+v", imported[0])
+	}
+	if !imported[1].outbound || imported[1].message.ID != "wamid.out" {
+		t.Errorf("expected the business message second and outbound, got %+v", imported[1])
+	}
+	for _, msg := range imported {
+		if msg.customer != "5511999999999" {
+			t.Errorf("expected the thread customer, got %q", msg.customer)
+		}
+	}
+}
+
+func TestParseHistory_Declined(t *testing.T) {
+	body := `{"messaging_product": "whatsapp", "errors": [{"code": 2593109, "message": "History sharing is turned off"}]}`
+	imported, errs, err := parseHistory(json.RawMessage(body))
+	if err != nil {
+		t.Fatalf("parseHistory: %v", err)
+	}
+	if len(imported) != 0 {
+		t.Errorf("expected no messages, got %d", len(imported))
+	}
+	if len(errs) != 1 || errs[0].Code != 2593109 {
+		t.Errorf("expected the declined error, got %+v", errs)
+	}
+}
+
+func TestSentMessage(t *testing.T) {
+	imported, err := parseEchoes(json.RawMessage(echoValueBody))
+	if err != nil {
+		t.Fatalf("parseEchoes: %v", err)
+	}
+
+	sent := sentMessage(imported[0].message.MessageReceived, imported[0].customer)
+	if sent.To != "5511999999999" || sent.Type != content.Type("text") {
+		t.Errorf("got to %q type %q", sent.To, sent.Type)
+	}
+	if sent.Text == nil || sent.Text.Body != "Sent from the app" {
+		t.Errorf("expected the text of the echo, got %+v", sent.Text)
+	}
+	if sent.Context == nil || sent.Context.MessageID != "wamid.question" {
+		t.Errorf("expected the context of the echo, got %+v", sent.Context)
+	}
+	if sent.MessagingProduct.MessagingProduct != "whatsapp" || sent.RecipientType != "individual" {
+		t.Errorf("expected the default fields, got %q %q", sent.MessagingProduct.MessagingProduct, sent.RecipientType)
+	}
+}
+
+func TestHistoryStatus(t *testing.T) {
+	imported, _, err := parseHistory(json.RawMessage(historyValueBody))
+	if err != nil {
+		t.Fatalf("parseHistory: %v", err)
+	}
+	if status, ok := historyStatus(imported[1].message); !ok || status != message.Delivered {
+		t.Errorf("expected the history status of the message, got %q (ok=%v)", status, ok)
+	}
+
+	cases := map[string]message.SendingStatus{
+		"SENT":      message.Sent,
+		"DELIVERED": message.Delivered,
+		"READ":      message.Read,
+		"PLAYED":    message.Read,
+		"ERROR":     message.Failed,
+	}
+	for value, want := range cases {
+		msg := echoMessage{}
+		msg.HistoryContext = &struct {
+			Status string `json:"status"`
+		}{Status: value}
+		if got, ok := historyStatus(msg); !ok || got != want {
+			t.Errorf("%s: expected %s, got %q (ok=%v)", value, want, got, ok)
+		}
+	}
+
+	for _, value := range []string{"PENDING", ""} {
+		msg := echoMessage{}
+		msg.HistoryContext = &struct {
+			Status string `json:"status"`
+		}{Status: value}
+		if got, ok := historyStatus(msg); ok {
+			t.Errorf("%q: expected no status, got %s", value, got)
+		}
+	}
+	if got, ok := historyStatus(echoMessage{}); ok {
+		t.Errorf("echo: expected no status, got %s", got)
+	}
+}
@@ -0,0 +1,85 @@
+package webhook_handler
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/Astervia/wacraft-server/src/database"
+	inbound_receipt_entity "github.com/Astervia/wacraft-server/src/inbound-receipt/entity"
+	inbound_receipt_service "github.com/Astervia/wacraft-server/src/inbound-receipt/service"
+	wh_model "github.com/Rfluid/whatsapp-cloud-api/src/webhook"
+	"github.com/google/uuid"
+)
+
+// --- Test bootstrap ---
+
+func TestMain(m *testing.M) {
+	database.DB.AutoMigrate(&inbound_receipt_entity.InboundReceipt{})
+	os.Exit(m.Run())
+}
+
+const messageValueBody = `{
+	"messaging_product": "whatsapp",
+	"metadata": {"display_phone_number": "15550000000", "phone_number_id": "123"},
+	"contacts": [{"profile": {"name": "Ada"}, "wa_id": "5511999999999"}],
+	"messages": [{"from": "5511999999999", "id": "wamid.redelivered", "timestamp": "1739321024", "type": "text", "text": {"body": "Hi"}}]
+}`
+
+// redeliveredMessage returns the value of a message whose receipt was already
+// claimed for a new messaging product.
+func redeliveredMessage(t *testing.T) (wh_model.Value, uuid.UUID) {
+	t.Helper()
+	var value wh_model.Value
+	if err := json.Unmarshal([]byte(messageValueBody), &value); err != nil {
+		t.Fatalf("unmarshal value: %v", err)
+	}
+
+	mpID := uuid.New()
+	t.Cleanup(func() {
+		database.DB.Exec("DELETE FROM inbound_receipts WHERE messaging_product_id = ?", mpID)
+	})
+	claimed, err := inbound_receipt_service.Claim(mpID, (*value.Messages)[0].ID, inbound_receipt_entity.ReceiptMessage, database.DB)
+	if err != nil || !claimed {
+		t.Fatalf("Claim: claimed=%v err=%v", claimed, err)
+	}
+	return value, mpID
+}
+
+// assertSkipped checks that handling the redelivered message stored nothing.
+func assertSkipped(t *testing.T, err error, handled *handledMessages) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("expected the redelivered message to be skipped, got %v", err)
+	}
+	if len(handled.messages) != 0 || len(handled.createdContacts) != 0 || len(handled.conversationEvents) != 0 {
+		t.Errorf("expected nothing to be handled, got %d messages and %d contacts", len(handled.messages), len(handled.createdContacts))
+	}
+}
+
+func TestHandleMessagesWithWorkspace_SkipsRedeliveredMessages(t *testing.T) {
+	value, mpID := redeliveredMessage(t)
+	workspaceID := uuid.New()
+	before := inbound_receipt_service.GetDuplicates()
+
+	var handled handledMessages
+	err := handleMessagesWithWorkspace(value, database.DB, mpID, &workspaceID, &handled)
+	assertSkipped(t, err, &handled)
+
+	if after := inbound_receipt_service.GetDuplicates(); after.Messages != before.Messages+1 {
+		t.Errorf("expected the duplicate to be counted, got %d", after.Messages-before.Messages)
+	}
+}
+
+func TestHandleMessages_SkipsRedeliveredMessages(t *testing.T) {
+	value, mpID := redeliveredMessage(t)
+	before := inbound_receipt_service.GetDuplicates()
+
+	var handled handledMessages
+	err := handleMessages(value, database.DB, mpID, &handled)
+	assertSkipped(t, err, &handled)
+
+	if after := inbound_receipt_service.GetDuplicates(); after.Messages != before.Messages+1 {
+		t.Errorf("expected the duplicate to be counted, got %d", after.Messages-before.Messages)
+	}
+}
@@ -0,0 +1,113 @@
+package webhook_handler
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	wh_model "github.com/Rfluid/whatsapp-cloud-api/src/webhook"
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+)
+
+const accountWebhookBody = `{
+	"object": "whatsapp_business_account",
+	"entry": [{
+		"id": "waba-1",
+		"time": 1739321024,
+		"changes": [
+			{"field": "message_template_status_update", "value": {"event": "APPROVED", "message_template_id": 1}},
+			{"field": "message_template_status_update", "value": {"event": "REJECTED", "message_template_id": 2, "reason": "INCORRECT_CATEGORY"}},
+			{"field": "phone_number_quality_update", "value": {"event": "DOWNGRADE", "current_limit": "TIER_1K"}}
+		]
+	}]
+}`
+
+func rawChangesCtx(t *testing.T, body string) (*fiber.App, *fiber.Ctx) {
+	t.Helper()
+	app := fiber.New()
+	ctx := app.AcquireCtx(&fasthttp.RequestCtx{})
+	ctx.Request().SetBody([]byte(body))
+	if err := RawChangesCtxHandler(ctx, &wh_model.WebhookBody{}); err != nil {
+		t.Fatalf("RawChangesCtxHandler: %v", err)
+	}
+	return app, ctx
+}
+
+func TestRawChanges_TakeReturnsEveryChangeOfTheFieldOnce(t *testing.T) {
+	app, ctx := rawChangesCtx(t, accountWebhookBody)
+	defer app.ReleaseCtx(ctx)
+
+	changes, ok := ctx.Locals(rawChangesCtxKey).(*rawChanges)
+	if !ok {
+		t.Fatal("expected raw changes in locals")
+	}
+
+	values := changes.take(wh_model.MessageTemplateStatusUpdate)
+	if len(values) != 2 {
+		t.Fatalf("expected 2 template changes, got %d", len(values))
+	}
+	var second struct {
+		Event  string `json:"event"`
+		Reason string `json:"reason"`
+	}
+	if err := json.Unmarshal(values[1], &second); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if second.Event != "REJECTED" || second.Reason != "INCORRECT_CATEGORY" {
+		t.Errorf("got %+v, want the REJECTED change with its reason", second)
+	}
+
+	// The handler runs once per change; only the first run gets the values.
+	if values := changes.take(wh_model.MessageTemplateStatusUpdate); values != nil {
+		t.Errorf("expected no values on second take, got %d", len(values))
+	}
+
+	if values := changes.take(wh_model.PhoneNumberQualityUpdate); len(values) != 1 {
+		t.Errorf("expected 1 quality change, got %d", len(values))
+	}
+}
+
+func TestRawChanges_TakeAccountKeysEveryChange(t *testing.T) {
+	app, ctx := rawChangesCtx(t, accountWebhookBody)
+	defer app.ReleaseCtx(ctx)
+	changes := ctx.Locals(rawChangesCtxKey).(*rawChanges)
+
+	account := changes.takeAccount()
+	if len(account) != 3 {
+		t.Fatalf("expected 3 account changes, got %d", len(account))
+	}
+	keys := make(map[string]bool, len(account))
+	for _, change := range account {
+		keys[change.Key] = true
+		if !change.ReportedAt.Equal(time.Unix(1739321024, 0)) {
+			t.Errorf("expected the entry time, got %s", change.ReportedAt)
+		}
+	}
+	if len(keys) != 3 {
+		t.Errorf("expected a key per change, got %v", keys)
+	}
+	if values := changes.takeAccount(); values != nil {
+		t.Errorf("expected no changes on second take, got %d", len(values))
+	}
+
+	// A redelivery of the webhook keys its changes the same way.
+	redeliveredApp, redelivered := rawChangesCtx(t, accountWebhookBody)
+	defer redeliveredApp.ReleaseCtx(redelivered)
+	for i, change := range redelivered.Locals(rawChangesCtxKey).(*rawChanges).takeAccount() {
+		if change.Key != account[i].Key {
+			t.Errorf("change %d: expected key %s on redelivery, got %s", i, account[i].Key, change.Key)
+		}
+	}
+}
+
+func TestRawChanges_InvalidBody(t *testing.T) {
+	app := fiber.New()
+	ctx := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(ctx)
+	ctx.Request().SetBody([]byte("not json"))
+
+	if err := RawChangesCtxHandler(ctx, &wh_model.WebhookBody{}); err == nil {
+		t.Error("expected an error for an invalid body")
+	}
+}
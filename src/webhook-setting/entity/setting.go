@@ -0,0 +1,59 @@
+package webhook_setting_entity
+
+import (
+	"time"
+
+	common_model "github.com/Astervia/wacraft-core/src/common/model"
+	webhook_entity "github.com/Astervia/wacraft-core/src/webhook/entity"
+	webhook_transform_model "github.com/Astervia/wacraft-server/src/webhook-transform/model"
+	"github.com/google/uuid"
+)
+
+// OrderingKey selects which deliveries of a webhook are sent in order.
+type OrderingKey string
+
+const (
+	// OrderingNone sends deliveries concurrently, in no particular order.
+	OrderingNone OrderingKey = ""
+	// OrderingContact sends the events of each messaging product contact in
+	// the order they were queued.
+	OrderingContact OrderingKey = "contact"
+	// OrderingCampaign sends the events of each campaign in the order they
+	// were queued.
+	OrderingCampaign OrderingKey = "campaign"
+)
+
+// WebhookSetting holds the delivery options of a webhook that are specific to
+// this server. Webhooks without a setting use the zero value.
+type WebhookSetting struct {
+	WebhookID   uuid.UUID               `json:"webhook_id" gorm:"type:uuid;not null;uniqueIndex"`
+	Webhook     *webhook_entity.Webhook `json:"-" gorm:"foreignKey:WebhookID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	OrderingKey OrderingKey             `json:"ordering_key" gorm:"not null;default:''"`
+
+	// BatchSize is the max number of deliveries sent together as a JSON array
+	// in one request. 0 or 1 sends every delivery on its own.
+	BatchSize int `json:"batch_size" gorm:"not null;default:0"`
+	// BatchMaxWaitMs is how long a delivery may wait for others to fill its
+	// batch before the batch is sent anyway.
+	BatchMaxWaitMs int `json:"batch_max_wait_ms" gorm:"not null;default:0"`
+
+	// Transform reshapes the payload before it is sent. nil sends the payload as is.
+	Transform *webhook_transform_model.WebhookTransform `json:"transform,omitempty" gorm:"type:jsonb;serializer:json"`
+
+	// CircuitForcedOpen holds the deliveries of the webhook until its circuit
+	// is closed through the API.
+	CircuitForcedOpen bool `json:"circuit_forced_open" gorm:"not null;default:false"`
+
+	// PreviousSigningSecret is the secret replaced by the last rotation. It
+	// signs deliveries next to the current one until it expires, then it is
+	// erased. Never returned by the API.
+	PreviousSigningSecret          string     `json:"-" gorm:"not null;default:''"`
+	PreviousSigningSecretExpiresAt *time.Time `json:"previous_signing_secret_expires_at,omitempty"`
+
+	common_model.Audit
+}
+
+// Batching reports whether the webhook sends its deliveries in batches.
+func (s WebhookSetting) Batching() bool {
+	return s.BatchSize > 1
+}
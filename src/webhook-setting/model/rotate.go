@@ -0,0 +1,18 @@
+package webhook_setting_model
+
+import "time"
+
+// RotateSigningSecret is the optional body of the signing secret rotation endpoint.
+type RotateSigningSecret struct {
+	// GracePeriodSeconds is how long the old secret keeps signing deliveries
+	// next to the new one. Defaults to WEBHOOK_SECRET_ROTATION_GRACE; 0
+	// retires it at once.
+	GracePeriodSeconds *int `json:"grace_period_seconds,omitempty" validate:"omitempty,min=0,max=2592000"`
+}
+
+// RotateSigningSecretResponse holds the new signing secret, which is only
+// returned once.
+type RotateSigningSecretResponse struct {
+	SigningSecret                  string     `json:"signing_secret"`
+	PreviousSigningSecretExpiresAt *time.Time `json:"previous_signing_secret_expires_at,omitempty"` // When the old secret stops signing deliveries.
+}
@@ -0,0 +1,23 @@
+package webhook_setting_model
+
+import (
+	webhook_setting_entity "github.com/Astervia/wacraft-server/src/webhook-setting/entity"
+	webhook_transform_model "github.com/Astervia/wacraft-server/src/webhook-transform/model"
+)
+
+// UpdateWebhookSetting is the body of the webhook setting update endpoint.
+// Omitted fields are left unchanged.
+type UpdateWebhookSetting struct {
+	// OrderingKey sends the deliveries sharing a contact or campaign one at a
+	// time, in the order they were queued. Empty turns ordering off.
+	OrderingKey *webhook_setting_entity.OrderingKey `json:"ordering_key,omitempty" validate:"omitempty,oneof=contact campaign"`
+	// BatchSize sends up to this many deliveries as one JSON array. 0 or 1
+	// turns batching off.
+	BatchSize *int `json:"batch_size,omitempty" validate:"omitempty,min=0,max=100"`
+	// BatchMaxWaitMs is how long a delivery may wait for its batch to fill up.
+	BatchMaxWaitMs *int `json:"batch_max_wait_ms,omitempty" validate:"omitempty,min=0,max=300000"`
+	// Transform reshapes the payload of the deliveries sent from now on.
+	Transform *webhook_transform_model.WebhookTransform `json:"transform,omitempty"`
+	// ClearTransform sends the payload as is again.
+	ClearTransform bool `json:"clear_transform,omitempty"`
+}
@@ -0,0 +1,76 @@
+package webhook_setting_service
+
+import (
+	"errors"
+
+	"github.com/Astervia/wacraft-server/src/database"
+	webhook_setting_entity "github.com/Astervia/wacraft-server/src/webhook-setting/entity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetWebhookSetting returns the settings of a webhook.
+// When the webhook has no settings yet, a zero-value setting is returned.
+func GetWebhookSetting(webhookID uuid.UUID, db *gorm.DB) (webhook_setting_entity.WebhookSetting, error) {
+	if db == nil {
+		db = database.DB
+	}
+
+	setting := webhook_setting_entity.WebhookSetting{WebhookID: webhookID}
+	err := db.Where("webhook_id = ?", webhookID).First(&setting).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return webhook_setting_entity.WebhookSetting{WebhookID: webhookID}, nil
+	}
+
+	return setting, err
+}
+
+// GetWebhookSettings returns the settings of the given webhooks by webhook ID,
+// loaded in one query. Webhooks without settings are left out of the map and
+// use the zero value.
+func GetWebhookSettings(webhookIDs []uuid.UUID, db *gorm.DB) (map[uuid.UUID]webhook_setting_entity.WebhookSetting, error) {
+	if db == nil {
+		db = database.DB
+	}
+
+	var settings []webhook_setting_entity.WebhookSetting
+	if err := db.Where("webhook_id IN ?", webhookIDs).Find(&settings).Error; err != nil {
+		return nil, err
+	}
+
+	byWebhook := make(map[uuid.UUID]webhook_setting_entity.WebhookSetting, len(settings))
+	for _, setting := range settings {
+		byWebhook[setting.WebhookID] = setting
+	}
+	return byWebhook, nil
+}
+
+// UpdateWebhookSetting creates the settings row of a webhook if needed and applies updates to it.
+func UpdateWebhookSetting(
+	webhookID uuid.UUID,
+	updates map[string]any,
+	db *gorm.DB,
+) (webhook_setting_entity.WebhookSetting, error) {
+	if db == nil {
+		db = database.DB
+	}
+
+	setting := webhook_setting_entity.WebhookSetting{WebhookID: webhookID}
+	if err := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "webhook_id"}},
+		DoNothing: true,
+	}).Create(&setting).Error; err != nil {
+		return setting, err
+	}
+
+	if len(updates) > 0 {
+		if err := db.Model(&webhook_setting_entity.WebhookSetting{}).
+			Where("webhook_id = ?", webhookID).
+			Updates(updates).Error; err != nil {
+			return setting, err
+		}
+	}
+
+	return GetWebhookSetting(webhookID, db)
+}
@@ -0,0 +1,16 @@
+package webhook_transform_model
+
+// PreviewTransform is the body of the transform preview endpoint.
+type PreviewTransform struct {
+	// Transform to render. Defaults to the transform saved for the webhook.
+	Transform *WebhookTransform `json:"transform,omitempty"`
+	// Payload to render the transform with. Defaults to the payload of the
+	// latest delivery of the webhook, or a test payload when there is none.
+	Payload any `json:"payload,omitempty"`
+}
+
+// PreviewResult is the body the webhook would receive.
+type PreviewResult struct {
+	ContentType string `json:"content_type"`
+	Body        string `json:"body"`
+}
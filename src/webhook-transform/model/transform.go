@@ -0,0 +1,284 @@
+// Package webhook_transform_model reshapes webhook payloads before they are
+// sent, so a webhook can post straight into receivers that expect their own
+// body, such as Slack or Teams incoming webhooks.
+//
+// A transform is either a Go text/template executed with the payload:
+//
+//	{"text": {{json (printf "%v happened at %v" .event .occurred_at)}}}
+//
+// or a JSON field mapping from output fields to payload paths:
+//
+//	{"text": "data.receiver_data.text.body", "meta.event": "event"}
+//
+// In both cases the payload is the JSON document that would otherwise be
+// sent, so fields are referred to by their JSON names.
+package webhook_transform_model
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+)
+
+const (
+	// DefaultContentType is sent when the transform does not set one.
+	DefaultContentType = "application/json"
+	// MaxTemplateSize is the max length of a template, in bytes.
+	MaxTemplateSize = 64 * 1024
+	// MaxMappingFields is the max number of fields of a mapping.
+	MaxMappingFields = 100
+	// MaxOutputSize is the max size of a rendered body, in bytes.
+	MaxOutputSize = 1024 * 1024
+	// MaxTemplateSteps is the max number of actions, loop iterations and
+	// template calls a template may run.
+	MaxTemplateSteps = 100000
+	// MaxRenderTime is the max time a template may run.
+	MaxRenderTime = time.Second
+)
+
+// ErrInvalidTransform is wrapped by every error caused by the transform content.
+var ErrInvalidTransform = errors.New("invalid transform")
+
+// WebhookTransform reshapes the payload of a webhook. Exactly one of
+// Template or Mapping must be set.
+type WebhookTransform struct {
+	// Template is a Go text/template executed with the payload. The json
+	// function renders a value as JSON, e.g. {{json .event}}. Rendering
+	// fails past MaxTemplateSteps steps or MaxRenderTime.
+	Template string `json:"template,omitempty"`
+	// Mapping builds a JSON object from payload paths, by output field.
+	// Dotted output fields build nested objects; paths index arrays with
+	// numbers, e.g. "data.product_data.messages.0.id". Missing paths are null.
+	Mapping map[string]string `json:"mapping,omitempty"`
+	// ContentType of the rendered body. Defaults to application/json.
+	ContentType string `json:"content_type,omitempty"`
+}
+
+var (
+	pathPattern        = regexp.MustCompile(`^[A-Za-z0-9_]+(\.[A-Za-z0-9_]+)*$`)
+	contentTypePattern = regexp.MustCompile(`^[\w!#$&^.+-]+/[\w!#$&^.+-]+(\s*;\s*[\w-]+=[\w.-]+)*$`)
+	templateFuncs      = template.FuncMap{
+		"json": func(v any) (string, error) {
+			b, err := json.Marshal(v)
+			return string(b), err
+		},
+	}
+)
+
+func invalid(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrInvalidTransform, fmt.Sprintf(format, args...))
+}
+
+// Validate checks the transform without rendering it.
+func (t WebhookTransform) Validate() error {
+	_, err := t.parse()
+	return err
+}
+
+// GetContentType returns the content type of the rendered body.
+func (t WebhookTransform) GetContentType() string {
+	if t.ContentType == "" {
+		return DefaultContentType
+	}
+	return t.ContentType
+}
+
+// Render reshapes payload, the JSON document that would be sent without the
+// transform, and returns the body to send.
+func (t WebhookTransform) Render(payload []byte) ([]byte, error) {
+	tmpl, err := t.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	var data any
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return nil, fmt.Errorf("unable to decode payload: %w", err)
+	}
+
+	if tmpl != nil {
+		tmpl.Funcs(template.FuncMap{stepFunc: newStepLimit()})
+		var out limitedBuffer
+		if err := tmpl.Execute(&out, data); err != nil {
+			return nil, fmt.Errorf("unable to execute template: %w", err)
+		}
+		return out.Bytes(), nil
+	}
+
+	result := map[string]any{}
+	for field, path := range t.Mapping {
+		if err := set(result, strings.Split(field, "."), lookup(data, path)); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(result)
+}
+
+// parse validates the transform and returns its template, nil for mappings.
+func (t WebhookTransform) parse() (*template.Template, error) {
+	if (t.Template == "") == (len(t.Mapping) == 0) {
+		return nil, invalid("exactly one of template or mapping must be set")
+	}
+	if t.ContentType != "" && !contentTypePattern.MatchString(t.ContentType) {
+		return nil, invalid("invalid content type %q", t.ContentType)
+	}
+
+	if t.Template != "" {
+		if len(t.Template) > MaxTemplateSize {
+			return nil, invalid("template is larger than %d bytes", MaxTemplateSize)
+		}
+		tmpl, err := template.New("webhook").Funcs(templateFuncs).Parse(t.Template)
+		if err != nil {
+			return nil, invalid("%v", err)
+		}
+		for _, defined := range tmpl.Templates() {
+			if defined.Tree != nil {
+				limitList(defined.Tree.Root)
+			}
+		}
+		return tmpl, nil
+	}
+
+	if len(t.Mapping) > MaxMappingFields {
+		return nil, invalid("mapping has more than %d fields", MaxMappingFields)
+	}
+	for field, path := range t.Mapping {
+		if !pathPattern.MatchString(field) {
+			return nil, invalid("invalid mapping field %q", field)
+		}
+		if !pathPattern.MatchString(path) {
+			return nil, invalid("invalid path %q of mapping field %q", path, field)
+		}
+	}
+	return nil, nil
+}
+
+// lookup returns the value at a dotted path of a decoded JSON document, or
+// nil when the path does not exist.
+func lookup(data any, path string) any {
+	for _, segment := range strings.Split(path, ".") {
+		switch node := data.(type) {
+		case map[string]any:
+			data = node[segment]
+		case []any:
+			i, err := strconv.Atoi(segment)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil
+			}
+			data = node[i]
+		default:
+			return nil
+		}
+	}
+	return data
+}
+
+// set stores value at the nested object path of result.
+func set(result map[string]any, path []string, value any) error {
+	for _, segment := range path[:len(path)-1] {
+		next, ok := result[segment].(map[string]any)
+		if !ok {
+			if _, taken := result[segment]; taken {
+				return invalid("mapping field %q is both a value and an object", segment)
+			}
+			next = map[string]any{}
+			result[segment] = next
+		}
+		result = next
+	}
+
+	last := path[len(path)-1]
+	if _, taken := result[last]; taken {
+		return invalid("mapping field %q is both a value and an object", last)
+	}
+	result[last] = value
+	return nil
+}
+
+// limitedBuffer fails writes past MaxOutputSize, so a template cannot build
+// an arbitrarily large body.
+type limitedBuffer struct {
+	bytes.Buffer
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.Len()+len(p) > MaxOutputSize {
+		return 0, fmt.Errorf("rendered body is larger than %d bytes", MaxOutputSize)
+	}
+	return b.Buffer.Write(p)
+}
+
+// stepFunc is called by the actions limitList adds to a template. It is only
+// bound when rendering, so templates cannot call it themselves.
+const stepFunc = "step"
+
+// newStepLimit returns the step function of a render, which fails once the
+// template ran MaxTemplateSteps steps or for MaxRenderTime, so a template
+// such as {{range 100000000000}}{{end}} cannot run without bounds.
+func newStepLimit() func() (string, error) {
+	steps := 0
+	deadline := time.Now().Add(MaxRenderTime)
+	return func() (string, error) {
+		steps++
+		if steps > MaxTemplateSteps {
+			return "", fmt.Errorf("template runs more than %d steps", MaxTemplateSteps)
+		}
+		if time.Now().After(deadline) {
+			return "", fmt.Errorf("template runs longer than %v", MaxRenderTime)
+		}
+		return "", nil
+	}
+}
+
+// limitList adds a step to the start of list and before each of its
+// actions, recursively. Every loop iteration and template call runs a list,
+// so each of them is a step too.
+func limitList(list *parse.ListNode) {
+	if list == nil {
+		return
+	}
+	nodes := []parse.Node{stepAction(list.Position())}
+	for _, node := range list.Nodes {
+		switch node := node.(type) {
+		case *parse.ActionNode, *parse.TemplateNode:
+			nodes = append(nodes, stepAction(node.Position()))
+		case *parse.IfNode:
+			limitList(node.List)
+			limitList(node.ElseList)
+		case *parse.RangeNode:
+			limitList(node.List)
+			limitList(node.ElseList)
+		case *parse.WithNode:
+			limitList(node.List)
+			limitList(node.ElseList)
+		case *parse.ListNode:
+			limitList(node)
+		}
+		nodes = append(nodes, node)
+	}
+	list.Nodes = nodes
+}
+
+// stepAction returns the action {{step}} at pos.
+func stepAction(pos parse.Pos) *parse.ActionNode {
+	return &parse.ActionNode{
+		NodeType: parse.NodeAction,
+		Pos:      pos,
+		Pipe: &parse.PipeNode{
+			NodeType: parse.NodePipe,
+			Pos:      pos,
+			Cmds: []*parse.CommandNode{{
+				NodeType: parse.NodeCommand,
+				Pos:      pos,
+				Args:     []parse.Node{(&parse.IdentifierNode{NodeType: parse.NodeIdentifier, Ident: stepFunc}).SetPos(pos)},
+			}},
+		},
+	}
+}
@@ -0,0 +1,142 @@
+package webhook_transform_model
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testPayload = []byte(`{
+	"event": "message.reaction",
+	"data": {
+		"id": "abc",
+		"product_data": {"messages": [{"id": "wamid.1"}]},
+		"receiver_data": {"text": {"body": "hi \"there\""}}
+	}
+}`)
+
+func TestRender_Template(t *testing.T) {
+	transform := WebhookTransform{
+		Template:    `{"text": {{json (printf "%v: %v" .event .data.receiver_data.text.body)}}}`,
+		ContentType: "application/json; charset=utf-8",
+	}
+
+	body, err := transform.Render(testPayload)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := `{"text": "message.reaction: hi \"there\""}`
+	if string(body) != want {
+		t.Errorf("expected %s, got %s", want, body)
+	}
+	if got := transform.GetContentType(); got != "application/json; charset=utf-8" {
+		t.Errorf("unexpected content type %q", got)
+	}
+}
+
+func TestRender_Mapping(t *testing.T) {
+	transform := WebhookTransform{
+		Mapping: map[string]string{
+			"text":       "data.receiver_data.text.body",
+			"meta.event": "event",
+			"meta.wamid": "data.product_data.messages.0.id",
+			"missing":    "data.product_data.messages.3.id",
+		},
+	}
+
+	body, err := transform.Render(testPayload)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("invalid JSON %s: %v", body, err)
+	}
+	want := map[string]any{
+		"text":    `hi "there"`,
+		"meta":    map[string]any{"event": "message.reaction", "wamid": "wamid.1"},
+		"missing": nil,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %#v, got %#v", want, got)
+	}
+	if got := transform.GetContentType(); got != DefaultContentType {
+		t.Errorf("unexpected content type %q", got)
+	}
+}
+
+func TestValidate_Rejects(t *testing.T) {
+	cases := map[string]WebhookTransform{
+		"empty":            {},
+		"both":             {Template: "{{.event}}", Mapping: map[string]string{"a": "event"}},
+		"bad template":     {Template: "{{.event"},
+		"unknown function": {Template: "{{exec .event}}"},
+		"bad field":        {Mapping: map[string]string{"a..b": "event"}},
+		"bad path":         {Mapping: map[string]string{"a": "data['id']"}},
+		"bad content type": {Template: "{{.event}}", ContentType: "text/plain\r\nX-Injected: 1"},
+		"large template":   {Template: strings.Repeat("a", MaxTemplateSize+1)},
+	}
+	for name, transform := range cases {
+		if err := transform.Validate(); !errors.Is(err, ErrInvalidTransform) {
+			t.Errorf("%s: expected ErrInvalidTransform, got %v", name, err)
+		}
+	}
+}
+
+func TestRender_Conflicts(t *testing.T) {
+	transform := WebhookTransform{
+		Mapping: map[string]string{"a": "event", "a.b": "event"},
+	}
+	if _, err := transform.Render(testPayload); !errors.Is(err, ErrInvalidTransform) {
+		t.Errorf("expected ErrInvalidTransform, got %v", err)
+	}
+}
+
+func TestRender_OutputLimit(t *testing.T) {
+	transform := WebhookTransform{
+		Template: `{{printf "%0*d" 900000 0}}{{printf "%0*d" 900000 0}}`,
+	}
+	if _, err := transform.Render(testPayload); err == nil || !strings.Contains(err.Error(), "larger than") {
+		t.Errorf("expected an oversized body error, got %v", err)
+	}
+}
+
+func TestRender_StepLimit(t *testing.T) {
+	cases := map[string]string{
+		"range over a number": `{{range 100000000000}}{{end}}`,
+		"nested ranges":       `{{range 10000}}{{range 10000}}{{$x := 1}}{{end}}{{end}}`,
+		"recursive template":  `{{define "a"}}{{template "a" .}}{{template "a" .}}{{end}}{{template "a" .}}`,
+	}
+	for name, tmpl := range cases {
+		start := time.Now()
+		_, err := WebhookTransform{Template: tmpl}.Render(testPayload)
+		if err == nil || !strings.Contains(err.Error(), "steps") {
+			t.Errorf("%s: expected a step limit error, got %v", name, err)
+		}
+		if elapsed := time.Since(start); elapsed > MaxRenderTime {
+			t.Errorf("%s: render ran for %v", name, elapsed)
+		}
+	}
+
+	if err := (WebhookTransform{Template: "{{step}}"}).Validate(); !errors.Is(err, ErrInvalidTransform) {
+		t.Errorf("expected templates not to call the step function, got %v", err)
+	}
+}
+
+func TestRender_Loops(t *testing.T) {
+	transform := WebhookTransform{
+		Template: `{{range $i, $m := .data.product_data.messages}}{{$i}}:{{$m.id}}{{end}} {{range 3}}{{.}}{{else}}none{{end}}`,
+	}
+	body, err := transform.Render(testPayload)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "0:wamid.1 012"; string(body) != want {
+		t.Errorf("expected %s, got %s", want, body)
+	}
+}
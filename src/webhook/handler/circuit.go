@@ -0,0 +1,142 @@
+package webhook_handler
+
+import (
+	common_model "github.com/Astervia/wacraft-core/src/common/model"
+	"github.com/Astervia/wacraft-server/src/validators"
+	webhook_service "github.com/Astervia/wacraft-server/src/webhook/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetAnalytics returns the delivery health of a webhook.
+//
+//	@Summary		Get webhook analytics
+//	@Description	Aggregates the webhook logs of the last hours (default 24): requests, success rate, p50/p95/p99 latency from duration_ms and failed attempts by HTTP code, where 0 means no response. Also returns the deliveries queued by status and the circuit breaker state.
+//	@Tags			Webhook
+//	@Produce		json
+//	@Param			id		path		string							true	"Webhook ID"
+//	@Param			query	query		webhook_service.AnalyticsQuery	false	"Period"
+//	@Success		200		{object}	webhook_service.WebhookAnalytics	"Webhook analytics"
+//	@Failure		400		{object}	common_model.DescriptiveError	"Invalid query parameters"
+//	@Failure		404		{object}	common_model.DescriptiveError	"Webhook not found"
+//	@Failure		500		{object}	common_model.DescriptiveError	"Internal server error"
+//	@Router			/webhook/{id}/analytics [get]
+//	@Security		ApiKeyAuth
+//	@Security		WorkspaceAuth
+func GetAnalytics(c *fiber.Ctx) error {
+	webhook, ok, err := findWebhook(c)
+	if !ok {
+		return err
+	}
+
+	query := new(webhook_service.AnalyticsQuery)
+	if err := c.QueryParser(query); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewParseJsonError(err).Send(),
+		)
+	}
+
+	if err := validators.Validator().Struct(query); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewValidationError(err).Send(),
+		)
+	}
+
+	analytics, err := webhook_service.GetAnalytics(webhook, *query)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			common_model.NewApiError("unable to get webhook analytics", err, "webhook_service").Send(),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(analytics)
+}
+
+// GetCircuit returns the circuit breaker state of a webhook.
+//
+//	@Summary		Get webhook circuit
+//	@Description	Returns the circuit breaker state of the webhook: closed, open, half_open or forced_open, with its failure count and, while open, when the next probe is let through.
+//	@Tags			Webhook
+//	@Produce		json
+//	@Param			id	path		string							true	"Webhook ID"
+//	@Success		200	{object}	webhook_service.CircuitStatus	"Circuit state"
+//	@Failure		400	{object}	common_model.DescriptiveError	"Invalid webhook ID"
+//	@Failure		404	{object}	common_model.DescriptiveError	"Webhook not found"
+//	@Failure		500	{object}	common_model.DescriptiveError	"Internal server error"
+//	@Router			/webhook/{id}/circuit [get]
+//	@Security		ApiKeyAuth
+//	@Security		WorkspaceAuth
+func GetCircuit(c *fiber.Ctx) error {
+	webhook, ok, err := findWebhook(c)
+	if !ok {
+		return err
+	}
+
+	status, err := webhook_service.GetCircuitStatus(webhook)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			common_model.NewApiError("unable to get webhook circuit", err, "webhook_service").Send(),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(status)
+}
+
+// OpenCircuit forces the circuit of a webhook open.
+//
+//	@Summary		Force webhook circuit open
+//	@Description	Stops sending the deliveries of the webhook until its circuit is closed through POST /webhook/{id}/circuit/close. Unlike a tripped circuit, new deliveries are still queued and no probe is sent.
+//	@Tags			Webhook
+//	@Produce		json
+//	@Param			id	path		string							true	"Webhook ID"
+//	@Success		200	{object}	webhook_service.CircuitStatus	"Circuit state"
+//	@Failure		400	{object}	common_model.DescriptiveError	"Invalid webhook ID"
+//	@Failure		404	{object}	common_model.DescriptiveError	"Webhook not found"
+//	@Failure		500	{object}	common_model.DescriptiveError	"Internal server error"
+//	@Router			/webhook/{id}/circuit/open [post]
+//	@Security		ApiKeyAuth
+//	@Security		WorkspaceAuth
+func OpenCircuit(c *fiber.Ctx) error {
+	webhook, ok, err := findWebhook(c)
+	if !ok {
+		return err
+	}
+
+	status, err := webhook_service.ForceCircuitOpen(webhook)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			common_model.NewApiError("unable to open webhook circuit", err, "webhook_service").Send(),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(status)
+}
+
+// CloseCircuit forces the circuit of a webhook closed.
+//
+//	@Summary		Force webhook circuit closed
+//	@Description	Closes the circuit of the webhook, whether it tripped or was forced open, and resets its failure count. Held deliveries are sent right away.
+//	@Tags			Webhook
+//	@Produce		json
+//	@Param			id	path		string							true	"Webhook ID"
+//	@Success		200	{object}	webhook_service.CircuitStatus	"Circuit state"
+//	@Failure		400	{object}	common_model.DescriptiveError	"Invalid webhook ID"
+//	@Failure		404	{object}	common_model.DescriptiveError	"Webhook not found"
+//	@Failure		500	{object}	common_model.DescriptiveError	"Internal server error"
+//	@Router			/webhook/{id}/circuit/close [post]
+//	@Security		ApiKeyAuth
+//	@Security		WorkspaceAuth
+func CloseCircuit(c *fiber.Ctx) error {
+	webhook, ok, err := findWebhook(c)
+	if !ok {
+		return err
+	}
+
+	status, err := webhook_service.ForceCircuitClosed(webhook)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			common_model.NewApiError("unable to close webhook circuit", err, "webhook_service").Send(),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(status)
+}
@@ -1,15 +1,14 @@
 package webhook_handler
 
 import (
-	"crypto/rand"
-	"encoding/hex"
-
 	common_model "github.com/Astervia/wacraft-core/src/common/model"
 	"github.com/Astervia/wacraft-core/src/repository"
 	webhook_entity "github.com/Astervia/wacraft-core/src/webhook/entity"
 	webhook_model "github.com/Astervia/wacraft-core/src/webhook/model"
 	"github.com/Astervia/wacraft-server/src/database"
 	"github.com/Astervia/wacraft-server/src/validators"
+	webhook_destination_service "github.com/Astervia/wacraft-server/src/webhook-destination/service"
+	webhook_service "github.com/Astervia/wacraft-server/src/webhook/service"
 	workspace_middleware "github.com/Astervia/wacraft-server/src/workspace/middleware"
 	"github.com/gofiber/fiber/v2"
 )
@@ -23,7 +22,7 @@ import (
 //	@Produce		json
 //	@Param			webhook	body		webhook_model.CreateWebhook		true	"Webhook data"
 //	@Success		201		{object}	webhook_entity.Webhook			"Created webhook"
-//	@Failure		400		{object}	common_model.DescriptiveError	"Invalid request body"
+//	@Failure		400		{object}	common_model.DescriptiveError	"Invalid request body or blocked destination"
 //	@Failure		500		{object}	common_model.DescriptiveError	"Internal server error"
 //	@Router			/webhook [post]
 //	@Security		ApiKeyAuth
@@ -44,6 +43,12 @@ func CreateWebhook(c *fiber.Ctx) error {
 		)
 	}
 
+	if err := webhook_destination_service.CheckURL(newWebhook.Url); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewApiError(err.Error(), err, "webhook_destination_service").Send(),
+		)
+	}
+
 	// Build webhook entity
 	isActive := true
 	webhookEntity := webhook_entity.Webhook{
@@ -65,7 +70,7 @@ func CreateWebhook(c *fiber.Ctx) error {
 	// Generate signing secret if signing is enabled
 	var signingSecret string
 	if newWebhook.SigningEnabled {
-		secret, err := generateSigningSecret()
+		secret, err := webhook_service.GenerateSigningSecret()
 		if err != nil {
 			return c.Status(fiber.StatusInternalServerError).JSON(
 				common_model.NewApiError("unable to generate signing secret", err, "crypto").Send(),
@@ -97,12 +102,3 @@ type CreateWebhookResponse struct {
 	webhook_entity.Webhook
 	SigningSecret string `json:"signing_secret,omitempty"` // Only returned on creation when signing is enabled
 }
-
-// generateSigningSecret generates a cryptographically secure random secret
-func generateSigningSecret() (string, error) {
-	bytes := make([]byte, 32) // 256 bits
-	if _, err := rand.Read(bytes); err != nil {
-		return "", err
-	}
-	return "whsec_" + hex.EncodeToString(bytes), nil
-}
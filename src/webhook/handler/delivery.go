@@ -0,0 +1,192 @@
+package webhook_handler
+
+import (
+	"errors"
+
+	common_model "github.com/Astervia/wacraft-core/src/common/model"
+	webhook_entity "github.com/Astervia/wacraft-core/src/webhook/entity"
+	"github.com/Astervia/wacraft-server/src/validators"
+	webhook_service "github.com/Astervia/wacraft-server/src/webhook/service"
+	workspace_middleware "github.com/Astervia/wacraft-server/src/workspace/middleware"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GetDeliveries returns the queued, delivered and failed deliveries of the workspace webhooks.
+//
+//	@Summary		Get webhook deliveries
+//	@Description	Lists webhook deliveries, newest first, with their attempts, last HTTP code, response body and error. Filter by webhook, status (pending, attempted, succeeded, dead_letter, cancelled), event type and creation time.
+//	@Tags			Webhook delivery
+//	@Produce		json
+//	@Param			query	query		webhook_service.DeliveryQuery		true	"Filters and pagination"
+//	@Success		200		{array}		webhook_entity.WebhookDelivery		"Deliveries"
+//	@Failure		400		{object}	common_model.DescriptiveError		"Invalid query parameters"
+//	@Failure		500		{object}	common_model.DescriptiveError		"Failed to get deliveries"
+//	@Router			/webhook/delivery [get]
+//	@Security		ApiKeyAuth
+//	@Security		WorkspaceAuth
+func GetDeliveries(c *fiber.Ctx) error {
+	query := new(webhook_service.DeliveryQuery)
+	if err := c.QueryParser(query); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewParseJsonError(err).Send(),
+		)
+	}
+
+	if err := validators.Validator().Struct(query); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewValidationError(err).Send(),
+		)
+	}
+
+	workspace := workspace_middleware.GetWorkspace(c)
+
+	deliveries, err := webhook_service.GetDeliveries(*query, workspace.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			common_model.NewApiError("unable to get webhook deliveries", err, "webhook_service").Send(),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(deliveries)
+}
+
+// RetryDelivery queues a failed delivery again.
+//
+//	@Summary		Retry webhook delivery
+//	@Description	Queues a dead letter or cancelled delivery again with the retry budget of its webhook. The worker sends it on its next poll.
+//	@Tags			Webhook delivery
+//	@Produce		json
+//	@Param			id	path		string							true	"Delivery ID"
+//	@Success		200	{object}	webhook_entity.WebhookDelivery	"Queued delivery"
+//	@Failure		400	{object}	common_model.DescriptiveError	"Invalid delivery ID"
+//	@Failure		404	{object}	common_model.DescriptiveError	"Delivery not found"
+//	@Failure		409	{object}	common_model.DescriptiveError	"Delivery is queued or succeeded"
+//	@Failure		500	{object}	common_model.DescriptiveError	"Failed to retry the delivery"
+//	@Router			/webhook/delivery/{id}/retry [post]
+//	@Security		ApiKeyAuth
+//	@Security		WorkspaceAuth
+func RetryDelivery(c *fiber.Ctx) error {
+	return transitionDelivery(c, webhook_service.RetryDelivery, webhook_service.ErrDeliveryNotRetryable, "unable to retry webhook delivery")
+}
+
+// CancelDelivery stops a queued delivery.
+//
+//	@Summary		Cancel webhook delivery
+//	@Description	Cancels a pending or attempted delivery so the worker does not send it again. Cancelled deliveries can be retried later.
+//	@Tags			Webhook delivery
+//	@Produce		json
+//	@Param			id	path		string							true	"Delivery ID"
+//	@Success		200	{object}	webhook_entity.WebhookDelivery	"Cancelled delivery"
+//	@Failure		400	{object}	common_model.DescriptiveError	"Invalid delivery ID"
+//	@Failure		404	{object}	common_model.DescriptiveError	"Delivery not found"
+//	@Failure		409	{object}	common_model.DescriptiveError	"Delivery is no longer queued"
+//	@Failure		500	{object}	common_model.DescriptiveError	"Failed to cancel the delivery"
+//	@Router			/webhook/delivery/{id}/cancel [post]
+//	@Security		ApiKeyAuth
+//	@Security		WorkspaceAuth
+func CancelDelivery(c *fiber.Ctx) error {
+	return transitionDelivery(c, webhook_service.CancelDelivery, webhook_service.ErrDeliveryNotCancellable, "unable to cancel webhook delivery")
+}
+
+// RedriveDeadLetters queues again the dead letters of a webhook.
+//
+//	@Summary		Redrive webhook dead letters
+//	@Description	Queues again every dead letter delivery of the webhook created within the optional window, e.g. after the receiver recovers from an outage.
+//	@Tags			Webhook delivery
+//	@Accept			json
+//	@Produce		json
+//	@Param			window	body		webhook_service.DeliveryWindow		true	"Webhook and time window"
+//	@Success		200		{object}	webhook_service.DeliveriesAffected	"Number of deliveries queued"
+//	@Failure		400		{object}	common_model.DescriptiveError		"Invalid request body"
+//	@Failure		500		{object}	common_model.DescriptiveError		"Failed to redrive deliveries"
+//	@Router			/webhook/delivery/redrive [post]
+//	@Security		ApiKeyAuth
+//	@Security		WorkspaceAuth
+func RedriveDeadLetters(c *fiber.Ctx) error {
+	return bulkDeliveries(c, webhook_service.RedriveDeadLetters, "unable to redrive webhook deliveries")
+}
+
+// CancelDeliveries stops the queued deliveries of a webhook.
+//
+//	@Summary		Cancel queued webhook deliveries
+//	@Description	Cancels every pending or attempted delivery of the webhook created within the optional window.
+//	@Tags			Webhook delivery
+//	@Accept			json
+//	@Produce		json
+//	@Param			window	body		webhook_service.DeliveryWindow		true	"Webhook and time window"
+//	@Success		200		{object}	webhook_service.DeliveriesAffected	"Number of deliveries cancelled"
+//	@Failure		400		{object}	common_model.DescriptiveError		"Invalid request body"
+//	@Failure		500		{object}	common_model.DescriptiveError		"Failed to cancel deliveries"
+//	@Router			/webhook/delivery/cancel [post]
+//	@Security		ApiKeyAuth
+//	@Security		WorkspaceAuth
+func CancelDeliveries(c *fiber.Ctx) error {
+	return bulkDeliveries(c, webhook_service.CancelDeliveries, "unable to cancel webhook deliveries")
+}
+
+func transitionDelivery(
+	c *fiber.Ctx,
+	transition func(uuid.UUID, uuid.UUID) (webhook_entity.WebhookDelivery, error),
+	errWrongStatus error,
+	failure string,
+) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewApiError("unable to parse delivery id string to UUID", err, "github.com/google/uuid").Send(),
+		)
+	}
+
+	workspace := workspace_middleware.GetWorkspace(c)
+
+	delivery, err := transition(id, workspace.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(
+			common_model.NewApiError("delivery not found", err, "webhook_service").Send(),
+		)
+	}
+	if errors.Is(err, errWrongStatus) {
+		return c.Status(fiber.StatusConflict).JSON(
+			common_model.NewApiError(err.Error(), err, "webhook_service").Send(),
+		)
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			common_model.NewApiError(failure, err, "webhook_service").Send(),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(delivery)
+}
+
+func bulkDeliveries(
+	c *fiber.Ctx,
+	apply func(webhook_service.DeliveryWindow, uuid.UUID) (int64, error),
+	failure string,
+) error {
+	var window webhook_service.DeliveryWindow
+	if err := c.BodyParser(&window); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewParseJsonError(err).Send(),
+		)
+	}
+
+	if err := validators.Validator().Struct(&window); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewValidationError(err).Send(),
+		)
+	}
+
+	workspace := workspace_middleware.GetWorkspace(c)
+
+	affected, err := apply(window, workspace.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			common_model.NewApiError(failure, err, "webhook_service").Send(),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(webhook_service.DeliveriesAffected{Affected: affected})
+}
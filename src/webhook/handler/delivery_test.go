@@ -0,0 +1,327 @@
+package webhook_handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	webhook_entity "github.com/Astervia/wacraft-core/src/webhook/entity"
+	webhook_model "github.com/Astervia/wacraft-core/src/webhook/model"
+	workspace_entity "github.com/Astervia/wacraft-core/src/workspace/entity"
+	"github.com/Astervia/wacraft-server/src/database"
+	database_fixture "github.com/Astervia/wacraft-server/src/database/fixture"
+	"github.com/Astervia/wacraft-server/src/validators"
+	webhook_service "github.com/Astervia/wacraft-server/src/webhook/service"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// --- Test bootstrap ---
+
+func TestMain(m *testing.M) {
+	validators.InitValidators()
+	database.DB.AutoMigrate(
+		&webhook_entity.Webhook{},
+		&webhook_entity.WebhookDelivery{},
+	)
+	// Columns added by the goose migrations on top of the core entity.
+	database.DB.Exec(`ALTER TABLE webhook_deliveries
+		ADD COLUMN IF NOT EXISTS lease_owner TEXT,
+		ADD COLUMN IF NOT EXISTS lease_expires_at TIMESTAMPTZ,
+		ADD COLUMN IF NOT EXISTS ordering_key TEXT`)
+	// Drop the workspace FK so test webhooks can use random workspace IDs.
+	database_fixture.DropForeignKeys("webhooks", "fk_webhooks_workspace")
+	os.Exit(m.Run())
+}
+
+// --- Helpers ---
+
+func newDeliveryApp(workspaceID uuid.UUID) *fiber.App {
+	app := fiber.New()
+	// Inject a fake workspace into locals so the handler can retrieve it.
+	app.Use(func(c *fiber.Ctx) error {
+		ws := &workspace_entity.Workspace{}
+		ws.ID = workspaceID
+		c.Locals("workspace", ws)
+		return c.Next()
+	})
+	app.Get("/webhook/delivery", GetDeliveries)
+	app.Post("/webhook/delivery/redrive", RedriveDeadLetters)
+	app.Post("/webhook/delivery/cancel", CancelDeliveries)
+	app.Post("/webhook/delivery/:id/retry", RetryDelivery)
+	app.Post("/webhook/delivery/:id/cancel", CancelDelivery)
+	return app
+}
+
+func createTestWebhook(t *testing.T, workspaceID uuid.UUID, maxRetries int) webhook_entity.Webhook {
+	t.Helper()
+	isActive := true
+	webhook := webhook_entity.Webhook{
+		Url:         "https://example.com/hook",
+		HttpMethod:  "POST",
+		Event:       webhook_model.SendWhatsAppMessage,
+		WorkspaceID: &workspaceID,
+		IsActive:    &isActive,
+		MaxRetries:  &maxRetries,
+	}
+	if err := database.DB.Create(&webhook).Error; err != nil {
+		t.Fatalf("createTestWebhook: %v", err)
+	}
+	t.Cleanup(func() {
+		database.DB.Exec("DELETE FROM webhook_deliveries WHERE webhook_id = ?", webhook.ID)
+		database.DB.Exec("DELETE FROM webhooks WHERE id = ?", webhook.ID)
+	})
+	return webhook
+}
+
+func createTestDelivery(t *testing.T, webhookID uuid.UUID, status webhook_entity.DeliveryStatus, attempts int) webhook_entity.WebhookDelivery {
+	t.Helper()
+	now := time.Now()
+	delivery := webhook_entity.WebhookDelivery{
+		WebhookID:      webhookID,
+		IdempotencyKey: uuid.NewString(),
+		Payload:        map[string]any{"test": true},
+		Status:         status,
+		AttemptCount:   attempts,
+		MaxAttempts:    attempts,
+		NextAttemptAt:  &now,
+		EventType:      string(webhook_model.SendWhatsAppMessage),
+		EventTimestamp: now,
+	}
+	if err := database.DB.Create(&delivery).Error; err != nil {
+		t.Fatalf("createTestDelivery: %v", err)
+	}
+	return delivery
+}
+
+func loadDelivery(t *testing.T, id uuid.UUID) webhook_entity.WebhookDelivery {
+	t.Helper()
+	var delivery webhook_entity.WebhookDelivery
+	if err := database.DB.First(&delivery, "id = ?", id).Error; err != nil {
+		t.Fatalf("loadDelivery: %v", err)
+	}
+	return delivery
+}
+
+func doRequest(t *testing.T, app *fiber.App, method, path string, body any) (int, []byte) {
+	t.Helper()
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			t.Fatalf("encode body: %v", err)
+		}
+	}
+	req := httptest.NewRequest(method, path, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req, 5000)
+	if err != nil {
+		t.Fatalf("app.Test: %v", err)
+	}
+	defer resp.Body.Close()
+	out, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read body: %v", err)
+	}
+	return resp.StatusCode, out
+}
+
+// --- List tests ---
+
+func TestGetDeliveries_ScopedToWorkspaceAndFiltered(t *testing.T) {
+	workspaceID := uuid.New()
+	webhook := createTestWebhook(t, workspaceID, 2)
+	other := createTestWebhook(t, uuid.New(), 2)
+
+	deadLetter := createTestDelivery(t, webhook.ID, webhook_entity.DeliveryStatusDeadLetter, 3)
+	createTestDelivery(t, webhook.ID, webhook_entity.DeliveryStatusSucceeded, 1)
+	createTestDelivery(t, other.ID, webhook_entity.DeliveryStatusDeadLetter, 3)
+
+	app := newDeliveryApp(workspaceID)
+
+	status, body := doRequest(t, app, "GET", "/webhook/delivery", nil)
+	if status != fiber.StatusOK {
+		t.Fatalf("list: got %d, want %d: %s", status, fiber.StatusOK, body)
+	}
+	var deliveries []webhook_entity.WebhookDelivery
+	if err := json.Unmarshal(body, &deliveries); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(deliveries) != 2 {
+		t.Fatalf("list: got %d deliveries, want the 2 of the workspace", len(deliveries))
+	}
+
+	status, body = doRequest(t, app, "GET", "/webhook/delivery?status=dead_letter", nil)
+	if status != fiber.StatusOK {
+		t.Fatalf("list by status: got %d, want %d: %s", status, fiber.StatusOK, body)
+	}
+	deliveries = nil
+	if err := json.Unmarshal(body, &deliveries); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(deliveries) != 1 || deliveries[0].ID != deadLetter.ID {
+		t.Errorf("list by status: expected only the dead letter %s, got %+v", deadLetter.ID, deliveries)
+	}
+}
+
+// --- Retry tests ---
+
+func TestRetryDelivery_RequeuesDeadLetter(t *testing.T) {
+	workspaceID := uuid.New()
+	webhook := createTestWebhook(t, workspaceID, 2)
+	delivery := createTestDelivery(t, webhook.ID, webhook_entity.DeliveryStatusDeadLetter, 3)
+
+	status, body := doRequest(t, newDeliveryApp(workspaceID), "POST", "/webhook/delivery/"+delivery.ID.String()+"/retry", nil)
+	if status != fiber.StatusOK {
+		t.Fatalf("retry: got %d, want %d: %s", status, fiber.StatusOK, body)
+	}
+
+	updated := loadDelivery(t, delivery.ID)
+	if updated.Status != webhook_entity.DeliveryStatusPending {
+		t.Errorf("status after retry: got %q, want %q", updated.Status, webhook_entity.DeliveryStatusPending)
+	}
+	// Attempts already made plus a full retry budget.
+	if updated.MaxAttempts != 3+2+1 {
+		t.Errorf("max attempts after retry: got %d, want %d", updated.MaxAttempts, 6)
+	}
+}
+
+func TestRetryDelivery_RejectsQueuedDelivery(t *testing.T) {
+	workspaceID := uuid.New()
+	webhook := createTestWebhook(t, workspaceID, 2)
+	delivery := createTestDelivery(t, webhook.ID, webhook_entity.DeliveryStatusPending, 0)
+
+	status, _ := doRequest(t, newDeliveryApp(workspaceID), "POST", "/webhook/delivery/"+delivery.ID.String()+"/retry", nil)
+	if status != fiber.StatusConflict {
+		t.Errorf("retry pending: got %d, want %d", status, fiber.StatusConflict)
+	}
+}
+
+func TestRetryDelivery_OtherWorkspace(t *testing.T) {
+	webhook := createTestWebhook(t, uuid.New(), 2)
+	delivery := createTestDelivery(t, webhook.ID, webhook_entity.DeliveryStatusDeadLetter, 3)
+
+	status, _ := doRequest(t, newDeliveryApp(uuid.New()), "POST", "/webhook/delivery/"+delivery.ID.String()+"/retry", nil)
+	if status != fiber.StatusNotFound {
+		t.Errorf("retry other workspace: got %d, want %d", status, fiber.StatusNotFound)
+	}
+	if updated := loadDelivery(t, delivery.ID); updated.Status != webhook_entity.DeliveryStatusDeadLetter {
+		t.Errorf("status after rejected retry: got %q, want %q", updated.Status, webhook_entity.DeliveryStatusDeadLetter)
+	}
+}
+
+func TestRetryDelivery_InvalidID(t *testing.T) {
+	status, _ := doRequest(t, newDeliveryApp(uuid.New()), "POST", "/webhook/delivery/not-a-uuid/retry", nil)
+	if status != fiber.StatusBadRequest {
+		t.Errorf("retry invalid id: got %d, want %d", status, fiber.StatusBadRequest)
+	}
+}
+
+// --- Cancel tests ---
+
+func TestCancelDelivery_CancelsQueuedDelivery(t *testing.T) {
+	workspaceID := uuid.New()
+	webhook := createTestWebhook(t, workspaceID, 2)
+	delivery := createTestDelivery(t, webhook.ID, webhook_entity.DeliveryStatusAttempted, 1)
+	app := newDeliveryApp(workspaceID)
+
+	status, body := doRequest(t, app, "POST", "/webhook/delivery/"+delivery.ID.String()+"/cancel", nil)
+	if status != fiber.StatusOK {
+		t.Fatalf("cancel: got %d, want %d: %s", status, fiber.StatusOK, body)
+	}
+	updated := loadDelivery(t, delivery.ID)
+	if updated.Status != webhook_service.DeliveryStatusCancelled || updated.NextAttemptAt != nil {
+		t.Errorf("after cancel: got status %q and next attempt %v", updated.Status, updated.NextAttemptAt)
+	}
+
+	// A cancelled delivery is no longer queued
+	status, _ = doRequest(t, app, "POST", "/webhook/delivery/"+delivery.ID.String()+"/cancel", nil)
+	if status != fiber.StatusConflict {
+		t.Errorf("cancel twice: got %d, want %d", status, fiber.StatusConflict)
+	}
+
+	// and can be retried
+	status, _ = doRequest(t, app, "POST", "/webhook/delivery/"+delivery.ID.String()+"/retry", nil)
+	if status != fiber.StatusOK {
+		t.Errorf("retry cancelled: got %d, want %d", status, fiber.StatusOK)
+	}
+}
+
+// --- Bulk tests ---
+
+func TestRedriveDeadLetters(t *testing.T) {
+	workspaceID := uuid.New()
+	webhook := createTestWebhook(t, workspaceID, 1)
+	first := createTestDelivery(t, webhook.ID, webhook_entity.DeliveryStatusDeadLetter, 2)
+	second := createTestDelivery(t, webhook.ID, webhook_entity.DeliveryStatusDeadLetter, 2)
+	succeeded := createTestDelivery(t, webhook.ID, webhook_entity.DeliveryStatusSucceeded, 1)
+
+	status, body := doRequest(t, newDeliveryApp(workspaceID), "POST", "/webhook/delivery/redrive",
+		webhook_service.DeliveryWindow{WebhookID: webhook.ID})
+	if status != fiber.StatusOK {
+		t.Fatalf("redrive: got %d, want %d: %s", status, fiber.StatusOK, body)
+	}
+	var affected webhook_service.DeliveriesAffected
+	if err := json.Unmarshal(body, &affected); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if affected.Affected != 2 {
+		t.Errorf("redrive: got %d deliveries, want 2", affected.Affected)
+	}
+	for _, id := range []uuid.UUID{first.ID, second.ID} {
+		if updated := loadDelivery(t, id); updated.Status != webhook_entity.DeliveryStatusPending {
+			t.Errorf("delivery %s after redrive: got %q, want %q", id, updated.Status, webhook_entity.DeliveryStatusPending)
+		}
+	}
+	if updated := loadDelivery(t, succeeded.ID); updated.Status != webhook_entity.DeliveryStatusSucceeded {
+		t.Errorf("succeeded delivery after redrive: got %q", updated.Status)
+	}
+}
+
+func TestRedriveDeadLetters_RequiresWebhook(t *testing.T) {
+	status, _ := doRequest(t, newDeliveryApp(uuid.New()), "POST", "/webhook/delivery/redrive", map[string]any{})
+	if status != fiber.StatusBadRequest {
+		t.Errorf("redrive without webhook: got %d, want %d", status, fiber.StatusBadRequest)
+	}
+}
+
+func TestCancelDeliveries(t *testing.T) {
+	workspaceID := uuid.New()
+	webhook := createTestWebhook(t, workspaceID, 1)
+	createTestDelivery(t, webhook.ID, webhook_entity.DeliveryStatusPending, 0)
+	createTestDelivery(t, webhook.ID, webhook_entity.DeliveryStatusAttempted, 1)
+	deadLetter := createTestDelivery(t, webhook.ID, webhook_entity.DeliveryStatusDeadLetter, 2)
+
+	status, body := doRequest(t, newDeliveryApp(workspaceID), "POST", "/webhook/delivery/cancel",
+		webhook_service.DeliveryWindow{WebhookID: webhook.ID})
+	if status != fiber.StatusOK {
+		t.Fatalf("cancel: got %d, want %d: %s", status, fiber.StatusOK, body)
+	}
+	var affected webhook_service.DeliveriesAffected
+	if err := json.Unmarshal(body, &affected); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if affected.Affected != 2 {
+		t.Errorf("cancel: got %d deliveries, want 2", affected.Affected)
+	}
+	if updated := loadDelivery(t, deadLetter.ID); updated.Status != webhook_entity.DeliveryStatusDeadLetter {
+		t.Errorf("dead letter after cancel: got %q", updated.Status)
+	}
+}
+
+func TestCancelDeliveries_OtherWorkspace(t *testing.T) {
+	webhook := createTestWebhook(t, uuid.New(), 1)
+	delivery := createTestDelivery(t, webhook.ID, webhook_entity.DeliveryStatusPending, 0)
+
+	status, body := doRequest(t, newDeliveryApp(uuid.New()), "POST", "/webhook/delivery/cancel",
+		webhook_service.DeliveryWindow{WebhookID: webhook.ID})
+	if status != fiber.StatusOK {
+		t.Fatalf("cancel: got %d, want %d: %s", status, fiber.StatusOK, body)
+	}
+	if updated := loadDelivery(t, delivery.ID); updated.Status != webhook_entity.DeliveryStatusPending {
+		t.Errorf("delivery of another workspace after cancel: got %q", updated.Status)
+	}
+}
@@ -0,0 +1,20 @@
+package webhook_handler
+
+import (
+	webhook_event_model "github.com/Astervia/wacraft-server/src/webhook-event/model"
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetEvents returns the catalogue of outbound webhook events.
+//
+//	@Summary		Get webhook event catalogue
+//	@Description	Lists the events a webhook can subscribe to. Enveloped events are delivered as `{id, event, version, occurred_at, workspace_id, data}` and event filters are evaluated against that envelope.
+//	@Tags			Webhook
+//	@Produce		json
+//	@Success		200	{array}	webhook_event_model.EventDescription	"Event catalogue"
+//	@Router			/webhook/event [get]
+//	@Security		ApiKeyAuth
+//	@Security		WorkspaceAuth
+func GetEvents(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusOK).JSON(webhook_event_model.Catalogue)
+}
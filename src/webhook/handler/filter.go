@@ -0,0 +1,60 @@
+package webhook_handler
+
+import (
+	"errors"
+
+	common_model "github.com/Astervia/wacraft-core/src/common/model"
+	"github.com/Astervia/wacraft-server/src/database"
+	database_filter "github.com/Astervia/wacraft-server/src/database/filter"
+	"github.com/Astervia/wacraft-server/src/validators"
+	webhook_service "github.com/Astervia/wacraft-server/src/webhook/service"
+	workspace_middleware "github.com/Astervia/wacraft-server/src/workspace/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+// SearchByFilter returns webhooks matching a JSON filter.
+//
+//	@Summary		Search webhooks with a filter
+//	@Description	Filters webhooks with nested and/or/not groups of conditions. Operators: eq, neq, gt, gte, lt, lte, in, exists and ilike.
+//	@Description	Fields: id, created_at, updated_at, url, event, http_method, timeout, is_active, signing_enabled, max_retries, retry_delay_ms, failure_count, circuit_state, last_failure_at and circuit_opened_at.
+//	@Tags			Webhook
+//	@Accept			json
+//	@Produce		json
+//	@Param			search	body		database_filter.Search			true	"Filter, order and pagination"
+//	@Success		200		{array}		webhook_entity.Webhook	"Matching webhooks"
+//	@Failure		400		{object}	common_model.DescriptiveError	"Invalid filter"
+//	@Failure		500		{object}	common_model.DescriptiveError	"Failed to query webhooks"
+//	@Security		ApiKeyAuth
+//	@Security		WorkspaceAuth
+//	@Router			/webhook/search [post]
+func SearchByFilter(c *fiber.Ctx) error {
+	search := new(database_filter.Search)
+	if err := c.BodyParser(search); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewParseJsonError(err).Send(),
+		)
+	}
+
+	if err := validators.Validator().Struct(search); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewValidationError(err).Send(),
+		)
+	}
+
+	workspace := workspace_middleware.GetWorkspace(c)
+	db := database.DB.Where("webhooks.workspace_id = ?", workspace.ID)
+
+	webhooks, err := webhook_service.SearchByFilter(*search, db)
+	if err != nil {
+		if errors.Is(err, database_filter.ErrInvalidFilter) {
+			return c.Status(fiber.StatusBadRequest).JSON(
+				common_model.NewApiError("invalid filter", err, "database_filter").Send(),
+			)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			common_model.NewApiError("unable to get webhooks", err, "database_filter").Send(),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(webhooks)
+}
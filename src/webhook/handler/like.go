@@ -16,6 +16,7 @@ import (
 //
 //	@Summary		Query webhooks by key and partial value
 //	@Description	Filters webhooks using the ILIKE operator on a specified field and partial value.
+//	@Description	Deprecated: use POST /webhook/search with an ilike condition.
 //	@Tags			Webhook
 //	@Accept			json
 //	@Produce		json
@@ -24,6 +25,7 @@ import (
 //	@Success		200			{array}		webhook_entity.Webhook				"List of webhooks"
 //	@Failure		400			{object}	common_model.DescriptiveError		"Invalid query or path parameters"
 //	@Failure		500			{object}	common_model.DescriptiveError		"Internal server error"
+//	@Deprecated
 //	@Router			/webhook/content/{keyName}/like/{likeText} [get]
 //	@Security		ApiKeyAuth
 //	@Security		WorkspaceAuth
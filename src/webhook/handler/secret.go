@@ -0,0 +1,80 @@
+package webhook_handler
+
+import (
+	"errors"
+	"time"
+
+	common_model "github.com/Astervia/wacraft-core/src/common/model"
+	"github.com/Astervia/wacraft-server/src/config/env"
+	"github.com/Astervia/wacraft-server/src/validators"
+	webhook_setting_model "github.com/Astervia/wacraft-server/src/webhook-setting/model"
+	webhook_service "github.com/Astervia/wacraft-server/src/webhook/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+// RotateSecret replaces the signing secret of a webhook.
+//
+//	@Summary		Rotate webhook signing secret
+//	@Description	Generates a new signing secret and returns it. This is the only time it can be read. During the grace period deliveries carry a signature for each secret, newest first, e.g. X-Wacraft-Signature: v1={new},v1={old}, so consumers can switch secrets without rejecting deliveries. The old secret is retired automatically once the grace period is over.
+//	@Tags			Webhook
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string										true	"Webhook ID"
+//	@Param			rotate	body		webhook_setting_model.RotateSigningSecret		false	"Rotation options"
+//	@Success		200		{object}	webhook_setting_model.RotateSigningSecretResponse	"New signing secret"
+//	@Failure		400		{object}	common_model.DescriptiveError				"Invalid request body or signing disabled"
+//	@Failure		404		{object}	common_model.DescriptiveError				"Webhook not found"
+//	@Failure		409		{object}	common_model.DescriptiveError				"Rotated concurrently"
+//	@Failure		500		{object}	common_model.DescriptiveError				"Internal server error"
+//	@Router			/webhook/{id}/secret/rotate [post]
+//	@Security		ApiKeyAuth
+//	@Security		WorkspaceAuth
+func RotateSecret(c *fiber.Ctx) error {
+	webhook, ok, err := findWebhook(c)
+	if !ok {
+		return err
+	}
+
+	var rotate webhook_setting_model.RotateSigningSecret
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&rotate); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(
+				common_model.NewParseJsonError(err).Send(),
+			)
+		}
+	}
+
+	if err := validators.Validator().Struct(&rotate); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewValidationError(err).Send(),
+		)
+	}
+
+	if !webhook.SigningEnabled {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewApiError("signing is not enabled for this webhook", nil, "handler").Send(),
+		)
+	}
+
+	grace := env.WebhookSecretRotationGrace
+	if rotate.GracePeriodSeconds != nil {
+		grace = time.Duration(*rotate.GracePeriodSeconds) * time.Second
+	}
+
+	secret, setting, err := webhook_service.RotateSigningSecret(webhook, grace)
+	if errors.Is(err, webhook_service.ErrConcurrentRotation) {
+		return c.Status(fiber.StatusConflict).JSON(
+			common_model.NewApiError(err.Error(), err, "webhook_service").Send(),
+		)
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			common_model.NewApiError("unable to rotate signing secret", err, "webhook_service").Send(),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(webhook_setting_model.RotateSigningSecretResponse{
+		SigningSecret:                  secret,
+		PreviousSigningSecretExpiresAt: setting.PreviousSigningSecretExpiresAt,
+	})
+}
@@ -0,0 +1,148 @@
+package webhook_handler
+
+import (
+	"encoding/json"
+	"errors"
+
+	common_model "github.com/Astervia/wacraft-core/src/common/model"
+	webhook_entity "github.com/Astervia/wacraft-core/src/webhook/entity"
+	"github.com/Astervia/wacraft-server/src/database"
+	"github.com/Astervia/wacraft-server/src/validators"
+	_ "github.com/Astervia/wacraft-server/src/webhook-setting/entity"
+	webhook_setting_model "github.com/Astervia/wacraft-server/src/webhook-setting/model"
+	webhook_setting_service "github.com/Astervia/wacraft-server/src/webhook-setting/service"
+	workspace_middleware "github.com/Astervia/wacraft-server/src/workspace/middleware"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GetSetting returns the delivery settings of a webhook.
+//
+//	@Summary		Get webhook settings
+//	@Description	Returns the delivery settings of the webhook, such as its ordering key, batching and payload transform.
+//	@Tags			Webhook
+//	@Produce		json
+//	@Param			id	path		string									true	"Webhook ID"
+//	@Success		200	{object}	webhook_setting_entity.WebhookSetting	"Webhook settings"
+//	@Failure		400	{object}	common_model.DescriptiveError			"Invalid webhook ID"
+//	@Failure		404	{object}	common_model.DescriptiveError			"Webhook not found"
+//	@Failure		500	{object}	common_model.DescriptiveError			"Internal server error"
+//	@Router			/webhook/{id}/setting [get]
+//	@Security		ApiKeyAuth
+//	@Security		WorkspaceAuth
+func GetSetting(c *fiber.Ctx) error {
+	webhook, ok, err := findWebhook(c)
+	if !ok {
+		return err
+	}
+
+	setting, err := webhook_setting_service.GetWebhookSetting(webhook.ID, nil)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			common_model.NewApiError("unable to get webhook settings", err, "webhook_setting_service").Send(),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(setting)
+}
+
+// UpdateSetting updates the delivery settings of a webhook.
+//
+//	@Summary		Update webhook settings
+//	@Description	Updates the delivery settings of the webhook. With a batch size above 1, deliveries are sent as a JSON array of up to batch_size events, each with its delivery_id and idempotency_key, once the batch is full or its oldest delivery waited batch_max_wait_ms. A transform reshapes the payload with a Go text/template or a JSON field mapping and may set the content type, see POST /webhook/{id}/transform/preview to try one. With an ordering key of contact or campaign, the deliveries sharing a contact or campaign are sent one at a time in the order they were queued, and a failing delivery holds back the following ones until it succeeds or is dead-lettered. Deliveries of different keys are still sent concurrently. An empty ordering key sends every delivery concurrently. Changes apply to deliveries queued afterwards.
+//	@Tags			Webhook
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string									true	"Webhook ID"
+//	@Param			setting	body		webhook_setting_model.UpdateWebhookSetting	true	"Settings to update"
+//	@Success		200		{object}	webhook_setting_entity.WebhookSetting	"Updated webhook settings"
+//	@Failure		400		{object}	common_model.DescriptiveError			"Invalid request body"
+//	@Failure		404		{object}	common_model.DescriptiveError			"Webhook not found"
+//	@Failure		500		{object}	common_model.DescriptiveError			"Internal server error"
+//	@Router			/webhook/{id}/setting [patch]
+//	@Security		ApiKeyAuth
+//	@Security		WorkspaceAuth
+func UpdateSetting(c *fiber.Ctx) error {
+	webhook, ok, err := findWebhook(c)
+	if !ok {
+		return err
+	}
+
+	var updateData webhook_setting_model.UpdateWebhookSetting
+	if err := c.BodyParser(&updateData); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewParseJsonError(err).Send(),
+		)
+	}
+
+	if err := validators.Validator().Struct(&updateData); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewValidationError(err).Send(),
+		)
+	}
+
+	// Build update map
+	updates := make(map[string]any)
+	if updateData.OrderingKey != nil {
+		updates["ordering_key"] = *updateData.OrderingKey
+	}
+	if updateData.BatchSize != nil {
+		updates["batch_size"] = *updateData.BatchSize
+	}
+	if updateData.BatchMaxWaitMs != nil {
+		updates["batch_max_wait_ms"] = *updateData.BatchMaxWaitMs
+	}
+	if updateData.ClearTransform {
+		updates["transform"] = nil
+	} else if updateData.Transform != nil {
+		if err := updateData.Transform.Validate(); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(
+				common_model.NewApiError(err.Error(), err, "webhook_transform_model").Send(),
+			)
+		}
+		transform, err := json.Marshal(updateData.Transform)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(
+				common_model.NewApiError("unable to encode transform", err, "encoding/json").Send(),
+			)
+		}
+		updates["transform"] = string(transform)
+	}
+
+	setting, err := webhook_setting_service.UpdateWebhookSetting(webhook.ID, updates, nil)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			common_model.NewApiError("unable to update webhook settings", err, "webhook_setting_service").Send(),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(setting)
+}
+
+// findWebhook loads the workspace webhook referenced by the :id path parameter.
+// When ok is false the error response has already been written and the handler must return err.
+func findWebhook(c *fiber.Ctx) (webhook webhook_entity.Webhook, ok bool, err error) {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return webhook, false, c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewApiError("unable to parse webhook id string to UUID", err, "github.com/google/uuid").Send(),
+		)
+	}
+
+	workspace := workspace_middleware.GetWorkspace(c)
+
+	err = database.DB.Where("id = ? AND workspace_id = ?", id, workspace.ID).First(&webhook).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return webhook, false, c.Status(fiber.StatusNotFound).JSON(
+			common_model.NewApiError("webhook not found", err, "database").Send(),
+		)
+	}
+	if err != nil {
+		return webhook, false, c.Status(fiber.StatusInternalServerError).JSON(
+			common_model.NewApiError("unable to get webhook", err, "database").Send(),
+		)
+	}
+
+	return webhook, true, nil
+}
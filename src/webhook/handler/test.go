@@ -10,12 +10,16 @@ import (
 
 	common_model "github.com/Astervia/wacraft-core/src/common/model"
 	webhook_entity "github.com/Astervia/wacraft-core/src/webhook/entity"
-	webhook_core_service "github.com/Astervia/wacraft-core/src/webhook/service"
 	"github.com/Astervia/wacraft-server/src/database"
 	"github.com/Astervia/wacraft-server/src/validators"
+	webhook_destination_service "github.com/Astervia/wacraft-server/src/webhook-destination/service"
+	webhook_setting_service "github.com/Astervia/wacraft-server/src/webhook-setting/service"
+	webhook_transform_model "github.com/Astervia/wacraft-server/src/webhook-transform/model"
+	webhook_service "github.com/Astervia/wacraft-server/src/webhook/service"
 	workspace_middleware "github.com/Astervia/wacraft-server/src/workspace/middleware"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
+	"github.com/pterm/pterm"
 )
 
 // TestWebhookRequest represents the request body for testing a webhook
@@ -77,13 +81,7 @@ func TestWebhook(c *fiber.Ctx) error {
 	// Use default test payload if none provided
 	payload := req.Payload
 	if payload == nil {
-		payload = map[string]any{
-			"test":       true,
-			"timestamp":  time.Now().UTC().Format(time.RFC3339),
-			"webhook_id": webhook.ID.String(),
-			"event":      string(webhook.Event),
-			"message":    "This is a test webhook delivery from Wacraft",
-		}
+		payload = testPayload(webhook)
 	}
 
 	// Execute the test
@@ -92,6 +90,17 @@ func TestWebhook(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(result)
 }
 
+// testPayload is sent when a test does not provide a payload.
+func testPayload(webhook webhook_entity.Webhook) map[string]any {
+	return map[string]any{
+		"test":       true,
+		"timestamp":  time.Now().UTC().Format(time.RFC3339),
+		"webhook_id": webhook.ID.String(),
+		"event":      string(webhook.Event),
+		"message":    "This is a test webhook delivery from Wacraft",
+	}
+}
+
 func executeTestWebhook(webhook *webhook_entity.Webhook, payload any) TestWebhookResponse {
 	result := TestWebhookResponse{
 		HeadersSent: make(map[string]string),
@@ -104,6 +113,37 @@ func executeTestWebhook(webhook *webhook_entity.Webhook, payload any) TestWebhoo
 		return result
 	}
 
+	setting, err := webhook_setting_service.GetWebhookSetting(webhook.ID, nil)
+	if err != nil {
+		result.Error = "Failed to get webhook settings: " + err.Error()
+		return result
+	}
+
+	// Reshape it like real deliveries
+	jsonPayload, contentType, err := webhook_service.RenderTransform(setting.Transform, jsonPayload)
+	if err != nil {
+		result.Error = "Failed to transform payload: " + err.Error()
+		return result
+	}
+
+	// Batching webhooks always receive arrays, so the test is a batch of one
+	if setting.Batching() {
+		event := webhook_service.BatchEvent{
+			IdempotencyKey: "test",
+			Event:          string(webhook.Event),
+			Attempt:        1,
+			Timestamp:      time.Now(),
+		}
+		if event.Payload, err = webhook_service.BatchPayload(jsonPayload); err == nil {
+			jsonPayload, err = json.Marshal([]webhook_service.BatchEvent{event})
+		}
+		if err != nil {
+			result.Error = "Failed to marshal batch: " + err.Error()
+			return result
+		}
+		contentType = webhook_transform_model.DefaultContentType
+	}
+
 	// Create request
 	req, err := http.NewRequest(webhook.HttpMethod, webhook.Url, bytes.NewBuffer(jsonPayload))
 	if err != nil {
@@ -112,8 +152,8 @@ func executeTestWebhook(webhook *webhook_entity.Webhook, payload any) TestWebhoo
 	}
 
 	// Set headers
-	req.Header.Set("Content-Type", "application/json")
-	result.HeadersSent["Content-Type"] = "application/json"
+	req.Header.Set("Content-Type", contentType)
+	result.HeadersSent["Content-Type"] = contentType
 
 	req.Header.Set("X-Wacraft-Test", "true")
 	result.HeadersSent["X-Wacraft-Test"] = "true"
@@ -121,6 +161,11 @@ func executeTestWebhook(webhook *webhook_entity.Webhook, payload any) TestWebhoo
 	req.Header.Set("X-Wacraft-Event", string(webhook.Event))
 	result.HeadersSent["X-Wacraft-Event"] = string(webhook.Event)
 
+	if setting.Batching() {
+		req.Header.Set("X-Wacraft-Batch-Size", "1")
+		result.HeadersSent["X-Wacraft-Batch-Size"] = "1"
+	}
+
 	// Add authorization header if set
 	if webhook.Authorization != "" {
 		req.Header.Set("Authorization", webhook.Authorization)
@@ -135,7 +180,11 @@ func executeTestWebhook(webhook *webhook_entity.Webhook, payload any) TestWebhoo
 
 	// Add signature headers if enabled
 	if webhook.SigningEnabled && webhook.SigningSecret != "" {
-		signature, timestamp := webhook_core_service.SignatureHeaders(webhook.SigningSecret, jsonPayload)
+		signature, timestamp, err := webhook_service.SignatureHeaders(*webhook, jsonPayload)
+		if err != nil {
+			result.Error = "Failed to sign payload: " + err.Error()
+			return result
+		}
 		req.Header.Set("X-Wacraft-Signature", signature)
 		req.Header.Set("X-Wacraft-Timestamp", timestamp)
 		result.HeadersSent["X-Wacraft-Signature"] = signature
@@ -153,7 +202,7 @@ func executeTestWebhook(webhook *webhook_entity.Webhook, payload any) TestWebhoo
 	req = req.WithContext(ctx)
 
 	// Execute request
-	client := &http.Client{}
+	client := webhook_destination_service.Client(timeout)
 	startTime := time.Now()
 	resp, err := client.Do(req)
 	result.DurationMs = time.Since(startTime).Milliseconds()
@@ -161,6 +210,9 @@ func executeTestWebhook(webhook *webhook_entity.Webhook, payload any) TestWebhoo
 	if err != nil {
 		result.Error = "Request failed: " + err.Error()
 		result.Success = false
+		if webhook_destination_service.IsBlocked(err) {
+			logBlockedTest(webhook, payload, result)
+		}
 		return result
 	}
 	defer resp.Body.Close()
@@ -185,3 +237,29 @@ func executeTestWebhook(webhook *webhook_entity.Webhook, payload any) TestWebhoo
 
 	return result
 }
+
+// logBlockedTest records a test refused by the destination policy in the
+// webhook logs, like blocked deliveries.
+func logBlockedTest(webhook *webhook_entity.Webhook, payload any, result TestWebhookResponse) {
+	requestHeaders := make(map[string]string, len(result.HeadersSent))
+	for key, value := range result.HeadersSent {
+		requestHeaders[key] = value
+	}
+	if _, ok := requestHeaders["X-Wacraft-Signature"]; ok {
+		requestHeaders["X-Wacraft-Signature"] = "[REDACTED]"
+	}
+
+	log := webhook_entity.WebhookLog{
+		Payload:        payload,
+		ResponseData:   map[string]any{"blocked": true, "error": result.Error},
+		WebhookID:      webhook.ID,
+		AttemptNumber:  1,
+		DurationMs:     result.DurationMs,
+		SignatureSent:  webhook.SigningEnabled && webhook.SigningSecret != "",
+		RequestHeaders: requestHeaders,
+		RequestUrl:     webhook.Url,
+	}
+	if err := database.DB.Create(&log).Error; err != nil {
+		pterm.DefaultLogger.Error("Failed to create webhook log: " + err.Error())
+	}
+}
@@ -0,0 +1,95 @@
+package webhook_handler
+
+import (
+	"encoding/json"
+	"errors"
+
+	common_model "github.com/Astervia/wacraft-core/src/common/model"
+	webhook_entity "github.com/Astervia/wacraft-core/src/webhook/entity"
+	"github.com/Astervia/wacraft-server/src/database"
+	webhook_setting_service "github.com/Astervia/wacraft-server/src/webhook-setting/service"
+	webhook_transform_model "github.com/Astervia/wacraft-server/src/webhook-transform/model"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// PreviewTransform renders a payload transform without sending anything.
+//
+//	@Summary		Preview webhook payload transform
+//	@Description	Renders a transform against a sample event and returns the body and content type the webhook would receive. The transform defaults to the one saved for the webhook and the sample to the payload of its latest delivery, or a test payload when it has none.
+//	@Tags			Webhook
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string										true	"Webhook ID"
+//	@Param			preview	body		webhook_transform_model.PreviewTransform	true	"Transform and sample payload"
+//	@Success		200		{object}	webhook_transform_model.PreviewResult		"Rendered body"
+//	@Failure		400		{object}	common_model.DescriptiveError				"Invalid transform or payload"
+//	@Failure		404		{object}	common_model.DescriptiveError				"Webhook not found"
+//	@Failure		500		{object}	common_model.DescriptiveError				"Internal server error"
+//	@Router			/webhook/{id}/transform/preview [post]
+//	@Security		ApiKeyAuth
+//	@Security		WorkspaceAuth
+func PreviewTransform(c *fiber.Ctx) error {
+	webhook, ok, err := findWebhook(c)
+	if !ok {
+		return err
+	}
+
+	var preview webhook_transform_model.PreviewTransform
+	if err := c.BodyParser(&preview); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewParseJsonError(err).Send(),
+		)
+	}
+
+	transform := preview.Transform
+	if transform == nil {
+		setting, err := webhook_setting_service.GetWebhookSetting(webhook.ID, nil)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(
+				common_model.NewApiError("unable to get webhook settings", err, "webhook_setting_service").Send(),
+			)
+		}
+		if setting.Transform == nil {
+			return c.Status(fiber.StatusBadRequest).JSON(
+				common_model.NewApiError("the webhook has no transform, provide one to preview", nil, "handler").Send(),
+			)
+		}
+		transform = setting.Transform
+	}
+
+	payload := preview.Payload
+	if payload == nil {
+		var latest webhook_entity.WebhookDelivery
+		err := database.DB.Where("webhook_id = ?", webhook.ID).Order("created_at DESC").First(&latest).Error
+		switch {
+		case err == nil:
+			payload = latest.Payload
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			payload = testPayload(webhook)
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(
+				common_model.NewApiError("unable to get latest webhook delivery", err, "database").Send(),
+			)
+		}
+	}
+
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewApiError("unable to encode payload", err, "encoding/json").Send(),
+		)
+	}
+
+	body, err := transform.Render(jsonPayload)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewApiError(err.Error(), err, "webhook_transform_model").Send(),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(webhook_transform_model.PreviewResult{
+		ContentType: transform.GetContentType(),
+		Body:        string(body),
+	})
+}
@@ -7,6 +7,7 @@ import (
 	webhook_model "github.com/Astervia/wacraft-core/src/webhook/model"
 	"github.com/Astervia/wacraft-server/src/database"
 	"github.com/Astervia/wacraft-server/src/validators"
+	webhook_destination_service "github.com/Astervia/wacraft-server/src/webhook-destination/service"
 	workspace_middleware "github.com/Astervia/wacraft-server/src/workspace/middleware"
 	"github.com/gofiber/fiber/v2"
 )
@@ -20,7 +21,7 @@ import (
 //	@Produce		json
 //	@Param			webhook	body		webhook_model.UpdateWebhook		true	"Updated webhook data"
 //	@Success		200		{object}	webhook_entity.Webhook			"Updated webhook"
-//	@Failure		400		{object}	common_model.DescriptiveError	"Invalid request body"
+//	@Failure		400		{object}	common_model.DescriptiveError	"Invalid request body or blocked destination"
 //	@Failure		500		{object}	common_model.DescriptiveError	"Internal server error"
 //	@Router			/webhook [put]
 //	@Security		ApiKeyAuth
@@ -41,6 +42,14 @@ func UpdateWebhook(c *fiber.Ctx) error {
 		)
 	}
 
+	if editWebhook.Url != "" {
+		if err := webhook_destination_service.CheckURL(editWebhook.Url); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(
+				common_model.NewApiError(err.Error(), err, "webhook_destination_service").Send(),
+			)
+		}
+	}
+
 	// Build update struct
 	updateData := webhook_entity.Webhook{
 		Url:           editWebhook.Url,
@@ -0,0 +1,50 @@
+package webhook_router
+
+import (
+	workspace_model "github.com/Astervia/wacraft-core/src/workspace/model"
+	auth_middleware "github.com/Astervia/wacraft-server/src/auth/middleware"
+	billing_middleware "github.com/Astervia/wacraft-server/src/billing/middleware"
+	webhook_handler "github.com/Astervia/wacraft-server/src/webhook/handler"
+	workspace_middleware "github.com/Astervia/wacraft-server/src/workspace/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+func deliveryRoutes(group fiber.Router) {
+	deliveryGroup := group.Group("/delivery")
+
+	deliveryGroup.Get("/",
+		auth_middleware.UserMiddleware,
+		auth_middleware.EmailVerifiedMiddleware,
+		workspace_middleware.WorkspaceMiddleware,
+		workspace_middleware.RequirePolicy(workspace_model.PolicyWebhookManage),
+		billing_middleware.ThroughputMiddleware,
+		webhook_handler.GetDeliveries)
+	deliveryGroup.Post("/redrive",
+		auth_middleware.UserMiddleware,
+		auth_middleware.EmailVerifiedMiddleware,
+		workspace_middleware.WorkspaceMiddleware,
+		workspace_middleware.RequirePolicy(workspace_model.PolicyWebhookManage),
+		billing_middleware.ThroughputMiddleware,
+		webhook_handler.RedriveDeadLetters)
+	deliveryGroup.Post("/cancel",
+		auth_middleware.UserMiddleware,
+		auth_middleware.EmailVerifiedMiddleware,
+		workspace_middleware.WorkspaceMiddleware,
+		workspace_middleware.RequirePolicy(workspace_model.PolicyWebhookManage),
+		billing_middleware.ThroughputMiddleware,
+		webhook_handler.CancelDeliveries)
+	deliveryGroup.Post("/:id/retry",
+		auth_middleware.UserMiddleware,
+		auth_middleware.EmailVerifiedMiddleware,
+		workspace_middleware.WorkspaceMiddleware,
+		workspace_middleware.RequirePolicy(workspace_model.PolicyWebhookManage),
+		billing_middleware.ThroughputMiddleware,
+		webhook_handler.RetryDelivery)
+	deliveryGroup.Post("/:id/cancel",
+		auth_middleware.UserMiddleware,
+		auth_middleware.EmailVerifiedMiddleware,
+		workspace_middleware.WorkspaceMiddleware,
+		workspace_middleware.RequirePolicy(workspace_model.PolicyWebhookManage),
+		billing_middleware.ThroughputMiddleware,
+		webhook_handler.CancelDelivery)
+}
@@ -14,6 +14,9 @@ func Route(app *fiber.App) {
 
 	mainRoutes(group)
 	logRoutes(group)
+	deliveryRoutes(group)
+	settingRoutes(group)
+	circuitRoutes(group)
 }
 
 func mainRoutes(group fiber.Router) {
@@ -24,6 +27,13 @@ func mainRoutes(group fiber.Router) {
 		workspace_middleware.RequirePolicy(workspace_model.PolicyWebhookRead),
 		billing_middleware.ThroughputMiddleware,
 		webhook_handler.GetWebhooks)
+	group.Get("/event",
+		auth_middleware.UserMiddleware,
+		auth_middleware.EmailVerifiedMiddleware,
+		workspace_middleware.WorkspaceMiddleware,
+		workspace_middleware.RequirePolicy(workspace_model.PolicyWebhookRead),
+		billing_middleware.ThroughputMiddleware,
+		webhook_handler.GetEvents)
 	group.Post("/",
 		auth_middleware.UserMiddleware,
 		auth_middleware.EmailVerifiedMiddleware,
@@ -52,6 +62,13 @@ func mainRoutes(group fiber.Router) {
 		workspace_middleware.RequirePolicy(workspace_model.PolicyWebhookRead),
 		billing_middleware.ThroughputMiddleware,
 		webhook_handler.ContentKeyLike)
+	group.Post("/search",
+		auth_middleware.UserMiddleware,
+		auth_middleware.EmailVerifiedMiddleware,
+		workspace_middleware.WorkspaceMiddleware,
+		workspace_middleware.RequirePolicy(workspace_model.PolicyWebhookRead),
+		billing_middleware.ThroughputMiddleware,
+		webhook_handler.SearchByFilter)
 	group.Post("/test",
 		auth_middleware.UserMiddleware,
 		auth_middleware.EmailVerifiedMiddleware,
@@ -0,0 +1,45 @@
+package webhook_router
+
+import (
+	workspace_model "github.com/Astervia/wacraft-core/src/workspace/model"
+	auth_middleware "github.com/Astervia/wacraft-server/src/auth/middleware"
+	billing_middleware "github.com/Astervia/wacraft-server/src/billing/middleware"
+	webhook_handler "github.com/Astervia/wacraft-server/src/webhook/handler"
+	workspace_middleware "github.com/Astervia/wacraft-server/src/workspace/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+func settingRoutes(group fiber.Router) {
+	settingGroup := group.Group("/:id/setting")
+
+	settingGroup.Get("",
+		auth_middleware.UserMiddleware,
+		auth_middleware.EmailVerifiedMiddleware,
+		workspace_middleware.WorkspaceMiddleware,
+		workspace_middleware.RequirePolicy(workspace_model.PolicyWebhookRead),
+		billing_middleware.ThroughputMiddleware,
+		webhook_handler.GetSetting)
+	settingGroup.Patch("",
+		auth_middleware.UserMiddleware,
+		auth_middleware.EmailVerifiedMiddleware,
+		workspace_middleware.WorkspaceMiddleware,
+		workspace_middleware.RequirePolicy(workspace_model.PolicyWebhookManage),
+		billing_middleware.ThroughputMiddleware,
+		webhook_handler.UpdateSetting)
+
+	group.Post("/:id/transform/preview",
+		auth_middleware.UserMiddleware,
+		auth_middleware.EmailVerifiedMiddleware,
+		workspace_middleware.WorkspaceMiddleware,
+		workspace_middleware.RequirePolicy(workspace_model.PolicyWebhookManage),
+		billing_middleware.ThroughputMiddleware,
+		webhook_handler.PreviewTransform)
+
+	group.Post("/:id/secret/rotate",
+		auth_middleware.UserMiddleware,
+		auth_middleware.EmailVerifiedMiddleware,
+		workspace_middleware.WorkspaceMiddleware,
+		workspace_middleware.RequirePolicy(workspace_model.PolicyWebhookManage),
+		billing_middleware.ThroughputMiddleware,
+		webhook_handler.RotateSecret)
+}
@@ -0,0 +1,132 @@
+package webhook_service
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	webhook_entity "github.com/Astervia/wacraft-core/src/webhook/entity"
+	"github.com/Astervia/wacraft-server/src/database"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AnalyticsQuery selects the period the analytics of a webhook cover.
+type AnalyticsQuery struct {
+	// Hours back from now, 24 when omitted.
+	Hours int `json:"hours,omitempty" query:"hours" validate:"omitempty,min=1,max=720"`
+}
+
+// WebhookAnalytics is the delivery health of a webhook over a period,
+// computed from its logs, with the state of its queue and circuit breaker.
+type WebhookAnalytics struct {
+	WebhookID uuid.UUID `json:"webhook_id"`
+	From      time.Time `json:"from"`
+	To        time.Time `json:"to"`
+
+	Requests    int64   `json:"requests"`     // Logged attempts, a batch counts once.
+	Succeeded   int64   `json:"succeeded"`    // Attempts answered with a 2xx code.
+	Failed      int64   `json:"failed"`       // Other attempts, including connection errors.
+	SuccessRate float64 `json:"success_rate"` // Succeeded over requests, 0 to 1. 0 without requests.
+
+	Latency LatencyPercentiles `json:"latency"`
+	Errors  []ErrorCount       `json:"errors"`
+
+	// Queue counts the current deliveries of the webhook by status.
+	Queue   map[webhook_entity.DeliveryStatus]int64 `json:"queue"`
+	Circuit CircuitStatus                           `json:"circuit"`
+}
+
+// LatencyPercentiles are request durations in milliseconds.
+type LatencyPercentiles struct {
+	P50 float64 `json:"p50"`
+	P95 float64 `json:"p95"`
+	P99 float64 `json:"p99"`
+}
+
+// ErrorCount is the number of failed attempts that got an HTTP code. Code 0
+// counts attempts without a response, such as timeouts, connection errors
+// and blocked destinations.
+type ErrorCount struct {
+	HttpCode int   `json:"http_code"`
+	Count    int64 `json:"count"`
+}
+
+// GetAnalytics computes the analytics of a webhook over the given query.
+func GetAnalytics(webhook webhook_entity.Webhook, query AnalyticsQuery) (WebhookAnalytics, error) {
+	hours := query.Hours
+	if hours == 0 {
+		hours = 24
+	}
+
+	to := time.Now()
+	analytics := WebhookAnalytics{
+		WebhookID: webhook.ID,
+		From:      to.Add(-time.Duration(hours) * time.Hour),
+		To:        to,
+		Errors:    []ErrorCount{},
+		Queue:     map[webhook_entity.DeliveryStatus]int64{},
+	}
+
+	logs := database.DB.Model(&webhook_entity.WebhookLog{}).
+		Where("webhook_id = ? AND created_at >= ? AND created_at < ?", webhook.ID, analytics.From, analytics.To)
+
+	var totals struct {
+		Requests  int64
+		Succeeded int64
+		P50       sql.NullFloat64 // NULL without requests.
+		P95       sql.NullFloat64
+		P99       sql.NullFloat64
+	}
+	err := logs.Session(&gorm.Session{}).
+		Select(`COUNT(*) AS requests,
+			COUNT(*) FILTER (WHERE http_response_code BETWEEN 200 AND 299) AS succeeded,
+			percentile_cont(0.50) WITHIN GROUP (ORDER BY duration_ms) AS p50,
+			percentile_cont(0.95) WITHIN GROUP (ORDER BY duration_ms) AS p95,
+			percentile_cont(0.99) WITHIN GROUP (ORDER BY duration_ms) AS p99`).
+		Scan(&totals).Error
+	if err != nil {
+		return analytics, fmt.Errorf("failed to aggregate webhook logs: %w", err)
+	}
+
+	analytics.Requests = totals.Requests
+	analytics.Succeeded = totals.Succeeded
+	analytics.Failed = totals.Requests - totals.Succeeded
+	if totals.Requests > 0 {
+		analytics.SuccessRate = float64(totals.Succeeded) / float64(totals.Requests)
+	}
+	analytics.Latency = LatencyPercentiles{
+		P50: totals.P50.Float64,
+		P95: totals.P95.Float64,
+		P99: totals.P99.Float64,
+	}
+
+	err = logs.Session(&gorm.Session{}).
+		Select("http_response_code AS http_code, COUNT(*) AS count").
+		Where("http_response_code NOT BETWEEN 200 AND 299").
+		Group("http_response_code").
+		Order("count DESC, http_response_code").
+		Scan(&analytics.Errors).Error
+	if err != nil {
+		return analytics, fmt.Errorf("failed to group webhook log errors: %w", err)
+	}
+
+	var queue []struct {
+		Status webhook_entity.DeliveryStatus
+		Count  int64
+	}
+	err = database.DB.Model(&webhook_entity.WebhookDelivery{}).
+		Select("status, COUNT(*) AS count").
+		Where("webhook_id = ?", webhook.ID).
+		Group("status").
+		Scan(&queue).Error
+	if err != nil {
+		return analytics, fmt.Errorf("failed to count webhook deliveries: %w", err)
+	}
+	for _, q := range queue {
+		analytics.Queue[q.Status] = q.Count
+	}
+
+	analytics.Circuit, err = GetCircuitStatus(webhook)
+	return analytics, err
+}
@@ -0,0 +1,107 @@
+package webhook_service
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	webhook_entity "github.com/Astervia/wacraft-core/src/webhook/entity"
+	"github.com/Astervia/wacraft-server/src/database"
+)
+
+// createTestLog stores an attempt of webhook answered with httpCode.
+func createTestLog(t *testing.T, webhook webhook_entity.Webhook, httpCode int, duration time.Duration, createdAt time.Time) {
+	t.Helper()
+	log := webhook_entity.WebhookLog{
+		Payload:          map[string]any{"test": true},
+		WebhookID:        webhook.ID,
+		AttemptNumber:    1,
+		HttpResponseCode: httpCode,
+		DurationMs:       duration.Milliseconds(),
+	}
+	log.CreatedAt = createdAt
+	if err := database.DB.Create(&log).Error; err != nil {
+		t.Fatalf("createTestLog: %v", err)
+	}
+}
+
+func TestGetAnalytics(t *testing.T) {
+	webhook := createTestWebhook(t)
+	now := time.Now()
+	for _, attempt := range []struct {
+		code     int
+		duration time.Duration
+	}{
+		{200, 100 * time.Millisecond},
+		{200, 200 * time.Millisecond},
+		{500, 300 * time.Millisecond},
+		{500, 400 * time.Millisecond},
+		{0, 500 * time.Millisecond},
+	} {
+		createTestLog(t, webhook, attempt.code, attempt.duration, now.Add(-time.Hour))
+	}
+	// Out of the period.
+	createTestLog(t, webhook, 404, 10*time.Second, now.Add(-48*time.Hour))
+	createTestDelivery(t, webhook.ID, "", now)
+
+	analytics, err := GetAnalytics(webhook, AnalyticsQuery{})
+	if err != nil {
+		t.Fatalf("GetAnalytics: %v", err)
+	}
+
+	if analytics.Requests != 5 || analytics.Succeeded != 2 || analytics.Failed != 3 {
+		t.Errorf("expected 5 requests, 2 succeeded and 3 failed, got %d, %d and %d", analytics.Requests, analytics.Succeeded, analytics.Failed)
+	}
+	if math.Abs(analytics.SuccessRate-0.4) > 1e-9 {
+		t.Errorf("expected a success rate of 0.4, got %f", analytics.SuccessRate)
+	}
+
+	// Interpolated between the closest durations, like percentile_cont.
+	want := LatencyPercentiles{P50: 300, P95: 480, P99: 496}
+	if math.Abs(analytics.Latency.P50-want.P50) > 1e-6 ||
+		math.Abs(analytics.Latency.P95-want.P95) > 1e-6 ||
+		math.Abs(analytics.Latency.P99-want.P99) > 1e-6 {
+		t.Errorf("expected latency %+v, got %+v", want, analytics.Latency)
+	}
+
+	wantErrors := []ErrorCount{{HttpCode: 500, Count: 2}, {HttpCode: 0, Count: 1}}
+	if len(analytics.Errors) != len(wantErrors) {
+		t.Fatalf("expected errors %+v, got %+v", wantErrors, analytics.Errors)
+	}
+	for i := range wantErrors {
+		if analytics.Errors[i] != wantErrors[i] {
+			t.Fatalf("expected errors %+v, got %+v", wantErrors, analytics.Errors)
+		}
+	}
+
+	if analytics.Queue[webhook_entity.DeliveryStatusPending] != 1 {
+		t.Errorf("expected one pending delivery, got %v", analytics.Queue)
+	}
+	if analytics.Circuit.State != webhook_entity.CircuitClosed {
+		t.Errorf("expected a closed circuit, got %s", analytics.Circuit.State)
+	}
+
+	// The period reaches back to the older attempt.
+	analytics, err = GetAnalytics(webhook, AnalyticsQuery{Hours: 72})
+	if err != nil {
+		t.Fatalf("GetAnalytics: %v", err)
+	}
+	if analytics.Requests != 6 {
+		t.Errorf("expected 6 requests over 72 hours, got %d", analytics.Requests)
+	}
+}
+
+func TestGetAnalytics_WithoutRequests(t *testing.T) {
+	webhook := createTestWebhook(t)
+
+	analytics, err := GetAnalytics(webhook, AnalyticsQuery{})
+	if err != nil {
+		t.Fatalf("GetAnalytics: %v", err)
+	}
+	if analytics.Requests != 0 || analytics.SuccessRate != 0 || analytics.Latency != (LatencyPercentiles{}) {
+		t.Errorf("expected empty analytics, got %+v", analytics)
+	}
+	if analytics.Errors == nil || len(analytics.Errors) != 0 {
+		t.Errorf("expected an empty error list, got %v", analytics.Errors)
+	}
+}
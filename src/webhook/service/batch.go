@@ -0,0 +1,137 @@
+package webhook_service
+
+import (
+	"encoding/json"
+	"time"
+
+	webhook_entity "github.com/Astervia/wacraft-core/src/webhook/entity"
+	"github.com/Astervia/wacraft-server/src/database"
+	webhook_setting_entity "github.com/Astervia/wacraft-server/src/webhook-setting/entity"
+	webhook_transform_model "github.com/Astervia/wacraft-server/src/webhook-transform/model"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BatchEvent is an element of the JSON array sent to webhooks that batch
+// their deliveries. Consumers deduplicate each element by its idempotency key.
+type BatchEvent struct {
+	DeliveryID     uuid.UUID       `json:"delivery_id"`
+	IdempotencyKey string          `json:"idempotency_key"`
+	Event          string          `json:"event"`
+	Attempt        int             `json:"attempt"`
+	Timestamp      time.Time       `json:"timestamp"`
+	Payload        json.RawMessage `json:"payload" swaggertype:"object"`
+}
+
+// NewBatchEvent renders the payload of a delivery with transform as an
+// element of a batch. Payloads the transform turns into something other than
+// JSON are sent as a JSON string.
+func NewBatchEvent(delivery *webhook_entity.WebhookDelivery, transform *webhook_transform_model.WebhookTransform) (BatchEvent, error) {
+	event := BatchEvent{
+		DeliveryID:     delivery.ID,
+		IdempotencyKey: delivery.IdempotencyKey,
+		Event:          delivery.EventType,
+		Attempt:        delivery.AttemptCount + 1,
+		Timestamp:      delivery.EventTimestamp,
+	}
+
+	payload, err := json.Marshal(delivery.Payload)
+	if err != nil {
+		return event, err
+	}
+	body, _, err := RenderTransform(transform, payload)
+	if err != nil {
+		return event, err
+	}
+
+	event.Payload, err = BatchPayload(body)
+	return event, err
+}
+
+// BatchPayload embeds a rendered payload in a batch event: JSON as is and
+// anything else as a JSON string.
+func BatchPayload(body []byte) (json.RawMessage, error) {
+	if json.Valid(body) {
+		return body, nil
+	}
+	return json.Marshal(string(body))
+}
+
+// UpdateBatchStatus updates the deliveries sent together in a batch after
+// an attempt, like UpdateDeliveryStatus. They share the outcome and the
+// attempt time, so deliveries on the same attempt are due again together and
+// retried as a batch. The deliveries are updated in one transaction, so a
+// failure leaves none of them updated.
+func UpdateBatchStatus(deliveries []*webhook_entity.WebhookDelivery, owner string, success bool, httpCode int, responseBody string, errMsg string) error {
+	now := time.Now()
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		for _, delivery := range deliveries {
+			if err := updateDeliveryStatus(tx, delivery, owner, now, success, httpCode, responseBody, errMsg); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// openBatch conditions the first attempts of a webhook that wait for their batch.
+const openBatch = "webhook_id = ? AND status = ? AND attempt_count = 0 AND lease_owner IS NULL AND next_attempt_at > NOW()"
+
+// lockOpenBatch locks and returns the deliveries of the open batch of a
+// webhook, earliest first. The claim skips locked rows, so the batch is not
+// sent while a delivery joins it.
+func lockOpenBatch(tx *gorm.DB, webhookID uuid.UUID) ([]webhook_entity.WebhookDelivery, error) {
+	var batch []webhook_entity.WebhookDelivery
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id", "next_attempt_at").
+		Where(openBatch, webhookID, webhook_entity.DeliveryStatusPending).
+		Order("next_attempt_at ASC").
+		Find(&batch).Error
+	return batch, err
+}
+
+// joinBatch sets a new delivery of a batching webhook to be sent with the
+// open batch of the webhook, or opens a batch sent after the max wait.
+//
+// The settings row of the webhook is locked first so concurrent deliveries
+// join the same batch instead of each opening one.
+func joinBatch(tx *gorm.DB, setting webhook_setting_entity.WebhookSetting, delivery *webhook_entity.WebhookDelivery) error {
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		Where("webhook_id = ?", delivery.WebhookID).
+		Take(&webhook_setting_entity.WebhookSetting{}).Error
+	if err != nil {
+		return err
+	}
+
+	batch, err := lockOpenBatch(tx, delivery.WebhookID)
+	if err != nil {
+		return err
+	}
+
+	if len(batch) > 0 && batch[0].NextAttemptAt != nil {
+		delivery.NextAttemptAt = batch[0].NextAttemptAt
+		return nil
+	}
+	window := time.Now().Add(time.Duration(setting.BatchMaxWaitMs) * time.Millisecond)
+	delivery.NextAttemptAt = &window
+	return nil
+}
+
+// flushFullBatch makes the open batch of a webhook due at once when it holds
+// enough deliveries to fill a batch.
+func flushFullBatch(tx *gorm.DB, setting webhook_setting_entity.WebhookSetting, webhookID uuid.UUID) error {
+	batch, err := lockOpenBatch(tx, webhookID)
+	if err != nil || len(batch) < setting.BatchSize {
+		return err
+	}
+
+	ids := make([]uuid.UUID, 0, len(batch))
+	for _, delivery := range batch {
+		ids = append(ids, delivery.ID)
+	}
+	return tx.Model(&webhook_entity.WebhookDelivery{}).
+		Where("id IN ?", ids).
+		UpdateColumn("next_attempt_at", gorm.Expr("NOW()")).Error
+}
@@ -0,0 +1,109 @@
+package webhook_service
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	webhook_entity "github.com/Astervia/wacraft-core/src/webhook/entity"
+	webhook_model "github.com/Astervia/wacraft-core/src/webhook/model"
+	"github.com/Astervia/wacraft-server/src/database"
+	webhook_setting_service "github.com/Astervia/wacraft-server/src/webhook-setting/service"
+	"github.com/google/uuid"
+)
+
+// newBatchDelivery builds a first attempt of a delivery of webhookID.
+func newBatchDelivery(webhookID uuid.UUID) webhook_entity.WebhookDelivery {
+	now := time.Now()
+	return webhook_entity.WebhookDelivery{
+		WebhookID:      webhookID,
+		IdempotencyKey: uuid.NewString(),
+		Payload:        map[string]any{"test": true},
+		Status:         webhook_entity.DeliveryStatusPending,
+		MaxAttempts:    3,
+		NextAttemptAt:  &now,
+		EventType:      string(webhook_model.SendWhatsAppMessage),
+		EventTimestamp: now,
+	}
+}
+
+// queueBatchDelivery queues a new delivery of a batching webhook.
+func queueBatchDelivery(t *testing.T, webhook *webhook_entity.Webhook) webhook_entity.WebhookDelivery {
+	t.Helper()
+	delivery := newBatchDelivery(webhook.ID)
+	if err := queueDelivery(webhook, &delivery); err != nil {
+		t.Fatalf("queueDelivery: %v", err)
+	}
+	return delivery
+}
+
+// createBatchingWebhook creates a webhook sending batches of size deliveries
+// after a max wait of one minute.
+func createBatchingWebhook(t *testing.T, size int) webhook_entity.Webhook {
+	t.Helper()
+	webhook := createTestWebhook(t)
+	if _, err := webhook_setting_service.UpdateWebhookSetting(webhook.ID, map[string]any{
+		"batch_size":        size,
+		"batch_max_wait_ms": int(time.Minute / time.Millisecond),
+	}, nil); err != nil {
+		t.Fatalf("UpdateWebhookSetting: %v", err)
+	}
+	return webhook
+}
+
+func TestQueueDelivery_BatchWaitsUntilFull(t *testing.T) {
+	webhook := createBatchingWebhook(t, 3)
+
+	first := queueBatchDelivery(t, &webhook)
+	second := queueBatchDelivery(t, &webhook)
+	if !first.NextAttemptAt.After(time.Now()) {
+		t.Fatalf("expected the first delivery to wait for its batch, due at %s", first.NextAttemptAt)
+	}
+	if !second.NextAttemptAt.Equal(*first.NextAttemptAt) {
+		t.Fatalf("expected the second delivery to join the open batch, due at %s instead of %s", second.NextAttemptAt, first.NextAttemptAt)
+	}
+	if claimed := claimedIDs(t, "worker-a"); claimed[first.ID] || claimed[second.ID] {
+		t.Fatalf("expected the open batch to wait, got %v", claimed)
+	}
+
+	// The delivery filling the batch makes the whole batch due.
+	third := queueBatchDelivery(t, &webhook)
+	claimed := claimedIDs(t, "worker-b")
+	for _, delivery := range []webhook_entity.WebhookDelivery{first, second, third} {
+		if !claimed[delivery.ID] {
+			t.Fatalf("expected the full batch to be claimed, got %v", claimed)
+		}
+	}
+
+	// The next delivery opens a new batch.
+	fourth := queueBatchDelivery(t, &webhook)
+	if !fourth.NextAttemptAt.After(time.Now()) {
+		t.Fatalf("expected the next delivery to open a new batch, due at %s", fourth.NextAttemptAt)
+	}
+}
+
+func TestQueueDelivery_ConcurrentDeliveriesShareTheBatch(t *testing.T) {
+	webhook := createBatchingWebhook(t, 100)
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			delivery := newBatchDelivery(webhook.ID)
+			if err := queueDelivery(&webhook, &delivery); err != nil {
+				t.Errorf("queueDelivery: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	var windows int64
+	database.DB.Model(&webhook_entity.WebhookDelivery{}).
+		Where("webhook_id = ?", webhook.ID).
+		Distinct("next_attempt_at").
+		Count(&windows)
+	if windows != 1 {
+		t.Fatalf("expected concurrent deliveries to join one batch, got %d windows", windows)
+	}
+}
@@ -0,0 +1,92 @@
+package webhook_service
+
+import (
+	"time"
+
+	webhook_entity "github.com/Astervia/wacraft-core/src/webhook/entity"
+	"github.com/Astervia/wacraft-server/src/config/env"
+	"github.com/Astervia/wacraft-server/src/database"
+	webhook_setting_service "github.com/Astervia/wacraft-server/src/webhook-setting/service"
+	"gorm.io/gorm"
+)
+
+// CircuitForcedOpen is the state reported for circuits forced open through
+// the API. Unlike an open circuit it never moves to half open by itself.
+const CircuitForcedOpen webhook_entity.CircuitState = "forced_open"
+
+// CircuitStatus is the circuit breaker state of a webhook.
+type CircuitStatus struct {
+	State         webhook_entity.CircuitState `json:"state"` // closed, open, half_open or forced_open.
+	FailureCount  int                         `json:"failure_count"`
+	LastFailureAt *time.Time                  `json:"last_failure_at,omitempty"`
+	OpenedAt      *time.Time                  `json:"opened_at,omitempty"`
+	// NextHalfOpenAt is when an open circuit lets the next probe through.
+	// In the past when the probe is due on the next delivery.
+	NextHalfOpenAt *time.Time `json:"next_half_open_at,omitempty"`
+}
+
+// GetCircuitStatus returns the circuit breaker state of a webhook.
+func GetCircuitStatus(webhook webhook_entity.Webhook) (CircuitStatus, error) {
+	status := CircuitStatus{
+		State:         webhook.CircuitState,
+		FailureCount:  webhook.FailureCount,
+		LastFailureAt: webhook.LastFailureAt,
+		OpenedAt:      webhook.CircuitOpenedAt,
+	}
+	if status.State == "" {
+		status.State = webhook_entity.CircuitClosed
+	}
+
+	setting, err := webhook_setting_service.GetWebhookSetting(webhook.ID, nil)
+	if err != nil {
+		return status, err
+	}
+	if setting.CircuitForcedOpen {
+		status.State = CircuitForcedOpen
+		return status, nil
+	}
+
+	if status.State == webhook_entity.CircuitOpen && status.OpenedAt != nil {
+		next := status.OpenedAt.Add(env.WebhookCircuitRecoveryTimeout)
+		status.NextHalfOpenAt = &next
+	}
+	return status, nil
+}
+
+// ForceCircuitOpen stops the deliveries of a webhook until its circuit is
+// closed again. Deliveries keep being queued, unlike with a tripped circuit,
+// and are sent once it is closed.
+func ForceCircuitOpen(webhook webhook_entity.Webhook) (CircuitStatus, error) {
+	if _, err := webhook_setting_service.UpdateWebhookSetting(webhook.ID, map[string]any{"circuit_forced_open": true}, nil); err != nil {
+		return CircuitStatus{}, err
+	}
+	return GetCircuitStatus(webhook)
+}
+
+// ForceCircuitClosed closes the circuit of a webhook, whether it tripped or
+// was forced open, resets its failure count and wakes the workers to send the
+// held deliveries.
+func ForceCircuitClosed(webhook webhook_entity.Webhook) (CircuitStatus, error) {
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if _, err := webhook_setting_service.UpdateWebhookSetting(webhook.ID, map[string]any{"circuit_forced_open": false}, tx); err != nil {
+			return err
+		}
+		return tx.Model(&webhook_entity.Webhook{}).
+			Where("id = ?", webhook.ID).
+			Updates(map[string]any{
+				"circuit_state":     webhook_entity.CircuitClosed,
+				"failure_count":     0,
+				"circuit_opened_at": nil,
+			}).Error
+	})
+	if err != nil {
+		return CircuitStatus{}, err
+	}
+
+	if err := database.DB.First(&webhook, "id = ?", webhook.ID).Error; err != nil {
+		return CircuitStatus{}, err
+	}
+
+	NotifyDeliveries()
+	return GetCircuitStatus(webhook)
+}
@@ -0,0 +1,79 @@
+package webhook_service
+
+import (
+	"testing"
+	"time"
+
+	webhook_entity "github.com/Astervia/wacraft-core/src/webhook/entity"
+	"github.com/Astervia/wacraft-server/src/config/env"
+	"github.com/Astervia/wacraft-server/src/database"
+)
+
+func TestGetCircuitStatus_NextHalfOpenAt(t *testing.T) {
+	recoveryTimeout := env.WebhookCircuitRecoveryTimeout
+	env.WebhookCircuitRecoveryTimeout = 2 * time.Minute
+	t.Cleanup(func() { env.WebhookCircuitRecoveryTimeout = recoveryTimeout })
+
+	webhook := createTestWebhook(t)
+	openedAt := time.Now().Add(-10 * time.Second)
+	webhook.CircuitState = webhook_entity.CircuitOpen
+	webhook.FailureCount = 5
+	webhook.CircuitOpenedAt = &openedAt
+
+	status, err := GetCircuitStatus(webhook)
+	if err != nil {
+		t.Fatalf("GetCircuitStatus: %v", err)
+	}
+	if status.State != webhook_entity.CircuitOpen || status.FailureCount != 5 {
+		t.Fatalf("expected an open circuit with 5 failures, got %+v", status)
+	}
+	if want := openedAt.Add(2 * time.Minute); status.NextHalfOpenAt == nil || !status.NextHalfOpenAt.Equal(want) {
+		t.Errorf("expected the next probe at %s, got %v", want, status.NextHalfOpenAt)
+	}
+}
+
+func TestForceCircuit_HoldsAndResumesDeliveries(t *testing.T) {
+	webhook := createTestWebhook(t)
+	other := createTestWebhook(t)
+	start := time.Now().Add(-time.Minute)
+	held := createTestDelivery(t, webhook.ID, "", start)
+
+	status, err := ForceCircuitOpen(webhook)
+	if err != nil {
+		t.Fatalf("ForceCircuitOpen: %v", err)
+	}
+	if status.State != CircuitForcedOpen || status.NextHalfOpenAt != nil {
+		t.Fatalf("expected a forced open circuit without probe, got %+v", status)
+	}
+
+	// Deliveries keep being queued but are not claimed while forced open.
+	queued := createTestDelivery(t, webhook.ID, "", start.Add(time.Second))
+	unrelated := createTestDelivery(t, other.ID, "", start)
+	claimed := claimedIDs(t, "worker-a")
+	if claimed[held.ID] || claimed[queued.ID] {
+		t.Fatalf("expected the deliveries of the forced open webhook to be held, got %v", claimed)
+	}
+	if !claimed[unrelated.ID] {
+		t.Fatalf("expected the deliveries of other webhooks to be claimed, got %v", claimed)
+	}
+
+	// Closing resets a tripped circuit too.
+	openedAt := time.Now()
+	database.DB.Model(&webhook_entity.Webhook{}).Where("id = ?", webhook.ID).Updates(map[string]any{
+		"circuit_state":     webhook_entity.CircuitOpen,
+		"failure_count":     5,
+		"circuit_opened_at": openedAt,
+	})
+	status, err = ForceCircuitClosed(webhook)
+	if err != nil {
+		t.Fatalf("ForceCircuitClosed: %v", err)
+	}
+	if status.State != webhook_entity.CircuitClosed || status.FailureCount != 0 || status.OpenedAt != nil {
+		t.Fatalf("expected a reset closed circuit, got %+v", status)
+	}
+
+	claimed = claimedIDs(t, "worker-b")
+	if !claimed[held.ID] || !claimed[queued.ID] {
+		t.Fatalf("expected the held deliveries to be claimed once closed, got %v", claimed)
+	}
+}
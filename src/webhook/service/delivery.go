@@ -0,0 +1,183 @@
+package webhook_service
+
+import (
+	"errors"
+	"time"
+
+	database_model "github.com/Astervia/wacraft-core/src/database/model"
+	webhook_entity "github.com/Astervia/wacraft-core/src/webhook/entity"
+	"github.com/Astervia/wacraft-server/src/database"
+	database_cursor "github.com/Astervia/wacraft-server/src/database/cursor"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DeliveryStatusCancelled marks deliveries cancelled through the API. The
+// worker never picks them up again unless they are retried.
+const DeliveryStatusCancelled webhook_entity.DeliveryStatus = "cancelled"
+
+// ErrDeliveryNotRetryable is returned when retrying a delivery that is still
+// queued or already succeeded.
+var ErrDeliveryNotRetryable = errors.New("only dead letter and cancelled deliveries can be retried")
+
+// ErrDeliveryNotCancellable is returned when cancelling a delivery that is no
+// longer queued.
+var ErrDeliveryNotCancellable = errors.New("only pending and attempted deliveries can be cancelled")
+
+// retryableStatuses can be sent again; queuedStatuses are waiting for the worker.
+var (
+	retryableStatuses = []webhook_entity.DeliveryStatus{webhook_entity.DeliveryStatusDeadLetter, DeliveryStatusCancelled}
+	queuedStatuses    = []webhook_entity.DeliveryStatus{webhook_entity.DeliveryStatusPending, webhook_entity.DeliveryStatusAttempted}
+)
+
+// workspaceDeliveries scopes deliveries to the webhooks of the workspace.
+const workspaceDeliveries = "webhook_deliveries.webhook_id IN (SELECT id FROM webhooks WHERE workspace_id = ?)"
+
+// requeueAssignments sends a delivery again with a fresh retry budget.
+var requeueAssignments = map[string]any{
+	"status":           webhook_entity.DeliveryStatusPending,
+	"next_attempt_at":  gorm.Expr("NOW()"),
+	"max_attempts":     gorm.Expr("webhook_deliveries.attempt_count + COALESCE((SELECT max_retries FROM webhooks WHERE webhooks.id = webhook_deliveries.webhook_id), 0) + 1"),
+	"lease_owner":      nil,
+	"lease_expires_at": nil,
+	"updated_at":       gorm.Expr("NOW()"),
+}
+
+// DeliveryQuery filters the deliveries listing.
+type DeliveryQuery struct {
+	WebhookID *uuid.UUID                    `json:"webhook_id,omitempty" query:"webhook_id"`
+	Status    webhook_entity.DeliveryStatus `json:"status,omitempty" query:"status"`
+	EventType string                        `json:"event_type,omitempty" query:"event_type"`
+
+	database_model.Paginate
+	database_model.DateWhere
+}
+
+// DeliveryWindow selects the deliveries of a webhook created within a time range.
+type DeliveryWindow struct {
+	WebhookID uuid.UUID  `json:"webhook_id" validate:"required"`
+	From      *time.Time `json:"from,omitempty"`
+	To        *time.Time `json:"to,omitempty"`
+}
+
+// DeliveriesAffected is the response of bulk delivery operations.
+type DeliveriesAffected struct {
+	Affected int64 `json:"affected"`
+}
+
+// GetDeliveries lists the deliveries of the workspace webhooks, newest first.
+func GetDeliveries(query DeliveryQuery, workspaceID uuid.UUID) ([]webhook_entity.WebhookDelivery, error) {
+	db := database.DB.Model(&webhook_entity.WebhookDelivery{}).
+		Where(workspaceDeliveries, workspaceID)
+
+	if query.WebhookID != nil {
+		db = db.Where("webhook_deliveries.webhook_id = ?", *query.WebhookID)
+	}
+	if query.Status != "" {
+		db = db.Where("webhook_deliveries.status = ?", query.Status)
+	}
+	if query.EventType != "" {
+		db = db.Where("webhook_deliveries.event_type = ?", query.EventType)
+	}
+	query.DateWhere.Where(&db, `"webhook_deliveries"`)
+
+	deliveries := []webhook_entity.WebhookDelivery{}
+	err := db.
+		Order("webhook_deliveries.created_at DESC, webhook_deliveries.id DESC").
+		Offset(query.Offset).
+		Limit(database_cursor.Limit(query.Limit)).
+		Find(&deliveries).Error
+	return deliveries, err
+}
+
+// RetryDelivery queues a dead letter or cancelled delivery again with a full
+// retry budget. Returns gorm.ErrRecordNotFound when the delivery is not in the workspace.
+func RetryDelivery(id uuid.UUID, workspaceID uuid.UUID) (webhook_entity.WebhookDelivery, error) {
+	delivery, err := transitionDelivery(id, workspaceID, retryableStatuses, requeueAssignments, ErrDeliveryNotRetryable)
+	if err == nil {
+		NotifyDeliveries()
+	}
+	return delivery, err
+}
+
+// CancelDelivery stops a queued delivery. Returns gorm.ErrRecordNotFound when
+// the delivery is not in the workspace.
+func CancelDelivery(id uuid.UUID, workspaceID uuid.UUID) (webhook_entity.WebhookDelivery, error) {
+	return transitionDelivery(id, workspaceID, queuedStatuses, cancelAssignments(), ErrDeliveryNotCancellable)
+}
+
+// RedriveDeadLetters queues again every dead letter of a webhook in the window,
+// e.g. after the receiver recovers from an outage.
+func RedriveDeadLetters(window DeliveryWindow, workspaceID uuid.UUID) (int64, error) {
+	result := windowQuery(window, workspaceID).
+		Where("webhook_deliveries.status = ?", webhook_entity.DeliveryStatusDeadLetter).
+		Updates(requeueAssignments)
+	if result.Error == nil && result.RowsAffected > 0 {
+		NotifyDeliveries()
+	}
+	return result.RowsAffected, result.Error
+}
+
+// CancelDeliveries cancels every queued delivery of a webhook in the window.
+func CancelDeliveries(window DeliveryWindow, workspaceID uuid.UUID) (int64, error) {
+	result := windowQuery(window, workspaceID).
+		Where("webhook_deliveries.status IN ?", queuedStatuses).
+		Updates(cancelAssignments())
+	return result.RowsAffected, result.Error
+}
+
+func cancelAssignments() map[string]any {
+	return map[string]any{
+		"status":           DeliveryStatusCancelled,
+		"next_attempt_at":  nil,
+		"lease_owner":      nil,
+		"lease_expires_at": nil,
+		"updated_at":       gorm.Expr("NOW()"),
+	}
+}
+
+func windowQuery(window DeliveryWindow, workspaceID uuid.UUID) *gorm.DB {
+	db := database.DB.Model(&webhook_entity.WebhookDelivery{}).
+		Where(workspaceDeliveries, workspaceID).
+		Where("webhook_deliveries.webhook_id = ?", window.WebhookID)
+	if window.From != nil {
+		db = db.Where("webhook_deliveries.created_at >= ?", *window.From)
+	}
+	if window.To != nil {
+		db = db.Where("webhook_deliveries.created_at <= ?", *window.To)
+	}
+	return db
+}
+
+// transitionDelivery applies assignments to the delivery when its status is
+// one of from. The status check is part of the update so a delivery the
+// worker just finished is never overwritten.
+func transitionDelivery(
+	id uuid.UUID,
+	workspaceID uuid.UUID,
+	from []webhook_entity.DeliveryStatus,
+	assignments map[string]any,
+	errWrongStatus error,
+) (webhook_entity.WebhookDelivery, error) {
+	var delivery webhook_entity.WebhookDelivery
+	err := database.DB.
+		Where(workspaceDeliveries, workspaceID).
+		Where("webhook_deliveries.id = ?", id).
+		First(&delivery).Error
+	if err != nil {
+		return delivery, err
+	}
+
+	result := database.DB.Model(&webhook_entity.WebhookDelivery{}).
+		Where("webhook_deliveries.id = ? AND webhook_deliveries.status IN ?", id, from).
+		Updates(assignments)
+	if result.Error != nil {
+		return delivery, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return delivery, errWrongStatus
+	}
+
+	err = database.DB.First(&delivery, "id = ?", id).Error
+	return delivery, err
+}
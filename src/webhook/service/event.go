@@ -0,0 +1,53 @@
+package webhook_service
+
+import (
+	status_entity "github.com/Astervia/wacraft-core/src/status/entity"
+	webhook_entity "github.com/Astervia/wacraft-core/src/webhook/entity"
+	"github.com/Astervia/wacraft-server/src/database"
+	webhook_event_model "github.com/Astervia/wacraft-server/src/webhook-event/model"
+	"github.com/google/uuid"
+	"github.com/pterm/pterm"
+)
+
+// SendEvent enqueues a catalogue event, wrapped in a versioned envelope, for
+// every active webhook of the workspace subscribed to it. A nil workspace
+// matches only the webhooks without workspace, so an event of the legacy
+// phone number never reaches the webhooks of a workspace.
+func SendEvent(event string, workspaceID *uuid.UUID, data any) error {
+	envelope := webhook_event_model.NewEnvelope(event, workspaceID, data)
+
+	var webhooks []webhook_entity.Webhook
+	db := database.DB.Where("event = ?", event).Where("is_active = ?", true)
+	if workspaceID != nil {
+		db = db.Where("workspace_id = ?", *workspaceID)
+	} else {
+		db = db.Where("workspace_id IS NULL")
+	}
+	if err := db.Find(&webhooks).Error; err != nil {
+		pterm.DefaultLogger.Error("Failed to find webhooks for event " + event + ": " + err.Error())
+		return err
+	}
+
+	for i := range webhooks {
+		// Keyed by the envelope ID so an event is delivered once per webhook.
+		key := webhooks[i].ID.String() + ":" + envelope.ID.String()
+		if err := EnqueueDeliveryWithCustomKey(&webhooks[i], envelope, event, key); err != nil {
+			pterm.DefaultLogger.Error("Failed to enqueue delivery for webhook " + webhooks[i].ID.String() + ": " + err.Error())
+		}
+	}
+
+	return nil
+}
+
+// SendStatusEvent sends the message.status.* event of a status update.
+// Statuses outside the catalogue are ignored.
+func SendStatusEvent(workspaceID *uuid.UUID, status status_entity.Status) error {
+	if status.ProductData == nil || status.ProductData.Status == nil || status.ProductData.Status.Status == nil {
+		return nil
+	}
+	event, ok := webhook_event_model.MessageStatusEvent(string(*status.ProductData.Status.Status))
+	if !ok {
+		return nil
+	}
+	return SendEvent(event, workspaceID, status)
+}
@@ -0,0 +1,40 @@
+package webhook_service
+
+import (
+	webhook_entity "github.com/Astervia/wacraft-core/src/webhook/entity"
+	"github.com/Astervia/wacraft-server/src/database"
+	database_filter "github.com/Astervia/wacraft-server/src/database/filter"
+	"gorm.io/gorm"
+)
+
+// FilterSchema whitelists the fields usable in webhook filters. The
+// authorization and custom headers are left out on purpose.
+var FilterSchema = database_filter.Schema{
+	Table: "webhooks",
+	Fields: map[string]database_filter.Field{
+		"id":                {Column: "webhooks.id", Type: database_filter.UUID},
+		"created_at":        {Column: "webhooks.created_at", Type: database_filter.Time},
+		"updated_at":        {Column: "webhooks.updated_at", Type: database_filter.Time},
+		"url":               {Column: "webhooks.url", Type: database_filter.String},
+		"event":             {Column: "webhooks.event", Type: database_filter.String},
+		"http_method":       {Column: "webhooks.http_method", Type: database_filter.String},
+		"timeout":           {Column: "webhooks.timeout", Type: database_filter.Number},
+		"is_active":         {Column: "webhooks.is_active", Type: database_filter.Bool},
+		"signing_enabled":   {Column: "webhooks.signing_enabled", Type: database_filter.Bool},
+		"max_retries":       {Column: "webhooks.max_retries", Type: database_filter.Number},
+		"retry_delay_ms":    {Column: "webhooks.retry_delay_ms", Type: database_filter.Number},
+		"failure_count":     {Column: "webhooks.failure_count", Type: database_filter.Number},
+		"circuit_state":     {Column: "webhooks.circuit_state", Type: database_filter.String},
+		"last_failure_at":   {Column: "webhooks.last_failure_at", Type: database_filter.Time},
+		"circuit_opened_at": {Column: "webhooks.circuit_opened_at", Type: database_filter.Time},
+	},
+}
+
+// SearchByFilter returns the webhooks matching the search.
+func SearchByFilter(search database_filter.Search, db *gorm.DB) ([]webhook_entity.Webhook, error) {
+	if db == nil {
+		db = database.DB
+	}
+
+	return database_filter.Find[webhook_entity.Webhook](search, FilterSchema, db.Model(&webhook_entity.Webhook{}))
+}
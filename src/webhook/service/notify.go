@@ -0,0 +1,59 @@
+package webhook_service
+
+import (
+	"database/sql"
+	"time"
+
+	synch_contract "github.com/Astervia/wacraft-core/src/synch/contract"
+	webhook_entity "github.com/Astervia/wacraft-core/src/webhook/entity"
+	"github.com/Astervia/wacraft-server/src/database"
+	"github.com/pterm/pterm"
+)
+
+// DeliveryChannel is the Postgres NOTIFY channel, and the PubSub channel in
+// Redis mode, on which queued deliveries wake the delivery workers.
+const DeliveryChannel = "webhook_deliveries"
+
+// deliveryPubSub is set during init from src/synch/main.go.
+// nil in memory mode, where Postgres NOTIFY is used instead.
+var deliveryPubSub synch_contract.PubSub
+
+// SetDeliveryPubSub sets the PubSub used to wake the delivery workers of every instance.
+// Called from src/synch/main.go when SYNC_BACKEND=redis.
+func SetDeliveryPubSub(pubsub synch_contract.PubSub) {
+	deliveryPubSub = pubsub
+}
+
+// DeliveryPubSub returns the PubSub set by SetDeliveryPubSub, or nil in memory mode.
+func DeliveryPubSub() synch_contract.PubSub {
+	return deliveryPubSub
+}
+
+// NotifyDeliveries wakes the delivery workers after deliveries are queued. A
+// failed notification is only logged, the workers still find the deliveries
+// on their next poll.
+func NotifyDeliveries() {
+	var err error
+	if deliveryPubSub != nil {
+		err = deliveryPubSub.Publish(DeliveryChannel, []byte{})
+	} else {
+		err = database.DB.Exec("SELECT pg_notify(?, '')", DeliveryChannel).Error
+	}
+	if err != nil {
+		pterm.DefaultLogger.Warn("Failed to notify delivery workers: " + err.Error())
+	}
+}
+
+// NextAttemptAt returns when the earliest queued delivery can be claimed, or
+// nil when no delivery is queued. Leased deliveries count from the lease expiry.
+func NextAttemptAt() (*time.Time, error) {
+	var next sql.NullTime
+	err := database.DB.Model(&webhook_entity.WebhookDelivery{}).
+		Select("MIN(GREATEST(next_attempt_at, lease_expires_at))").
+		Where("status IN ?", queuedStatuses).
+		Row().Scan(&next)
+	if err != nil || !next.Valid {
+		return nil, err
+	}
+	return &next.Time, nil
+}
@@ -0,0 +1,99 @@
+package webhook_service
+
+import (
+	message_entity "github.com/Astervia/wacraft-core/src/message/entity"
+	messaging_product_entity "github.com/Astervia/wacraft-core/src/messaging-product/entity"
+	status_entity "github.com/Astervia/wacraft-core/src/status/entity"
+	"github.com/Astervia/wacraft-server/src/database"
+	message_reaction_model "github.com/Astervia/wacraft-server/src/message-reaction/model"
+	message_thread_model "github.com/Astervia/wacraft-server/src/message-thread/model"
+	webhook_event_model "github.com/Astervia/wacraft-server/src/webhook-event/model"
+	webhook_setting_entity "github.com/Astervia/wacraft-server/src/webhook-setting/entity"
+	"github.com/google/uuid"
+	"github.com/pterm/pterm"
+)
+
+// orderingKey returns the ordering key of a delivery of payload to a webhook
+// ordered by key. Payloads without a contact or campaign, e.g. phone config
+// events, have no key and are sent concurrently.
+func orderingKey(key webhook_setting_entity.OrderingKey, payload any) *string {
+	if envelope, ok := payload.(webhook_event_model.Envelope); ok {
+		payload = envelope.Data
+	}
+
+	var id *uuid.UUID
+	switch key {
+	case webhook_setting_entity.OrderingContact:
+		id = payloadContact(payload)
+	case webhook_setting_entity.OrderingCampaign:
+		id = payloadCampaign(payload)
+	}
+	if id == nil {
+		return nil
+	}
+
+	orderingKey := string(key) + ":" + id.String()
+	return &orderingKey
+}
+
+// payloadContact returns the messaging product contact a payload is about.
+func payloadContact(payload any) *uuid.UUID {
+	switch p := payload.(type) {
+	case message_thread_model.ThreadedMessage:
+		return messageContact(p.Message)
+	case message_entity.Message:
+		return messageContact(p)
+	case status_entity.Status:
+		return lookupID("SELECT COALESCE(from_id, to_id) FROM messages WHERE id = ?", p.MessageID)
+	case messaging_product_entity.MessagingProductContact:
+		return &p.ID
+	case message_reaction_model.ReactionEvent:
+		if !p.Reaction.Outbound {
+			return &p.Reaction.ReactorID
+		}
+		// Outbound reactions are keyed by the contact of the reacted message.
+		return lookupID(
+			"SELECT COALESCE(from_id, to_id) FROM messages WHERE id = ? OR (messaging_product_id = ? AND receiver_data->>'id' = ?) LIMIT 1",
+			p.Reaction.MessageID, p.Reaction.MessagingProductID, p.Reaction.TargetWamID,
+		)
+	}
+	return nil
+}
+
+// payloadCampaign returns the campaign a payload is about. Messages and
+// statuses belong to the campaign that sent the message.
+func payloadCampaign(payload any) *uuid.UUID {
+	const messageCampaign = "SELECT campaign_id FROM campaign_messages WHERE message_id = ? LIMIT 1"
+
+	switch p := payload.(type) {
+	case webhook_event_model.CampaignData:
+		return &p.Campaign.ID
+	case message_thread_model.ThreadedMessage:
+		return lookupID(messageCampaign, p.ID)
+	case message_entity.Message:
+		return lookupID(messageCampaign, p.ID)
+	case status_entity.Status:
+		return lookupID(messageCampaign, p.MessageID)
+	}
+	return nil
+}
+
+// messageContact returns the contact that sent an inbound message or
+// received an outbound one.
+func messageContact(message message_entity.Message) *uuid.UUID {
+	if message.FromID != nil {
+		return message.FromID
+	}
+	return message.ToID
+}
+
+// lookupID runs a query selecting a single ID. nil is returned when no row
+// matches or the query fails, so the delivery is sent without ordering.
+func lookupID(query string, args ...any) *uuid.UUID {
+	var id *uuid.UUID
+	if err := database.DB.Raw(query, args...).Scan(&id).Error; err != nil {
+		pterm.DefaultLogger.Error("Failed to resolve webhook delivery ordering key: " + err.Error())
+		return nil
+	}
+	return id
+}
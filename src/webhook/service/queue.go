@@ -8,7 +8,10 @@ import (
 	webhook_entity "github.com/Astervia/wacraft-core/src/webhook/entity"
 	webhook_service "github.com/Astervia/wacraft-core/src/webhook/service"
 	"github.com/Astervia/wacraft-server/src/database"
+	webhook_setting_entity "github.com/Astervia/wacraft-server/src/webhook-setting/entity"
+	webhook_setting_service "github.com/Astervia/wacraft-server/src/webhook-setting/service"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 // EnqueueDelivery creates a new delivery record for a webhook
@@ -62,10 +65,48 @@ func EnqueueDelivery(webhook *webhook_entity.Webhook, payload any, eventType str
 		EventTimestamp: now,
 	}
 
-	if err := database.DB.Create(&delivery).Error; err != nil {
+	return queueDelivery(webhook, &delivery)
+}
+
+// queueDelivery stores a new delivery, with its ordering key when the webhook
+// orders its deliveries, and wakes the delivery workers. Deliveries of
+// batching webhooks wait for their batch to fill up or for the max wait.
+func queueDelivery(webhook *webhook_entity.Webhook, delivery *webhook_entity.WebhookDelivery) error {
+	setting, err := webhook_setting_service.GetWebhookSetting(webhook.ID, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get webhook settings: %w", err)
+	}
+
+	var key *string
+	if setting.OrderingKey != webhook_setting_entity.OrderingNone {
+		key = orderingKey(setting.OrderingKey, delivery.Payload)
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if setting.Batching() {
+			if err := joinBatch(tx, setting, delivery); err != nil {
+				return err
+			}
+		}
+		if err := tx.Create(delivery).Error; err != nil {
+			return err
+		}
+		if key != nil {
+			// Set in the same transaction so the delivery is never claimed without its key.
+			if err := tx.Model(delivery).UpdateColumn("ordering_key", *key).Error; err != nil {
+				return err
+			}
+		}
+		if setting.Batching() {
+			return flushFullBatch(tx, setting, webhook.ID)
+		}
+		return nil
+	})
+	if err != nil {
 		return fmt.Errorf("failed to create delivery: %w", err)
 	}
 
+	NotifyDeliveries()
 	return nil
 }
 
@@ -123,38 +164,88 @@ func EnqueueDeliveryWithCustomKey(webhook *webhook_entity.Webhook, payload any,
 		EventTimestamp: now,
 	}
 
-	if err := database.DB.Create(&delivery).Error; err != nil {
-		return fmt.Errorf("failed to create delivery: %w", err)
-	}
-
-	return nil
+	return queueDelivery(webhook, &delivery)
 }
 
-// GetPendingDeliveries retrieves deliveries that are ready for processing
-func GetPendingDeliveries(limit int) ([]webhook_entity.WebhookDelivery, error) {
-	var deliveries []webhook_entity.WebhookDelivery
-	now := time.Now()
+// ClaimDeliveries leases up to limit due deliveries to owner until the lease
+// expires. Rows locked by a concurrent claim are skipped, so every worker
+// instance gets its own deliveries. Deliveries whose lease expired, e.g.
+// because their worker crashed, are claimed again.
+//
+// A delivery with an ordering key is only claimed once every earlier
+// delivery of the same webhook and key left the queue, so each key is sent
+// one delivery at a time, in queue order. Deliveries of webhooks whose
+// circuit was forced open are not claimed.
+func ClaimDeliveries(owner string, limit int, lease time.Duration) ([]webhook_entity.WebhookDelivery, error) {
+	var ids []uuid.UUID
+	err := database.DB.Raw(
+		`UPDATE webhook_deliveries
+		    SET lease_owner = ?, lease_expires_at = NOW() + make_interval(secs => ?)
+		  WHERE id IN (
+		        SELECT d.id FROM webhook_deliveries d
+		         WHERE d.status IN ?
+		           AND d.next_attempt_at <= NOW()
+		           AND (d.lease_expires_at IS NULL OR d.lease_expires_at <= NOW())
+		           AND (d.ordering_key IS NULL OR NOT EXISTS (
+		                SELECT 1 FROM webhook_deliveries p
+		                 WHERE p.webhook_id = d.webhook_id
+		                   AND p.ordering_key = d.ordering_key
+		                   AND p.status IN ?
+		                   AND (p.created_at, p.id) < (d.created_at, d.id)))
+		           AND NOT EXISTS (
+		                SELECT 1 FROM webhook_settings s
+		                 WHERE s.webhook_id = d.webhook_id
+		                   AND s.circuit_forced_open)
+		         ORDER BY d.next_attempt_at ASC
+		         LIMIT ?
+		           FOR UPDATE SKIP LOCKED)
+		RETURNING id`,
+		owner, lease.Seconds(), queuedStatuses, queuedStatuses, limit,
+	).Scan(&ids).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim deliveries: %w", err)
+	}
+
+	deliveries := []webhook_entity.WebhookDelivery{}
+	if len(ids) == 0 {
+		return deliveries, nil
+	}
 
-	err := database.DB.
-		Where("status IN ?", []webhook_entity.DeliveryStatus{
-			webhook_entity.DeliveryStatusPending,
-			webhook_entity.DeliveryStatusAttempted,
-		}).
-		Where("next_attempt_at <= ?", now).
+	err = database.DB.
+		Where("id IN ?", ids).
 		Preload("Webhook").
 		Order("next_attempt_at ASC").
-		Limit(limit).
 		Find(&deliveries).Error
 	if err != nil {
-		return nil, fmt.Errorf("failed to get pending deliveries: %w", err)
+		return nil, fmt.Errorf("failed to load claimed deliveries: %w", err)
 	}
 
 	return deliveries, nil
 }
 
-// UpdateDeliveryStatus updates the status of a delivery after an attempt
-func UpdateDeliveryStatus(delivery *webhook_entity.WebhookDelivery, success bool, httpCode int, responseBody string, errMsg string) error {
-	now := time.Now()
+// ReleaseDelivery gives up the lease of owner on a delivery that was not
+// attempted, so it is claimed again as soon as it is due.
+func ReleaseDelivery(delivery *webhook_entity.WebhookDelivery, owner string) error {
+	return database.DB.Model(&webhook_entity.WebhookDelivery{}).
+		Where("id = ? AND lease_owner = ?", delivery.ID, owner).
+		Updates(releasedLease).Error
+}
+
+// releasedLease clears the lease of a delivery.
+var releasedLease = map[string]any{
+	"lease_owner":      nil,
+	"lease_expires_at": nil,
+}
+
+// UpdateDeliveryStatus updates the status of a delivery after an attempt and
+// releases the lease of owner. Nothing is written when the lease was lost to
+// another worker, which owns the delivery from then on.
+func UpdateDeliveryStatus(delivery *webhook_entity.WebhookDelivery, owner string, success bool, httpCode int, responseBody string, errMsg string) error {
+	return updateDeliveryStatus(database.DB, delivery, owner, time.Now(), success, httpCode, responseBody, errMsg)
+}
+
+// updateDeliveryStatus updates a delivery attempted at now within db.
+func updateDeliveryStatus(db *gorm.DB, delivery *webhook_entity.WebhookDelivery, owner string, now time.Time, success bool, httpCode int, responseBody string, errMsg string) error {
 	delivery.LastAttemptAt = &now
 	delivery.AttemptCount++
 
@@ -190,7 +281,20 @@ func UpdateDeliveryStatus(delivery *webhook_entity.WebhookDelivery, success bool
 		delivery.NextAttemptAt = &nextAttempt
 	}
 
-	return database.DB.Save(delivery).Error
+	// A delivery cancelled while it was being attempted stays cancelled.
+	return db.Model(delivery).
+		Where("status <> ? AND lease_owner = ?", DeliveryStatusCancelled, owner).
+		Updates(map[string]any{
+			"status":             delivery.Status,
+			"attempt_count":      delivery.AttemptCount,
+			"next_attempt_at":    delivery.NextAttemptAt,
+			"last_attempt_at":    delivery.LastAttemptAt,
+			"last_http_code":     delivery.LastHttpCode,
+			"last_response_body": delivery.LastResponseBody,
+			"last_error":         delivery.LastError,
+			"lease_owner":        nil,
+			"lease_expires_at":   nil,
+		}).Error
 }
 
 // payloadToJSON converts a payload to JSON bytes
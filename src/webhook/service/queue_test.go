@@ -0,0 +1,180 @@
+package webhook_service
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	message_entity "github.com/Astervia/wacraft-core/src/message/entity"
+	message_model "github.com/Astervia/wacraft-core/src/message/model"
+	messaging_product_entity "github.com/Astervia/wacraft-core/src/messaging-product/entity"
+	webhook_entity "github.com/Astervia/wacraft-core/src/webhook/entity"
+	webhook_model "github.com/Astervia/wacraft-core/src/webhook/model"
+	"github.com/Astervia/wacraft-server/src/database"
+	database_fixture "github.com/Astervia/wacraft-server/src/database/fixture"
+	webhook_event_model "github.com/Astervia/wacraft-server/src/webhook-event/model"
+	webhook_setting_entity "github.com/Astervia/wacraft-server/src/webhook-setting/entity"
+	"github.com/google/uuid"
+)
+
+// --- Test bootstrap ---
+
+func TestMain(m *testing.M) {
+	database.DB.AutoMigrate(
+		&webhook_entity.Webhook{},
+		&webhook_entity.WebhookDelivery{},
+		&webhook_entity.WebhookLog{},
+		&webhook_setting_entity.WebhookSetting{},
+	)
+	// Columns added by the goose migrations on top of the core entity.
+	database.DB.Exec(`ALTER TABLE webhook_deliveries
+		ADD COLUMN IF NOT EXISTS lease_owner TEXT,
+		ADD COLUMN IF NOT EXISTS lease_expires_at TIMESTAMPTZ,
+		ADD COLUMN IF NOT EXISTS ordering_key TEXT`)
+	// Test webhooks use random workspace IDs.
+	database_fixture.DropForeignKeys("webhooks", "fk_webhooks_workspace")
+	os.Exit(m.Run())
+}
+
+// --- Helpers ---
+
+func createTestWebhook(t *testing.T) webhook_entity.Webhook {
+	t.Helper()
+	isActive := true
+	maxRetries := 2
+	retryDelayMs := 1000
+	workspaceID := uuid.New()
+	webhook := webhook_entity.Webhook{
+		Url:         "https://example.com/hook",
+		HttpMethod:  "POST",
+		Event:       webhook_model.SendWhatsAppMessage,
+		WorkspaceID: &workspaceID,
+		IsActive:    &isActive,
+		MaxRetries:  &maxRetries,
+	}
+	webhook.RetryDelayMs = &retryDelayMs
+	if err := database.DB.Create(&webhook).Error; err != nil {
+		t.Fatalf("createTestWebhook: %v", err)
+	}
+	t.Cleanup(func() {
+		database.DB.Exec("DELETE FROM webhook_settings WHERE webhook_id = ?", webhook.ID)
+		database.DB.Exec("DELETE FROM webhook_deliveries WHERE webhook_id = ?", webhook.ID)
+		database.DB.Exec("DELETE FROM webhook_logs WHERE webhook_id = ?", webhook.ID)
+		database.DB.Exec("DELETE FROM webhooks WHERE id = ?", webhook.ID)
+	})
+	return webhook
+}
+
+// createTestDelivery stores a due delivery created at createdAt, with an
+// ordering key unless key is empty.
+func createTestDelivery(t *testing.T, webhookID uuid.UUID, key string, createdAt time.Time) webhook_entity.WebhookDelivery {
+	t.Helper()
+	due := createdAt
+	delivery := webhook_entity.WebhookDelivery{
+		WebhookID:      webhookID,
+		IdempotencyKey: uuid.NewString(),
+		Payload:        map[string]any{"test": true},
+		Status:         webhook_entity.DeliveryStatusPending,
+		MaxAttempts:    3,
+		NextAttemptAt:  &due,
+		EventType:      string(webhook_model.SendWhatsAppMessage),
+		EventTimestamp: createdAt,
+	}
+	delivery.CreatedAt = createdAt
+	if err := database.DB.Create(&delivery).Error; err != nil {
+		t.Fatalf("createTestDelivery: %v", err)
+	}
+	if key != "" {
+		database.DB.Exec("UPDATE webhook_deliveries SET ordering_key = ? WHERE id = ?", key, delivery.ID)
+	}
+	return delivery
+}
+
+// claimedIDs claims every due delivery for owner and returns the claimed IDs.
+func claimedIDs(t *testing.T, owner string) map[uuid.UUID]bool {
+	t.Helper()
+	deliveries, err := ClaimDeliveries(owner, 1000, time.Minute)
+	if err != nil {
+		t.Fatalf("ClaimDeliveries: %v", err)
+	}
+	ids := make(map[uuid.UUID]bool, len(deliveries))
+	for _, delivery := range deliveries {
+		ids[delivery.ID] = true
+	}
+	return ids
+}
+
+// --- Ordering tests ---
+
+func TestClaimDeliveries_OrderingKeyWaitsForPredecessor(t *testing.T) {
+	webhook := createTestWebhook(t)
+	start := time.Now().Add(-time.Minute)
+	first := createTestDelivery(t, webhook.ID, "contact:a", start)
+	second := createTestDelivery(t, webhook.ID, "contact:a", start.Add(time.Second))
+	otherKey := createTestDelivery(t, webhook.ID, "contact:b", start.Add(2*time.Second))
+	unordered := createTestDelivery(t, webhook.ID, "", start.Add(3*time.Second))
+
+	claimed := claimedIDs(t, "worker-a")
+	if !claimed[first.ID] || !claimed[otherKey.ID] || !claimed[unordered.ID] {
+		t.Fatalf("expected the heads of each key and the unordered delivery to be claimed, got %v", claimed)
+	}
+	if claimed[second.ID] {
+		t.Fatal("expected the second delivery of the key to wait for the first")
+	}
+
+	// A failed attempt keeps the head queued, so its successor keeps waiting.
+	first.Webhook = &webhook
+	if err := UpdateDeliveryStatus(&first, "worker-a", false, 500, "", "server error"); err != nil {
+		t.Fatalf("UpdateDeliveryStatus: %v", err)
+	}
+	database.DB.Exec("UPDATE webhook_deliveries SET next_attempt_at = NOW() WHERE id = ?", first.ID)
+	claimed = claimedIDs(t, "worker-b")
+	if !claimed[first.ID] || claimed[second.ID] {
+		t.Fatalf("expected only the retried head to be claimed, got %v", claimed)
+	}
+
+	// Once the head succeeds the next delivery of the key is claimable.
+	if err := UpdateDeliveryStatus(&first, "worker-b", true, 200, "", ""); err != nil {
+		t.Fatalf("UpdateDeliveryStatus: %v", err)
+	}
+	if claimed = claimedIDs(t, "worker-c"); !claimed[second.ID] {
+		t.Fatalf("expected the second delivery to be claimed after the first succeeded, got %v", claimed)
+	}
+}
+
+func TestOrderingKey(t *testing.T) {
+	campaignData := webhook_event_model.CampaignData{}
+	campaignData.Campaign.ID = uuid.New()
+
+	contact := messaging_product_entity.MessagingProductContact{}
+	contact.ID = uuid.New()
+
+	fromID := uuid.New()
+	inbound := message_entity.Message{MessageFields: message_model.MessageFields{FromID: &fromID}}
+
+	cases := []struct {
+		name    string
+		key     webhook_setting_entity.OrderingKey
+		payload any
+		want    string
+	}{
+		{"campaign", webhook_setting_entity.OrderingCampaign, campaignData, string(webhook_setting_entity.OrderingCampaign) + ":" + campaignData.Campaign.ID.String()},
+		{"enveloped campaign", webhook_setting_entity.OrderingCampaign, webhook_event_model.NewEnvelope(webhook_event_model.CampaignStarted, nil, campaignData), string(webhook_setting_entity.OrderingCampaign) + ":" + campaignData.Campaign.ID.String()},
+		{"contact", webhook_setting_entity.OrderingContact, contact, string(webhook_setting_entity.OrderingContact) + ":" + contact.ID.String()},
+		{"inbound message", webhook_setting_entity.OrderingContact, inbound, string(webhook_setting_entity.OrderingContact) + ":" + fromID.String()},
+		{"payload without contact", webhook_setting_entity.OrderingContact, campaignData, ""},
+		{"no ordering", webhook_setting_entity.OrderingNone, contact, ""},
+	}
+	for _, c := range cases {
+		got := orderingKey(c.key, c.payload)
+		if c.want == "" {
+			if got != nil {
+				t.Errorf("%s: expected no key, got %s", c.name, *got)
+			}
+			continue
+		}
+		if got == nil || *got != c.want {
+			t.Errorf("%s: expected %s, got %v", c.name, c.want, got)
+		}
+	}
+}
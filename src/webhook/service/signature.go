@@ -0,0 +1,124 @@
+package webhook_service
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	webhook_entity "github.com/Astervia/wacraft-core/src/webhook/entity"
+	"github.com/Astervia/wacraft-server/src/database"
+	webhook_setting_entity "github.com/Astervia/wacraft-server/src/webhook-setting/entity"
+	webhook_setting_service "github.com/Astervia/wacraft-server/src/webhook-setting/service"
+	"gorm.io/gorm"
+)
+
+// ErrConcurrentRotation is returned when the signing secret changed while it
+// was being rotated.
+var ErrConcurrentRotation = errors.New("the signing secret was rotated concurrently")
+
+// GenerateSigningSecret generates a cryptographically secure random secret.
+func GenerateSigningSecret() (string, error) {
+	bytes := make([]byte, 32) // 256 bits
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(bytes), nil
+}
+
+// Sign returns the v1 signature of a body: the hex HMAC-SHA256 of
+// "v1:{timestamp}:{body}" prefixed with "v1=".
+func Sign(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v1:" + timestamp + ":"))
+	mac.Write(body)
+	return "v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// SignatureHeaders returns the X-Wacraft-Signature and X-Wacraft-Timestamp
+// values of a body. While a rotated secret is in its grace period the body is
+// signed with both secrets, newest first, and the signatures are joined with
+// a comma, so consumers still holding the old secret keep accepting deliveries.
+func SignatureHeaders(webhook webhook_entity.Webhook, body []byte) (string, string, error) {
+	setting, err := webhook_setting_service.GetWebhookSetting(webhook.ID, nil)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get webhook settings: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	signatures := []string{Sign(webhook.SigningSecret, timestamp, body)}
+
+	// Expired secrets no longer sign; RetireExpiredSigningSecrets erases them.
+	if setting.PreviousSigningSecret != "" &&
+		setting.PreviousSigningSecretExpiresAt != nil &&
+		time.Now().Before(*setting.PreviousSigningSecretExpiresAt) {
+		signatures = append(signatures, Sign(setting.PreviousSigningSecret, timestamp, body))
+	}
+
+	return strings.Join(signatures, ","), timestamp, nil
+}
+
+// RetireExpiredSigningSecrets erases the previous secrets whose grace period
+// is over, so they are not kept after they stopped signing. Returns the
+// number of secrets erased.
+func RetireExpiredSigningSecrets() (int64, error) {
+	result := database.DB.Model(&webhook_setting_entity.WebhookSetting{}).
+		Where("previous_signing_secret <> ''").
+		Where("previous_signing_secret_expires_at IS NULL OR previous_signing_secret_expires_at <= NOW()").
+		Updates(map[string]any{
+			"previous_signing_secret":            "",
+			"previous_signing_secret_expires_at": nil,
+		})
+	return result.RowsAffected, result.Error
+}
+
+// RotateSigningSecret replaces the signing secret of a webhook with a new one
+// and returns it. The old secret keeps signing deliveries next to the new one
+// until the grace period is over. A zero grace period retires it at once.
+// Rotating again during a grace period retires the oldest secret.
+func RotateSigningSecret(
+	webhook webhook_entity.Webhook,
+	grace time.Duration,
+) (string, webhook_setting_entity.WebhookSetting, error) {
+	var setting webhook_setting_entity.WebhookSetting
+
+	secret, err := GenerateSigningSecret()
+	if err != nil {
+		return "", setting, err
+	}
+
+	updates := map[string]any{
+		"previous_signing_secret":            "",
+		"previous_signing_secret_expires_at": nil,
+	}
+	if grace > 0 && webhook.SigningSecret != "" {
+		updates["previous_signing_secret"] = webhook.SigningSecret
+		updates["previous_signing_secret_expires_at"] = time.Now().Add(grace)
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		// Guarded by the old secret so concurrent rotations cannot both keep it.
+		result := tx.Model(&webhook_entity.Webhook{}).
+			Where("id = ? AND signing_secret = ?", webhook.ID, webhook.SigningSecret).
+			Update("signing_secret", secret)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrConcurrentRotation
+		}
+
+		setting, err = webhook_setting_service.UpdateWebhookSetting(webhook.ID, updates, tx)
+		return err
+	})
+	if err != nil {
+		return "", setting, err
+	}
+
+	return secret, setting, nil
+}
@@ -0,0 +1,66 @@
+package webhook_service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Astervia/wacraft-server/src/database"
+	webhook_setting_entity "github.com/Astervia/wacraft-server/src/webhook-setting/entity"
+)
+
+func TestSign_MatchesDocumentedScheme(t *testing.T) {
+	got := Sign("whsec_test", "1707048000", []byte(`{"a":1}`))
+	want := "v1=904e19f9f6bf6f2b2b0445fed546a28df5ff3630c0ad2c81fdfc1e172cb0968c"
+	if got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
+
+func TestGenerateSigningSecret(t *testing.T) {
+	a, err := GenerateSigningSecret()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, _ := GenerateSigningSecret()
+	if !strings.HasPrefix(a, "whsec_") || len(a) != len("whsec_")+64 {
+		t.Errorf("unexpected secret format %q", a)
+	}
+	if a == b {
+		t.Error("expected distinct secrets")
+	}
+}
+
+func TestRetireExpiredSigningSecrets(t *testing.T) {
+	expired := time.Now().Add(-time.Minute)
+	inGrace := time.Now().Add(time.Hour)
+	cases := map[string]*time.Time{"expired": &expired, "in grace": &inGrace}
+
+	settings := map[string]webhook_setting_entity.WebhookSetting{}
+	for name, expiresAt := range cases {
+		setting := webhook_setting_entity.WebhookSetting{
+			WebhookID:                      createTestWebhook(t).ID,
+			PreviousSigningSecret:          "whsec_" + strings.ReplaceAll(name, " ", "_"),
+			PreviousSigningSecretExpiresAt: expiresAt,
+		}
+		if err := database.DB.Create(&setting).Error; err != nil {
+			t.Fatalf("create setting: %v", err)
+		}
+		settings[name] = setting
+	}
+
+	if _, err := RetireExpiredSigningSecrets(); err != nil {
+		t.Fatalf("RetireExpiredSigningSecrets: %v", err)
+	}
+
+	for name, setting := range settings {
+		var stored webhook_setting_entity.WebhookSetting
+		if err := database.DB.First(&stored, "webhook_id = ?", setting.WebhookID).Error; err != nil {
+			t.Fatalf("load setting: %v", err)
+		}
+		retired := stored.PreviousSigningSecret == "" && stored.PreviousSigningSecretExpiresAt == nil
+		if want := name == "expired"; retired != want {
+			t.Errorf("%s: expected retired=%v, got secret %q expiring %v", name, want, stored.PreviousSigningSecret, stored.PreviousSigningSecretExpiresAt)
+		}
+	}
+}
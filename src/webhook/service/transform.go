@@ -0,0 +1,33 @@
+package webhook_service
+
+import (
+	"fmt"
+
+	webhook_setting_service "github.com/Astervia/wacraft-server/src/webhook-setting/service"
+	webhook_transform_model "github.com/Astervia/wacraft-server/src/webhook-transform/model"
+	"github.com/google/uuid"
+)
+
+// RenderPayload applies the transform of a webhook, if any, to the JSON
+// payload of a delivery and returns the body to send with its content type.
+func RenderPayload(webhookID uuid.UUID, payload []byte) ([]byte, string, error) {
+	setting, err := webhook_setting_service.GetWebhookSetting(webhookID, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get webhook settings: %w", err)
+	}
+	return RenderTransform(setting.Transform, payload)
+}
+
+// RenderTransform applies transform, if not nil, to a JSON payload and
+// returns the body to send with its content type.
+func RenderTransform(transform *webhook_transform_model.WebhookTransform, payload []byte) ([]byte, string, error) {
+	if transform == nil {
+		return payload, webhook_transform_model.DefaultContentType, nil
+	}
+
+	body, err := transform.Render(payload)
+	if err != nil {
+		return nil, "", err
+	}
+	return body, transform.GetContentType(), nil
+}
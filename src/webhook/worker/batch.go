@@ -0,0 +1,226 @@
+package webhook_worker
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	webhook_entity "github.com/Astervia/wacraft-core/src/webhook/entity"
+	webhook_core_service "github.com/Astervia/wacraft-core/src/webhook/service"
+	billing_service "github.com/Astervia/wacraft-server/src/billing/service"
+	"github.com/Astervia/wacraft-server/src/database"
+	webhook_setting_entity "github.com/Astervia/wacraft-server/src/webhook-setting/entity"
+	webhook_setting_service "github.com/Astervia/wacraft-server/src/webhook-setting/service"
+	webhook_transform_model "github.com/Astervia/wacraft-server/src/webhook-transform/model"
+	webhook_service "github.com/Astervia/wacraft-server/src/webhook/service"
+	"github.com/google/uuid"
+	"github.com/pterm/pterm"
+)
+
+// deliveryBatch is a unit of work of a pass: a single delivery, or the
+// deliveries of a batching webhook sent together.
+type deliveryBatch struct {
+	deliveries []*webhook_entity.WebhookDelivery
+	setting    *webhook_setting_entity.WebhookSetting // Set for batching webhooks only.
+}
+
+// loadBatchSettings loads the settings of the webhooks of the claimed
+// deliveries once per webhook. On error every delivery is sent on its own.
+func loadBatchSettings(deliveries []webhook_entity.WebhookDelivery) map[uuid.UUID]webhook_setting_entity.WebhookSetting {
+	seen := make(map[uuid.UUID]bool)
+	var webhookIDs []uuid.UUID
+	for _, delivery := range deliveries {
+		if !seen[delivery.WebhookID] {
+			seen[delivery.WebhookID] = true
+			webhookIDs = append(webhookIDs, delivery.WebhookID)
+		}
+	}
+	if len(webhookIDs) == 0 {
+		return nil
+	}
+
+	settings, err := webhook_setting_service.GetWebhookSettings(webhookIDs, nil)
+	if err != nil {
+		pterm.DefaultLogger.Error("Failed to get webhook settings: " + err.Error())
+		return nil
+	}
+	return settings
+}
+
+// groupBatches splits claimed deliveries into batches of at most the batch
+// size of their webhook. Deliveries of other webhooks are sent one by one.
+func groupBatches(deliveries []webhook_entity.WebhookDelivery, settings map[uuid.UUID]webhook_setting_entity.WebhookSetting) []deliveryBatch {
+	var batches []deliveryBatch
+	open := make(map[uuid.UUID]int) // Index of the batch being filled per webhook.
+
+	for i := range deliveries {
+		delivery := &deliveries[i]
+
+		setting, ok := settings[delivery.WebhookID]
+		if !ok || !setting.Batching() || delivery.Webhook == nil {
+			batches = append(batches, deliveryBatch{deliveries: []*webhook_entity.WebhookDelivery{delivery}})
+			continue
+		}
+
+		if j, ok := open[delivery.WebhookID]; ok && len(batches[j].deliveries) < setting.BatchSize {
+			batches[j].deliveries = append(batches[j].deliveries, delivery)
+			continue
+		}
+		open[delivery.WebhookID] = len(batches)
+		batches = append(batches, deliveryBatch{deliveries: []*webhook_entity.WebhookDelivery{delivery}, setting: &setting})
+	}
+
+	return batches
+}
+
+// lockBatch acquires the per-delivery lock of each delivery of a batch, like
+// processDelivery does. Deliveries another instance holds are released and
+// left out of the batch. unlock releases the acquired locks.
+func (w *DeliveryWorker) lockBatch(deliveries []*webhook_entity.WebhookDelivery) ([]*webhook_entity.WebhookDelivery, func()) {
+	if w.lock == nil {
+		return deliveries, func() {}
+	}
+
+	locked := deliveries[:0:0]
+	lockKeys := make([]string, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		lockKey := delivery.ID.String()
+		acquired, err := w.lock.TryLock(lockKey)
+		if err != nil {
+			pterm.DefaultLogger.Error("Delivery lock error: " + err.Error())
+			w.release(delivery)
+			continue
+		}
+		if !acquired {
+			w.release(delivery) // Another instance is already processing this delivery
+			continue
+		}
+		lockKeys = append(lockKeys, lockKey)
+		locked = append(locked, delivery)
+	}
+
+	return locked, func() {
+		for _, lockKey := range lockKeys {
+			w.lock.Unlock(lockKey) //nolint:errcheck
+		}
+	}
+}
+
+// processBatch sends the deliveries of a batching webhook as one request,
+// like processDelivery does for a single delivery: the batch takes one unit
+// of throughput, one circuit breaker outcome and one log entry. Returns how
+// many deliveries left this worker.
+func (w *DeliveryWorker) processBatch(batch deliveryBatch) int {
+	deliveries, unlock := w.lockBatch(batch.deliveries)
+	if len(deliveries) == 0 {
+		return 0
+	}
+
+	webhook := deliveries[0].Webhook
+
+	// Check circuit breaker
+	cb := webhook_core_service.NewCircuitBreaker(database.DB)
+	allowed, err := cb.AllowRequest(webhook.ID)
+	if err != nil || !allowed {
+		if err != nil {
+			pterm.DefaultLogger.Error("Circuit breaker check failed: " + err.Error())
+		} else {
+			pterm.DefaultLogger.Warn("Circuit open for webhook: " + webhook.ID.String())
+		}
+		for _, delivery := range deliveries {
+			w.release(delivery) // Don't update status, will retry later
+		}
+		unlock()
+		return 0
+	}
+
+	// The whole batch counts as a single request against the throughput.
+	if !billing_service.ConsumeWorkspaceThroughput(webhook.WorkspaceID, 1) {
+		pterm.DefaultLogger.Warn("Workspace throughput limit exceeded for webhook batch: " + webhook.ID.String())
+		if updateErr := webhook_service.UpdateBatchStatus(deliveries, w.owner, false, 0, "", "workspace throughput limit exceeded — upgrade your plan to increase quota"); updateErr != nil {
+			pterm.DefaultLogger.Error("Failed to update delivery status: " + updateErr.Error())
+		}
+		unlock()
+		return len(deliveries)
+	}
+
+	// Execute the webhook
+	batchID := uuid.New()
+	startTime := time.Now()
+	events, httpCode, responseBody, err := w.executeBatch(webhook, batchID, batch.setting.Transform, deliveries)
+	duration := time.Since(startTime)
+
+	success := err == nil && httpCode >= 200 && httpCode < 300
+
+	w.createBatchLogEntry(webhook, batchID, events, httpCode, responseBody, err, duration)
+
+	// Update circuit breaker
+	if success {
+		cb.RecordSuccess(webhook.ID)
+	} else {
+		cb.RecordFailure(webhook.ID)
+	}
+
+	// Update delivery status
+	errMsg := ""
+	if err != nil {
+		errMsg = err.Error()
+	}
+	if updateErr := webhook_service.UpdateBatchStatus(deliveries, w.owner, success, httpCode, responseBody, errMsg); updateErr != nil {
+		pterm.DefaultLogger.Error("Failed to update delivery status: " + updateErr.Error())
+	}
+	unlock()
+	return len(deliveries)
+}
+
+// executeBatch sends the deliveries as a JSON array of events and returns
+// the events with the response code and body.
+func (w *DeliveryWorker) executeBatch(
+	webhook *webhook_entity.Webhook,
+	batchID uuid.UUID,
+	transform *webhook_transform_model.WebhookTransform,
+	deliveries []*webhook_entity.WebhookDelivery,
+) ([]webhook_service.BatchEvent, int, string, error) {
+	events := make([]webhook_service.BatchEvent, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		event, err := webhook_service.NewBatchEvent(delivery, transform)
+		if err != nil {
+			return events, 0, "", err
+		}
+		events = append(events, event)
+	}
+
+	body, err := json.Marshal(events)
+	if err != nil {
+		return events, 0, "", err
+	}
+
+	httpCode, responseBody, err := w.send(webhook, body, webhook_transform_model.DefaultContentType, batchHeaders(webhook, batchID, len(events)))
+	return events, httpCode, responseBody, err
+}
+
+// batchHeaders are the delivery headers of a batch request.
+func batchHeaders(webhook *webhook_entity.Webhook, batchID uuid.UUID, size int) map[string]string {
+	return map[string]string{
+		"X-Wacraft-Batch-ID":   batchID.String(),
+		"X-Wacraft-Batch-Size": strconv.Itoa(size),
+		"X-Wacraft-Event":      string(webhook.Event),
+	}
+}
+
+// createBatchLogEntry creates one webhook log entry for a batch attempt. Its
+// payload is the array of events, which holds the delivery IDs.
+func (w *DeliveryWorker) createBatchLogEntry(webhook *webhook_entity.Webhook, batchID uuid.UUID, events []webhook_service.BatchEvent, httpCode int, responseBody string, execErr error, duration time.Duration) {
+	attempt := 0
+	for _, event := range events {
+		attempt = max(attempt, event.Attempt)
+	}
+
+	log := webhook_entity.WebhookLog{
+		Payload:        events,
+		WebhookID:      webhook.ID,
+		AttemptNumber:  attempt,
+		IdempotencyKey: "batch:" + batchID.String(),
+	}
+	w.saveLog(webhook, log, batchHeaders(webhook, batchID, len(events)), webhook_transform_model.DefaultContentType, httpCode, responseBody, execErr, duration)
+}
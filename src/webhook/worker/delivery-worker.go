@@ -6,28 +6,30 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"os"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	synch_contract "github.com/Astervia/wacraft-core/src/synch/contract"
 	webhook_entity "github.com/Astervia/wacraft-core/src/webhook/entity"
 	webhook_core_service "github.com/Astervia/wacraft-core/src/webhook/service"
 	billing_service "github.com/Astervia/wacraft-server/src/billing/service"
+	"github.com/Astervia/wacraft-server/src/config/env"
 	"github.com/Astervia/wacraft-server/src/database"
+	webhook_destination_service "github.com/Astervia/wacraft-server/src/webhook-destination/service"
+	webhook_transform_model "github.com/Astervia/wacraft-server/src/webhook-transform/model"
 	webhook_service "github.com/Astervia/wacraft-server/src/webhook/service"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/pterm/pterm"
 	"golang.org/x/sync/errgroup"
 )
 
-const (
-	// PollInterval is how often the worker checks for pending deliveries
-	PollInterval = 5 * time.Second
-	// PoolSize is the max number of concurrent delivery workers
-	PoolSize = 10
-	// BatchSize is the max number of deliveries to fetch per poll
-	BatchSize = 50
-)
+// SweepInterval is how often the worker erases the signing secrets whose
+// grace period is over.
+const SweepInterval = time.Minute
 
 // DeliveryWorker handles webhook delivery processing
 type DeliveryWorker struct {
@@ -35,8 +37,16 @@ type DeliveryWorker struct {
 	cancel     context.CancelFunc
 	wg         sync.WaitGroup
 	httpClient *http.Client
-	// lock guards per-delivery execution across instances.
-	// nil in memory mode (single instance — no distributed contention).
+	// wake is signalled when deliveries are queued. Buffered with size 1 so
+	// a burst of notifications collapses into a single pass.
+	wake chan struct{}
+	// pubsub carries wake-ups across instances in Redis mode.
+	// nil in memory mode, where Postgres LISTEN is used instead.
+	pubsub synch_contract.PubSub
+	// owner identifies this worker in the leases of the deliveries it claims.
+	owner string
+	// lock guards per-delivery execution across instances on top of the lease.
+	// nil in memory mode, where the lease alone keeps instances apart.
 	lock synch_contract.DistributedLock[string]
 }
 
@@ -56,19 +66,32 @@ func SetDeliveryLock(l synch_contract.DistributedLock[string]) {
 func NewDeliveryWorker() *DeliveryWorker {
 	ctx, cancel := context.WithCancel(context.Background())
 	return &DeliveryWorker{
-		ctx:    ctx,
-		cancel: cancel,
-		lock:   deliveryLock,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second, // Default timeout, will be overridden per-request
-		},
+		ctx:        ctx,
+		cancel:     cancel,
+		lock:       deliveryLock,
+		owner:      newOwner(),
+		wake:       make(chan struct{}, 1),
+		pubsub:     webhook_service.DeliveryPubSub(),
+		httpClient: webhook_destination_service.Client(30 * time.Second), // Default timeout, will be overridden per-request
 	}
 }
 
+// newOwner returns a lease owner unique to this worker, prefixed with the
+// host name so leases can be traced back to an instance.
+func newOwner() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return host + ":" + uuid.NewString()
+}
+
 // Start begins the delivery worker
 func (w *DeliveryWorker) Start() {
-	w.wg.Add(1)
+	w.wg.Add(3)
+	go w.listen()
 	go w.run()
+	go w.sweep()
 	pterm.DefaultLogger.Info("Webhook delivery worker started")
 }
 
@@ -80,11 +103,34 @@ func (w *DeliveryWorker) Stop() {
 	pterm.DefaultLogger.Info("Webhook delivery worker stopped")
 }
 
-// run is the main loop that polls for pending deliveries
+// run is the main loop. It processes due deliveries whenever it is woken up
+// and otherwise sleeps until the earliest next_attempt_at, but never longer
+// than the poll interval.
 func (w *DeliveryWorker) run() {
 	defer w.wg.Done()
 
-	ticker := time.NewTicker(PollInterval)
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-w.ctx.Done():
+			return
+		case <-w.wake:
+		case <-timer.C:
+		}
+
+		attempted := w.processPendingDeliveries()
+		timer.Reset(w.nextWait(attempted))
+	}
+}
+
+// sweep periodically erases the signing secrets whose grace period is over.
+// Every instance sweeps; the update is idempotent.
+func (w *DeliveryWorker) sweep() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(SweepInterval)
 	defer ticker.Stop()
 
 	for {
@@ -92,35 +138,138 @@ func (w *DeliveryWorker) run() {
 		case <-w.ctx.Done():
 			return
 		case <-ticker.C:
-			w.processPendingDeliveries()
+			if _, err := webhook_service.RetireExpiredSigningSecrets(); err != nil {
+				pterm.DefaultLogger.Error("Failed to retire expired signing secrets: " + err.Error())
+			}
 		}
 	}
 }
 
-// processPendingDeliveries fetches and processes pending deliveries
-func (w *DeliveryWorker) processPendingDeliveries() {
-	deliveries, err := webhook_service.GetPendingDeliveries(BatchSize)
+// nextWait returns how long to sleep after a pass that attempted the given
+// number of deliveries.
+func (w *DeliveryWorker) nextWait(attempted int) time.Duration {
+	if attempted > 0 {
+		// More deliveries may already be due: the rest of a full batch, or
+		// the next delivery of an ordering key whose head was just attempted.
+		return 0
+	}
+
+	next, err := webhook_service.NextAttemptAt()
 	if err != nil {
-		pterm.DefaultLogger.Error("Failed to get pending deliveries: " + err.Error())
+		pterm.DefaultLogger.Error("Failed to get next delivery attempt: " + err.Error())
+		return env.WebhookDeliveryPollInterval
+	}
+	return waitUntil(next, time.Now(), env.WebhookDeliveryPollInterval)
+}
+
+// waitUntil returns how long to sleep until the next attempt, never longer
+// than poll. A pass that attempted nothing while deliveries were already due
+// skipped them, e.g. a full batch behind an open circuit, so they wait for
+// the next poll instead of being claimed again right away.
+func waitUntil(next *time.Time, now time.Time, poll time.Duration) time.Duration {
+	if next == nil || !next.After(now) {
+		return poll
+	}
+	return min(next.Sub(now), poll)
+}
+
+// signal wakes run without blocking when a wake-up is already pending.
+func (w *DeliveryWorker) signal() {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// listen forwards delivery notifications to run until the worker stops. If
+// listening fails the worker keeps working on the poll interval alone.
+func (w *DeliveryWorker) listen() {
+	defer w.wg.Done()
+
+	if w.pubsub != nil {
+		w.listenPubSub()
 		return
 	}
+	w.listenPostgres()
+}
 
-	if len(deliveries) == 0 {
+// listenPubSub receives the wake-ups published by every instance in Redis mode.
+func (w *DeliveryWorker) listenPubSub() {
+	sub, err := w.pubsub.Subscribe(webhook_service.DeliveryChannel)
+	if err != nil {
+		pterm.DefaultLogger.Error("Failed to subscribe to webhook deliveries, falling back to polling: " + err.Error())
 		return
 	}
+	defer sub.Unsubscribe() //nolint:errcheck
+
+	for {
+		select {
+		case <-w.ctx.Done():
+			return
+		case _, ok := <-sub.Channel():
+			if !ok {
+				return
+			}
+			w.signal()
+		}
+	}
+}
+
+// listenPostgres receives the wake-ups sent with pg_notify in memory mode.
+func (w *DeliveryWorker) listenPostgres() {
+	listener := pq.NewListener(env.DatabaseURL, time.Second, time.Minute, func(_ pq.ListenerEventType, err error) {
+		if err != nil {
+			pterm.DefaultLogger.Warn("Webhook delivery listener: " + err.Error())
+		}
+	})
+	defer listener.Close()
+
+	if err := listener.Listen(webhook_service.DeliveryChannel); err != nil {
+		pterm.DefaultLogger.Error("Failed to listen for webhook deliveries, falling back to polling: " + err.Error())
+		return
+	}
+
+	for {
+		select {
+		case <-w.ctx.Done():
+			return
+		case <-listener.Notify:
+			// A nil notification follows a reconnect, when notifications may
+			// have been missed, so it wakes the worker too.
+			w.signal()
+		}
+	}
+}
+
+// processPendingDeliveries claims and processes due deliveries and returns
+// how many were attempted.
+func (w *DeliveryWorker) processPendingDeliveries() int {
+	deliveries, err := webhook_service.ClaimDeliveries(w.owner, env.WebhookDeliveryBatchSize, env.WebhookDeliveryLease)
+	if err != nil {
+		pterm.DefaultLogger.Error("Failed to claim pending deliveries: " + err.Error())
+		return 0
+	}
+
+	if len(deliveries) == 0 {
+		return 0
+	}
 
 	// Use errgroup with limited concurrency
 	g, ctx := errgroup.WithContext(w.ctx)
-	g.SetLimit(PoolSize)
+	g.SetLimit(env.WebhookDeliveryPoolSize)
 
-	for i := range deliveries {
-		delivery := &deliveries[i] // Create a copy for the goroutine
+	var attempted atomic.Int64
+	for _, batch := range groupBatches(deliveries, loadBatchSettings(deliveries)) {
 		g.Go(func() error {
 			select {
 			case <-ctx.Done():
 				return ctx.Err()
 			default:
-				w.processDelivery(delivery)
+				if batch.setting != nil {
+					attempted.Add(int64(w.processBatch(batch)))
+				} else if w.processDelivery(batch.deliveries[0]) {
+					attempted.Add(1)
+				}
 				return nil
 			}
 		})
@@ -129,22 +278,26 @@ func (w *DeliveryWorker) processPendingDeliveries() {
 	if err := g.Wait(); err != nil && err != context.Canceled {
 		pterm.DefaultLogger.Error("Error processing deliveries: " + err.Error())
 	}
+	return int(attempted.Load())
 }
 
-// processDelivery handles a single delivery attempt.
-// When a distributed lock is configured, it acquires a per-delivery lock before
-// processing to prevent duplicate execution across instances. If the lock is
-// already held by another instance the delivery is silently skipped.
-func (w *DeliveryWorker) processDelivery(delivery *webhook_entity.WebhookDelivery) {
+// processDelivery handles a single attempt of a delivery claimed by this worker.
+// When a distributed lock is configured, it also acquires a per-delivery lock
+// before processing. If the lock cannot be acquired the delivery is skipped and
+// its lease released, so it can be claimed again right away. Returns whether
+// the delivery status was updated, i.e. the delivery left this worker.
+func (w *DeliveryWorker) processDelivery(delivery *webhook_entity.WebhookDelivery) bool {
 	if w.lock != nil {
 		lockKey := delivery.ID.String()
 		acquired, err := w.lock.TryLock(lockKey)
 		if err != nil {
 			pterm.DefaultLogger.Error("Delivery lock error: " + err.Error())
-			return
+			w.release(delivery)
+			return false
 		}
 		if !acquired {
-			return // Another instance is already processing this delivery
+			w.release(delivery) // Another instance is already processing this delivery
+			return false
 		}
 		defer w.lock.Unlock(lockKey) //nolint:errcheck
 	}
@@ -155,8 +308,8 @@ func (w *DeliveryWorker) processDelivery(delivery *webhook_entity.WebhookDeliver
 		if err := database.DB.First(&webhook, "id = ?", delivery.WebhookID).Error; err != nil {
 			pterm.DefaultLogger.Error("Failed to load webhook: " + err.Error())
 			errMsg := err.Error()
-			webhook_service.UpdateDeliveryStatus(delivery, false, 0, "", errMsg)
-			return
+			webhook_service.UpdateDeliveryStatus(delivery, w.owner, false, 0, "", errMsg)
+			return true
 		}
 		delivery.Webhook = &webhook
 	}
@@ -166,33 +319,35 @@ func (w *DeliveryWorker) processDelivery(delivery *webhook_entity.WebhookDeliver
 	allowed, err := cb.AllowRequest(delivery.WebhookID)
 	if err != nil {
 		pterm.DefaultLogger.Error("Circuit breaker check failed: " + err.Error())
-		return // Don't update status, will retry later
+		w.release(delivery) // Don't update status, will retry later
+		return false
 	}
 	if !allowed {
 		pterm.DefaultLogger.Warn("Circuit open for webhook: " + delivery.WebhookID.String())
-		return // Don't update status, will retry when circuit closes
+		w.release(delivery) // Don't update status, will retry when circuit closes
+		return false
 	}
 
 	// Check and consume throughput before executing the webhook.
 	// Quota is reserved upfront so every attempt counts, regardless of outcome.
 	if !billing_service.ConsumeWorkspaceThroughput(delivery.Webhook.WorkspaceID, 1) {
 		pterm.DefaultLogger.Warn("Workspace throughput limit exceeded for webhook delivery: " + delivery.ID.String())
-		if updateErr := webhook_service.UpdateDeliveryStatus(delivery, false, 0, "", "workspace throughput limit exceeded — upgrade your plan to increase quota"); updateErr != nil {
+		if updateErr := webhook_service.UpdateDeliveryStatus(delivery, w.owner, false, 0, "", "workspace throughput limit exceeded — upgrade your plan to increase quota"); updateErr != nil {
 			pterm.DefaultLogger.Error("Failed to update delivery status: " + updateErr.Error())
 		}
-		return
+		return true
 	}
 
 	// Execute the webhook
 	startTime := time.Now()
-	httpCode, responseBody, err := w.executeWebhook(delivery)
+	httpCode, responseBody, contentType, err := w.executeWebhook(delivery)
 	duration := time.Since(startTime)
 
 	// Determine success (2xx status codes)
 	success := err == nil && httpCode >= 200 && httpCode < 300
 
 	// Create log entry
-	w.createLogEntry(delivery, httpCode, responseBody, err, duration, success)
+	w.createLogEntry(delivery, contentType, httpCode, responseBody, err, duration, success)
 
 	// Update circuit breaker
 	if success {
@@ -206,32 +361,59 @@ func (w *DeliveryWorker) processDelivery(delivery *webhook_entity.WebhookDeliver
 	if err != nil {
 		errMsg = err.Error()
 	}
-	if updateErr := webhook_service.UpdateDeliveryStatus(delivery, success, httpCode, responseBody, errMsg); updateErr != nil {
+	if updateErr := webhook_service.UpdateDeliveryStatus(delivery, w.owner, success, httpCode, responseBody, errMsg); updateErr != nil {
 		pterm.DefaultLogger.Error("Failed to update delivery status: " + updateErr.Error())
 	}
+	return true
+}
+
+// release gives up the lease on a delivery that was not attempted.
+func (w *DeliveryWorker) release(delivery *webhook_entity.WebhookDelivery) {
+	if err := webhook_service.ReleaseDelivery(delivery, w.owner); err != nil {
+		pterm.DefaultLogger.Error("Failed to release delivery: " + err.Error())
+	}
 }
 
-// executeWebhook makes the HTTP request to the webhook endpoint
-func (w *DeliveryWorker) executeWebhook(delivery *webhook_entity.WebhookDelivery) (int, string, error) {
+// executeWebhook makes the HTTP request to the webhook endpoint and returns
+// the response code and body, and the content type that was sent.
+func (w *DeliveryWorker) executeWebhook(delivery *webhook_entity.WebhookDelivery) (int, string, string, error) {
 	webhook := delivery.Webhook
+	contentType := webhook_transform_model.DefaultContentType
 
 	// Convert payload to JSON
 	jsonPayload, err := json.Marshal(delivery.Payload)
 	if err != nil {
-		return 0, "", err
+		return 0, "", contentType, err
 	}
 
+	// Reshape it with the webhook transform, if any
+	jsonPayload, contentType, err = webhook_service.RenderPayload(webhook.ID, jsonPayload)
+	if err != nil {
+		return 0, "", webhook_transform_model.DefaultContentType, err
+	}
+
+	httpCode, responseBody, err := w.send(webhook, jsonPayload, contentType, map[string]string{
+		"X-Wacraft-Delivery-ID": delivery.ID.String(),
+		"X-Wacraft-Event":       delivery.EventType,
+		"X-Wacraft-Attempt":     strconv.Itoa(delivery.AttemptCount + 1),
+	})
+	return httpCode, responseBody, contentType, err
+}
+
+// send posts a body to the webhook endpoint with the given delivery headers
+// and returns the response code and body.
+func (w *DeliveryWorker) send(webhook *webhook_entity.Webhook, body []byte, contentType string, headers map[string]string) (int, string, error) {
 	// Create request
-	req, err := http.NewRequest(webhook.HttpMethod, webhook.Url, bytes.NewBuffer(jsonPayload))
+	req, err := http.NewRequest(webhook.HttpMethod, webhook.Url, bytes.NewBuffer(body))
 	if err != nil {
 		return 0, "", err
 	}
 
 	// Set headers
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-Wacraft-Delivery-ID", delivery.ID.String())
-	req.Header.Set("X-Wacraft-Event", delivery.EventType)
-	req.Header.Set("X-Wacraft-Attempt", strconv.Itoa(delivery.AttemptCount+1))
+	req.Header.Set("Content-Type", contentType)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
 
 	// Add authorization header if set
 	if webhook.Authorization != "" {
@@ -245,7 +427,10 @@ func (w *DeliveryWorker) executeWebhook(delivery *webhook_entity.WebhookDelivery
 
 	// Add signature headers if enabled
 	if webhook.SigningEnabled && webhook.SigningSecret != "" {
-		signature, timestamp := webhook_core_service.SignatureHeaders(webhook.SigningSecret, jsonPayload)
+		signature, timestamp, err := webhook_service.SignatureHeaders(*webhook, body)
+		if err != nil {
+			return 0, "", err
+		}
 		req.Header.Set("X-Wacraft-Signature", signature)
 		req.Header.Set("X-Wacraft-Timestamp", timestamp)
 	}
@@ -277,7 +462,25 @@ func (w *DeliveryWorker) executeWebhook(delivery *webhook_entity.WebhookDelivery
 }
 
 // createLogEntry creates a webhook log entry for the attempt
-func (w *DeliveryWorker) createLogEntry(delivery *webhook_entity.WebhookDelivery, httpCode int, responseBody string, execErr error, duration time.Duration, success bool) {
+func (w *DeliveryWorker) createLogEntry(delivery *webhook_entity.WebhookDelivery, contentType string, httpCode int, responseBody string, execErr error, duration time.Duration, success bool) {
+	log := webhook_entity.WebhookLog{
+		Payload:        delivery.Payload,
+		WebhookID:      delivery.WebhookID,
+		DeliveryID:     &delivery.ID,
+		AttemptNumber:  delivery.AttemptCount + 1,
+		IdempotencyKey: delivery.IdempotencyKey,
+	}
+	requestHeaders := map[string]string{
+		"X-Wacraft-Delivery-ID": delivery.ID.String(),
+		"X-Wacraft-Event":       delivery.EventType,
+		"X-Wacraft-Attempt":     strconv.Itoa(delivery.AttemptCount + 1),
+	}
+	w.saveLog(delivery.Webhook, log, requestHeaders, contentType, httpCode, responseBody, execErr, duration)
+}
+
+// saveLog completes a log entry with the response and the headers that were
+// sent, on top of requestHeaders, and stores it.
+func (w *DeliveryWorker) saveLog(webhook *webhook_entity.Webhook, log webhook_entity.WebhookLog, requestHeaders map[string]string, contentType string, httpCode int, responseBody string, execErr error, duration time.Duration) {
 	// Parse response data if JSON
 	var responseData any
 	if responseBody != "" {
@@ -286,38 +489,32 @@ func (w *DeliveryWorker) createLogEntry(delivery *webhook_entity.WebhookDelivery
 		}
 	}
 
-	// Build request headers map
-	requestHeaders := map[string]string{
-		"Content-Type":          "application/json",
-		"X-Wacraft-Delivery-ID": delivery.ID.String(),
-		"X-Wacraft-Event":       delivery.EventType,
-		"X-Wacraft-Attempt":     strconv.Itoa(delivery.AttemptCount + 1),
+	// Record why the destination policy refused the attempt
+	if webhook_destination_service.IsBlocked(execErr) {
+		responseData = webhook_destination_service.BlockedResponseData(execErr)
+		pterm.DefaultLogger.Warn("Blocked webhook request to " + webhook.ID.String() + ": " + execErr.Error())
 	}
-	if delivery.Webhook.Authorization != "" {
+
+	// Build request headers map
+	requestHeaders["Content-Type"] = contentType
+	if webhook.Authorization != "" {
 		requestHeaders["Authorization"] = "[REDACTED]"
 	}
-	for key := range delivery.Webhook.CustomHeaders {
-		requestHeaders[key] = delivery.Webhook.CustomHeaders[key]
+	for key := range webhook.CustomHeaders {
+		requestHeaders[key] = webhook.CustomHeaders[key]
 	}
-	signatureSent := delivery.Webhook.SigningEnabled && delivery.Webhook.SigningSecret != ""
+	signatureSent := webhook.SigningEnabled && webhook.SigningSecret != ""
 	if signatureSent {
 		requestHeaders["X-Wacraft-Signature"] = "[REDACTED]"
 		requestHeaders["X-Wacraft-Timestamp"] = "[SET]"
 	}
 
-	log := webhook_entity.WebhookLog{
-		Payload:          delivery.Payload,
-		HttpResponseCode: httpCode,
-		ResponseData:     responseData,
-		WebhookID:        delivery.WebhookID,
-		DeliveryID:       &delivery.ID,
-		AttemptNumber:    delivery.AttemptCount + 1,
-		DurationMs:       duration.Milliseconds(),
-		SignatureSent:    signatureSent,
-		IdempotencyKey:   delivery.IdempotencyKey,
-		RequestHeaders:   requestHeaders,
-		RequestUrl:       delivery.Webhook.Url,
-	}
+	log.HttpResponseCode = httpCode
+	log.ResponseData = responseData
+	log.DurationMs = duration.Milliseconds()
+	log.SignatureSent = signatureSent
+	log.RequestHeaders = requestHeaders
+	log.RequestUrl = webhook.Url
 
 	if err := database.DB.Create(&log).Error; err != nil {
 		pterm.DefaultLogger.Error("Failed to create webhook log: " + err.Error())
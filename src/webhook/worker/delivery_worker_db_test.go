@@ -0,0 +1,188 @@
+package webhook_worker
+
+// Tests of the delivery worker that need the database. The tests of
+// delivery_worker_test.go do not.
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	synch_service "github.com/Astervia/wacraft-core/src/synch/service"
+	webhook_entity "github.com/Astervia/wacraft-core/src/webhook/entity"
+	webhook_model "github.com/Astervia/wacraft-core/src/webhook/model"
+	"github.com/Astervia/wacraft-server/src/database"
+	database_fixture "github.com/Astervia/wacraft-server/src/database/fixture"
+	webhook_setting_entity "github.com/Astervia/wacraft-server/src/webhook-setting/entity"
+	webhook_service "github.com/Astervia/wacraft-server/src/webhook/service"
+	"github.com/google/uuid"
+)
+
+func TestMain(m *testing.M) {
+	database.DB.AutoMigrate(
+		&webhook_entity.Webhook{},
+		&webhook_entity.WebhookDelivery{},
+		&webhook_setting_entity.WebhookSetting{},
+	)
+	// Columns added by the goose migrations on top of the core entity.
+	database.DB.Exec(`ALTER TABLE webhook_deliveries
+		ADD COLUMN IF NOT EXISTS lease_owner TEXT,
+		ADD COLUMN IF NOT EXISTS lease_expires_at TIMESTAMPTZ,
+		ADD COLUMN IF NOT EXISTS ordering_key TEXT`)
+	// Test webhooks use random workspace IDs.
+	database_fixture.DropForeignKeys("webhooks", "fk_webhooks_workspace")
+	os.Exit(m.Run())
+}
+
+// createTestDelivery stores a due delivery of a new webhook.
+func createTestDelivery(t *testing.T) webhook_entity.WebhookDelivery {
+	t.Helper()
+	isActive := true
+	maxRetries := 2
+	workspaceID := uuid.New()
+	webhook := webhook_entity.Webhook{
+		Url:         "https://example.com/hook",
+		HttpMethod:  "POST",
+		Event:       webhook_model.SendWhatsAppMessage,
+		WorkspaceID: &workspaceID,
+		IsActive:    &isActive,
+		MaxRetries:  &maxRetries,
+	}
+	if err := database.DB.Create(&webhook).Error; err != nil {
+		t.Fatalf("create webhook: %v", err)
+	}
+
+	now := time.Now()
+	delivery := webhook_entity.WebhookDelivery{
+		WebhookID:      webhook.ID,
+		IdempotencyKey: uuid.NewString(),
+		Payload:        map[string]any{"test": true},
+		Status:         webhook_entity.DeliveryStatusPending,
+		MaxAttempts:    maxRetries + 1,
+		NextAttemptAt:  &now,
+		EventType:      string(webhook.Event),
+		EventTimestamp: now,
+	}
+	if err := database.DB.Create(&delivery).Error; err != nil {
+		t.Fatalf("create delivery: %v", err)
+	}
+
+	t.Cleanup(func() {
+		database.DB.Exec("DELETE FROM webhook_deliveries WHERE webhook_id = ?", webhook.ID)
+		database.DB.Exec("DELETE FROM webhooks WHERE id = ?", webhook.ID)
+	})
+	return delivery
+}
+
+// claimDelivery claims due deliveries for owner and returns the given one,
+// or nil when it was not claimed.
+func claimDelivery(t *testing.T, owner string, id uuid.UUID) *webhook_entity.WebhookDelivery {
+	t.Helper()
+	deliveries, err := webhook_service.ClaimDeliveries(owner, 1000, time.Minute)
+	if err != nil {
+		t.Fatalf("claim deliveries: %v", err)
+	}
+	for i := range deliveries {
+		if deliveries[i].ID == id {
+			return &deliveries[i]
+		}
+	}
+	return nil
+}
+
+// TestDeliveryWorker_ListenPostgresWakesOnNotify verifies that a delivery
+// queued by any instance wakes the worker through Postgres LISTEN/NOTIFY.
+func TestDeliveryWorker_ListenPostgresWakesOnNotify(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	worker := &DeliveryWorker{ctx: ctx, cancel: cancel, wake: make(chan struct{}, 1)}
+
+	worker.wg.Add(1)
+	go worker.listenPostgres()
+	t.Cleanup(func() {
+		cancel()
+		worker.wg.Wait()
+	})
+
+	// LISTEN starts asynchronously, so notify until the worker wakes up.
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	deadline := time.After(5 * time.Second)
+	for {
+		select {
+		case <-worker.wake:
+			return
+		case <-ticker.C:
+			webhook_service.NotifyDeliveries()
+		case <-deadline:
+			t.Fatal("worker was not woken by NOTIFY")
+		}
+	}
+}
+
+// TestDeliveryWorker_ReleasesDeliveryWhenLockIsHeld verifies that a claimed
+// delivery skipped because another instance holds its lock can be claimed
+// again right away instead of waiting for its lease to expire.
+func TestDeliveryWorker_ReleasesDeliveryWhenLockIsHeld(t *testing.T) {
+	delivery := createTestDelivery(t)
+	lock := synch_service.NewMemoryLock[string]()
+	worker := &DeliveryWorker{lock: lock, owner: "worker-a"}
+
+	claimed := claimDelivery(t, worker.owner, delivery.ID)
+	if claimed == nil {
+		t.Fatal("expected the delivery to be claimed")
+	}
+
+	// Another instance is processing the delivery.
+	acquired, err := lock.TryLock(delivery.ID.String())
+	if err != nil || !acquired {
+		t.Fatalf("failed to acquire lock: err=%v acquired=%v", err, acquired)
+	}
+	defer lock.Unlock(delivery.ID.String()) //nolint:errcheck
+
+	if worker.processDelivery(claimed) {
+		t.Fatal("expected the delivery to be skipped")
+	}
+
+	if claimDelivery(t, "worker-b", delivery.ID) == nil {
+		t.Fatal("expected the skipped delivery to be claimable again")
+	}
+}
+
+// TestDeliveryWorker_LockBatchReleasesHeldDeliveries verifies that the
+// deliveries of a batch held by another instance are released and left out
+// of the batch, and that the locks of the others are released after the send.
+func TestDeliveryWorker_LockBatchReleasesHeldDeliveries(t *testing.T) {
+	free := createTestDelivery(t)
+	held := createTestDelivery(t)
+	lock := synch_service.NewMemoryLock[string]()
+	worker := &DeliveryWorker{lock: lock, owner: "worker-a"}
+
+	claimedFree := claimDelivery(t, worker.owner, free.ID)
+	claimedHeld := claimDelivery(t, worker.owner, held.ID)
+	if claimedFree == nil || claimedHeld == nil {
+		t.Fatal("expected both deliveries to be claimed")
+	}
+
+	// Another instance is processing one of the deliveries.
+	acquired, err := lock.TryLock(held.ID.String())
+	if err != nil || !acquired {
+		t.Fatalf("failed to acquire lock: err=%v acquired=%v", err, acquired)
+	}
+	defer lock.Unlock(held.ID.String()) //nolint:errcheck
+
+	locked, unlock := worker.lockBatch([]*webhook_entity.WebhookDelivery{claimedFree, claimedHeld})
+	if len(locked) != 1 || locked[0].ID != free.ID {
+		t.Fatalf("expected only the free delivery to be locked, got %d deliveries", len(locked))
+	}
+	if claimDelivery(t, "worker-b", held.ID) == nil {
+		t.Fatal("expected the held delivery to be claimable again")
+	}
+
+	unlock()
+	acquired, err = lock.TryLock(free.ID.String())
+	if err != nil || !acquired {
+		t.Fatalf("expected the lock of the sent delivery to be released: err=%v acquired=%v", err, acquired)
+	}
+	lock.Unlock(free.ID.String()) //nolint:errcheck
+}
@@ -10,6 +10,7 @@ import (
 	synch_redis "github.com/Astervia/wacraft-core/src/synch/redis"
 	synch_service "github.com/Astervia/wacraft-core/src/synch/service"
 	webhook_entity "github.com/Astervia/wacraft-core/src/webhook/entity"
+	webhook_setting_entity "github.com/Astervia/wacraft-server/src/webhook-setting/entity"
 	"github.com/google/uuid"
 )
 
@@ -302,3 +303,99 @@ func TestDeliveryWorker_NilLockProcessesAll(t *testing.T) {
 		t.Fatalf("expected %d total, got %d", n*2, got)
 	}
 }
+
+// TestDeliveryWorker_NextWaitAfterAttempts verifies that a pass that attempted
+// deliveries runs again right away, since more may already be due.
+func TestDeliveryWorker_NextWaitAfterAttempts(t *testing.T) {
+	worker := &DeliveryWorker{}
+	if wait := worker.nextWait(1); wait != 0 {
+		t.Fatalf("expected no wait after an attempt, got %s", wait)
+	}
+}
+
+// TestWaitUntil verifies the sleep between passes that attempted nothing. Due
+// deliveries were skipped, e.g. a full batch behind an open circuit, and must
+// not make the worker spin.
+func TestWaitUntil(t *testing.T) {
+	now := time.Now()
+	poll := 5 * time.Second
+	past := now.Add(-time.Second)
+	soon := now.Add(time.Second)
+	later := now.Add(time.Minute)
+
+	cases := []struct {
+		name string
+		next *time.Time
+		want time.Duration
+	}{
+		{"nothing queued", nil, poll},
+		{"due deliveries skipped", &past, poll},
+		{"due now", &now, poll},
+		{"next attempt before the poll", &soon, time.Second},
+		{"next attempt after the poll", &later, poll},
+	}
+	for _, c := range cases {
+		if got := waitUntil(c.next, now, poll); got != c.want {
+			t.Errorf("%s: expected %s, got %s", c.name, c.want, got)
+		}
+	}
+}
+
+// TestDeliveryWorker_SignalCollapses verifies that a burst of notifications
+// results in a single pending wake-up.
+func TestDeliveryWorker_SignalCollapses(t *testing.T) {
+	worker := &DeliveryWorker{wake: make(chan struct{}, 1)}
+	for range 3 {
+		worker.signal()
+	}
+	if n := len(worker.wake); n != 1 {
+		t.Fatalf("expected 1 pending wake-up, got %d", n)
+	}
+}
+
+func TestGroupBatches(t *testing.T) {
+	batching := &webhook_entity.Webhook{}
+	batching.ID = uuid.New()
+	unset := &webhook_entity.Webhook{}
+	unset.ID = uuid.New()
+	single := &webhook_entity.Webhook{}
+	single.ID = uuid.New()
+
+	delivery := func(webhook *webhook_entity.Webhook) webhook_entity.WebhookDelivery {
+		return webhook_entity.WebhookDelivery{WebhookID: webhook.ID, Webhook: webhook}
+	}
+	deliveries := []webhook_entity.WebhookDelivery{
+		delivery(batching), delivery(unset), delivery(batching), delivery(single), delivery(batching),
+		{WebhookID: batching.ID}, // Webhook not preloaded.
+	}
+	settings := map[uuid.UUID]webhook_setting_entity.WebhookSetting{
+		batching.ID: {WebhookID: batching.ID, BatchSize: 2},
+		single.ID:   {WebhookID: single.ID, BatchSize: 1},
+	}
+
+	batches := groupBatches(deliveries, settings)
+	sizes := make([]int, 0, len(batches))
+	for _, batch := range batches {
+		sizes = append(sizes, len(batch.deliveries))
+		if (batch.setting != nil) != (batch.deliveries[0].Webhook == batching) {
+			t.Errorf("unexpected setting %v for a batch of webhook %s", batch.setting, batch.deliveries[0].WebhookID)
+		}
+	}
+
+	want := []int{2, 1, 1, 1, 1}
+	if len(sizes) != len(want) {
+		t.Fatalf("expected batches of %v, got %v", want, sizes)
+	}
+	for i := range want {
+		if sizes[i] != want[i] {
+			t.Fatalf("expected batches of %v, got %v", want, sizes)
+		}
+	}
+	if batches[0].deliveries[1] != &deliveries[2] || batches[3].deliveries[0] != &deliveries[4] {
+		t.Error("expected the third batching delivery to open a new batch once the first is full")
+	}
+
+	if batches := groupBatches(deliveries, nil); len(batches) != len(deliveries) {
+		t.Errorf("expected every delivery on its own without settings, got %d batches", len(batches))
+	}
+}
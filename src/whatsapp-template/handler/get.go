@@ -1,6 +1,8 @@
 package whatsapp_template_handler
 
 import (
+	"errors"
+
 	common_model "github.com/Astervia/wacraft-core/src/common/model"
 	phone_config_service "github.com/Astervia/wacraft-server/src/phone-config/service"
 	"github.com/Astervia/wacraft-server/src/validators"
@@ -15,12 +17,14 @@ import (
 //
 //	@Summary		Get WhatsApp templates
 //	@Description	Retrieves a paginated list of WhatsApp templates using the Graph API.
+//	@Description	The WhatsApp Business Account is the one of the selected sender ("phone_config_id" or "messaging_product_id"), falling back to the workspace default sender.
 //	@Tags			WhatsApp template
 //	@Accept			json
 //	@Produce		json
-//	@Param			template	query		template_model.TemplateQueryParams	true	"Pagination and query parameters"
-//	@Success		200			{array}		template_model.GetTemplateResponse	"List of templates"
-//	@Failure		400			{object}	common_model.DescriptiveError		"Invalid query parameters"
+//	@Param			template	query		template_model.TemplateQueryParams		true	"Pagination and query parameters"
+//	@Param			sender		query		phone_config_service.SenderSelection	false	"Optional sender selection"
+//	@Success		200			{array}		template_model.GetTemplateResponse		"List of templates"
+//	@Failure		400			{object}	common_model.DescriptiveError			"Invalid query parameters or sender"
 //	@Failure		500			{object}	common_model.DescriptiveError		"Unable to retrieve templates from API"
 //	@Router			/whatsapp-template [get]
 //	@Security		ApiKeyAuth
@@ -34,9 +38,18 @@ func Get(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(common_model.NewValidationError(err).Send())
 	}
 
+	var selection phone_config_service.SenderSelection
+	if err := c.QueryParser(&selection); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(common_model.NewParseJsonError(err).Send())
+	}
+
 	workspace := workspace_middleware.GetWorkspace(c)
-	api, err := phone_config_service.GetFirstConfigAPIByWorkspace(workspace.ID)
-	if err == gorm.ErrRecordNotFound {
+	api, err := phone_config_service.GetWorkspaceSenderAPI(workspace.ID, selection)
+	if errors.Is(err, phone_config_service.ErrSenderNotInWorkspace) {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewApiError("invalid sender", err, "phone_config_service").Send(),
+		)
+	} else if errors.Is(err, gorm.ErrRecordNotFound) {
 		return c.Status(fiber.StatusOK).JSON(template.GetTemplateResponse{})
 	} else if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(
@@ -0,0 +1,26 @@
+package workspace_setting_entity
+
+import (
+	common_model "github.com/Astervia/wacraft-core/src/common/model"
+	phone_config_entity "github.com/Astervia/wacraft-core/src/phone-config/entity"
+	workspace_entity "github.com/Astervia/wacraft-core/src/workspace/entity"
+	"github.com/google/uuid"
+)
+
+// WorkspaceSetting holds per-workspace messaging preferences.
+// A workspace without a row behaves as if every setting had its zero value.
+type WorkspaceSetting struct {
+	WorkspaceID uuid.UUID                   `json:"workspace_id" gorm:"type:uuid;not null;uniqueIndex"`
+	Workspace   *workspace_entity.Workspace `json:"workspace,omitempty" gorm:"foreignKey:WorkspaceID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+
+	// DefaultPhoneConfigID is the sender used when a send does not select one and
+	// the contact never wrote to any of the workspace numbers.
+	DefaultPhoneConfigID *uuid.UUID                       `json:"default_phone_config_id,omitempty" gorm:"type:uuid"`
+	DefaultPhoneConfig   *phone_config_entity.PhoneConfig `json:"default_phone_config,omitempty" gorm:"foreignKey:DefaultPhoneConfigID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
+
+	// DisableServiceWindowCheck lets free-form messages be sent outside the 24-hour
+	// customer service window instead of rejecting them before reaching Meta.
+	DisableServiceWindowCheck bool `json:"disable_service_window_check" gorm:"not null;default:false"`
+
+	common_model.Audit
+}
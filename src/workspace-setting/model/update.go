@@ -0,0 +1,13 @@
+package workspace_setting_model
+
+import "github.com/google/uuid"
+
+// UpdateWorkspaceSetting is the body of the workspace setting update endpoint.
+// Omitted fields are left unchanged.
+type UpdateWorkspaceSetting struct {
+	DefaultPhoneConfigID *uuid.UUID `json:"default_phone_config_id,omitempty"`
+	// ClearDefaultPhoneConfig removes the default sender.
+	ClearDefaultPhoneConfig bool `json:"clear_default_phone_config,omitempty"`
+	// DisableServiceWindowCheck turns the 24-hour customer service window check off (true) or on (false).
+	DisableServiceWindowCheck *bool `json:"disable_service_window_check,omitempty"`
+}
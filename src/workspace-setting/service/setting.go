@@ -0,0 +1,94 @@
+package workspace_setting_service
+
+import (
+	"errors"
+
+	phone_config_entity "github.com/Astervia/wacraft-core/src/phone-config/entity"
+	"github.com/Astervia/wacraft-server/src/database"
+	workspace_setting_entity "github.com/Astervia/wacraft-server/src/workspace-setting/entity"
+	workspace_setting_model "github.com/Astervia/wacraft-server/src/workspace-setting/model"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrPhoneConfigNotInWorkspace is returned when the default sender does not belong to the workspace.
+var ErrPhoneConfigNotInWorkspace = errors.New("phone config does not belong to the workspace")
+
+// GetWorkspaceSetting returns the settings of a workspace.
+// When the workspace has no settings yet, a zero-value setting is returned.
+func GetWorkspaceSetting(workspaceID uuid.UUID, db *gorm.DB) (workspace_setting_entity.WorkspaceSetting, error) {
+	if db == nil {
+		db = database.DB
+	}
+
+	setting := workspace_setting_entity.WorkspaceSetting{WorkspaceID: workspaceID}
+	err := db.Where("workspace_id = ?", workspaceID).First(&setting).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return workspace_setting_entity.WorkspaceSetting{WorkspaceID: workspaceID}, nil
+	}
+
+	return setting, err
+}
+
+// UpdateWorkspaceSetting creates the settings row of a workspace if needed and applies updates to it.
+func UpdateWorkspaceSetting(
+	workspaceID uuid.UUID,
+	updates map[string]any,
+	db *gorm.DB,
+) (workspace_setting_entity.WorkspaceSetting, error) {
+	if db == nil {
+		db = database.DB
+	}
+
+	setting := workspace_setting_entity.WorkspaceSetting{WorkspaceID: workspaceID}
+	if err := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "workspace_id"}},
+		DoNothing: true,
+	}).Create(&setting).Error; err != nil {
+		return setting, err
+	}
+
+	if len(updates) > 0 {
+		if err := db.Model(&workspace_setting_entity.WorkspaceSetting{}).
+			Where("workspace_id = ?", workspaceID).
+			Updates(updates).Error; err != nil {
+			return setting, err
+		}
+	}
+
+	return GetWorkspaceSetting(workspaceID, db)
+}
+
+// Update applies the update body to the settings of a workspace. Returns
+// ErrPhoneConfigNotInWorkspace when the default phone config belongs to another workspace.
+func Update(
+	workspaceID uuid.UUID,
+	body workspace_setting_model.UpdateWorkspaceSetting,
+	db *gorm.DB,
+) (workspace_setting_entity.WorkspaceSetting, error) {
+	if db == nil {
+		db = database.DB
+	}
+
+	updates := make(map[string]any)
+	if body.ClearDefaultPhoneConfig {
+		updates["default_phone_config_id"] = nil
+	} else if body.DefaultPhoneConfigID != nil {
+		var count int64
+		if err := db.Model(&phone_config_entity.PhoneConfig{}).
+			Where("id = ? AND workspace_id = ?", *body.DefaultPhoneConfigID, workspaceID).
+			Count(&count).Error; err != nil {
+			return workspace_setting_entity.WorkspaceSetting{}, err
+		}
+		if count == 0 {
+			return workspace_setting_entity.WorkspaceSetting{}, ErrPhoneConfigNotInWorkspace
+		}
+		updates["default_phone_config_id"] = *body.DefaultPhoneConfigID
+	}
+	if body.DisableServiceWindowCheck != nil {
+		updates["disable_service_window_check"] = *body.DisableServiceWindowCheck
+	}
+
+	return UpdateWorkspaceSetting(workspaceID, updates, db)
+}
@@ -0,0 +1,85 @@
+package workspace_handler
+
+import (
+	"errors"
+
+	common_model "github.com/Astervia/wacraft-core/src/common/model"
+	"github.com/Astervia/wacraft-server/src/validators"
+	_ "github.com/Astervia/wacraft-server/src/workspace-setting/entity"
+	workspace_setting_model "github.com/Astervia/wacraft-server/src/workspace-setting/model"
+	workspace_setting_service "github.com/Astervia/wacraft-server/src/workspace-setting/service"
+	workspace_middleware "github.com/Astervia/wacraft-server/src/workspace/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetSetting returns the messaging settings of a workspace.
+//
+//	@Summary		Get workspace settings
+//	@Description	Returns the messaging settings of the workspace, such as the default sender phone config and whether the service window check is enabled.
+//	@Tags			Workspace
+//	@Produce		json
+//	@Param			workspace_id	path		string										true	"Workspace ID"
+//	@Success		200				{object}	workspace_setting_entity.WorkspaceSetting	"Workspace settings"
+//	@Failure		500				{object}	common_model.DescriptiveError				"Internal server error"
+//	@Security		ApiKeyAuth
+//	@Security		WorkspaceAuth
+//	@Router			/workspace/{workspace_id}/setting [get]
+func GetSetting(c *fiber.Ctx) error {
+	workspace := workspace_middleware.GetWorkspace(c)
+
+	setting, err := workspace_setting_service.GetWorkspaceSetting(workspace.ID, nil)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			common_model.NewApiError("Unable to get workspace settings", err, "workspace_setting_service").Send(),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(setting)
+}
+
+// UpdateSetting updates the messaging settings of a workspace.
+//
+//	@Summary		Update workspace settings
+//	@Description	Updates the messaging settings of the workspace. The default phone config is used as sender when a send does not select one and the contact never wrote to any of the workspace numbers. Disabling the service window check lets free-form messages reach Meta outside the 24-hour window. Requires workspace.settings policy.
+//	@Tags			Workspace
+//	@Accept			json
+//	@Produce		json
+//	@Param			workspace_id	path		string											true	"Workspace ID"
+//	@Param			setting			body		workspace_setting_model.UpdateWorkspaceSetting	true	"Settings to update"
+//	@Success		200				{object}	workspace_setting_entity.WorkspaceSetting		"Updated workspace settings"
+//	@Failure		400				{object}	common_model.DescriptiveError					"Invalid request body or phone config"
+//	@Failure		403				{object}	common_model.DescriptiveError					"Forbidden"
+//	@Failure		500				{object}	common_model.DescriptiveError					"Internal server error"
+//	@Security		ApiKeyAuth
+//	@Security		WorkspaceAuth
+//	@Router			/workspace/{workspace_id}/setting [patch]
+func UpdateSetting(c *fiber.Ctx) error {
+	workspace := workspace_middleware.GetWorkspace(c)
+
+	var updateData workspace_setting_model.UpdateWorkspaceSetting
+	if err := c.BodyParser(&updateData); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewParseJsonError(err).Send(),
+		)
+	}
+
+	if err := validators.Validator().Struct(&updateData); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewValidationError(err).Send(),
+		)
+	}
+
+	setting, err := workspace_setting_service.Update(workspace.ID, updateData, nil)
+	if errors.Is(err, workspace_setting_service.ErrPhoneConfigNotInWorkspace) {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			common_model.NewApiError("Phone config does not belong to the workspace", err, "workspace_setting_service").Send(),
+		)
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			common_model.NewApiError("Unable to update workspace settings", err, "workspace_setting_service").Send(),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(setting)
+}
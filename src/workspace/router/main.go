@@ -17,6 +17,7 @@ func Route(app *fiber.App) {
 	workspaceRoutes(group)
 	memberRoutes(group)
 	invitationRoutes(group)
+	settingRoutes(group)
 
 	// Phone config routes under /workspace/:workspace_id/phone-config
 	phoneConfigGroup := group.Group("/:workspace_id")
@@ -144,3 +145,27 @@ func invitationRoutes(group fiber.Router) {
 		workspace_handler.RevokeInvitation,
 	)
 }
+
+// Workspace setting routes
+func settingRoutes(group fiber.Router) {
+	settingGroup := group.Group("/:workspace_id/setting")
+
+	// Get settings - any workspace member can view
+	settingGroup.Get("",
+		auth_middleware.UserMiddleware,
+		auth_middleware.EmailVerifiedMiddleware,
+		workspace_middleware.WorkspaceMiddleware,
+		billing_middleware.ThroughputMiddleware,
+		workspace_handler.GetSetting,
+	)
+
+	// Update settings - requires workspace.settings policy
+	settingGroup.Patch("",
+		auth_middleware.UserMiddleware,
+		auth_middleware.EmailVerifiedMiddleware,
+		workspace_middleware.WorkspaceMiddleware,
+		workspace_middleware.RequirePolicy(workspace_model.PolicyWorkspaceSettings),
+		billing_middleware.ThroughputMiddleware,
+		workspace_handler.UpdateSetting,
+	)
+}